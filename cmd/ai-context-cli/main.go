@@ -0,0 +1,874 @@
+package main
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"ai-context-cli/internal/app"
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/gitdiff"
+	"ai-context-cli/internal/keyring"
+	"ai-context-cli/internal/mcp"
+	"ai-context-cli/internal/providers"
+	"ai-context-cli/internal/server"
+	"ai-context-cli/internal/ui"
+	"ai-context-cli/internal/watch"
+	"ai-context-cli/pkg/types"
+)
+
+func main() {
+	profile := flag.String("profile", "", "configuration profile to use")
+	logFile := flag.String("log-file", "", "write the event log to a file")
+	anonymize := flag.Bool("anonymize", false, "redact file paths in the event log")
+	list := flag.Bool("list", false, "print the files that would be included and exit")
+	flag.Usage = printHelp
+	flag.Parse()
+
+	if *list {
+		root := "."
+		if flag.NArg() > 0 {
+			root = flag.Arg(0)
+		}
+		if err := runList(root, *profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "version":
+			fmt.Printf("ai-context-cli %s\n", ui.Version)
+			return
+		case "help":
+			printHelp()
+			return
+		case "scan":
+			exitOnError(runScan(flag.Args()[1:], *profile))
+			return
+		case "generate":
+			exitOnError(runGenerate(flag.Args()[1:], *profile))
+			return
+		case "models":
+			exitOnError(runModels(flag.Args()[1:], *profile))
+			return
+		case "templates":
+			exitOnError(runTemplates(flag.Args()[1:], *profile))
+			return
+		case "watch":
+			exitOnError(runWatch(flag.Args()[1:], *profile))
+			return
+		case "serve":
+			exitOnError(runServe(flag.Args()[1:]))
+			return
+		case "mcp":
+			exitOnError(runMCP(flag.Args()[1:], *profile))
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", flag.Arg(0))
+			printHelp()
+			os.Exit(1)
+		}
+	}
+
+	cfg, err := loadConfig(*profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Warn about capability typos that would silently break filtering
+	var warnings []string
+	warnings = append(warnings, providers.RegistryWarnings()...)
+	warnings = append(warnings, providers.ValidateModels(cfg.Models)...)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	model := app.NewModel().WithConfig(cfg)
+	model.EventLog().SetAnonymize(*anonymize)
+	if *logFile != "" {
+		if err := model.EventLog().OpenFile(*logFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
+			os.Exit(1)
+		}
+		defer model.EventLog().Close()
+	}
+
+	// Capturing the mouse stops the terminal from selecting text, so it is opt-in
+	var options []tea.ProgramOption
+	if cfg.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	program := tea.NewProgram(model, options...)
+	final, err := program.Run()
+	if final, ok := final.(app.Model); ok {
+		final.Close()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usageError marks invalid arguments, which exit with status 2 like flag parse errors
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// exitOnError reports a command error and exits: 2 for usage errors, 1 otherwise
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	var usage usageError
+	if errors.As(err, &usage) {
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+// loadConfig loads the requested profile, or the active one if none is given
+func loadConfig(profile string) (*config.Config, error) {
+	if profile == "" {
+		return config.Load()
+	}
+
+	configDir, err := config.DefaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadProfile(configDir, profile)
+}
+
+// commandScanConfig builds a command's scan configuration from the profile's
+// config file and the project blocklist, as the TUI does
+func commandScanConfig(root, profile string) (context.ScanConfig, error) {
+	cfg, err := loadConfig(profile)
+	if err != nil {
+		return context.ScanConfig{}, fmt.Errorf("loading configuration: %w", err)
+	}
+	return app.ScanConfig(cfg, root), nil
+}
+
+// runList prints the relative paths of included files, one per line
+func runList(root, profile string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	scanConfig, err := commandScanConfig(root, profile)
+	if err != nil {
+		return err
+	}
+	result, err := context.NewProjectScanner(scanConfig).Scan(stdcontext.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, file := range context.NewContextGenerator().IncludedFiles(result) {
+		relativePath, err := filepath.Rel(result.RootPath, file.Path)
+		if err != nil {
+			relativePath = file.Path
+		}
+		fmt.Println(filepath.ToSlash(relativePath))
+	}
+	return nil
+}
+
+// runGenerate writes the context for a directory once, optionally split into structure and content files
+func runGenerate(args []string, profile string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	output := flags.String("output", "context.md", "file the generated context is written to")
+	split := flags.Bool("split", false, "write structure and file contents to separate files")
+	paths := flags.String("paths", "relative", "show paths relative to the scanned directory or absolute")
+	path := flags.String("path", "", "directory to scan (instead of the dir argument)")
+	format := flags.String("format", "markdown", "output format: markdown, text, json or xml")
+	noRedact := flags.Bool("no-redact", false, "include detected secrets unmasked")
+	redactSkip := flags.String("redact-skip", "", "comma-separated secret patterns to leave unmasked: "+strings.Join(context.RedactionPatterns(), ", "))
+	since := flags.String("since", "", "only include files changed since a git ref, with their diffs")
+	outline := flags.String("outline", "off", "API outline of Go packages: off, add (with contents) or only (instead of Go contents)")
+	detail := flags.String("detail", "full", "code file contents: full, outline (signatures only) or skeleton (bodies elided)")
+	includeGenerated := flags.Bool("include-generated", false, "include lockfiles, generated code and duplicate copies")
+	compress := flags.String("compress", "none", "comma-separated compression: license, comments, blank-lines, data (JSON/YAML), all or none")
+	focus := flags.String("focus", "", "comma-separated keywords; files whose path or content mention them are picked first")
+	largeFiles := flags.String("large-files", "structural", "oversized files: structural (cut at a declaration), head, tail, both or skip")
+	largeFileLines := flags.Int("large-file-lines", 200, "lines kept from each oversized file")
+	group := flags.String("group", "type", "content sections per file type or per top-level directory: type or directory")
+	separateTests := flags.Bool("separate-tests", false, "put test files in their own Tests section")
+	maxSections := flags.Int("max-sections", 0, "keep only the highest-priority content sections (0: no cap)")
+	dirReadmes := flags.Bool("dir-readmes", false, "add each subdirectory's README after the project structure")
+	headerLevel := flags.Int("header-level", 1, "markdown level of section headers, e.g. 2 for ## when embedding")
+	keepLineEndings := flags.Bool("keep-line-endings", false, "leave CRLF/CR line endings instead of converting them to LF")
+	readBuffer := flags.Int("read-buffer", 32*1024, "bytes buffered when streaming file content")
+	flags.Parse(args)
+
+	pathStyle, err := context.ParsePathStyle(*paths)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	outlineMode, err := context.ParseOutlineMode(*outline)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	contentDetail, err := context.ParseContentDetail(*detail)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	compression, err := context.ParseCompression(*compress)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	largeFileMode, err := context.ParseLargeFileMode(*largeFiles)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	grouping, err := context.ParseContentGrouping(*group)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	formatter, err := context.ParseFormat(*format)
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if *split && formatter.Name() != "markdown" {
+		return usageError("--split only supports the markdown format")
+	}
+
+	// Match the default output file to the format
+	outputSet := false
+	flags.Visit(func(f *flag.Flag) {
+		outputSet = outputSet || f.Name == "output"
+	})
+	if !outputSet {
+		*output = "context" + formatter.Extension()
+	}
+
+	root, err := commandRoot(*path, flags)
+	if err != nil {
+		return err
+	}
+	outputPath, err := filepath.Abs(*output)
+	if err != nil {
+		return err
+	}
+
+	generator := context.NewContextGenerator()
+	generator.SetPathStyle(pathStyle)
+	generator.SetOutlineMode(outlineMode)
+	generator.SetContentDetail(contentDetail)
+	generator.SetCompression(compression)
+	generator.SetFocusKeywords(strings.Split(*focus, ","))
+	generator.SetLargeFilePolicy(context.LargeFilePolicy{Mode: largeFileMode, LineBudget: *largeFileLines})
+	generator.SetRedaction(!*noRedact, strings.Split(*redactSkip, ","))
+	generator.SetContentGrouping(grouping)
+	generator.SetSeparateTests(*separateTests)
+	generator.SetMaxContentSections(*maxSections)
+	generator.SetIncludeDirectoryReadmes(*dirReadmes)
+	generator.SetHeaderBaseLevel(*headerLevel)
+	generator.SetNormalizeNewlines(!*keepLineEndings)
+	generator.SetReadBufferSize(*readBuffer)
+
+	// Never include earlier exports
+	structurePath, contentPath := splitPaths(outputPath)
+	scanConfig, err := commandScanConfig(root, profile)
+	if err != nil {
+		return err
+	}
+	scanConfig.ExcludePatterns = append(scanConfig.ExcludePatterns,
+		scannedPath(outputPath), scannedPath(structurePath), scannedPath(contentPath))
+	if *includeGenerated {
+		scanConfig.SkipGenerated = false
+	}
+
+	var generated *context.ContextResult
+	if *since != "" {
+		generated, err = gitdiff.Context(stdcontext.Background(), generator, scanConfig, *since)
+		var changesErr *gitdiff.ChangesError
+		if errors.As(err, &changesErr) {
+			err = usageError(err.Error())
+		}
+	} else {
+		var result *context.ScanResult
+		result, err = context.NewProjectScanner(scanConfig).Scan(stdcontext.Background())
+		if err != nil {
+			return err
+		}
+		generated, err = generator.GenerateContext(stdcontext.Background(), result, filepath.Base(root))
+	}
+	if err != nil {
+		return err
+	}
+	if len(generated.Redactions) > 0 {
+		fmt.Printf("Redacted %d possible secrets\n", len(generated.Redactions))
+	}
+
+	written, err := writeContext(generated, formatter, outputPath, *split)
+	if err != nil {
+		return err
+	}
+	for _, path := range written {
+		fmt.Printf("Context written to %s\n", path)
+	}
+	return nil
+}
+
+// scannedPath resolves symlinks in an absolute path's directory, as
+// DefaultScanConfig does for the scan root, so output files can be compared with
+// scanned paths; the file itself may not exist yet
+func scannedPath(path string) string {
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return path
+	}
+	return filepath.Join(dir, filepath.Base(path))
+}
+
+// commandRoot returns the absolute directory a command works on, from --path or
+// the first argument, defaulting to the current directory
+func commandRoot(path string, flags *flag.FlagSet) (string, error) {
+	root := "."
+	switch {
+	case path != "":
+		root = path
+	case flags.NArg() > 0:
+		root = flags.Arg(0)
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", usageError(fmt.Sprintf("cannot scan %s: %v", root, err))
+	}
+	if !info.IsDir() {
+		return "", usageError(fmt.Sprintf("%s is not a directory", root))
+	}
+	return root, nil
+}
+
+// scanSummary is the machine-readable result of the scan command
+type scanSummary struct {
+	Root         string         `json:"root"`
+	Files        int            `json:"files"`
+	Directories  int            `json:"directories"`
+	SizeBytes    int64          `json:"size_bytes"`
+	Lines        int            `json:"lines"`
+	Excluded     int            `json:"excluded"`
+	ProjectTypes []string       `json:"project_types"`
+	Extensions   map[string]int `json:"extensions"`
+	IgnoreRules  map[string]int `json:"ignore_rules,omitempty"` // files excluded per .aicontextignore rule
+	IgnoreDirs   map[string]int `json:"ignore_rule_folders,omitempty"` // folders excluded whole per rule
+	Errors       []string       `json:"errors,omitempty"`       // paths skipped because they could not be read
+}
+
+// runScan scans a directory and prints a summary without generating context
+func runScan(args []string, profile string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	path := flags.String("path", "", "directory to scan (instead of the dir argument)")
+	asJSON := flags.Bool("json", false, "print the summary as JSON")
+	includeGenerated := flags.Bool("include-generated", false, "include lockfiles, generated code and duplicate copies")
+	flags.Parse(args)
+
+	root, err := commandRoot(*path, flags)
+	if err != nil {
+		return err
+	}
+
+	scanConfig, err := commandScanConfig(root, profile)
+	if err != nil {
+		return err
+	}
+	if *includeGenerated {
+		scanConfig.SkipGenerated = false
+	}
+	result, err := context.NewProjectScanner(scanConfig).Scan(stdcontext.Background())
+	if err != nil {
+		return err
+	}
+
+	summary := scanSummary{
+		Root:         result.RootPath,
+		Files:        result.TotalFiles,
+		Directories:  result.TotalDirectories,
+		SizeBytes:    result.TotalSize,
+		Lines:        result.TotalLines,
+		Excluded:     result.ExcludedFiles,
+		ProjectTypes: result.ProjectTypes,
+		Extensions:   result.Extensions,
+		IgnoreRules:  result.IgnoreRuleHits,
+		IgnoreDirs:   result.IgnoreRuleDirs,
+	}
+	for _, scanErr := range result.Errors {
+		summary.Errors = append(summary.Errors, scanErr.Error())
+	}
+	if *asJSON {
+		return printJSON(summary)
+	}
+
+	fmt.Printf("root\t%s\n", summary.Root)
+	fmt.Printf("files\t%d\n", summary.Files)
+	fmt.Printf("directories\t%d\n", summary.Directories)
+	fmt.Printf("size_bytes\t%d\n", summary.SizeBytes)
+	fmt.Printf("lines\t%d\n", summary.Lines)
+	fmt.Printf("excluded\t%d\n", summary.Excluded)
+	fmt.Printf("project_types\t%s\n", strings.Join(summary.ProjectTypes, ","))
+	for _, counts := range []struct {
+		label string
+		hits  map[string]int
+	}{{"ignore_rule", summary.IgnoreRules}, {"ignore_rule_folders", summary.IgnoreDirs}} {
+		rules := make([]string, 0, len(counts.hits))
+		for rule := range counts.hits {
+			rules = append(rules, rule)
+		}
+		sort.Strings(rules)
+		for _, rule := range rules {
+			fmt.Printf("%s\t%s\t%d\n", counts.label, rule, counts.hits[rule])
+		}
+	}
+	for _, scanErr := range summary.Errors {
+		fmt.Printf("error\t%s\n", scanErr)
+	}
+	return nil
+}
+
+// modelStatus is the machine-readable form of a configured model and its test result
+type modelStatus struct {
+	Name      string `json:"name"`
+	Provider  string `json:"provider"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Default   bool   `json:"default,omitempty"`
+	Key       string `json:"key,omitempty"` // masked, with where it was found
+	OK        *bool  `json:"ok,omitempty"`
+	LatencyMS int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// runModels lists the configured models or tests their connections
+func runModels(args []string, profile string) error {
+	if len(args) == 0 || (args[0] != "list" && args[0] != "test" && args[0] != "available") {
+		return usageError("usage: models list|test [--json] [name...] | models available [--json] <provider>")
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("models "+action, flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print models as JSON")
+	flags.Parse(args[1:])
+
+	cfg, err := loadConfig(profile)
+	if err != nil {
+		return err
+	}
+	if action == "available" {
+		if flags.NArg() != 1 {
+			return usageError("usage: models available [--json] <provider>")
+		}
+		return runAvailableModels(cfg, flags.Arg(0), *asJSON)
+	}
+	active, _ := cfg.ActiveModel()
+
+	models := cfg.Models
+	if flags.NArg() > 0 {
+		models = nil
+		for _, name := range flags.Args() {
+			model, ok := findModel(cfg.Models, name)
+			if !ok {
+				return usageError(fmt.Sprintf("model %q is not configured", name))
+			}
+			models = append(models, model)
+		}
+	}
+
+	var statuses []modelStatus
+	failed := 0
+	tester := providers.NewConnectionTester()
+	keys := keyring.System()
+	for _, model := range models {
+		status := modelStatus{
+			Name:     model.Name,
+			Provider: model.Provider,
+			Endpoint: model.APIEndpoint,
+			Default:  model.Name == active.Name,
+		}
+		key, source := keyring.Lookup(keys, model.Provider, model.APIKey)
+		if source != keyring.SourceNone {
+			status.Key = fmt.Sprintf("%s (%s)", keyring.Mask(key), source)
+		}
+		if action == "test" {
+			model.APIKey = key
+			result := tester.Test(model)
+			ok := result.Success
+			status.OK = &ok
+			status.LatencyMS = result.Latency.Milliseconds()
+			if result.Error != nil {
+				status.Error = keyring.MaskIn(result.Error.Error(), key)
+			}
+			if !ok {
+				failed++
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	if *asJSON {
+		if err := printJSON(statuses); err != nil {
+			return err
+		}
+	} else {
+		for _, status := range statuses {
+			printModelStatus(status)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d models failed the connection test", failed, len(statuses))
+	}
+	return nil
+}
+
+// runAvailableModels lists the models a provider serves from its /models
+// endpoint, reached through the first configured model of that provider or
+// the built-in template
+func runAvailableModels(cfg *config.Config, provider string, asJSON bool) error {
+	if !providers.ListsModels(provider) {
+		return usageError(fmt.Sprintf("provider %q does not support model listing", provider))
+	}
+	var template types.AIModel
+	found := false
+	for _, model := range append(append([]types.AIModel(nil), cfg.Models...), providers.Models(provider)...) {
+		if model.Provider == provider {
+			template, found = model, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no %s model is configured", provider)
+	}
+	template = keyring.WithKey(keyring.System(), template)
+
+	models, err := providers.ListModels(&http.Client{Timeout: 10 * time.Second}, template)
+	if err != nil {
+		return errors.New(keyring.MaskIn(err.Error(), template.APIKey))
+	}
+
+	var statuses []modelStatus
+	for _, model := range models {
+		statuses = append(statuses, modelStatus{Name: model.Name, Provider: model.Provider, Endpoint: model.APIEndpoint})
+	}
+	if asJSON {
+		return printJSON(statuses)
+	}
+	for _, status := range statuses {
+		printModelStatus(status)
+	}
+	return nil
+}
+
+// findModel looks up a configured model by name
+func findModel(models []types.AIModel, name string) (types.AIModel, bool) {
+	for _, model := range models {
+		if model.Name == name {
+			return model, true
+		}
+	}
+	return types.AIModel{}, false
+}
+
+// printModelStatus prints one tab-separated line per model
+func printModelStatus(status modelStatus) {
+	fields := []string{status.Name, status.Provider}
+	if status.OK == nil {
+		marker := ""
+		if status.Default {
+			marker = "default"
+		}
+		fields = append(fields, status.Endpoint, status.Key, marker)
+	} else if *status.OK {
+		fields = append(fields, "ok", fmt.Sprintf("%dms", status.LatencyMS))
+	} else {
+		fields = append(fields, "failed", status.Error)
+	}
+	fmt.Println(strings.TrimRight(strings.Join(fields, "\t"), "\t"))
+}
+
+// printJSON writes a value to stdout as indented JSON
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeContext writes the formatted context to outputPath, or the Markdown to
+// <name>.structure<ext> and <name>.content<ext> beside it when split, returning the files written
+func writeContext(generated *context.ContextResult, formatter context.Formatter, outputPath string, split bool) ([]string, error) {
+	if !split {
+		formatted, err := formatter.Format(generated)
+		if err != nil {
+			return nil, err
+		}
+		return []string{outputPath}, os.WriteFile(outputPath, []byte(formatted), 0644)
+	}
+
+	structurePath, contentPath := splitPaths(outputPath)
+	structure, content := generated.SplitMarkdown()
+	if err := os.WriteFile(structurePath, []byte(structure), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(contentPath, []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	return []string{structurePath, contentPath}, nil
+}
+
+// splitPaths returns the structure and content files used for a split export
+func splitPaths(outputPath string) (structurePath, contentPath string) {
+	ext := filepath.Ext(outputPath)
+	base := strings.TrimSuffix(outputPath, ext)
+	return base + ".structure" + ext, base + ".content" + ext
+}
+
+// runTemplates imports context templates into a profile or exports its templates to a file
+func runTemplates(args []string, profile string) error {
+	if len(args) != 2 || (args[0] != "import" && args[0] != "export") {
+		return usageError("usage: templates import|export <file>")
+	}
+	action, path := args[0], args[1]
+
+	cfg, err := loadConfig(profile)
+	if err != nil {
+		return err
+	}
+
+	if action == "export" {
+		if err := config.ExportTemplates(path, cfg.ContextTemplates); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %d templates to %s\n", len(cfg.ContextTemplates), path)
+		return nil
+	}
+
+	templates, err := config.ImportTemplates(path)
+	if err != nil {
+		return err
+	}
+	added, replaced := cfg.MergeTemplates(templates)
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d templates (%d new, %d replaced)\n", len(templates), added, replaced)
+	return nil
+}
+
+// runWatch regenerates the context file whenever files under the root change
+func runWatch(args []string, profile string) error {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	output := flags.String("output", "context.md", "file the generated context is written to")
+	debounce := flags.Duration("debounce", 500*time.Millisecond, "quiet period before regenerating")
+	interval := flags.Duration("interval", time.Second, "how often to check for changes")
+	paths := flags.String("paths", "relative", "show paths relative to the watched directory or absolute")
+	flags.Parse(args)
+
+	pathStyle, err := context.ParsePathStyle(*paths)
+	if err != nil {
+		return err
+	}
+
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	outputPath, err := filepath.Abs(*output)
+	if err != nil {
+		return err
+	}
+
+	// Never feed the output file back into the scan or the watcher, which both
+	// see paths under the symlink-free root
+	scannedOutput := scannedPath(outputPath)
+	scanConfig, err := commandScanConfig(root, profile)
+	if err != nil {
+		return err
+	}
+	scanConfig.ExcludePatterns = append(scanConfig.ExcludePatterns, scannedOutput)
+	rules := context.NewProjectScanner(scanConfig)
+	exclude := func(path string, isDir bool) bool {
+		return path == scannedOutput || rules.IsExcluded(path, isDir)
+	}
+
+	regenerate := func() error {
+		result, err := context.NewProjectScanner(scanConfig).Scan(stdcontext.Background())
+		if err != nil {
+			return err
+		}
+		generator := context.NewContextGenerator()
+		generator.SetPathStyle(pathStyle)
+		generated, err := generator.GenerateContext(stdcontext.Background(), result, filepath.Base(root))
+		if err != nil {
+			return err
+		}
+		if _, err := writeContext(generated, context.MarkdownFormatter{}, outputPath, false); err != nil {
+			return err
+		}
+		fmt.Printf("[%s] Context written to %s (%d files)\n", time.Now().Format("15:04:05"), outputPath, result.TotalFiles)
+		return nil
+	}
+
+	if err := regenerate(); err != nil {
+		return err
+	}
+
+	watcher := watch.NewPollingWatcher(scanConfig.RootPath, *interval, exclude)
+	defer watcher.Close()
+
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop)\n", root)
+	loop := &watch.Loop{
+		Watcher:    watcher,
+		Debounce:   *debounce,
+		Regenerate: regenerate,
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "Regeneration failed: %v\n", err)
+		},
+	}
+	loop.Run(stop)
+	return nil
+}
+
+// runServe exposes scanning and context generation over HTTP until interrupted
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:7878", "address to listen on")
+	token := flags.String("token", os.Getenv("AI_CONTEXT_TOKEN"), "require this bearer token (default $AI_CONTEXT_TOKEN)")
+	root := flags.String("root", "", "only allow scanning inside this directory")
+	noRedact := flags.Bool("no-redact", false, "serve detected secrets unmasked")
+	redactSkip := flags.String("redact-skip", "", "comma-separated secret patterns to leave unmasked: "+strings.Join(context.RedactionPatterns(), ", "))
+	flags.Parse(args)
+
+	if *root != "" {
+		if _, err := commandRoot(*root, flags); err != nil {
+			return err
+		}
+	}
+	if *token == "" && !server.Loopback(*addr) {
+		return usageError(fmt.Sprintf("--token is required to listen on %s; without one only loopback addresses are allowed", *addr))
+	}
+	handler, err := server.New(server.Options{
+		Token:     *token,
+		Root:      *root,
+		Redaction: server.Redaction{Disabled: *noRedact, Skip: strings.Split(*redactSkip, ",")},
+	})
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		httpServer.Close()
+	}()
+
+	if *token == "" {
+		fmt.Fprintln(os.Stderr, "Warning: no --token set, any local program can read files through the server")
+	}
+	fmt.Printf("Serving on http://%s (POST /scan, POST /context; Ctrl+C to stop)\n", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// runMCP serves the Model Context Protocol on stdin and stdout for MCP clients
+func runMCP(args []string, profile string) error {
+	flags := flag.NewFlagSet("mcp", flag.ExitOnError)
+	root := flags.String("root", "", "only allow scanning inside this directory")
+	flags.Parse(args)
+
+	if *root != "" {
+		if _, err := commandRoot(*root, flags); err != nil {
+			return err
+		}
+	}
+	cfg, err := loadConfig(profile)
+	if err != nil {
+		return err
+	}
+	mcpServer, err := mcp.New(mcp.Options{Name: "ai-context-cli", Version: ui.Version, Root: *root, LibraryDir: cfg.ConfigDir})
+	if err != nil {
+		return err
+	}
+	// stdout carries the protocol, so nothing else may be printed to it
+	return mcpServer.Serve(os.Stdin, os.Stdout)
+}
+
+func printHelp() {
+	fmt.Printf("ai-context-cli %s - AI context engineering in your terminal\n\n", ui.Version)
+	fmt.Println("Usage:")
+	fmt.Println("  ai-context-cli [flags] [command]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  help       Show this help")
+	fmt.Println("  version    Show version")
+	fmt.Println("  scan       Print a summary of the files a directory would contribute")
+	fmt.Println("             [--path dir] [--json] [--include-generated] [dir]")
+	fmt.Println("  generate   Write the context for a directory to a file")
+	fmt.Println("             [--path dir] [--output file] [--split] [--paths relative|absolute]")
+	fmt.Println("             [--format markdown|text|json|xml] [--no-redact] [--redact-skip patterns] [dir]")
+	fmt.Println("             [--since ref]  (only files changed since a git ref, with diffs)")
+	fmt.Println("             [--outline off|add|only]  (API outline of Go packages)")
+	fmt.Println("             [--detail full|outline|skeleton]  (signatures only, or bodies elided)")
+	fmt.Println("             [--compress license,comments,blank-lines,data|all]  (trade fidelity for tokens)")
+	fmt.Println("             [--include-generated]  (keep lockfiles, generated code and duplicates)")
+	fmt.Println("             [--focus keywords]  (pick files mentioning them first)")
+	fmt.Println("             [--large-files structural|head|tail|both|skip] [--large-file-lines 200]")
+	fmt.Println("  models     List configured models or test their connections")
+	fmt.Println("             list|test [--json] [name...]")
+	fmt.Println("             available [--json] <provider>  (openai, openrouter, lmstudio)")
+	fmt.Println("  templates  Share context templates as JSON")
+	fmt.Println("             import|export <file>")
+	fmt.Println("  watch      Regenerate a context file whenever sources change")
+	fmt.Println("             [--output file] [--debounce 500ms] [--interval 1s]")
+	fmt.Println("             [--paths relative|absolute] [dir]")
+	fmt.Println("  serve      Serve POST /scan and POST /context as JSON for editors and tools")
+	fmt.Println("             [--addr 127.0.0.1:7878] [--token secret] [--root dir]")
+	fmt.Println("             [--no-redact] [--redact-skip patterns]  (applies to every request)")
+	fmt.Println("  mcp        Run as an MCP server on stdio (get_project_context tool, saved contexts)")
+	fmt.Println("             [--root dir]")
+	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --profile <name>   Configuration profile to use")
+	fmt.Println("  --log-file <path>  Write the event log to a file")
+	fmt.Println("  --anonymize        Redact file paths in the event log")
+	fmt.Println("  --list [dir]       Print the files that would be included and exit")
+	fmt.Println()
+	fmt.Println("Exit status is 0 on success, 1 when a command fails and 2 for invalid arguments.")
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/keyring"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// apiKeyStatus is where a provider's key currently comes from
+type apiKeyStatus struct {
+	key    string
+	source keyring.Source
+}
+
+// apiKeyScreen lists the providers' keys and edits one at a time. Statuses
+// are looked up once per change since keychain reads run a command.
+type apiKeyScreen struct {
+	cursor   int
+	editing  bool
+	input    string
+	statuses map[string]apiKeyStatus
+}
+
+// configuredKey returns the plaintext key a provider's models carry in the config file
+func (m Model) configuredKey(provider string) string {
+	if m.appConfig == nil {
+		return ""
+	}
+	for _, model := range m.appConfig.Models {
+		if model.Provider == provider && model.APIKey != "" {
+			return model.APIKey
+		}
+	}
+	return ""
+}
+
+// apiKeyStatuses looks up every provider's key
+func (m Model) apiKeyStatuses() map[string]apiKeyStatus {
+	statuses := make(map[string]apiKeyStatus)
+	for _, provider := range keyring.Providers() {
+		key, source := keyring.Lookup(m.keyring, provider, m.configuredKey(provider))
+		statuses[provider] = apiKeyStatus{key: key, source: source}
+	}
+	return statuses
+}
+
+// openAPIKeys shows the API key settings screen
+func (m Model) openAPIKeys() (Model, tea.Cmd) {
+	m.apiKeys = &apiKeyScreen{statuses: m.apiKeyStatuses()}
+	m.eventLog.Record(events.EventNavigation, "Opened API keys")
+	return m, nil
+}
+
+// handleAPIKeyKeys moves between providers, edits the selected key and closes on esc
+func (m Model) handleAPIKeyKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	screen := *m.apiKeys
+	providers := keyring.Providers()
+	provider := providers[screen.cursor]
+
+	if screen.editing {
+		switch msg.String() {
+		case "esc":
+			screen.editing = false
+			screen.input = ""
+		case "enter":
+			key := strings.TrimSpace(screen.input)
+			screen.editing = false
+			screen.input = ""
+			m.apiKeys = &screen
+			if key == "" {
+				return m, nil
+			}
+			return m.storeAPIKey(provider, key)
+		case "backspace":
+			if len(screen.input) > 0 {
+				runes := []rune(screen.input)
+				screen.input = string(runes[:len(runes)-1])
+			}
+		case "ctrl+c":
+			return m, tea.Quit
+		default:
+			if msg.Type == tea.KeyRunes {
+				screen.input += string(msg.Runes)
+			}
+		}
+		m.apiKeys = &screen
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if screen.cursor > 0 {
+			screen.cursor--
+		}
+	case "down", "j":
+		if screen.cursor < len(providers)-1 {
+			screen.cursor++
+		}
+	case "enter", "e":
+		screen.editing = true
+		screen.input = ""
+	case "d", "delete":
+		return m.deleteAPIKey(provider)
+	case "esc":
+		m.apiKeys = nil
+		return m, nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	}
+	m.apiKeys = &screen
+	return m, nil
+}
+
+// storeAPIKey saves a provider's key to the keychain and drops any plaintext
+// copy from the config file. Without a keychain the user is pointed at the
+// environment variable instead of writing the key to disk.
+func (m Model) storeAPIKey(provider, key string) (Model, tea.Cmd) {
+	if m.keyring == nil || !m.keyring.Available() {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("No system keychain available. Set %s instead.", keyring.EnvVar(provider)), feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	if err := m.keyring.Set(provider, key); err != nil {
+		return m.reportError("Failed to store API key", err, "Provider: "+provider)
+	}
+	m.eventLog.Record(events.EventSettings, "Stored %s API key in the keychain", provider)
+
+	message := fmt.Sprintf("Saved %s API key to the keychain", provider)
+	toastType := feedback.ToastSuccess
+	if err := m.clearConfiguredKeys(provider); err != nil {
+		message = fmt.Sprintf("%s (plaintext copy not removed: %v)", message, err)
+		toastType = feedback.ToastWarning
+	}
+	m = m.refreshAPIKeys()
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// deleteAPIKey removes a provider's key from the keychain and the config file
+func (m Model) deleteAPIKey(provider string) (Model, tea.Cmd) {
+	if m.keyring != nil && m.keyring.Available() {
+		if err := m.keyring.Delete(provider); err != nil && err != keyring.ErrNotFound {
+			return m.reportError("Failed to delete API key", err, "Provider: "+provider)
+		}
+	}
+	if err := m.clearConfiguredKeys(provider); err != nil {
+		return m.reportError("Failed to delete API key", err, "Provider: "+provider)
+	}
+	m.eventLog.Record(events.EventSettings, "Deleted %s API key", provider)
+	m = m.refreshAPIKeys()
+
+	message := fmt.Sprintf("Deleted %s API key", provider)
+	if m.apiKeys.statuses[provider].source == keyring.SourceEnv {
+		message += fmt.Sprintf(" (%s is still set)", keyring.EnvVar(provider))
+	}
+	toastManager, toastCmd := m.toastManager.AddToast(message, feedback.ToastInfo)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// clearConfiguredKeys removes plaintext keys for a provider from the config file
+func (m Model) clearConfiguredKeys(provider string) error {
+	if m.appConfig == nil {
+		return nil
+	}
+	changed := false
+	for i, model := range m.appConfig.Models {
+		if model.Provider == provider && model.APIKey != "" {
+			m.appConfig.Models[i].APIKey = ""
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return m.appConfig.Save()
+}
+
+// refreshAPIKeys looks the keys up again after a change
+func (m Model) refreshAPIKeys() Model {
+	if m.apiKeys == nil {
+		return m
+	}
+	screen := *m.apiKeys
+	screen.statuses = m.apiKeyStatuses()
+	m.apiKeys = &screen
+	return m
+}
+
+// renderAPIKeys lists each provider's masked key and where it comes from
+func (m Model) renderAPIKeys() string {
+	var result strings.Builder
+	screen := m.apiKeys
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B"))
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3B82F6")).
+		Padding(0, 1).
+		Width(m.boxWidth(60))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	result.WriteString(titleStyle.Render("🔑 API Keys"))
+	result.WriteString("\n\n")
+
+	for i, provider := range keyring.Providers() {
+		status := screen.statuses[provider]
+		line := fmt.Sprintf("%-10s ", provider)
+		var note string
+		switch status.source {
+		case keyring.SourceKeyring:
+			line += keyring.Mask(status.key) + "  (keychain)"
+		case keyring.SourceEnv:
+			line += keyring.Mask(status.key) + fmt.Sprintf("  (env %s)", keyring.EnvVar(provider))
+		case keyring.SourceConfig:
+			line += keyring.Mask(status.key) + "  (config file)"
+			note = "stored in plaintext - press Enter to move it to the keychain"
+		default:
+			line += fmt.Sprintf("not set (keychain or %s)", keyring.EnvVar(provider))
+		}
+
+		if i == screen.cursor {
+			result.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			result.WriteString(textStyle.Render("  " + line))
+		}
+		result.WriteString("\n")
+		if note != "" {
+			result.WriteString(warningStyle.Render("    ⚠ " + note))
+			result.WriteString("\n")
+		}
+	}
+	result.WriteString("\n")
+
+	if m.keyring == nil || !m.keyring.Available() {
+		result.WriteString(warningStyle.Render("No system keychain available; keys are read from environment variables"))
+		result.WriteString("\n\n")
+	}
+
+	if screen.editing {
+		provider := keyring.Providers()[screen.cursor]
+		result.WriteString(textStyle.Render(fmt.Sprintf("New %s API key:", provider)))
+		result.WriteString("\n")
+		result.WriteString(inputStyle.Render(strings.Repeat("•", len([]rune(screen.input))) + "█"))
+		result.WriteString("\n")
+		result.WriteString(instructionStyle.Render("Enter: save to keychain • ESC: cancel"))
+	} else {
+		result.WriteString(instructionStyle.Render("↑↓/jk: navigate • Enter: set key • d: delete • ESC: back"))
+	}
+
+	return result.String()
+}
@@ -1,18 +1,28 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"ai-context-cli/internal/chat"
+	"ai-context-cli/internal/clipboard"
+	"ai-context-cli/internal/config"
 	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
 	"ai-context-cli/internal/feedback"
 	"ai-context-cli/internal/folder"
+	"ai-context-cli/internal/keyring"
 	"ai-context-cli/internal/navigation"
 	"ai-context-cli/internal/preview"
+	"ai-context-cli/internal/providers"
+	"ai-context-cli/internal/usage"
+	"ai-context-cli/pkg/types"
 )
 
 type MenuItem struct {
@@ -42,6 +52,7 @@ type Model struct {
 	
 	// Context system
 	scanner      *context.ProjectScanner
+	operations   *cancelScope // cancels the running scan or generation
 	scanResult   *context.ScanResult
 	contextResult *context.ContextResult
 	showingResult bool
@@ -54,8 +65,118 @@ type Model struct {
 	// Context preview system
 	contextPreview *preview.ContextPreviewModel
 	showingPreview bool
+	
+	// Terminal size from the last tea.WindowSizeMsg; zero until one arrives
+	width  int
+	height int
+	
+	// Configuration profile
+	appConfig *config.Config
+	
+	// Rescan state for result view actions
+	scanRoot           string
+	scanPaths          []string // files and folders checked in the browser; empty scans all of scanRoot
+	excludedExtensions []string
+	showingExtensions  bool
+	extensionCursor    int
+	scanCache          *context.ScanCache
+	
+	// Excluded files inspector
+	showingExcluded    bool
+	excludedCursor     int
+	excludedBySize     bool
+	excludedFilter     string
+	forceIncluded      []string
+	structureOnly      bool
+	
+	// Paths the last scan skipped because they could not be read
+	showingScanErrors  bool
+	scanErrorCursor    int
+	
+	// Result view section list
+	resultCursor    int
+	sectionExpanded bool
+	
+	// Clipboard used by copy actions
+	clipboard clipboard.Clipboard
+	lastCopy  clipboard.Result
+	
+	// Cumulative tokens and spend for context copied this session
+	usage *usage.Tracker
+	
+	// Event log for debugging state transitions
+	eventLog        *events.EventLog
+	showingEventLog bool
+	
+	// Last reported error, kept for the detail panel
+	lastError          *ErrorDetail
+	showingErrorDetail bool
+	
+	// Set when generation was blocked by the hard size cap
+	limitExceeded *context.LimitExceededError
+	
+	// Scan of a home, root or volume directory waiting for confirmation
+	pendingScan   *pendingScan
+	confirmedRoot string
+	
+	// Pre-scan file type picker and the extensions it selected
+	extensionPicker   *extensionPicker
+	includeExtensions []string
+	
+	// Reset-to-defaults confirmation
+	confirmingReset bool
+	
+	// API key settings screen and the keychain keys are stored in
+	apiKeys *apiKeyScreen
+	keyring keyring.Keyring
+	
+	// Settings form for the active model
+	modelEditor *modelEditor
+	
+	// Saved context library screen
+	library *libraryScreen
+	
+	// Picks two contexts and shows what changed between them
+	compare *compareScreen
+	
+	// Base ref picker for context from a git diff
+	gitDiffPicker *gitDiffPicker
+	
+	// Prompt template screen and the template applied to exports and chat
+	promptScreen *promptScreen
+	activePrompt *activePrompt
+	
+	// Export file name prompt, and an export held back by an unwritable output directory
+	exportPrompt     *exportPrompt
+	pendingExport    *context.ContextResult
+	pickingOutputDir bool
+	
+	// Presentation mode hiding banner, breadcrumbs and instructions
+	minimalChrome bool
+	
+	// Dashboard home screen and the last known model connection statuses,
+	// kept current by the background refresher when the profile enables it
+	showingDashboard bool
+	modelStatuses    map[string]providers.TestResult
+	statusRefresher  *providers.StatusRefresher
+	testModel        func(types.AIModel) providers.TestResult // test override
+	
+	// Open chat screen, and a client factory tests can replace
+	chat          *chatState
+	newChatClient func(types.AIModel) (chat.Client, error)
 }
 
+const (
+	// defaultViewWidth is used until the terminal reports its size
+	defaultViewWidth = 100
+	// minBoxWidth keeps bordered boxes readable on very narrow terminals
+	minBoxWidth = 30
+	// menuButtonLines is the usual height of a menu button
+	menuButtonLines = 4
+	// menuChrome is roughly how many lines the menu uses for the banner, toolbar and hints
+	menuChrome = 12
+)
+
 // LoadingState represents different loading states
 type LoadingState int
 
@@ -86,9 +207,15 @@ type OperationCompleteMsg struct {
 	Message string
 }
 
+// ResetMsg returns the app to the main menu after an operation finishes
+type ResetMsg struct{}
+
 // ScanProgressMsg is sent during real project scanning
 type ScanProgressMsg struct {
 	Progress context.ScanProgress
+	
+	// Channel the update came from, re-subscribed after each message
+	updates <-chan context.ScanProgress
 }
 
 // ScanCompleteMsg is sent when scanning completes
@@ -99,8 +226,9 @@ type ScanCompleteMsg struct {
 
 // ContextGeneratedMsg is sent when context generation completes
 type ContextGeneratedMsg struct {
-	Result *context.ContextResult
-	Error  error
+	Result   *context.ContextResult
+	Error    error
+	DiffBase string // set when the context holds git changes rather than a scan
 }
 
 // FolderSelectedMsg is sent when a folder is selected
@@ -147,6 +275,42 @@ func NewModel() Model {
 				Icon:        "🤖",
 				DetailHelp:  "Select from available AI models (GPT-4, Claude, etc.), configure API keys, and adjust model-specific settings like temperature and max tokens.",
 			},
+			{
+				Title:       "💬 Chat with Model",
+				Description: "Ask the selected model about your project",
+				Icon:        "💬",
+				DetailHelp:  "Opens a conversation with the selected AI model. The current context is attached as the system message so the model can answer questions about your code; Ctrl+T attaches or detaches it.",
+			},
+			{
+				Title:       "⏪ Resume Last Session",
+				Description: "Reopen the last generated context without rescanning",
+				Icon:        "⏪",
+				DetailHelp:  "Every generated context is saved with its scan, the selected folders and the model. Resuming restores them exactly as they were, so you can copy, export or chat again after restarting without waiting for a new scan.",
+			},
+			{
+				Title:       "📚 Context Library",
+				Description: "Browse, reload, rename and delete saved contexts",
+				Icon:        "📚",
+				DetailHelp:  "Every exported context is also kept in the library with its project, date and token count. Preview one, load it back into the preview screen to copy or export it again, rename it or delete it.",
+			},
+			{
+				Title:       "🔀 Context from Git Diff",
+				Description: "Only the files changed since a branch or commit",
+				Icon:        "🔀",
+				DetailHelp:  "Pick a base ref - HEAD, a branch or a recent commit - and generate context from the files changed since then: the list of changes, their diffs and the current contents. Handy for code review prompts.",
+			},
+			{
+				Title:       "📝 Prompt Templates",
+				Description: "Write prompts around the context with variables",
+				Icon:        "📝",
+				DetailHelp:  "Templates use Go template syntax: {{.ProjectName}}, {{.Context}}, {{.Model}}, {{.Files}}, {{.Tokens}} and {{.Date}} are filled in, and any other {{.Name}} is asked for. Preview a template against the current context, then apply it so exports and chat send the rendered prompt.",
+			},
+			{
+				Title:       "🔍 Compare Contexts",
+				Description: "See what changed between two contexts",
+				Icon:        "🔍",
+				DetailHelp:  "Pick two saved contexts, or the current context and the last saved one, and see a section by section diff: the files each section gained or lost, files whose contents changed and how the token counts moved. Useful to check exactly what your latest edits add to the prompt.",
+			},
 			{
 				Title:       "🚪 Exit",
 				Description: "Quit the application",
@@ -164,11 +328,17 @@ func NewModel() Model {
 		navStack:     navigation.NewNavigationStack().Push(navigation.MainMenuScreen),
 		navRenderer:  navigation.NewNavigationRenderer(),
 		currentScreen: "main_menu",
+		eventLog:     events.NewEventLog(200),
+		usage:        usage.NewTracker(),
+		scanCache:    context.NewScanCache(),
+		operations:   newCancelScope(),
+		clipboard:    clipboard.System(),
+		keyring:      keyring.System(),
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	return m.startStatusRefresher()
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -195,6 +365,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleScanComplete(msg)
 	case ContextGeneratedMsg:
 		return m.handleContextGenerated(msg)
+	case ExtensionCountsMsg:
+		return m.handleExtensionCounts(msg)
+	case ModelStatusMsg:
+		return m.handleModelStatus(msg)
+	case ChatStreamMsg:
+		return m.handleChatStream(msg)
+	case SessionSavedMsg:
+		return m.handleSessionSaved(msg)
 	case FolderSelectedMsg:
 		return m.handleFolderSelected(msg)
 	case FolderBrowserMsg:
@@ -235,7 +413,129 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		// Reset to menu after showing result
 		return m, tea.Batch(toastCmd, m.resetToMenuAfterDelay())
+	case ResetMsg:
+		return m.resetToMenu(), nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m.applyWindowSize(), nil
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case tea.KeyMsg:
+		// Event log toggle works from every screen
+		if msg.String() == "ctrl+l" {
+			m.showingEventLog = !m.showingEventLog
+			return m, nil
+		}
+		if m.showingEventLog {
+			if msg.String() == "esc" {
+				m.showingEventLog = false
+			}
+			return m, nil
+		}
+		
+		// Error details open while an error toast is showing
+		if m.showingErrorDetail {
+			if msg.String() == "esc" || msg.String() == "!" {
+				m.showingErrorDetail = false
+			}
+			return m, nil
+		}
+		if msg.String() == "!" && m.lastError != nil && m.toastManager.HasActive(feedback.ToastError) {
+			m.showingErrorDetail = true
+			return m, nil
+		}
+		
+		// ESC or Ctrl+C stops a running scan or generation
+		if m.busy() && (msg.String() == "esc" || msg.String() == "ctrl+c") {
+			return m.cancelOperation()
+		}
+		
+		// A held scan needs an explicit answer
+		if m.pendingScan != nil {
+			return m.handlePendingScanKeys(msg)
+		}
+		
+		// Reset confirmation needs an explicit choice
+		if m.confirmingReset {
+			return m.handleResetKeys(msg)
+		}
+		
+		// The API key screen takes all keys while open
+		if m.apiKeys != nil {
+			return m.handleAPIKeyKeys(msg)
+		}
+		
+		// The model settings form takes all keys while open
+		if m.modelEditor != nil {
+			return m.handleModelEditorKeys(msg)
+		}
+		
+		// The context library takes all keys while open
+		if m.library != nil {
+			return m.handleLibraryKeys(msg)
+		}
+		
+		// The context comparison takes all keys while open
+		if m.compare != nil {
+			return m.handleCompareKeys(msg)
+		}
+		
+		// The git diff base picker takes all keys while open
+		if m.gitDiffPicker != nil {
+			return m.handleGitDiffPickerKeys(msg)
+		}
+		
+		// The prompt template screen takes all keys while open
+		if m.promptScreen != nil {
+			return m.handlePromptKeys(msg)
+		}
+		
+		// The file type picker takes all keys while open
+		if m.extensionPicker != nil {
+			return m.handleExtensionPickerKeys(msg)
+		}
+		
+		// Picking a replacement output directory
+		if m.pickingOutputDir && m.folderBrowser != nil {
+			if msg.String() == "esc" && !m.folderBrowser.Filtering() {
+				m.pickingOutputDir = false
+				m = m.closeFolderBrowser()
+				m.pendingExport = nil
+				return m, nil
+			}
+			browser, cmd := m.folderBrowser.Update(msg)
+			m.folderBrowser = browser
+			return m, cmd
+		}
+		
+		// A failed export offers another directory
+		if m.pendingExport != nil {
+			return m.handleUnwritableDirKeys(msg)
+		}
+		
+		// The export prompt takes all keys while naming the file
+		if m.exportPrompt != nil {
+			return m.handleExportPromptKeys(msg)
+		}
+		
+		// The chat screen takes all keys while open
+		if m.chat != nil {
+			return m.handleChatKeys(msg)
+		}
+		
+		// The size cap screen stays until dismissed
+		if m.limitExceeded != nil {
+			switch msg.String() {
+			case "esc", "enter":
+				m.limitExceeded = nil
+				return m.resetToMenu(), nil
+			case "ctrl+c", "q":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		
 		// Handle context preview first - it should get all key events when active
 		if m.showingPreview && m.contextPreview != nil {
 			preview, cmd := m.contextPreview.Update(msg)
@@ -264,7 +564,62 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		
+		// Handle extension exclusion list when open on the result view
+		if m.showingExtensions {
+			return m.handleExtensionKeys(msg)
+		}
+		
+		// Handle excluded files inspector when open on the result view
+		if m.showingExcluded {
+			return m.handleExcludedKeys(msg)
+		}
+		
+		// Handle skipped paths list when open on the result view
+		if m.showingScanErrors {
+			return m.handleScanErrorKeys(msg)
+		}
+		
+		// Navigate and expand generated sections on the result view
+		if m.showingResult && m.contextResult != nil && !m.showingHelp {
+			if updated, handled := m.handleResultSectionKeys(msg); handled {
+				return updated, nil
+			}
+		}
+		
 		switch msg.String() {
+		case "x":
+			// Open extension exclusion list from the result view
+			if m.showingResult && m.contextResult != nil {
+				m.showingExtensions = true
+				m.extensionCursor = 0
+			}
+		case "c":
+			// Flip between structure only and full content from the result view
+			if m.showingResult && m.scanResult != nil {
+				return m.toggleIncludeContent()
+			}
+		case "y":
+			// Copy the generated context from the result view
+			if m.showingResult && m.contextResult != nil {
+				return m.copyContext()
+			}
+		case "Y":
+			// Copy the context as one fenced block for pasting into chat UIs
+			if m.showingResult && m.contextResult != nil {
+				return m.copyFencedContext()
+			}
+		case "e":
+			// Open excluded files inspector from the result view
+			if m.showingResult && m.scanResult != nil {
+				m.showingExcluded = true
+				m.excludedCursor = 0
+			}
+		case "w":
+			// List the paths the scan skipped because of errors
+			if m.showingResult && m.scanErrorCount() > 0 {
+				m.showingScanErrors = true
+				m.scanErrorCursor = 0
+			}
 		case "ctrl+c", "q":
 			if m.showingHelp {
 				// Close help modal
@@ -289,8 +644,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 			// Handle folder browser close
 			if m.showingBrowser {
-				m.showingBrowser = false
-				m.folderBrowser = nil
+				m = m.closeFolderBrowser()
 				return m, nil
 			}
 			
@@ -301,6 +655,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.navStack = navStack
 					if current, ok := m.navStack.Current(); ok {
 						m.currentScreen = current.ID
+						m.eventLog.Record(events.EventNavigation, "Returned to %s", current.Title)
 						// Show toast for navigation
 						toastManager, toastCmd := m.toastManager.AddToast("Returned to "+current.Title, feedback.ToastInfo)
 						m.toastManager = toastManager
@@ -336,14 +691,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.loadingState == StateMenu {
 				return m.handleMenuAction(m.cursor)
 			}
-		case "r":
-			// Reset to menu (used internally after operations)
-			m.loadingState = StateMenu
-			m.spinner = m.spinner.Stop()
-			m.progress = feedback.NewProgress(0, "")
-			// Reset navigation to main menu
-			m.navStack = navigation.NewNavigationStack().Push(navigation.MainMenuScreen)
-			m.currentScreen = "main_menu"
+		case "z":
+			// Toggle minimal chrome for screenshots and demos
+			m.minimalChrome = !m.minimalChrome
+			if m.folderBrowser != nil {
+				m.folderBrowser.SetMinimalChrome(m.minimalChrome)
+			}
+			if m.contextPreview != nil {
+				m.contextPreview.SetMinimalChrome(m.minimalChrome)
+			}
+			return m, nil
+		case "H":
+			// Switch the home screen between the menu and the dashboard
+			if m.showingHelp || m.loadingState != StateMenu || m.showingResult {
+				return m, nil
+			}
+			return m.toggleDashboard()
+		case "M":
+			// Quick-switch between recently used models (ctrl+m arrives as enter)
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.switchToNextRecentModel()
+		case "P":
+			// Switch to the next configuration profile
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.switchToNextProfile()
+		case "D":
+			// Reset models or scan settings to defaults
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.openResetConfirm()
+		case "K":
+			// Enter or update provider API keys
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.openAPIKeys()
+		case "E":
+			// Edit the active model's settings
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.openModelEditor()
+		case "T":
+			// Test the active model's connection
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.testActiveModel()
+		case "t":
+			// Pick file types before scanning the current directory
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			return m.openExtensionPicker()
+		case "s", "f", "m", "p":
+			// p pauses or resumes a running scan
+			if msg.String() == "p" && m.loadingState == StateScanning {
+				return m.togglePause(), nil
+			}
+			
+			// Quick actions share the menu handlers
+			if m.showingHelp || m.loadingState != StateMenu {
+				return m, nil
+			}
+			if action, ok := findQuickAction(msg.String()); ok {
+				m.cursor = action.MenuIndex
+				return m.handleMenuAction(action.MenuIndex)
+			}
 		}
 	}
 	
@@ -352,49 +771,65 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleScanProgress handles real scan progress updates
 func (m Model) handleScanProgress(msg ScanProgressMsg) (Model, tea.Cmd) {
+	// Keep draining updates; late ones after completion only need consuming
+	next := listenForScanProgress(msg.updates)
+	if m.loadingState != StateScanning {
+		return m, next
+	}
+	
 	progress := msg.Progress
 	
-	// Update spinner message
-	m.spinner = m.spinner.SetMessage(progress.CurrentPhase)
+	// Update spinner message with the file being scanned
+	message := progress.CurrentPhase
+	if progress.CurrentFile != "" {
+		message = fmt.Sprintf("%s %s", message, filepath.Base(progress.CurrentFile))
+	}
+	m.spinner = m.spinner.SetMessage(message)
 	
-	// Update progress bar
+	// Update progress bar with live counts
 	if progress.TotalEstimated > 0 {
-		m.progress = m.progress.SetProgress(progress.ProcessedFiles).
-			SetMessage(fmt.Sprintf("%s (%d/%d files)", 
-				progress.CurrentPhase, progress.ProcessedFiles, progress.TotalEstimated))
-		
-		// Update total if we have better estimate
-		if progress.TotalEstimated > 0 {
-			m.progress = feedback.NewProgress(progress.TotalEstimated, "Scanning files")
-			m.progress = m.progress.SetProgress(progress.ProcessedFiles)
-		}
+		m.progress = feedback.NewProgress(progress.TotalEstimated,
+			fmt.Sprintf("Scanning files (%d/%d)", progress.ProcessedFiles, progress.TotalEstimated)).
+			SetProgress(progress.ProcessedFiles)
 	}
 	
-	return m, nil
+	return m, next
 }
 
 // handleScanComplete handles scan completion
 func (m Model) handleScanComplete(msg ScanCompleteMsg) (Model, tea.Cmd) {
+	// A cancelled scan has already returned to the menu
+	if isCancelled(msg.Error) {
+		return m, nil
+	}
+	m.scanner = nil
+	
 	if msg.Error != nil {
 		m.loadingState = StateComplete
 		m.spinner = m.spinner.Stop()
 		
-		toastManager, toastCmd := m.toastManager.AddToast(
-			fmt.Sprintf("Scan failed: %v", msg.Error), feedback.ToastError)
-		m.toastManager = toastManager
-		
+		m, toastCmd := m.reportError("Scan failed", msg.Error, "Scan root: "+m.scanRoot)
 		return m, tea.Batch(toastCmd, m.resetToMenuAfterDelay())
 	}
 	
 	// Store scan result and start context generation
 	m.scanResult = msg.Result
+	m.eventLog.Record(events.EventScanComplete, "Scanned %d files (%d excluded) in %v",
+		msg.Result.TotalFiles, msg.Result.ExcludedFiles, msg.Result.ScanDuration.Round(time.Millisecond))
+	for _, scanErr := range msg.Result.Errors {
+		m.eventLog.Record(events.EventError, "Skipped %v", scanErr)
+	}
 	m.loadingState = StateProcessing
 	m.spinner = m.spinner.SetMessage("Generating comprehensive context...").Start()
 	m.progress = feedback.NewProgress(0, "Processing scan results")
 	
-	toastManager, toastCmd := m.toastManager.AddToast(
-		fmt.Sprintf("Scanned %d files in %v", msg.Result.TotalFiles, msg.Result.ScanDuration.Round(time.Millisecond)), 
-		feedback.ToastSuccess)
+	message := fmt.Sprintf("Scanned %d files in %v", msg.Result.TotalFiles, msg.Result.ScanDuration.Round(time.Millisecond))
+	toastType := feedback.ToastSuccess
+	if skipped := len(msg.Result.Errors); skipped > 0 {
+		message += " • " + skippedSummary(skipped)
+		toastType = feedback.ToastWarning
+	}
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
 	m.toastManager = toastManager
 	
 	return m, tea.Batch(toastCmd, m.generateContext())
@@ -402,19 +837,39 @@ func (m Model) handleScanComplete(msg ScanCompleteMsg) (Model, tea.Cmd) {
 
 // handleContextGenerated handles context generation completion
 func (m Model) handleContextGenerated(msg ContextGeneratedMsg) (Model, tea.Cmd) {
+	// A cancelled generation has already returned to the menu
+	if isCancelled(msg.Error) {
+		return m, nil
+	}
 	if msg.Error != nil {
 		m.loadingState = StateComplete
 		m.spinner = m.spinner.Stop()
 		
-		toastManager, toastCmd := m.toastManager.AddToast(
-			fmt.Sprintf("Context generation failed: %v", msg.Error), feedback.ToastError)
-		m.toastManager = toastManager
+		// Oversized contexts get an explanation instead of a passing toast
+		var limitErr *context.LimitExceededError
+		if errors.As(msg.Error, &limitErr) {
+			m.limitExceeded = limitErr
+			m.eventLog.Record(events.EventError, "Generation blocked: %v", limitErr)
+			return m, nil
+		}
 		
+		m, toastCmd := m.reportError("Context generation failed", msg.Error, "Scan root: "+m.scanRoot)
 		return m, tea.Batch(toastCmd, m.resetToMenuAfterDelay())
 	}
 	
 	// Store context result and show success
 	m.contextResult = msg.Result
+	if msg.DiffBase != "" {
+		// The previous scan does not describe a change set
+		m.scanResult = nil
+	}
+	m.resultCursor = 0
+	m.sectionExpanded = false
+	m.eventLog.Record(events.EventGeneration, "Generated %d sections (~%d tokens)",
+		len(msg.Result.Sections), msg.Result.TokenEstimate)
+	if len(msg.Result.Redactions) > 0 {
+		m.eventLog.Record(events.EventGeneration, "Redacted %d possible secrets", len(msg.Result.Redactions))
+	}
 	m.loadingState = StateComplete
 	m.spinner = m.spinner.Stop()
 	m.showingResult = true
@@ -425,14 +880,17 @@ func (m Model) handleContextGenerated(msg ContextGeneratedMsg) (Model, tea.Cmd)
 		feedback.ToastSuccess)
 	m.toastManager = toastManager
 	
-	return m, toastCmd
+	return m, tea.Batch(toastCmd, m.saveSession())
 }
 
 // handleFolderSelected handles folder selection from browser
 func (m Model) handleFolderSelected(msg FolderSelectedMsg) (Model, tea.Cmd) {
 	m.selectedFolder = msg.Folder
-	m.showingBrowser = false
-	m.folderBrowser = nil
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	
+	m = m.closeFolderBrowser()
 	
 	if msg.Folder == nil {
 		toastManager, toastCmd := m.toastManager.AddToast("No folder selected", feedback.ToastWarning)
@@ -440,7 +898,20 @@ func (m Model) handleFolderSelected(msg FolderSelectedMsg) (Model, tea.Cmd) {
 		return m, toastCmd
 	}
 	
-	// Start folder scanning
+	// Confirm before scanning a home, root or volume directory
+	m, held := m.guardSensitiveScan(msg.Folder.Path, func(m Model) (Model, tea.Cmd) {
+		return m.handleFolderSelected(msg)
+	})
+	if held {
+		return m, nil
+	}
+	
+	// Start folder scanning with every file type
+	m.rememberFolder(msg.Folder.Path)
+	m.includeExtensions = nil
+	m.scanRoot = msg.Folder.Path
+	m.scanPaths = nil
+	m.eventLog.Record(events.EventScanStart, "Folder scan started: %s", msg.Folder.Path)
 	m.loadingState = StateScanning
 	m.spinner = m.spinner.SetMessage(fmt.Sprintf("Scanning folder '%s'...", msg.Folder.Name)).Start()
 	m.progress = feedback.NewProgress(0, "Scanning folder files")
@@ -449,20 +920,75 @@ func (m Model) handleFolderSelected(msg FolderSelectedMsg) (Model, tea.Cmd) {
 		fmt.Sprintf("Selected folder: %s", msg.Folder.Name), feedback.ToastInfo)
 	m.toastManager = toastManager
 	
+	m.scanner = m.newScanner(msg.Folder.Path)
+	
 	return m, tea.Batch(
 		toastCmd,
 		m.spinner.InitSpinner(),
-		m.startFolderScan(msg.Folder.Path),
+		m.runScan(m.scanner),
 	)
 }
 
+// handlePathsSelected scans the files and folders checked in the browser as one context
+func (m Model) handlePathsSelected(root string, paths []string) (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	if m.pickingOutputDir {
+		// Only a single directory can be an output directory
+		toastManager, toastCmd := m.toastManager.AddToast("Choose a single folder with C", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	m = m.closeFolderBrowser()
+	
+	m.rememberFolder(root)
+	m.includeExtensions = nil
+	m.scanRoot = root
+	m.scanPaths = paths
+	m.eventLog.Record(events.EventScanStart, "Scan started for %d selected paths in %s", len(paths), root)
+	m.loadingState = StateScanning
+	m.spinner = m.spinner.SetMessage(fmt.Sprintf("Scanning %d selected items...", len(paths))).Start()
+	m.progress = feedback.NewProgress(0, "Scanning selected files")
+	
+	m.scanner = m.newScanner(root)
+	return m, tea.Batch(m.spinner.InitSpinner(), m.runScan(m.scanner))
+}
+
 // handleFolderBrowser handles folder browser events
 func (m Model) handleFolderBrowser(msg FolderBrowserMsg) (Model, tea.Cmd) {
 	switch msg.Type {
 	case "folder_selected":
 		if node, ok := msg.Data.(*folder.FolderNode); ok {
+			if m.pickingOutputDir {
+				return m.setOutputDir(node.Path)
+			}
 			return m.handleFolderSelected(FolderSelectedMsg{Folder: node})
 		}
+	case "paths_selected":
+		if paths, ok := msg.Data.([]string); ok && m.folderBrowser != nil {
+			return m.handlePathsSelected(m.folderBrowser.GetTree().GetPath(), paths)
+		}
+	case "never_include":
+		if node, ok := msg.Data.(*folder.FolderNode); ok {
+			return m.toggleNeverInclude(node.Path)
+		}
+	case "explain_exclusion":
+		if node, ok := msg.Data.(*folder.FolderNode); ok {
+			return m.explainExclusion(node.Path)
+		}
+	case "toggle_bookmark":
+		if node, ok := msg.Data.(*folder.FolderNode); ok {
+			return m.toggleBookmark(node.Path)
+		}
+	case "stats_loaded", "filter_indexed":
+		// Background stats and filter walks belong to the browser that requested them
+		if m.folderBrowser != nil {
+			browser, cmd := m.folderBrowser.Update(folder.BrowserMsg{Type: msg.Type, Data: msg.Data})
+			m.folderBrowser = browser
+			return m, cmd
+		}
 	}
 	
 	return m, nil
@@ -472,11 +998,8 @@ func (m Model) handleFolderBrowser(msg FolderBrowserMsg) (Model, tea.Cmd) {
 func (m Model) handleContextPreview(msg ContextPreviewMsg) (Model, tea.Cmd) {
 	switch msg.Type {
 	case "save_requested":
-		if _, ok := msg.Data.(*context.ContextResult); ok {
-			// Handle context save
-			toastManager, toastCmd := m.toastManager.AddToast("Context saved successfully", feedback.ToastSuccess)
-			m.toastManager = toastManager
-			return m, toastCmd
+		if result, ok := msg.Data.(*context.ContextResult); ok {
+			return m.openExportPrompt(result)
 		}
 	case "refresh_requested":
 		// Handle context refresh
@@ -484,8 +1007,14 @@ func (m Model) handleContextPreview(msg ContextPreviewMsg) (Model, tea.Cmd) {
 		m.toastManager = toastManager
 		return m, toastCmd
 	case "template_applied":
-		// Handle template application
-		toastManager, toastCmd := m.toastManager.AddToast("Template applied successfully", feedback.ToastSuccess)
+		// Report the template and the size of the rewritten context
+		message := "Template applied successfully"
+		if template, ok := msg.Data.(preview.ContextTemplate); ok && m.contextResult != nil {
+			message = fmt.Sprintf("Applied %s template: %d sections, ~%d tokens",
+				template.Name, len(m.contextResult.Sections), m.contextResult.TokenEstimate)
+			m.eventLog.Record(events.EventGeneration, "Applied %s template", template.Name)
+		}
+		toastManager, toastCmd := m.toastManager.AddToast(message, feedback.ToastSuccess)
 		m.toastManager = toastManager
 		return m, toastCmd
 	case "exit_preview":
@@ -500,47 +1029,30 @@ func (m Model) handleContextPreview(msg ContextPreviewMsg) (Model, tea.Cmd) {
 
 // startProjectScan starts a real project scan
 func (m Model) startProjectScan() tea.Cmd {
-	return func() tea.Msg {
-		// Get current working directory
-		wd, err := os.Getwd()
-		if err != nil {
+	if m.scanner != nil {
+		return m.runScan(m.scanner)
+	}
+	
+	// Get current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		return func() tea.Msg {
 			return ScanCompleteMsg{Error: fmt.Errorf("failed to get working directory: %w", err)}
 		}
-		
-		// Create scanner with default config
-		config := context.DefaultScanConfig(wd)
-		scanner := context.NewProjectScanner(config)
-		
-		// Start progress monitoring in a goroutine
-		progressChan := scanner.GetProgressChannel()
-		go func() {
-			for progress := range progressChan {
-				// Send progress updates to the main loop
-				// Note: In a real implementation, you'd want to use a proper
-				// channel or callback mechanism here
-				_ = progress
-			}
-		}()
-		
-		// Perform the scan
-		result, err := scanner.Scan()
-		if err != nil {
-			return ScanCompleteMsg{Error: err}
-		}
-		
-		return ScanCompleteMsg{Result: result}
 	}
+	return m.runScan(m.newScanner(wd))
 }
 
 // generateContext generates context from scan results
 func (m Model) generateContext() tea.Cmd {
+	ctx := m.operations.context()
 	return func() tea.Msg {
 		if m.scanResult == nil {
 			return ContextGeneratedMsg{Error: fmt.Errorf("no scan result available")}
 		}
 		
 		// Create context generator
-		generator := context.NewContextGenerator()
+		generator := m.contextGenerator()
 		
 		// Get project name from current directory
 		wd, _ := os.Getwd()
@@ -553,7 +1065,7 @@ func (m Model) generateContext() tea.Cmd {
 		}
 		
 		// Generate context
-		result, err := generator.GenerateContext(m.scanResult, projectName)
+		result, err := generator.GenerateContext(ctx, m.scanResult, projectName)
 		if err != nil {
 			return ContextGeneratedMsg{Error: err}
 		}
@@ -562,17 +1074,90 @@ func (m Model) generateContext() tea.Cmd {
 	}
 }
 
+// contextGenerator returns a generator set up from the config
+func (m Model) contextGenerator() *context.ContextGenerator {
+	generator := context.NewContextGenerator()
+	generator.SetIncludeContent(!m.structureOnly)
+	if m.appConfig != nil {
+		generator.SetLanguageOverrides(m.appConfig.FenceLanguages)
+		generator.SetSizeLimit(context.SizeLimit{
+			MaxFiles:  m.appConfig.MaxContextFiles,
+			MaxBytes:  m.appConfig.MaxContextBytes,
+			MaxTokens: m.appConfig.MaxContextTokens,
+		})
+		if pathStyle, err := context.ParsePathStyle(m.appConfig.PathStyle); err == nil {
+			generator.SetPathStyle(pathStyle)
+		}
+		if outlineMode, err := context.ParseOutlineMode(m.appConfig.Outline); err == nil {
+			generator.SetOutlineMode(outlineMode)
+		}
+		if largeFileMode, err := context.ParseLargeFileMode(m.appConfig.LargeFiles); err == nil {
+			generator.SetLargeFilePolicy(context.LargeFilePolicy{Mode: largeFileMode, LineBudget: m.appConfig.LargeFileLines})
+		}
+		if grouping, err := context.ParseContentGrouping(m.appConfig.ContentGrouping); err == nil {
+			generator.SetContentGrouping(grouping)
+		}
+		generator.SetSeparateTests(m.appConfig.SeparateTests)
+		generator.SetMaxContentSections(m.appConfig.MaxSections)
+		generator.SetIncludeDirectoryReadmes(m.appConfig.DirectoryReadmes)
+		if m.appConfig.HeaderLevel > 0 {
+			generator.SetHeaderBaseLevel(m.appConfig.HeaderLevel)
+		}
+		generator.SetNormalizeNewlines(!m.appConfig.KeepLineEndings)
+		generator.SetReadBufferSize(m.appConfig.ReadBufferSize)
+		generator.SetFocusKeywords(m.appConfig.FocusKeywords)
+		generator.SetRedaction(!m.appConfig.NoRedaction, m.appConfig.RedactSkip)
+	}
+	return generator
+}
+
+// busy reports whether a scan or context generation is running
+func (m Model) busy() bool {
+	return m.loadingState == StateScanning || m.loadingState == StateProcessing
+}
+
+// operationInProgress leaves the running operation untouched and tells the user why
+func (m Model) operationInProgress() (Model, tea.Cmd) {
+	toastManager, toastCmd := m.toastManager.AddToast("Operation already in progress", feedback.ToastWarning)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
 // handleMenuAction processes menu item selection
 func (m Model) handleMenuAction(index int) (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	
 	switch index {
 	case 0: // Add Context (All)
+		// Confirm before scanning a home, root or volume directory
+		if wd, err := os.Getwd(); err == nil {
+			var held bool
+			m, held = m.guardSensitiveScan(wd, func(m Model) (Model, tea.Cmd) {
+				return m.handleMenuAction(index)
+			})
+			if held {
+				return m, nil
+			}
+		}
+		
 		// Navigate to Add Context All screen
 		m.navStack = m.navStack.Push(navigation.AddContextAllScreen)
 		m.currentScreen = "add_context_all"
+		m.eventLog.Record(events.EventNavigation, "Opened %s", m.currentScreen)
 		m.loadingState = StateScanning
 		m.spinner = m.spinner.SetMessage("Initializing project scan...").Start()
 		m.progress = feedback.NewProgress(0, "Scanning project files")
 		m.showingResult = false
+		m.scanner = nil
+		m.includeExtensions = nil
+		m.scanPaths = nil
+		if wd, err := os.Getwd(); err == nil {
+			m.scanRoot = wd
+			m.scanner = m.newScanner(wd)
+		}
+		m.eventLog.Record(events.EventScanStart, "Project scan started: %s", m.scanRoot)
 		
 		// Start real project scanning
 		return m, tea.Batch(
@@ -583,6 +1168,7 @@ func (m Model) handleMenuAction(index int) (Model, tea.Cmd) {
 		// Navigate to Add Context Folder screen and open browser
 		m.navStack = m.navStack.Push(navigation.AddContextFolderScreen)
 		m.currentScreen = "add_context_folder"
+		m.eventLog.Record(events.EventNavigation, "Opened %s", m.currentScreen)
 		
 		// Initialize folder browser
 		wd, err := os.Getwd()
@@ -593,7 +1179,7 @@ func (m Model) handleMenuAction(index int) (Model, tea.Cmd) {
 			return m, toastCmd
 		}
 		
-		browser, err := folder.NewBrowserModel(wd)
+		browser, err := m.newFolderBrowser(wd)
 		if err != nil {
 			toastManager, toastCmd := m.toastManager.AddToast(
 				fmt.Sprintf("Error initializing folder browser: %v", err), feedback.ToastError)
@@ -604,12 +1190,14 @@ func (m Model) handleMenuAction(index int) (Model, tea.Cmd) {
 		m.folderBrowser = browser
 		m.showingBrowser = true
 		m.showingResult = false
+		m = m.applyWindowSize()
 		
-		return m, nil
+		return m, browser.Init()
 	case 2: // Context Before
 		// Navigate to Context Preview screen
 		m.navStack = m.navStack.Push(navigation.ContextPreviewScreen)
 		m.currentScreen = "context_preview"
+		m.eventLog.Record(events.EventNavigation, "Opened %s", m.currentScreen)
 		
 		// Check if we have context to preview
 		if m.contextResult == nil {
@@ -619,28 +1207,64 @@ func (m Model) handleMenuAction(index int) (Model, tea.Cmd) {
 			return m, toastCmd
 		}
 		
-		// Initialize context preview
-		contextPreview := preview.NewContextPreviewModel(m.contextResult, m.scanResult)
-		m.contextPreview = contextPreview
-		m.showingPreview = true
-		m.showingResult = false
-		
-		return m, nil
+		return m.openContextPreview()
 	case 3: // Select Model
 		// Navigate to Model Selection screen
 		m.navStack = m.navStack.Push(navigation.ModelSelectionScreen)
 		m.currentScreen = "model_selection"
+		m.eventLog.Record(events.EventNavigation, "Opened %s", m.currentScreen)
 		m.loadingState = StateProcessing
 		m.spinner = m.spinner.SetMessage("Loading available models...").Start()
 		return m, tea.Batch(
 			m.spinner.InitSpinner(),
 			m.simulateModelLoading(),
 		)
+	case 4: // Chat
+		return m.openChat()
+	case 5: // Resume last session
+		return m.resumeSession()
+	case 6: // Context library
+		return m.openLibrary()
+	case 7: // Context from git diff
+		return m.openGitDiffPicker()
+	case 8: // Prompt templates
+		return m.openPromptTemplates()
+	case 9: // Compare contexts
+		return m.openCompare()
 	default:
 		return m, nil
 	}
 }
 
+// openContextPreview shows the current context in the preview screen with the
+// active model, templates and output format applied
+func (m Model) openContextPreview() (Model, tea.Cmd) {
+	contextPreview := preview.NewContextPreviewModel(m.contextResult, m.scanResult)
+	if m.appConfig != nil {
+		if model, ok := m.appConfig.ActiveModel(); ok {
+			contextPreview.SetModel(model)
+		}
+		contextPreview.SetCustomTemplates(m.appConfig.ContextTemplates)
+		contextPreview.SetFormatter(m.configuredFormatter())
+		contextPreview.SetHighlight(!m.appConfig.NoHighlight)
+	}
+	contextPreview.SetMinimalChrome(m.minimalChrome)
+	m.contextPreview = contextPreview
+	m.showingPreview = true
+	m.showingResult = false
+	m = m.applyWindowSize()
+	
+	// Suggest a smaller template when the context won't fit the model
+	if template, ok := contextPreview.SuggestTemplate(); ok {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("Context exceeds model window - try the '%s' template", template.Name), feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	return m, nil
+}
+
 // handleSimulateOperation processes simulation messages
 func (m Model) handleSimulateOperation(msg SimulateOperationMsg) (Model, tea.Cmd) {
 	switch msg.Operation {
@@ -702,10 +1326,56 @@ func (m Model) completeOperation(success bool, message string) tea.Cmd {
 
 func (m Model) resetToMenuAfterDelay() tea.Cmd {
 	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
-		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}} // Reset signal
+		return ResetMsg{}
 	})
 }
 
+// resetToMenu clears operation state and returns to the main menu
+func (m Model) resetToMenu() Model {
+	m.loadingState = StateMenu
+	m.spinner = m.spinner.Stop()
+	m.progress = feedback.NewProgress(0, "")
+	// Reset navigation to main menu
+	m.navStack = navigation.NewNavigationStack().Push(navigation.MainMenuScreen)
+	m.currentScreen = "main_menu"
+	return m
+}
+
+// viewWidth is the width views center their content in
+func (m Model) viewWidth() int {
+	if m.width <= 0 {
+		return defaultViewWidth
+	}
+	return m.width
+}
+
+// boxWidth shrinks a bordered box's preferred width to fit the terminal
+func (m Model) boxWidth(preferred int) int {
+	width := m.viewWidth() - 4 // border and a margin on each side
+	if width < minBoxWidth {
+		width = minBoxWidth
+	}
+	if preferred < width {
+		return preferred
+	}
+	return width
+}
+
+// applyWindowSize passes the terminal size on to the open browser and preview
+func (m Model) applyWindowSize() Model {
+	if m.width <= 0 {
+		return m
+	}
+	size := tea.WindowSizeMsg{Width: m.width, Height: m.height}
+	if m.folderBrowser != nil {
+		m.folderBrowser, _ = m.folderBrowser.Update(size)
+	}
+	if m.contextPreview != nil {
+		m.contextPreview, _ = m.contextPreview.Update(size)
+	}
+	return m
+}
+
 // Helper function to center text within a given width
 func centerText(text string, width int) string {
 	lines := strings.Split(text, "\n")
@@ -735,7 +1405,7 @@ func (m Model) createButton(item MenuItem, index int, isSelected bool) string {
 	bgSelectedColor := lipgloss.Color("#1E1B4B") // Dark purple background
 	
 	// Button dimensions - wider for more info
-	buttonWidth := 50
+	buttonWidth := m.boxWidth(50)
 	buttonHeight := 2
 	
 	// Create the button content with title and description
@@ -776,7 +1446,7 @@ func (m Model) createHelpModal(item MenuItem) string {
 		Background(lipgloss.Color("#1E1B4B")).
 		Foreground(lipgloss.Color("#FFFFFF")).
 		Padding(1, 2).
-		Width(60).
+		Width(m.boxWidth(60)).
 		Bold(true)
 	
 	content := "Help: " + item.Title + "\n\n" + item.DetailHelp + "\n\nPress ESC or Enter to close"
@@ -785,20 +1455,84 @@ func (m Model) createHelpModal(item MenuItem) string {
 
 func (m Model) View() string {
 	var result strings.Builder
+	result.WriteString(m.renderTopBar())
 	
-	// Always show navigation at the top
-	navView := m.navRenderer.RenderFullNavigation(m.navStack)
-	if navView != "" {
-		centeredNav := m.navRenderer.CenterNavigation(navView, 100)
-		result.WriteString(centeredNav)
-		result.WriteString("\n\n")
+	// Show event log over everything when toggled
+	if m.showingEventLog {
+		return result.String() + m.renderEventLog()
 	}
 	
-	// Always show toasts after navigation
-	if toastView := m.toastManager.View(); toastView != "" {
-		centeredToast := centerText(toastView, 100)
-		result.WriteString(centeredToast)
-		result.WriteString("\n\n")
+	// Show the last error in full when requested
+	if m.showingErrorDetail && m.lastError != nil {
+		return result.String() + m.renderErrorDetail()
+	}
+	
+	// Ask before scanning a home, root or volume directory
+	if m.pendingScan != nil {
+		return result.String() + m.renderPendingScan()
+	}
+	
+	// Confirm resetting settings to defaults
+	if m.confirmingReset && m.appConfig != nil {
+		return result.String() + m.renderResetConfirm()
+	}
+	
+	// Enter or update provider API keys
+	if m.apiKeys != nil {
+		return result.String() + m.renderAPIKeys()
+	}
+	
+	// Edit the active model's settings
+	if m.modelEditor != nil {
+		return result.String() + m.renderModelEditor()
+	}
+	
+	// Browse saved contexts
+	if m.library != nil {
+		return result.String() + m.renderLibrary()
+	}
+	
+	// Compare two contexts
+	if m.compare != nil {
+		return result.String() + m.renderCompare()
+	}
+	
+	// Choose the base ref for a git diff context
+	if m.gitDiffPicker != nil {
+		return result.String() + m.renderGitDiffPicker()
+	}
+	
+	// Manage and preview prompt templates
+	if m.promptScreen != nil {
+		return result.String() + m.renderPromptTemplates()
+	}
+	
+	// Choose file types before a scan
+	if m.extensionPicker != nil {
+		return result.String() + m.renderExtensionPicker()
+	}
+	
+	// Pick another output directory after a failed export
+	if m.pickingOutputDir && m.folderBrowser != nil {
+		return result.String() + m.folderBrowser.View()
+	}
+	if m.pendingExport != nil {
+		return result.String() + m.renderUnwritableDir()
+	}
+	
+	// Name the exported file
+	if m.exportPrompt != nil {
+		return result.String() + m.renderExportPrompt()
+	}
+	
+	// Show the chat screen
+	if m.chat != nil {
+		return result.String() + m.renderChat()
+	}
+	
+	// Explain why generation was blocked by the size cap
+	if m.limitExceeded != nil {
+		return result.String() + m.renderLimitExceeded()
 	}
 	
 	// If showing help modal, render it over everything
@@ -808,7 +1542,7 @@ func (m Model) View() string {
 		
 		// Create overlay with help modal
 		helpModal := m.createHelpModal(m.menuItems[m.helpForItem])
-		centeredModal := centerText(helpModal, 100)
+		centeredModal := centerText(helpModal, m.viewWidth())
 		
 		// Simple overlay - just show the modal over the base view
 		result.WriteString(baseView)
@@ -829,6 +1563,21 @@ func (m Model) View() string {
 		return result.String() + m.folderBrowser.View()
 	}
 	
+	// Show extension exclusion list over the result view
+	if m.showingExtensions && m.showingResult && m.contextResult != nil {
+		return result.String() + m.renderExtensionList()
+	}
+	
+	// Show excluded files inspector over the result view
+	if m.showingExcluded && m.showingResult && m.scanResult != nil {
+		return result.String() + m.renderExcludedInspector()
+	}
+	
+	// Show skipped paths over the result view
+	if m.showingScanErrors && m.showingResult && m.scanErrorCount() > 0 {
+		return result.String() + m.renderScanErrors()
+	}
+	
 	// Show result view if available
 	if m.showingResult && m.contextResult != nil {
 		return result.String() + m.renderResultView()
@@ -842,32 +1591,47 @@ func (m Model) View() string {
 	return result.String() + m.renderBaseView()
 }
 
-// renderLoadingView renders the loading interface
-func (m Model) renderLoadingView() string {
+// renderTopBar renders the navigation and toasts shown above every screen
+func (m Model) renderTopBar() string {
 	var result strings.Builder
 	
-	// Compact banner
-	bannerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
-		Align(lipgloss.Center)
+	// Show navigation at the top unless chrome is hidden
+	navView := m.navRenderer.RenderFullNavigation(m.navStack)
+	if navView != "" && !m.minimalChrome {
+		centeredNav := m.navRenderer.CenterNavigation(navView, m.viewWidth())
+		result.WriteString(centeredNav)
+		result.WriteString("\n\n")
+	}
 	
-	compactBanner := []string{
-		"╔═══════════════════════════╗",
-		"║      Context Engine       ║", 
-		"╚═══════════════════════════╝",
+	// Always show toasts after navigation
+	if toastView := m.toastManager.View(); toastView != "" {
+		centeredToast := centerText(toastView, m.viewWidth())
+		result.WriteString(centeredToast)
+		result.WriteString("\n\n")
 	}
 	
-	for _, line := range compactBanner {
-		centeredLine := centerText(bannerStyle.Render(line), 100)
-		result.WriteString(centeredLine)
+	return result.String()
+}
+
+// renderLoadingView renders the loading interface
+func (m Model) renderLoadingView() string {
+	var result strings.Builder
+	
+	// Compact banner
+	if !m.minimalChrome {
+		result.WriteString(m.renderCompactBanner())
 		result.WriteString("\n")
 	}
-	result.WriteString("\n")
 	
-	// Show spinner if active
-	if spinnerView := m.spinner.View(); spinnerView != "" {
-		centeredSpinner := centerText(spinnerView, 100)
+	// A paused scan replaces the spinner so it doesn't look busy
+	if m.scanPaused() {
+		pausedStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#F59E0B"))
+		result.WriteString(centerText(pausedStyle.Render("⏸ Scan paused"), m.viewWidth()))
+		result.WriteString("\n\n")
+	} else if spinnerView := m.spinner.View(); spinnerView != "" {
+		centeredSpinner := centerText(spinnerView, m.viewWidth())
 		result.WriteString(centeredSpinner)
 		result.WriteString("\n\n")
 	}
@@ -875,7 +1639,7 @@ func (m Model) renderLoadingView() string {
 	// Show progress bar if operation has progress
 	if m.loadingState == StateScanning && m.progress.Percentage() > 0 {
 		progressView := m.progress.View()
-		centeredProgress := centerText(progressView, 100)
+		centeredProgress := centerText(progressView, m.viewWidth())
 		result.WriteString(centeredProgress)
 		result.WriteString("\n\n")
 	}
@@ -886,47 +1650,48 @@ func (m Model) renderLoadingView() string {
 		Italic(true)
 	
 	instructions := "⏳ Loading... "
-	if m.navStack.CanGoBack() {
-		instructions += "ESC: Back • "
+	if m.loadingState == StateScanning && m.scanner != nil {
+		instructions += "P: Pause • "
+	}
+	instructions += "ESC/Ctrl+C: Cancel"
+	if !m.minimalChrome {
+		centeredInstructions := centerText(instructionStyle.Render(instructions), m.viewWidth())
+		result.WriteString(centeredInstructions)
 	}
-	instructions += "Ctrl+C: Cancel"
-	centeredInstructions := centerText(instructionStyle.Render(instructions), 100)
-	result.WriteString(centeredInstructions)
 	
 	return result.String()
 }
 
 func (m Model) renderBaseView() string {
 	var result strings.Builder
+	result.WriteString(m.renderMenuHeader())
 	
-	// Compact banner
-	bannerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
-		Align(lipgloss.Center)
-	
-	compactBanner := []string{
-		"╔═══════════════════════════╗",
-		"║      Context Engine       ║", 
-		"╚═══════════════════════════╝",
-	}
-	
-	for _, line := range compactBanner {
-		centeredLine := centerText(bannerStyle.Render(line), 100)
-		result.WriteString(centeredLine)
-		result.WriteString("\n")
-	}
-	result.WriteString("\n") // Single line spacing after banner
-	
-	// Create buttons layout
-	for i, item := range m.menuItems {
-		isSelected := i == m.cursor
-		button := m.createButton(item, i, isSelected)
-		
-		// Center each button
-		centeredButton := centerText(button, 100)
-		result.WriteString(centeredButton)
-		result.WriteString("\n") // Single line spacing between buttons
+	// The dashboard replaces the menu buttons when chosen as home screen
+	if m.showingDashboard {
+		result.WriteString(m.renderDashboard())
+	} else {
+		// Create buttons layout, scrolled to the cursor when they don't all fit
+		start, end := m.visibleMenuItems()
+		moreStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Italic(true)
+		if start > 0 {
+			result.WriteString(centerText(moreStyle.Render(fmt.Sprintf("↑ %d more", start)), m.viewWidth()))
+			result.WriteString("\n")
+		}
+		for i := start; i < end; i++ {
+			isSelected := i == m.cursor
+			button := m.createButton(m.menuItems[i], i, isSelected)
+			
+			// Center each button
+			centeredButton := centerText(button, m.viewWidth())
+			result.WriteString(centeredButton)
+			result.WriteString("\n") // Single line spacing between buttons
+		}
+		if end < len(m.menuItems) {
+			result.WriteString(centerText(moreStyle.Render(fmt.Sprintf("↓ %d more", len(m.menuItems)-end)), m.viewWidth()))
+			result.WriteString("\n")
+		}
 	}
 	
 	// Add compact instructions with navigation
@@ -935,22 +1700,59 @@ func (m Model) renderBaseView() string {
 		Italic(true)
 	
 	instructions := "↑↓/jk: navigate • Enter: select • ?: help"
+	if m.appConfig != nil {
+		instructions += fmt.Sprintf(" • P: profile (%s)", m.appConfig.Profile)
+		if model, ok := m.appConfig.ActiveModel(); ok {
+			instructions += fmt.Sprintf(" • M: model (%s) • E: edit • T: test", model.Name)
+		}
+		instructions += " • D: defaults • K: API keys"
+	}
+	instructions += " • H: dashboard"
 	if m.navStack.CanGoBack() {
 		instructions += " • ESC: back"
 	}
 	instructions += " • q: quit"
-	centeredInstructions := centerText(instructionStyle.Render(instructions), 100)
-	result.WriteString("\n")
-	result.WriteString(centeredInstructions)
+	if !m.minimalChrome {
+		centeredInstructions := centerText(instructionStyle.Render(instructions), m.viewWidth())
+		result.WriteString("\n")
+		result.WriteString(centeredInstructions)
+	}
 	
 	return result.String()
 }
 
-// renderResultView renders the context generation results
-func (m Model) renderResultView() string {
+// renderMenuHeader renders the compact banner and toolbar above the menu
+func (m Model) renderMenuHeader() string {
+	if m.minimalChrome {
+		return ""
+	}
+	return m.renderCompactBanner() + m.renderToolbar() + m.renderSessionUsage() +
+		"\n\n" // Single line spacing after toolbar
+}
+
+// visibleMenuItems returns the range of menu buttons that fit the terminal height,
+// keeping the cursor in view
+func (m Model) visibleMenuItems() (start, end int) {
+	if m.height <= 0 {
+		return 0, len(m.menuItems)
+	}
+	shown := (m.height - menuChrome) / menuButtonLines
+	if shown < 1 {
+		shown = 1
+	}
+	if shown >= len(m.menuItems) {
+		return 0, len(m.menuItems)
+	}
+	if m.cursor >= shown {
+		start = m.cursor - shown + 1
+	}
+	return start, start + shown
+}
+
+// renderCompactBanner renders the small banner shown above each view
+func (m Model) renderCompactBanner() string {
 	var result strings.Builder
 	
-	// Compact banner
 	bannerStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#7D56F4")).
@@ -963,11 +1765,24 @@ func (m Model) renderResultView() string {
 	}
 	
 	for _, line := range compactBanner {
-		centeredLine := centerText(bannerStyle.Render(line), 100)
+		centeredLine := centerText(bannerStyle.Render(line), m.viewWidth())
 		result.WriteString(centeredLine)
 		result.WriteString("\n")
 	}
-	result.WriteString("\n")
+	
+	return result.String()
+}
+
+// renderResultView renders the context generation results
+func (m Model) renderResultView() string {
+	var result strings.Builder
+	
+	// Compact banner
+	if !m.minimalChrome {
+		result.WriteString(m.renderCompactBanner())
+		result.WriteString(m.renderSessionUsage())
+		result.WriteString("\n")
+	}
 	
 	// Context Results Title
 	titleStyle := lipgloss.NewStyle().
@@ -976,7 +1791,7 @@ func (m Model) renderResultView() string {
 		Align(lipgloss.Center)
 	
 	title := fmt.Sprintf("✨ Context Generated Successfully! ✨")
-	centeredTitle := centerText(titleStyle.Render(title), 100)
+	centeredTitle := centerText(titleStyle.Render(title), m.viewWidth())
 	result.WriteString(centeredTitle)
 	result.WriteString("\n\n")
 	
@@ -985,58 +1800,41 @@ func (m Model) renderResultView() string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("#10B981")).
 		Padding(1, 2).
-		Width(60).
+		Width(m.boxWidth(60)).
 		Align(lipgloss.Center)
 	
 	var summaryContent strings.Builder
 	summaryContent.WriteString(fmt.Sprintf("📁 Project: %s\n", m.contextResult.ProjectName))
+	if len(m.contextResult.ProjectTypes) > 0 {
+		summaryContent.WriteString(fmt.Sprintf("🏷️ Type: %s\n", strings.Join(m.contextResult.ProjectTypes, ", ")))
+	}
 	summaryContent.WriteString(fmt.Sprintf("📊 Files Processed: %d\n", m.contextResult.TotalFiles))
+	if m.scanResult != nil && m.scanResult.UnchangedFiles+m.scanResult.RescannedFiles > 0 {
+		summaryContent.WriteString(fmt.Sprintf("♻️ %d files unchanged, %d rescanned\n",
+			m.scanResult.UnchangedFiles, m.scanResult.RescannedFiles))
+	}
 	summaryContent.WriteString(fmt.Sprintf("📄 Total Size: %s\n", context.FormatSize(m.contextResult.TotalSize)))
 	summaryContent.WriteString(fmt.Sprintf("📝 Sections Generated: %d\n", len(m.contextResult.Sections)))
 	summaryContent.WriteString(fmt.Sprintf("🧠 Estimated Tokens: ~%s\n", context.FormatNumber(m.contextResult.TokenEstimate)))
 	summaryContent.WriteString(fmt.Sprintf("⏱️ Generated: %s", m.contextResult.GeneratedAt.Format("15:04:05")))
 	
 	summaryRendered := summaryBox.Render(summaryContent.String())
-	centeredSummary := centerText(summaryRendered, 100)
+	centeredSummary := centerText(summaryRendered, m.viewWidth())
 	result.WriteString(centeredSummary)
 	result.WriteString("\n\n")
 	
+	// Partial results are flagged so missing files aren't a surprise
+	if skipped := m.scanErrorCount(); skipped > 0 {
+		warningStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#F59E0B")).
+			Bold(true)
+		result.WriteString(centerText(warningStyle.Render(fmt.Sprintf("⚠️ %s • W: details", skippedSummary(skipped))), m.viewWidth()))
+		result.WriteString("\n\n")
+	}
+	
 	// Sections overview
 	if len(m.contextResult.Sections) > 0 {
-		sectionTitle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#3B82F6")).
-			Render("📋 Generated Sections:")
-		
-		centeredSectionTitle := centerText(sectionTitle, 100)
-		result.WriteString(centeredSectionTitle)
-		result.WriteString("\n\n")
-		
-		for i, section := range m.contextResult.Sections {
-			if i >= 5 { // Show first 5 sections
-				moreText := lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#6B7280")).
-					Italic(true).
-					Render(fmt.Sprintf("... and %d more sections", len(m.contextResult.Sections)-5))
-				centeredMore := centerText(moreText, 100)
-				result.WriteString(centeredMore)
-				result.WriteString("\n")
-				break
-			}
-			
-			sectionItem := fmt.Sprintf("• %s", section.Title)
-			if len(section.Files) > 0 {
-				sectionItem += fmt.Sprintf(" (%d files)", len(section.Files))
-			}
-			
-			sectionStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#374151"))
-			
-			centeredSection := centerText(sectionStyle.Render(sectionItem), 100)
-			result.WriteString(centeredSection)
-			result.WriteString("\n")
-		}
-		result.WriteString("\n")
+		result.WriteString(m.renderResultSections())
 	}
 	
 	// Instructions
@@ -1045,12 +1843,15 @@ func (m Model) renderResultView() string {
 		Italic(true)
 	
 	instructions := "✨ Context ready for AI interaction!"
+	instructions += " • ↑↓: sections • O: expand • X: exclude extension • E: excluded files • C: toggle content • y/Y: copy/copy fenced"
 	if m.navStack.CanGoBack() {
 		instructions += " • ESC: back"
 	}
 	instructions += " • q: quit"
-	centeredInstructions := centerText(instructionStyle.Render(instructions), 100)
-	result.WriteString(centeredInstructions)
+	if !m.minimalChrome {
+		centeredInstructions := centerText(instructionStyle.Render(instructions), m.viewWidth())
+		result.WriteString(centeredInstructions)
+	}
 	
 	return result.String()
 }
\ No newline at end of file
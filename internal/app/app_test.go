@@ -1,9 +1,28 @@
 package app
 
 import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"ai-context-cli/internal/chat"
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/folder"
+	"ai-context-cli/internal/keyring"
+	"ai-context-cli/internal/library"
+	"ai-context-cli/internal/providers"
+	"ai-context-cli/pkg/types"
 )
 
 func TestNewModel(t *testing.T) {
@@ -63,4 +82,2110 @@ func TestModelView(t *testing.T) {
 	if view == "" {
 		t.Error("Expected view to return non-empty string")
 	}
-}
\ No newline at end of file
+}
+
+func TestToolbarRendersKeyHints(t *testing.T) {
+	model := NewModel()
+	toolbar := model.renderToolbar()
+	
+	expected := []string{"📂", "scan", "📁", "folder", "🤖", "model", "📋", "preview", "?", "help"}
+	for _, hint := range expected {
+		if !strings.Contains(toolbar, hint) {
+			t.Errorf("Expected toolbar to contain %q", hint)
+		}
+	}
+	
+	if !strings.Contains(model.View(), "preview") {
+		t.Error("Expected main menu view to include the toolbar")
+	}
+	
+	// Quick action keys trigger the same handler as the menu
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m := updated.(Model)
+	if m.currentScreen != "context_preview" {
+		t.Errorf("Expected preview quick action to open context preview, got %q", m.currentScreen)
+	}
+}
+
+func TestExcludeExtensionRegeneratesContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "exclude_ext_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "data.json"), []byte("{\"key\": \"value\"}"), 0644)
+	
+	// generate runs a scan and context generation synchronously
+	generate := func(m Model) Model {
+		scanMsg := m.startFolderScan(m.scanRoot)().(ScanCompleteMsg)
+		if scanMsg.Error != nil {
+			t.Fatalf("Scan failed: %v", scanMsg.Error)
+		}
+		m.scanResult = scanMsg.Result
+		
+		contextMsg := m.generateContext()().(ContextGeneratedMsg)
+		if contextMsg.Error != nil {
+			t.Fatalf("Context generation failed: %v", contextMsg.Error)
+		}
+		m.contextResult = contextMsg.Result
+		m.showingResult = true
+		return m
+	}
+	
+	model := NewModel()
+	model.scanRoot = tempDir
+	model = generate(model)
+	
+	if !containsSection(model, "JSON Files Content") {
+		t.Fatal("Expected JSON content before exclusion")
+	}
+	
+	// Open the extension list and select .json
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	model = updated.(Model)
+	if !model.showingExtensions {
+		t.Fatal("Expected extension list to open on the result view")
+	}
+	
+	for i, contribution := range model.extensionContributions() {
+		if contribution.Extension == ".json" {
+			model.extensionCursor = i
+		}
+	}
+	
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected regeneration command after toggling extension")
+	}
+	
+	if len(model.excludedExtensions) != 1 || model.excludedExtensions[0] != ".json" {
+		t.Fatalf("Expected .json to be excluded, got %v", model.excludedExtensions)
+	}
+	
+	model = generate(model)
+	if containsSection(model, "JSON Files Content") {
+		t.Error("Expected JSON content to disappear after exclusion")
+	}
+	if !containsSection(model, "GO Files Content") {
+		t.Error("Expected Go content to remain after exclusion")
+	}
+}
+
+func TestExtensionContributionsSplitMixedSections(t *testing.T) {
+	model := NewModel()
+	model.contextResult = &context.ContextResult{
+		Sections: []context.ContextSection{
+			{
+				Title:     "internal/app",
+				IsContent: true,
+				Files:     []string{"internal/app/app.go", "internal/app/README.md"},
+				Documents: []context.FileDocument{
+					{Path: "internal/app/app.go", Content: strings.Repeat("g", 300)},
+					{Path: "internal/app/README.md", Content: strings.Repeat("m", 100)},
+				},
+			},
+		},
+	}
+	
+	chars := make(map[string]int)
+	files := make(map[string]int)
+	for _, contribution := range model.extensionContributions() {
+		chars[contribution.Extension] = contribution.Chars
+		files[contribution.Extension] = contribution.Files
+	}
+	
+	if chars[".go"] != 300 || files[".go"] != 1 {
+		t.Errorf("Expected .go to contribute 1 file and 300 chars, got %d files and %d chars", files[".go"], chars[".go"])
+	}
+	if chars[".md"] != 100 || files[".md"] != 1 {
+		t.Errorf("Expected .md to contribute 1 file and 100 chars, got %d files and %d chars", files[".md"], chars[".md"])
+	}
+}
+
+func containsSection(m Model, title string) bool {
+	for _, section := range m.contextResult.Sections {
+		if section.Title == title {
+			return true
+		}
+	}
+	return false
+}
+
+func TestScanCompleteEventRecorded(t *testing.T) {
+	model := NewModel()
+	
+	updated, _ := model.Update(ScanCompleteMsg{Result: &context.ScanResult{TotalFiles: 3}})
+	m := updated.(Model)
+	
+	found := false
+	for _, event := range m.EventLog().Events() {
+		if event.Type == events.EventScanComplete && strings.Contains(event.Message, "3 files") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected scan-complete event to be recorded")
+	}
+	
+	// ctrl+l opens the event log view
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	m = updated.(Model)
+	if !m.showingEventLog {
+		t.Fatal("Expected ctrl+l to open the event log")
+	}
+	if !strings.Contains(m.View(), "scan_complete") {
+		t.Error("Expected event log view to list the scan-complete event")
+	}
+}
+
+func TestQuickSwitchCyclesRecentModels(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "recent_models_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+	
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	now := time.Now()
+	cfg.Models = []types.AIModel{
+		{Name: "gpt-4", Provider: "openai", LastUsed: now},
+		{Name: "claude-3-sonnet", Provider: "anthropic", LastUsed: now.Add(-time.Hour)},
+		{Name: "never-used", Provider: "ollama"},
+	}
+	cfg.DefaultModel = "gpt-4"
+	
+	model := NewModel().WithConfig(cfg)
+	quickSwitch := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}}
+	
+	updated, _ := model.Update(quickSwitch)
+	model = updated.(Model)
+	if active, _ := model.appConfig.ActiveModel(); active.Name != "claude-3-sonnet" {
+		t.Errorf("Expected claude-3-sonnet after first switch, got %s", active.Name)
+	}
+	
+	updated, _ = model.Update(quickSwitch)
+	model = updated.(Model)
+	if active, _ := model.appConfig.ActiveModel(); active.Name != "gpt-4" {
+		t.Errorf("Expected gpt-4 after second switch, got %s", active.Name)
+	}
+	
+	if !strings.Contains(model.View(), "M: model (gpt-4)") {
+		t.Error("Expected active model indicator in main menu")
+	}
+}
+
+func TestResetMsgAndUserRKey(t *testing.T) {
+	model := NewModel()
+	
+	// A user pressing r at the menu must not disturb state
+	model.cursor = 2
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	model = updated.(Model)
+	if model.loadingState != StateMenu || model.currentScreen != "main_menu" || model.cursor != 2 {
+		t.Error("Expected r keypress at the menu to leave state untouched")
+	}
+	
+	// r during a finished operation no longer acts as a hidden reset
+	model.loadingState = StateComplete
+	model.currentScreen = "context_preview"
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	model = updated.(Model)
+	if model.loadingState != StateComplete {
+		t.Error("Expected r keypress not to reset a completed operation")
+	}
+	
+	updated, _ = model.Update(ResetMsg{})
+	model = updated.(Model)
+	if model.loadingState != StateMenu {
+		t.Errorf("Expected ResetMsg to return to menu state, got %v", model.loadingState)
+	}
+	if model.currentScreen != "main_menu" || model.navStack.CanGoBack() {
+		t.Error("Expected ResetMsg to reset navigation to the main menu")
+	}
+}
+
+func TestMinimalChromeHidesBanner(t *testing.T) {
+	model := NewModel()
+	if !strings.Contains(model.View(), "Context Engine") {
+		t.Fatal("Expected banner in the default menu view")
+	}
+	
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	model = updated.(Model)
+	
+	view := model.View()
+	if strings.Contains(view, "Context Engine") || strings.Contains(view, "╔") {
+		t.Error("Expected banner lines to be hidden in minimal mode")
+	}
+	if strings.Contains(view, "Enter: select") {
+		t.Error("Expected instructions to be hidden in minimal mode")
+	}
+	if !strings.Contains(view, model.menuItems[0].Title) {
+		t.Error("Expected menu content to remain visible in minimal mode")
+	}
+	
+	// Screens opened afterwards drop their key hints too
+	browser, err := model.newFolderBrowser(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open browser: %v", err)
+	}
+	if strings.Contains(browser.View(), "R: refresh") {
+		t.Error("Expected the folder browser footer to drop its key hints")
+	}
+	model.contextResult = &context.ContextResult{Sections: []context.ContextSection{{Title: "Overview", Content: "body"}}}
+	model, _ = model.openContextPreview()
+	if view := model.contextPreview.View(); strings.Contains(view, "ESC: exit") || !strings.Contains(view, "tokens") {
+		t.Error("Expected the preview footer to keep its stats and drop its key hints")
+	}
+}
+
+func TestGenerationUsesLargeFileSetting(t *testing.T) {
+	tempDir := t.TempDir()
+	var lines []string
+	for i := 1; i <= 8000; i++ {
+		lines = append(lines, fmt.Sprintf("entry-%04d", i))
+	}
+	os.WriteFile(filepath.Join(tempDir, "server.txt"), []byte(strings.Join(lines, "\n")), 0644)
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	
+	model := NewModel().WithConfig(&config.Config{LargeFiles: "tail", LargeFileLines: 10, FocusKeywords: []string{"entry"}})
+	scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	model.scanResult = scanMsg.Result
+	contextMsg := model.generateContext()().(ContextGeneratedMsg)
+	if contextMsg.Error != nil {
+		t.Fatalf("Context generation failed: %v", contextMsg.Error)
+	}
+	markdown := contextMsg.Result.Markdown()
+	if !strings.Contains(markdown, "entry-8000") || strings.Contains(markdown, "entry-7980") {
+		t.Error("Expected the last 10 lines of the oversized file from the tail setting")
+	}
+}
+
+func TestContentLayoutSettingsFromConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "pkg"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\r\n\r\nfunc main() {}\r\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "main_test.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "pkg", "README.md"), []byte("Shared utilities\n"), 0644)
+	
+	generate := func(cfg *config.Config) *context.ContextResult {
+		model := NewModel().WithConfig(cfg)
+		scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg)
+		if scanMsg.Error != nil {
+			t.Fatalf("Scan failed: %v", scanMsg.Error)
+		}
+		model.scanResult = scanMsg.Result
+		contextMsg := model.generateContext()().(ContextGeneratedMsg)
+		if contextMsg.Error != nil {
+			t.Fatalf("Context generation failed: %v", contextMsg.Error)
+		}
+		return contextMsg.Result
+	}
+	
+	result := generate(&config.Config{
+		ContentGrouping:  "directory",
+		SeparateTests:    true,
+		DirectoryReadmes: true,
+		HeaderLevel:      2,
+		KeepLineEndings:  true,
+		ReadBufferSize:   16,
+	})
+	for _, title := range []string{"pkg/ Files Content", "Tests", "Directory READMEs"} {
+		if !containsSection(Model{contextResult: result}, title) {
+			t.Errorf("Expected a %q section from the config, got %v", title, sectionTitles(result))
+		}
+	}
+	markdown := result.Markdown()
+	if !strings.HasPrefix(markdown, "## ") || strings.Contains(markdown, "\n# ") {
+		t.Errorf("Expected section headers at level 2 from header_level, got:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "func main() {}\r\n") {
+		t.Error("Expected CRLF line endings kept with keep_line_endings")
+	}
+	
+	result = generate(&config.Config{MaxSections: 1})
+	if !strings.Contains(result.Markdown(), "more file groups omitted (max 1 content sections)") {
+		t.Errorf("Expected max_content_sections to cap the content sections, got %v", sectionTitles(result))
+	}
+}
+
+// sectionTitles lists a result's section titles for failure messages
+func sectionTitles(result *context.ContextResult) []string {
+	var titles []string
+	for _, section := range result.Sections {
+		titles = append(titles, section.Title)
+	}
+	return titles
+}
+
+func TestExcludedInspectorForceInclude(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "excluded_inspector_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "big.txt"), []byte(strings.Repeat("big-content ", 200)), 0644)
+	os.WriteFile(filepath.Join(tempDir, "debug.log"), []byte("log line\n"), 0644)
+	
+	generate := func(m Model) Model {
+		scanMsg := m.startFolderScan(m.scanRoot)().(ScanCompleteMsg)
+		if scanMsg.Error != nil {
+			t.Fatalf("Scan failed: %v", scanMsg.Error)
+		}
+		m.scanResult = scanMsg.Result
+		
+		contextMsg := m.generateContext()().(ContextGeneratedMsg)
+		if contextMsg.Error != nil {
+			t.Fatalf("Context generation failed: %v", contextMsg.Error)
+		}
+		m.contextResult = contextMsg.Result
+		m.showingResult = true
+		return m
+	}
+	
+	model := NewModel().WithConfig(&config.Config{MaxFileSize: 1024})
+	model.scanRoot = tempDir
+	model = generate(model)
+	
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	model = updated.(Model)
+	if !model.showingExcluded {
+		t.Fatal("Expected excluded files inspector to open on the result view")
+	}
+	if len(model.excludedFiles()) != 2 {
+		t.Fatalf("Expected 2 excluded files, got %d", len(model.excludedFiles()))
+	}
+	
+	// Cycle the reason filter to "too large"
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	model = updated.(Model)
+	if model.excludedFilter != "too large" {
+		t.Fatalf("Expected too large filter, got %q", model.excludedFilter)
+	}
+	
+	files := model.excludedFiles()
+	if len(files) != 1 || filepath.Base(files[0].Path) != "big.txt" {
+		t.Fatalf("Expected only big.txt under too large filter, got %v", files)
+	}
+	
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected regeneration command after force-including a file")
+	}
+	
+	model = generate(model)
+	found := false
+	for _, section := range model.contextResult.Sections {
+		if strings.Contains(section.Content, "big-content") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected force-included file content in the regenerated context")
+	}
+}
+
+func TestToggleIncludeContent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "toggle_content_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	
+	model := NewModel()
+	model.scanRoot = tempDir
+	scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	model.scanResult = scanMsg.Result
+	
+	// regenerate presses c on the result view and applies the generated context
+	regenerate := func(m Model) Model {
+		m.showingResult = true
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+		m = updated.(Model)
+		
+		contextMsg := m.generateContext()().(ContextGeneratedMsg)
+		if contextMsg.Error != nil {
+			t.Fatalf("Context generation failed: %v", contextMsg.Error)
+		}
+		updated, _ = m.Update(contextMsg)
+		return updated.(Model)
+	}
+	
+	model = regenerate(model)
+	if !model.structureOnly || containsSection(model, "GO Files Content") {
+		t.Error("Expected content sections to disappear after first toggle")
+	}
+	if !containsSection(model, "Directory Structure") {
+		t.Error("Expected structure to remain in structure-only mode")
+	}
+	
+	model = regenerate(model)
+	if model.structureOnly || !containsSection(model, "GO Files Content") {
+		t.Error("Expected content sections to reappear after second toggle")
+	}
+}
+
+// unavailableClipboard simulates a headless environment
+type unavailableClipboard struct{}
+
+func (unavailableClipboard) Available() bool         { return false }
+func (unavailableClipboard) Write(text string) error { return fmt.Errorf("no clipboard") }
+
+func TestCopyContextFallsBackWithoutClipboard(t *testing.T) {
+	model := NewModel()
+	model.clipboard = unavailableClipboard{}
+	model.showingResult = true
+	model.contextResult = &context.ContextResult{
+		Sections: []context.ContextSection{{Title: "Overview", Content: "# Overview\n\nbody\n"}},
+	}
+	
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected a toast command after copying")
+	}
+	
+	if !model.lastCopy.Fallback || model.lastCopy.Path == "" {
+		t.Fatalf("Expected fallback file, got %+v", model.lastCopy)
+	}
+	defer os.Remove(model.lastCopy.Path)
+	
+	content, err := os.ReadFile(model.lastCopy.Path)
+	if err != nil || !strings.Contains(string(content), "body") {
+		t.Errorf("Expected context in fallback file, got %q (%v)", content, err)
+	}
+	
+	if !strings.Contains(model.toastManager.View(), "saved to") {
+		t.Error("Expected toast reporting the fallback file")
+	}
+	
+	// The active prompt wraps what is copied, as it does exports and chat
+	model.activePrompt = &activePrompt{template: types.ContextTemplate{Name: "Review", Template: "Review for {{.Team}}:\n{{.Context}}"}, values: map[string]string{"Team": "platform"}}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updated.(Model)
+	defer os.Remove(model.lastCopy.Path)
+	content, _ = os.ReadFile(model.lastCopy.Path)
+	if !strings.HasPrefix(string(content), "Review for platform:") || !strings.Contains(string(content), "body") {
+		t.Errorf("Expected the prompt around the copied context, got %q", content)
+	}
+}
+
+func TestNeverIncludePersistsAcrossScans(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "blocklist_config_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+	
+	projectDir, err := os.MkdirTemp("", "blocklist_project_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(projectDir)
+	
+	secretsDir := filepath.Join(projectDir, "secrets")
+	os.MkdirAll(secretsDir, 0755)
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(secretsDir, "key.txt"), []byte("hunter2\n"), 0644)
+	
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	model.scanRoot = projectDir
+	updated, _ := model.Update(folder.BrowserMsg{
+		Type: "never_include",
+		Data: &folder.FolderNode{Path: secretsDir, IsDir: true},
+	})
+	model = updated.(Model)
+	
+	blocklist, err := config.LoadBlocklist(configDir, projectDir)
+	if err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+	if len(blocklist.Entries) != 1 || blocklist.Entries[0] != "secrets" {
+		t.Fatalf("Expected persisted entry \"secrets\", got %v", blocklist.Entries)
+	}
+	
+	// A fresh session picks the blocklist up from disk
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	fresh := NewModel().WithConfig(reloaded)
+	scanMsg := fresh.startFolderScan(projectDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	
+	for _, file := range scanMsg.Result.Files {
+		if strings.HasPrefix(file.Path, secretsDir) {
+			t.Errorf("Expected blocklisted path to be excluded, found %s", file.Path)
+		}
+	}
+	if scanMsg.Result.TotalFiles != 1 {
+		t.Errorf("Expected only main.go to be scanned, got %d files", scanMsg.Result.TotalFiles)
+	}
+}
+
+func TestBlocklistCoversWholeProject(t *testing.T) {
+	projectDir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", projectDir).CombinedOutput(); err != nil {
+		t.Skipf("git unavailable: %v\n%s", err, out)
+	}
+	fixturesDir := filepath.Join(projectDir, "app", "fixtures")
+	os.MkdirAll(fixturesDir, 0755)
+	os.WriteFile(filepath.Join(projectDir, "app", "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(fixturesDir, "data.json"), []byte("{}\n"), 0644)
+	
+	cfg, err := config.LoadProfile(t.TempDir(), config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	model := NewModel().WithConfig(cfg)
+	toggle := func() {
+		updated, _ := model.Update(folder.BrowserMsg{
+			Type: "never_include",
+			Data: &folder.FolderNode{Path: fixturesDir, IsDir: true},
+		})
+		model = updated.(Model)
+	}
+	scanned := func(root string) int {
+		scanMsg := model.startFolderScan(root)().(ScanCompleteMsg)
+		if scanMsg.Error != nil {
+			t.Fatalf("Scan failed: %v", scanMsg.Error)
+		}
+		return scanMsg.Result.TotalFiles
+	}
+	
+	// Blocklisted while browsing a subfolder, stored against the repository root
+	model.scanRoot = filepath.Join(projectDir, "app")
+	toggle()
+	blocklist, err := config.LoadBlocklist(cfg.ConfigDir, projectDir)
+	if err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+	if len(blocklist.Entries) != 1 || blocklist.Entries[0] != "app/fixtures" {
+		t.Fatalf("Expected the entry relative to the repository, got %v", blocklist.Entries)
+	}
+	if got := scanned(projectDir); got != 1 {
+		t.Errorf("Expected the project scan to skip the fixtures, got %d files", got)
+	}
+	if got := scanned(filepath.Join(projectDir, "app")); got != 1 {
+		t.Errorf("Expected the subfolder scan to skip the fixtures, got %d files", got)
+	}
+	
+	// Pressing it again takes the entry off the list
+	model.scanRoot = projectDir
+	toggle()
+	if got := scanned(projectDir); got != 2 {
+		t.Errorf("Expected the fixtures back after removing the entry, got %d files", got)
+	}
+}
+
+// memoryClipboard keeps the last copied text in memory
+type memoryClipboard struct{ text string }
+
+func (c *memoryClipboard) Available() bool         { return true }
+func (c *memoryClipboard) Write(text string) error { c.text = text; return nil }
+
+func TestCopiesAndExportsAccumulateSessionUsage(t *testing.T) {
+	clip := &memoryClipboard{}
+	model := NewModel()
+	model.clipboard = clip
+	model.appConfig = &config.Config{Models: []types.AIModel{{Name: "priced", CostPer1K: 0.01}}}
+	model.showingResult = true
+	model.contextResult = &context.ContextResult{
+		Sections: []context.ContextSection{{Title: "Overview", Content: strings.Repeat("a", 32000)}},
+		// A stale estimate must not be what gets counted
+		TokenEstimate: 1,
+	}
+	
+	copyKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}
+	updated, _ := model.Update(copyKey)
+	model = updated.(Model)
+	tokens := len(clip.text) / 4
+	model.contextResult.Sections[0].Content = strings.Repeat("b", 16000)
+	updated, _ = model.Update(copyKey)
+	model = updated.(Model)
+	tokens += len(clip.text) / 4
+	
+	path := filepath.Join(t.TempDir(), "context.md")
+	model, _ = model.writeExport(model.contextResult, context.MarkdownFormatter{}, path)
+	exported, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	tokens += len(exported) / 4
+	
+	if model.usage.Tokens() != tokens || model.usage.Operations() != 3 {
+		t.Errorf("Expected %d tokens over 3 operations, got %d over %d", tokens, model.usage.Tokens(), model.usage.Operations())
+	}
+	if cost := float64(tokens) / 1000 * 0.01; model.usage.Cost() < cost-1e-9 || model.usage.Cost() > cost+1e-9 {
+		t.Errorf("Expected the model's price applied, got $%f for %d tokens", model.usage.Cost(), tokens)
+	}
+	if !strings.Contains(model.View(), model.usage.Summary()) {
+		t.Error("Expected session usage in the result view chrome")
+	}
+}
+
+func TestResultSectionExpandsInPlace(t *testing.T) {
+	model := NewModel()
+	model.showingResult = true
+	model.contextResult = &context.ContextResult{
+		ProjectName: "demo",
+		Sections: []context.ContextSection{
+			{Title: "Project Overview", Content: "# Project Overview\n\noverview body\n"},
+			{Title: "GO Files Content", Content: "# GO Files Content\n\nfunc secretHelper() {}\n"},
+		},
+	}
+	
+	press := func(key rune) {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		model = updated.(Model)
+	}
+	
+	press('j')
+	if model.resultCursor != 1 {
+		t.Fatalf("Expected cursor on second section, got %d", model.resultCursor)
+	}
+	if strings.Contains(model.View(), "secretHelper") {
+		t.Fatal("Expected section content hidden before expanding")
+	}
+	
+	press('o')
+	if !model.showingResult || !strings.Contains(model.View(), "func secretHelper() {}") {
+		t.Error("Expected expanded section snippet in the result view")
+	}
+	
+	press('o')
+	if strings.Contains(model.View(), "secretHelper") {
+		t.Error("Expected snippet hidden after collapsing")
+	}
+}
+
+func TestSecondScanDuringScanningIsIgnored(t *testing.T) {
+	model := NewModel()
+	model, cmd := model.handleMenuAction(0)
+	if model.loadingState != StateScanning || cmd == nil {
+		t.Fatalf("Expected first trigger to start scanning, got state %v", model.loadingState)
+	}
+	root := model.scanRoot
+	depth := len(model.navStack.GetPath())
+	
+	model, _ = model.handleMenuAction(0)
+	if model.loadingState != StateScanning || model.scanRoot != root || len(model.navStack.GetPath()) != depth {
+		t.Error("Expected second scan trigger to leave the running scan untouched")
+	}
+	
+	model, _ = model.handleFolderSelected(FolderSelectedMsg{Folder: &folder.FolderNode{Path: t.TempDir(), Name: "other"}})
+	if model.scanRoot != root {
+		t.Errorf("Expected folder selection to be ignored during a scan, root changed to %s", model.scanRoot)
+	}
+	
+	if !strings.Contains(model.toastManager.View(), "Operation already in progress") {
+		t.Error("Expected toast explaining the ignored trigger")
+	}
+}
+
+func TestCopyFencedContext(t *testing.T) {
+	clip := &memoryClipboard{}
+	model := NewModel()
+	model.clipboard = clip
+	model.showingResult = true
+	model.contextResult = &context.ContextResult{
+		Sections: []context.ContextSection{{Title: "Overview", Content: "# Overview\n"}},
+	}
+	
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	model = updated.(Model)
+	if clip.text != "```text\n# Overview\n```" {
+		t.Errorf("Expected fenced context on the clipboard, got %q", clip.text)
+	}
+}
+
+func TestExplainExclusionFromBrowser(t *testing.T) {
+	projectDir, _ := filepath.EvalSymlinks(t.TempDir())
+	dep := filepath.Join(projectDir, "node_modules", "dep")
+	os.MkdirAll(dep, 0755)
+	os.WriteFile(filepath.Join(dep, "index.js"), []byte("module.exports = {}\n"), 0644)
+	
+	model := NewModel()
+	model.scanRoot = projectDir
+	updated, _ := model.Update(folder.BrowserMsg{
+		Type: "explain_exclusion",
+		Data: &folder.FolderNode{Path: filepath.Join(dep, "index.js")},
+	})
+	model = updated.(Model)
+	
+	if !strings.Contains(model.toastManager.View(), "node_modules/**") {
+		t.Errorf("Expected toast naming the matching pattern, got %q", model.toastManager.View())
+	}
+}
+
+func TestLongScanErrorViewableInDetailPanel(t *testing.T) {
+	model := NewModel()
+	
+	long := "open /very/long/path/" + strings.Repeat("nested-directory/", 20) + "config.json: permission denied"
+	scanErr := fmt.Errorf("scanning project: %w", fmt.Errorf("%s", long))
+	updated, _ := model.Update(ScanCompleteMsg{Error: scanErr})
+	m := updated.(Model)
+	
+	if m.LastError() == nil || m.LastError().Err != scanErr {
+		t.Fatal("Expected scan error to be kept as the last error")
+	}
+	
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+	m = updated.(Model)
+	if !m.showingErrorDetail {
+		t.Fatal("Expected ! to open the error detail panel")
+	}
+	
+	// The panel wraps long lines, so compare without whitespace
+	squash := func(s string) string { return strings.Join(strings.Fields(s), "") }
+	if !strings.Contains(squash(m.View()), squash(scanErr.Error())) {
+		t.Error("Expected the full scan error in the detail panel")
+	}
+	
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.showingErrorDetail {
+		t.Error("Expected esc to close the error detail panel")
+	}
+	if m.LastError() == nil {
+		t.Error("Expected the last error to persist after closing the panel")
+	}
+}
+
+func TestHardCapBlocksGeneration(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "hard_cap_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	
+	model := NewModel().WithConfig(&config.Config{MaxContextTokens: 10})
+	model.scanRoot = tempDir
+	scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	model.scanResult = scanMsg.Result
+	
+	contextMsg := model.generateContext()().(ContextGeneratedMsg)
+	if contextMsg.Result != nil || contextMsg.Error == nil {
+		t.Fatal("Expected generation above the hard cap to be blocked")
+	}
+	
+	updated, _ := model.Update(contextMsg)
+	m := updated.(Model)
+	if m.showingResult {
+		t.Error("Expected no result view for a blocked context")
+	}
+	
+	view := m.View()
+	for _, expected := range []string{"Context Too Large", "tokens (limit 10)", "Lower max_file_size"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected blocked screen to contain %q", expected)
+		}
+	}
+	
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.limitExceeded != nil || m.loadingState != StateMenu {
+		t.Error("Expected esc to dismiss the blocked screen and return to the menu")
+	}
+}
+
+func TestPauseKeyPausesRunningScan(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	
+	model, _ := NewModel().handleFolderSelected(FolderSelectedMsg{Folder: &folder.FolderNode{Path: tempDir, Name: "project"}})
+	if model.scanner == nil {
+		t.Fatal("Expected the model to hold the running scanner")
+	}
+	scanner := model.scanner
+	pause := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+	
+	updated, _ := model.Update(pause)
+	model = updated.(Model)
+	if !scanner.IsPaused() {
+		t.Fatal("Expected p to pause the scanner")
+	}
+	if !strings.Contains(model.View(), "Scan paused") {
+		t.Error("Expected the loading view to show the paused state")
+	}
+	
+	updated, _ = model.Update(pause)
+	model = updated.(Model)
+	if scanner.IsPaused() || strings.Contains(model.View(), "Scan paused") {
+		t.Error("Expected a second p to resume the scan")
+	}
+}
+
+func TestScanningHomeDirectoryRequiresConfirmation(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	os.WriteFile(filepath.Join(home, "notes.txt"), []byte("private\n"), 0644)
+	
+	model, _ := NewModel().handleFolderSelected(FolderSelectedMsg{Folder: &folder.FolderNode{Path: home, Name: "home"}})
+	if model.pendingScan == nil {
+		t.Fatal("Expected scanning the home directory to wait for confirmation")
+	}
+	if model.loadingState != StateMenu || model.scanner != nil {
+		t.Error("Expected no scan to start before confirmation")
+	}
+	if !strings.Contains(model.View(), "Scan your home directory?") {
+		t.Error("Expected a warning naming the home directory")
+	}
+	
+	// Declining leaves everything idle
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	declined := updated.(Model)
+	if declined.pendingScan != nil || declined.loadingState != StateMenu {
+		t.Error("Expected n to cancel the held scan")
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updated.(Model)
+	if model.pendingScan != nil || model.loadingState != StateScanning {
+		t.Error("Expected y to start the held scan")
+	}
+	
+	if reason := sensitiveRootReason(filepath.Join(home, "project")); reason != "" {
+		t.Errorf("Expected a project below home to be allowed, got %q", reason)
+	}
+	if reason := sensitiveRootReason("/"); reason == "" {
+		t.Error("Expected the filesystem root to be flagged")
+	}
+}
+
+func TestExtensionPickerLimitsScanToSelectedTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "docs"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "util.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "docs", "guide.md"), []byte("# Guide\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("notes\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "data.json"), []byte("{}\n"), 0644)
+	
+	model := NewModel()
+	countMsg := model.countExtensions(tempDir)().(ExtensionCountsMsg)
+	if countMsg.Error != nil {
+		t.Fatalf("Count pass failed: %v", countMsg.Error)
+	}
+	if countMsg.Counts[".go"] != 2 || countMsg.Counts[".md"] != 1 {
+		t.Errorf("Unexpected extension counts %v", countMsg.Counts)
+	}
+	
+	updated, _ := model.Update(countMsg)
+	model = updated.(Model)
+	if model.extensionPicker == nil || !strings.Contains(model.View(), "Choose File Types") {
+		t.Fatal("Expected the extension picker to open after the count pass")
+	}
+	
+	// Untick everything, then tick .go and .md
+	press := func(key tea.KeyMsg) {
+		updated, _ := model.Update(key)
+		model = updated.(Model)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	for i, count := range model.extensionPicker.counts {
+		if count.Extension == ".go" || count.Extension == ".md" {
+			model.extensionPicker.cursor = i
+			press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+		}
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	if model.extensionPicker != nil || model.loadingState != StateScanning || model.scanner == nil {
+		t.Fatal("Expected enter to start a scan with the selected types")
+	}
+	result, err := model.scanner.Scan(stdcontext.Background())
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(result.Extensions) != 2 || result.Extensions[".go"] != 2 || result.Extensions[".md"] != 1 {
+		t.Errorf("Expected only .go and .md files, got %v", result.Extensions)
+	}
+}
+
+func TestResetRestoresDefaultModels(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	defaults := append([]types.AIModel(nil), cfg.Models...)
+	
+	cfg.Models = append(cfg.Models, types.AIModel{Name: "my-local-llm", Provider: "ollama"})
+	cfg.DefaultModel = "my-local-llm"
+	cfg.MaxFileSize = 1024
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	model.excludedExtensions = []string{".json"}
+	press := func(r rune) {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(Model)
+	}
+	
+	press('D')
+	if !model.confirmingReset || !strings.Contains(model.View(), "Reset to Defaults") {
+		t.Fatal("Expected D to ask for confirmation")
+	}
+	press('a')
+	if model.confirmingReset {
+		t.Error("Expected the confirmation to close after choosing")
+	}
+	
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if len(reloaded.Models) != len(defaults) || reloaded.Models[0].Name != defaults[0].Name {
+		t.Errorf("Expected persisted default models %v, got %v", defaults, reloaded.Models)
+	}
+	if reloaded.DefaultModel != "" || reloaded.MaxFileSize != 0 {
+		t.Errorf("Expected default model and scan overrides cleared, got %q and %d", reloaded.DefaultModel, reloaded.MaxFileSize)
+	}
+	if len(model.excludedExtensions) != 0 {
+		t.Error("Expected session exclusions to be cleared")
+	}
+}
+
+func TestExportToReadOnlyOutputDir(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	readOnly := filepath.Join(t.TempDir(), "readonly")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatalf("Failed to create read-only dir: %v", err)
+	}
+	if checkWritable(readOnly) == nil {
+		// Permissions are not enforced (e.g. running as root); a path below a file never is writable
+		blocker := filepath.Join(t.TempDir(), "blocker")
+		if err := os.WriteFile(blocker, []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		readOnly = filepath.Join(blocker, "out")
+	}
+	cfg.OutputDir = readOnly
+	
+	model := NewModel().WithConfig(cfg)
+	result := &context.ContextResult{ProjectName: "demo"}
+	
+	updated, _ := model.Update(ContextPreviewMsg{Type: "save_requested", Data: result})
+	model = updated.(Model)
+	
+	if !strings.Contains(model.toastManager.View(), "Output directory not writable: "+readOnly) {
+		t.Errorf("Expected a not writable toast, got %q", model.toastManager.View())
+	}
+	if model.pendingExport == nil || !strings.Contains(model.View(), "Pick another directory") {
+		t.Fatal("Expected an offer to pick another directory")
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updated.(Model)
+	if !model.pickingOutputDir || model.folderBrowser == nil {
+		t.Fatal("Expected y to open the directory picker")
+	}
+	
+	writable := t.TempDir()
+	updated, _ = model.Update(FolderBrowserMsg{Type: "folder_selected", Data: &folder.FolderNode{Path: writable}})
+	model = updated.(Model)
+	if model.pendingExport != nil || model.pickingOutputDir || model.exportPrompt == nil {
+		t.Fatal("Expected the file name prompt after picking a directory")
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if _, err := os.Stat(filepath.Join(writable, "demo-context.md")); err != nil {
+		t.Errorf("Expected context written to picked directory: %v", err)
+	}
+	if cfg.OutputDir != writable {
+		t.Errorf("Expected output dir %q remembered, got %q", writable, cfg.OutputDir)
+	}
+}
+
+func TestDashboardShowsProjectStatsAndModel(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	if model.showingDashboard {
+		t.Fatal("Expected the menu to be the default home screen")
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'H'}})
+	model = updated.(Model)
+	if !model.showingDashboard {
+		t.Fatal("Expected H to switch to the dashboard")
+	}
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil || !reloaded.DashboardHome {
+		t.Error("Expected the dashboard choice to be saved")
+	}
+	
+	model.contextResult = &context.ContextResult{
+		ProjectName:   "demo",
+		TotalFiles:    42,
+		TotalSize:     2048,
+		TokenEstimate: 1500,
+		GeneratedAt:   time.Now(),
+	}
+	active, _ := cfg.ActiveModel()
+	updated, _ = model.Update(ModelStatusMsg{Result: providers.TestResult{ModelName: active.Name, Success: true, Latency: 120 * time.Millisecond}})
+	model = updated.(Model)
+	
+	view := model.View()
+	for _, want := range []string{"42 files", "~1.5K tokens", "Model: " + active.Name, "✓ connected (120ms)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected dashboard to contain %q", want)
+		}
+	}	
+	// A failed test shows its error and remediation hint in the model panel
+	updated, _ = model.Update(ModelStatusMsg{Result: providers.TestResult{
+		ModelName:  active.Name,
+		StatusCode: http.StatusUnauthorized,
+		Error:      errors.New("endpoint returned 401 Unauthorized"),
+	}})
+	model = updated.(Model)
+	view = model.View()
+	for _, want := range []string{"✗ failed", "Error: endpoint returned 401 Unauthorized", "Hint: Check your API key"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected dashboard to contain %q", want)
+		}
+	}
+}
+
+// manualTicker is a refresh ticker driven by the test
+type manualTicker chan time.Time
+
+func (t manualTicker) C() <-chan time.Time { return t }
+func (t manualTicker) Stop()               {}
+
+func TestModelConnectionTestsReachDashboard(t *testing.T) {
+	cfg, err := config.LoadProfile(t.TempDir(), config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.StatusRefreshSecs = 60
+	active, _ := cfg.ActiveModel()
+	
+	model := NewModel()
+	model.testModel = func(m types.AIModel) providers.TestResult {
+		return providers.TestResult{ModelName: m.Name, Success: m.Name == active.Name, Latency: 80 * time.Millisecond, Timestamp: time.Now()}
+	}
+	model = model.WithConfig(cfg)
+	model.showingDashboard = true
+	
+	// T tests the active model and announces the result
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	model = updated.(Model)
+	var status tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(ModelStatusMsg); ok {
+			status = msg
+		}
+	}
+	if status == nil {
+		t.Fatal("Expected T to test the active model")
+	}
+	updated, _ = model.Update(status)
+	model = updated.(Model)
+	if view := model.View(); !strings.Contains(view, "✓ connected (80ms) · checked") {
+		t.Error("Expected the manual test result and when it was checked on the dashboard")
+	}
+	if recorded, ok := model.statusRefresher.Status(active.Name); !ok || !recorded.Success {
+		t.Error("Expected the manual result to be shared with the refresher")
+	}
+	
+	// Background refreshes keep arriving through Init's listener
+	ticker := make(manualTicker, 1)
+	model.statusRefresher.SetTicker(func(time.Duration) providers.Ticker { return ticker })
+	defer model.statusRefresher.Close()
+	listen := model.Init()
+	ticker <- time.Now()
+	msg, ok := listen().(ModelStatusMsg)
+	if !ok {
+		t.Fatal("Expected a refreshed status from the background refresher")
+	}
+	updated, next := model.Update(msg)
+	model = updated.(Model)
+	if _, ok := model.modelStatuses[msg.Result.ModelName]; !ok || next == nil {
+		t.Error("Expected the refreshed status to be stored and the listener renewed")
+	}
+	
+	// Switching profiles stops the old refresher and starts one for the new models
+	work, err := config.LoadProfile(cfg.ConfigDir, "work")
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+	work.StatusRefreshSecs = 60
+	if err := work.Save(); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	old := model.statusRefresher
+	model, _ = model.switchToNextProfile()
+	defer model.Close()
+	if model.appConfig.Profile != "work" || model.statusRefresher == nil || model.statusRefresher == old {
+		t.Fatal("Expected a new refresher for the new profile")
+	}
+	if msg := next(); msg != nil {
+		t.Errorf("Expected the old refresher's listener to end, got %v", msg)
+	}
+}
+
+func TestScanProgressReachesUpdateLoop(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "util.go"), []byte("package main\n"), 0644)
+	
+	model := NewModel()
+	model.loadingState = StateScanning
+	model.spinner = model.spinner.Start()
+	model.scanner = model.newScanner(tempDir)
+	
+	// Scan first; the buffered channel holds every update for the listener to replay
+	updates := model.scanner.GetProgressChannel()
+	if msg := scanCmd(stdcontext.Background(), model.scanner)().(ScanCompleteMsg); msg.Error != nil {
+		t.Fatalf("Scan failed: %v", msg.Error)
+	}
+	
+	sawFile := false
+	received := 0
+	cmd := listenForScanProgress(updates)
+	for cmd != nil {
+		msg := cmd()
+		if msg == nil {
+			break
+		}
+		received++
+		var updated tea.Model
+		updated, cmd = model.Update(msg)
+		model = updated.(Model)
+		if strings.Contains(model.View(), "main.go") {
+			sawFile = true
+		}
+	}
+	
+	if received < 2 {
+		t.Fatalf("Expected several progress updates, got %d", received)
+	}
+	if !sawFile {
+		t.Error("Expected the current file name in the scanning view")
+	}
+	if !strings.Contains(model.progress.View(), "Scanning files (2/") {
+		t.Errorf("Expected live counts in the progress bar, got %q", model.progress.View())
+	}
+}
+
+func TestExportPromptNamesFileAndConfirmsOverwrite(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	model := NewModel().WithConfig(cfg)
+	
+	save := func(content string) {
+		result := &context.ContextResult{ProjectName: "demo", Sections: []context.ContextSection{{Content: content}}}
+		updated, _ := model.Update(ContextPreviewMsg{Type: "save_requested", Data: result})
+		model = updated.(Model)
+		if model.exportPrompt == nil {
+			t.Fatal("Expected save to ask for a file name")
+		}
+		for range model.exportPrompt.name {
+			updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+			model = updated.(Model)
+		}
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("notes.md")})
+		model = updated.(Model)
+		updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = updated.(Model)
+	}
+	
+	path := filepath.Join(configDir, "contexts", "notes.md")
+	save("first")
+	if data, err := os.ReadFile(path); err != nil || string(data) != "first" {
+		t.Fatalf("Expected context written to the default contexts folder, got %q (%v)", data, err)
+	}
+	if !strings.Contains(model.toastManager.View(), path) {
+		t.Errorf("Expected the success toast to show %s", path)
+	}
+	
+	save("second")
+	if model.exportPrompt == nil || !model.exportPrompt.confirmOverwrite {
+		t.Fatal("Expected an overwrite confirmation for an existing file")
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = updated.(Model)
+	if data, _ := os.ReadFile(path); string(data) != "second" {
+		t.Errorf("Expected the file to be overwritten, got %q", data)
+	}
+}
+
+// echoChatClient streams back the last message and records what it was sent.
+// With hold set it stops after the first chunk until the request is cancelled.
+type echoChatClient struct {
+	sent []*types.ChatSession
+	hold bool
+}
+
+func (c *echoChatClient) Send(ctx stdcontext.Context, session *types.ChatSession) (types.ChatMessage, error) {
+	c.sent = append(c.sent, session)
+	last := session.Messages[len(session.Messages)-1]
+	return types.ChatMessage{Role: chat.RoleAssistant, Content: "echo: " + last.Content}, nil
+}
+
+func (c *echoChatClient) Stream(ctx stdcontext.Context, session *types.ChatSession) (<-chan chat.Chunk, error) {
+	c.sent = append(c.sent, session)
+	last := session.Messages[len(session.Messages)-1]
+	chunks := make(chan chat.Chunk)
+	go func() {
+		defer close(chunks)
+		chunks <- chat.Chunk{Text: "echo: "}
+		if c.hold {
+			<-ctx.Done()
+			chunks <- chat.Chunk{Err: ctx.Err()}
+			return
+		}
+		chunks <- chat.Chunk{Text: last.Content}
+		chunks <- chat.Chunk{Done: true}
+	}()
+	return chunks, nil
+}
+
+// typeChatMessage types text into the open chat and sends it, returning the stream's first chunk
+func typeChatMessage(t *testing.T, model Model, text string) (Model, tea.Msg) {
+	t.Helper()
+	for _, r := range text {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(Model)
+	}
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if !model.chat.waiting || cmd == nil {
+		t.Fatal("Expected enter to send the message")
+	}
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(ChatStreamMsg); ok {
+			return model, msg
+		}
+	}
+	t.Fatal("Expected enter to start streaming the reply")
+	return model, nil
+}
+
+func TestChatSendsContextToModel(t *testing.T) {
+	cfg, err := config.LoadProfile(t.TempDir(), config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	client := &echoChatClient{}
+	model := NewModel().WithConfig(cfg)
+	model.newChatClient = func(types.AIModel) (chat.Client, error) { return client, nil }
+	model.contextResult = &context.ContextResult{
+		ProjectName: "demo",
+		Sections:    []context.ContextSection{{Title: "Overview", Content: "# Overview\n\nA demo project\n"}},
+		GeneratedAt: time.Now(),
+	}
+	
+	model, _ = model.handleMenuAction(4)
+	if model.chat == nil {
+		t.Fatal("Expected the chat menu item to open the chat screen")
+	}
+	if !strings.Contains(model.chat.session.Context, "A demo project") {
+		t.Error("Expected the current context to be attached to the session")
+	}
+	
+	model, msg := typeChatMessage(t, model, "hi there")
+	updated, cmd := model.Update(msg)
+	model = updated.(Model)
+	if model.chat.reply != "echo: " || !strings.Contains(model.View(), "echo: ▌") {
+		t.Error("Expected the first chunk to render before the reply completes")
+	}
+	for cmd != nil {
+		updated, cmd = model.Update(cmd())
+		model = updated.(Model)
+	}
+	if model.chat.waiting {
+		t.Fatal("Expected the stream to finish")
+	}
+	
+	if len(client.sent) != 1 || !strings.Contains(client.sent[0].Context, "A demo project") {
+		t.Error("Expected the client to receive the session with its context")
+	}
+	if sent := (len(client.sent[0].Context) + len("hi there")) / 4; model.usage.Operations() != 1 || model.usage.Tokens() != sent {
+		t.Errorf("Expected the sent message counted as %d tokens, got %d over %d", sent, model.usage.Tokens(), model.usage.Operations())
+	}
+	active, _ := cfg.ActiveModel()
+	if recent := cfg.RecentModels(); len(recent) != 1 || recent[0].Name != active.Name {
+		t.Errorf("Expected sending a message to mark %s as recently used, got %+v", active.Name, recent)
+	}
+	if saved, _ := config.LoadProfile(cfg.ConfigDir, config.DefaultProfile); len(saved.RecentModels()) != 1 {
+		t.Error("Expected the last use to be saved")
+	}
+	view := model.View()
+	for _, want := range []string{"hi there", "echo: hi there", "Context attached: demo"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected chat view to contain %q", want)
+		}
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+	if model.chat.session.Context != "" {
+		t.Error("Expected ctrl+t to detach the context")
+	}
+	
+	// ESC stops a reply mid-stream and keeps the text received so far
+	client.hold = true
+	model, msg = typeChatMessage(t, model, "again")
+	updated, cmd = model.Update(msg)
+	model = updated.(Model)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.chat == nil || model.chat.waiting {
+		t.Fatal("Expected esc to stop the stream without closing the chat")
+	}
+	messages := model.chat.session.Messages
+	if last := messages[len(messages)-1]; last.Content != "echo: [stopped]" {
+		t.Errorf("Expected the partial reply to be kept, got %q", last.Content)
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(Model)
+	if len(model.chat.session.Messages) != len(messages) {
+		t.Error("Expected chunks from a stopped stream to be ignored")
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.chat != nil {
+		t.Error("Expected esc to close the chat")
+	}
+}
+
+func TestCheckedPathsScanTogether(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, file := range []string{"api/handler.go", "web/app.js", "docs/guide.md"} {
+		os.MkdirAll(filepath.Join(tempDir, filepath.Dir(file)), 0755)
+		os.WriteFile(filepath.Join(tempDir, file), []byte("content\n"), 0644)
+	}
+	
+	model := NewModel()
+	browser, err := folder.NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser: %v", err)
+	}
+	model.folderBrowser = browser
+	model.showingBrowser = true
+	root := browser.GetTree().GetPath()
+	
+	paths := []string{filepath.Join(root, "api"), filepath.Join(root, "docs", "guide.md")}
+	updated, cmd := model.Update(folder.BrowserMsg{Type: "paths_selected", Data: paths})
+	model = updated.(Model)
+	if cmd == nil || model.showingBrowser || model.loadingState != StateScanning {
+		t.Fatal("Expected the checked paths to start a scan")
+	}
+	if model.scanRoot != root || len(model.scanPaths) != 2 {
+		t.Errorf("Expected the scan to keep the browser root and checked paths, got %s %v", model.scanRoot, model.scanPaths)
+	}
+	
+	scanMsg := model.startFolderScan(model.scanRoot)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	if scanMsg.Result.TotalFiles != 2 {
+		t.Errorf("Expected only the 2 checked files to be scanned, got %d", scanMsg.Result.TotalFiles)
+	}
+	for _, file := range scanMsg.Result.Files {
+		if strings.Contains(file.Path, "web") {
+			t.Errorf("Expected unchecked folders to be skipped, got %s", file.Path)
+		}
+	}
+}
+
+// memoryKeyring keeps keys in a map
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Available() bool { return true }
+func (k memoryKeyring) Get(provider string) (string, error) {
+	if key, ok := k[provider]; ok {
+		return key, nil
+	}
+	return "", keyring.ErrNotFound
+}
+func (k memoryKeyring) Set(provider, key string) error { k[provider] = key; return nil }
+func (k memoryKeyring) Delete(provider string) error   { delete(k, provider); return nil }
+
+func TestAPIKeyScreenStoresKeysInKeychain(t *testing.T) {
+	for _, name := range []string{"OPENAI_API_KEY", "ANTHROPIC_API_KEY", "GEMINI_API_KEY", "GOOGLE_API_KEY"} {
+		t.Setenv(name, "")
+	}
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.Models[0].APIKey = "sk-plaintext-in-config-9999"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	
+	keys := memoryKeyring{}
+	model := NewModel().WithConfig(cfg)
+	model.keyring = keys
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'K'}})
+	view := model.View()
+	if model.apiKeys == nil || !strings.Contains(view, "API Keys") {
+		t.Fatal("Expected K to open the API key screen")
+	}
+	if strings.Contains(view, "sk-plaintext-in-config-9999") || !strings.Contains(view, "sk-…9999") {
+		t.Errorf("Expected the plaintext key to be shown masked, got %q", view)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sk-typed-into-the-screen-4321")})
+	if strings.Contains(model.View(), "sk-typed") {
+		t.Error("Expected the key to be hidden while typing")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	if keys["openai"] != "sk-typed-into-the-screen-4321" {
+		t.Errorf("Expected the key in the keychain, got %v", keys)
+	}
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	for _, m := range reloaded.Models {
+		if m.APIKey != "" {
+			t.Errorf("Expected plaintext keys removed from the config, got %q on %s", m.APIKey, m.Name)
+		}
+	}
+	if view := model.View(); !strings.Contains(view, "sk-…4321  (keychain)") {
+		t.Errorf("Expected the stored key to show as masked from the keychain, got %q", view)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if _, ok := keys["openai"]; ok {
+		t.Error("Expected d to delete the key")
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.apiKeys != nil {
+		t.Error("Expected esc to close the API key screen")
+	}
+}
+
+func TestModelEditorValidatesAndSaves(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	
+	keys := memoryKeyring{}
+	model := NewModel().WithConfig(cfg)
+	model.keyring = keys
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	typeText := func(text string) {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	if model.modelEditor == nil || !strings.Contains(model.View(), "Model Settings: gpt-3.5-turbo") {
+		t.Fatal("Expected E to open the settings form for the active model")
+	}
+	
+	// Move to retries and enter an out-of-range value
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	typeText("sk-editor-entered-key-5678")
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("42")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.modelEditor == nil || !strings.Contains(model.View(), "Retries must be between 0 and 10") {
+		t.Fatalf("Expected the form to stay open with a retries error, got %q", model.View())
+	}
+	if strings.Contains(model.View(), "sk-editor") {
+		t.Error("Expected the API key to be masked in the form")
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("3")
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	typeText("0.5")
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.modelEditor != nil {
+		t.Fatalf("Expected a valid form to save and close, got %q", model.View())
+	}
+	
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	saved := reloaded.Models[0]
+	if saved.Retries != 3 || saved.Temperature == nil || *saved.Temperature != 0.5 || saved.APIKey != "" {
+		t.Errorf("Expected retries and temperature saved without a plaintext key, got %+v", saved)
+	}
+	if keys["openai"] != "sk-editor-entered-key-5678" {
+		t.Errorf("Expected the key in the keychain, got %v", keys)
+	}
+}
+
+func TestResumeLastSessionRestoresContext(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+	
+	model := NewModel().WithConfig(cfg)
+	model.scanRoot = projectDir
+	scanMsg := model.startFolderScan(projectDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	model.scanResult = scanMsg.Result
+	contextMsg := model.generateContext()().(ContextGeneratedMsg)
+	
+	model, _ = model.handleContextGenerated(contextMsg)
+	if result := model.saveSession()().(SessionSavedMsg); result.Error != nil {
+		t.Fatalf("Saving the session failed: %v", result.Error)
+	}
+	
+	// A fresh run resumes without scanning
+	resumed := NewModel().WithConfig(cfg)
+	resumed.cursor = 5
+	updated, _ := resumed.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	resumed = updated.(Model)
+	if resumed.contextResult == nil || !resumed.showingResult {
+		t.Fatal("Expected Resume Last Session to open the saved context")
+	}
+	if resumed.scanRoot != projectDir || resumed.contextResult.TokenEstimate != contextMsg.Result.TokenEstimate {
+		t.Errorf("Expected the saved scan root and context, got %s and ~%d tokens", resumed.scanRoot, resumed.contextResult.TokenEstimate)
+	}
+	if resumed.scanResult == nil || resumed.scanResult.TotalFiles != scanMsg.Result.TotalFiles {
+		t.Error("Expected the saved scan result to be restored")
+	}
+}
+
+func TestContextLibraryKeepsExports(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	result := &context.ContextResult{
+		ProjectName:   "api",
+		TokenEstimate: 250,
+		Sections:      []context.ContextSection{{Title: "Overview", Content: "# api overview\n"}},
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	model, _ = model.writeExport(result, context.MarkdownFormatter{}, filepath.Join(t.TempDir(), "api.md"))
+	
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			updated, _ := model.Update(key)
+			model = updated.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	
+	model.cursor = 6
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.library == nil || len(model.library.entries) != 1 {
+		t.Fatalf("Expected the export in the library, got %+v", model.library)
+	}
+	if entry := model.library.entries[0]; entry.Name != "api.md" || entry.Project != "api" || entry.Tokens != 250 {
+		t.Errorf("Expected the export's metadata, got %+v", entry)
+	}
+	
+	press(runes("v"))
+	if !strings.Contains(model.library.preview, "# api overview") {
+		t.Errorf("Expected an inline preview, got %q", model.library.preview)
+	}
+	
+	press(runes("r"), tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyBackspace}, runes(" v1"), tea.KeyMsg{Type: tea.KeyEnter})
+	if model.library.renaming || model.library.entries[0].Name != "api v1" {
+		t.Errorf("Expected the entry to be renamed, got %+v", model.library.entries[0])
+	}
+	
+	// Loading replaces the current context and opens the preview
+	model.contextResult = nil
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.library != nil || !model.showingPreview || model.contextResult == nil || model.contextResult.TokenEstimate != 250 {
+		t.Fatal("Expected the saved context to open in the preview screen")
+	}
+	
+	model.showingPreview = false
+	model.contextPreview = nil
+	model, _ = model.openLibrary()
+	if model.library.entries[0].Name != "api v1" {
+		t.Errorf("Expected the new name to persist, got %q", model.library.entries[0].Name)
+	}
+	press(runes("d"), runes("n"))
+	if len(model.library.entries) != 1 {
+		t.Fatal("Expected declining the confirmation to keep the entry")
+	}
+	press(runes("d"), runes("y"))
+	if len(model.library.entries) != 0 {
+		t.Errorf("Expected the entry to be deleted, got %+v", model.library.entries)
+	}
+	if entries, _ := library.Open(configDir).List(); len(entries) != 0 {
+		t.Errorf("Expected the library on disk to be empty, got %+v", entries)
+	}
+}
+
+func TestContextFromGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "util.go"), []byte("package main\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "initial commit")
+	os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	
+	model := NewModel()
+	model.scanRoot = repo
+	model.cursor = 7
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if model.gitDiffPicker == nil || len(model.gitDiffPicker.refs) < 3 {
+		t.Fatalf("Expected the base picker with HEAD, main and a commit, got %+v", model.gitDiffPicker)
+	}
+	
+	// A typed ref is used instead of the list
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("/")}, {Type: tea.KeyRunes, Runes: []rune("main")}} {
+		updated, _ = model.Update(key)
+		model = updated.(Model)
+	}
+	if !model.gitDiffPicker.typing || model.gitDiffPicker.input != "main" {
+		t.Fatalf("Expected to be typing a ref, got %+v", model.gitDiffPicker)
+	}
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if model.gitDiffPicker != nil || cmd == nil || !model.busy() {
+		t.Fatal("Expected generation to start")
+	}
+	
+	msg := model.generateGitDiffContext(model.scanRoot, "main")().(ContextGeneratedMsg)
+	if msg.Error != nil {
+		t.Fatalf("Generating from the diff failed: %v", msg.Error)
+	}
+	model, _ = model.handleContextGenerated(msg)
+	markdown := model.contextResult.Markdown()
+	if !strings.Contains(markdown, "Changes since main") || !strings.Contains(markdown, "+func main() {}") {
+		t.Errorf("Expected the change list and diff, got:\n%s", markdown)
+	}
+	if strings.Contains(markdown, "util.go") {
+		t.Error("Expected unchanged files to be left out")
+	}
+	if model.scanResult != nil || !model.showingResult {
+		t.Error("Expected the diff context to replace the scan and show the result")
+	}
+}
+
+func TestPromptTemplatesRenderIntoExports(t *testing.T) {
+	cfg, err := config.LoadProfile(t.TempDir(), config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	result := &context.ContextResult{
+		ProjectName: "api",
+		TotalFiles:  3,
+		Sections:    []context.ContextSection{{Title: "Overview", Content: "# api overview\n"}},
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	model.contextResult = result
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			updated, _ := model.Update(key)
+			model = updated.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	
+	defaults := len(cfg.ContextTemplates)
+	model.cursor = 8
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.promptScreen == nil {
+		t.Fatal("Expected the prompt template screen to open")
+	}
+	
+	// A new template with a custom variable; a broken template is refused
+	press(runes("n"), runes("Review"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	model.promptScreen.form[2] = "Review {{.ProjectName}} for {{.Focus"
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.promptScreen.mode != promptEdit || model.promptScreen.formErr == "" {
+		t.Fatalf("Expected a syntax error, got %+v", model.promptScreen)
+	}
+	press(runes("}}"), tea.KeyMsg{Type: tea.KeyCtrlN}, runes("{{.Context}}"), tea.KeyMsg{Type: tea.KeyEnter})
+	if model.promptScreen.mode != promptList || len(cfg.ContextTemplates) != defaults+1 {
+		t.Fatalf("Expected the template to be saved, got %+v (%q)", cfg.ContextTemplates, model.promptScreen.formErr)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.promptScreen.mode != promptValues || len(model.promptScreen.variables) != 1 || model.promptScreen.variables[0] != "Focus" {
+		t.Fatalf("Expected to be asked for Focus, got %+v", model.promptScreen)
+	}
+	press(runes("security"), tea.KeyMsg{Type: tea.KeyEnter})
+	rendered := model.promptScreen.rendered
+	if !strings.HasPrefix(rendered, "Review api for security\n") || !strings.Contains(rendered, "# api overview") {
+		t.Fatalf("Expected the rendered preview, got:\n%s", rendered)
+	}
+	
+	press(runes("a"))
+	if model.promptScreen != nil || model.activePrompt == nil {
+		t.Fatal("Expected the template to be applied")
+	}
+	
+	dir := t.TempDir()
+	model, _ = model.writeExport(result, context.MarkdownFormatter{}, filepath.Join(dir, "api.md"))
+	model, _ = model.writeExport(result, context.JSONFormatter{}, filepath.Join(dir, "api.json"))
+	markdown, _ := os.ReadFile(filepath.Join(dir, "api.md"))
+	if !strings.HasPrefix(string(markdown), "Review api for security\n") {
+		t.Errorf("Expected the markdown export to use the prompt, got:\n%s", markdown)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "api.json")); strings.Contains(string(data), "Review api") {
+		t.Error("Expected JSON exports to be left as data")
+	}
+	
+	// Deleting the applied template clears it
+	model.cursor = 8
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	for model.promptScreen.cursor < defaults {
+		press(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	press(runes("d"), runes("y"))
+	if len(cfg.ContextTemplates) != defaults || model.activePrompt != nil {
+		t.Errorf("Expected the template and active prompt to be gone, got %+v", model.activePrompt)
+	}
+}
+
+func TestWindowSizeAdaptsViews(t *testing.T) {
+	model := NewModel()
+	if model.viewWidth() != defaultViewWidth {
+		t.Errorf("Expected the default width before a resize, got %d", model.viewWidth())
+	}
+	
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	model = updated.(Model)
+	if model.viewWidth() != 60 || model.boxWidth(80) != 56 || model.boxWidth(50) != 50 {
+		t.Errorf("Expected views to fit 60 columns, got width %d and box %d", model.viewWidth(), model.boxWidth(80))
+	}
+	
+	// Only the buttons that fit are shown, scrolled to the cursor
+	start, end := model.visibleMenuItems()
+	if start != 0 || end-start != (30-menuChrome)/menuButtonLines {
+		t.Errorf("Expected a window of menu buttons, got %d-%d", start, end)
+	}
+	model.cursor = len(model.menuItems) - 1
+	if start, end = model.visibleMenuItems(); end != len(model.menuItems) || start == 0 {
+		t.Errorf("Expected the window to follow the cursor, got %d-%d", start, end)
+	}
+	view := model.View()
+	if !strings.Contains(view, "more") || strings.Contains(view, model.menuItems[0].Title) {
+		t.Errorf("Expected the menu to scroll to the cursor, got:\n%s", view)
+	}
+	
+	// The open preview is resized along with the app
+	model.contextResult = &context.ContextResult{
+		ProjectName: "api",
+		Sections:    []context.ContextSection{{Title: "Overview", Content: "# api overview\n"}},
+	}
+	model, _ = model.openContextPreview()
+	updated, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updated.(Model)
+	if model.viewWidth() != 120 || model.resultSectionsVisible() != 40-resultViewChrome {
+		t.Errorf("Expected the new size to apply, got width %d", model.viewWidth())
+	}
+	if !strings.Contains(model.View(), "Overview") {
+		t.Error("Expected the resized preview to render")
+	}
+}
+
+func TestMouseSelectsMenuButtons(t *testing.T) {
+	model := NewModel()
+	buttonRow := func(index int) int {
+		row := strings.Count(model.renderTopBar(), "\n") + strings.Count(model.renderMenuHeader(), "\n")
+		for i := 0; i < index; i++ {
+			row += lipgloss.Height(model.createButton(model.menuItems[i], i, i == model.cursor))
+		}
+		return row + 1
+	}
+	click := func(y int) tea.Cmd {
+		updated, cmd := model.Update(tea.MouseMsg{Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		model = updated.(Model)
+		return cmd
+	}
+	
+	// The clicked row is the button drawn there
+	lines := strings.Split(model.View(), "\n")
+	if !strings.Contains(lines[buttonRow(3)], strings.TrimSpace(model.menuItems[3].Title[4:])) {
+		t.Fatalf("Expected row %d to show %q, got %q", buttonRow(3), model.menuItems[3].Title, lines[buttonRow(3)])
+	}
+	
+	click(buttonRow(3))
+	if model.cursor != 3 {
+		t.Fatalf("Expected a click to select button 3, got %d", model.cursor)
+	}
+	click(buttonRow(2) + 2)
+	if model.cursor != 2 {
+		t.Errorf("Expected a click on a button's border to hit it, got %d", model.cursor)
+	}
+	
+	updated, _ := model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown})
+	model = updated.(Model)
+	if model.cursor != 3 {
+		t.Errorf("Expected the wheel to move the selection, got %d", model.cursor)
+	}
+	
+	// Clicking the selected button activates it
+	click(buttonRow(len(model.menuItems) - 1))
+	if cmd := click(buttonRow(len(model.menuItems) - 1)); cmd == nil {
+		t.Fatal("Expected clicking the selected Exit button to quit")
+	} else if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected clicking the selected Exit button to quit")
+	}
+	
+	// Dialogs keep the keyboard
+	model.cursor = 0
+	model.confirmingReset = true
+	click(buttonRow(3))
+	if model.cursor != 0 {
+		t.Error("Expected clicks behind a dialog to be ignored")
+	}
+}
+
+func TestBookmarksAndRecentFoldersPersist(t *testing.T) {
+	configDir := t.TempDir()
+	projectDir := t.TempDir()
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+	
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	model := NewModel().WithConfig(cfg)
+	browser, err := model.newFolderBrowser(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to open browser: %v", err)
+	}
+	model.folderBrowser = browser
+	model.showingBrowser = true
+	
+	updated, _ := model.Update(folder.BrowserMsg{
+		Type: "toggle_bookmark",
+		Data: &folder.FolderNode{Path: projectDir, IsDir: true},
+	})
+	model = updated.(Model)
+	if !strings.Contains(model.folderBrowser.View(), "1 ⭐") {
+		t.Error("Expected the browser to offer the new bookmark")
+	}
+	
+	updated, _ = model.Update(FolderBrowserMsg{Type: "folder_selected", Data: &folder.FolderNode{Name: "project", Path: projectDir, IsDir: true}})
+	model = updated.(Model)
+	
+	reloaded, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if !reloaded.IsBookmarked(projectDir) {
+		t.Errorf("Expected the bookmark to persist, got %v", reloaded.Bookmarks)
+	}
+	if len(reloaded.RecentFolders) != 1 || reloaded.RecentFolders[0] != projectDir {
+		t.Errorf("Expected the scanned folder in the recent list, got %v", reloaded.RecentFolders)
+	}
+}
+
+func TestSkippedPathsWarning(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.Symlink(filepath.Join(tempDir, "missing.go"), filepath.Join(tempDir, "broken.go"))
+	
+	model := NewModel()
+	model.scanRoot = tempDir
+	scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg)
+	if scanMsg.Error != nil {
+		t.Fatalf("Scan failed: %v", scanMsg.Error)
+	}
+	updated, _ := model.Update(scanMsg)
+	model = updated.(Model)
+	if !strings.Contains(model.toastManager.View(), "1 path skipped due to an error") {
+		t.Error("Expected the scan toast to mention the skipped path")
+	}
+	
+	contextMsg := model.generateContext()().(ContextGeneratedMsg)
+	updated, _ = model.Update(contextMsg)
+	model = updated.(Model)
+	if view := model.View(); !strings.Contains(view, "1 path skipped due to an error • W: details") {
+		t.Errorf("Expected a skipped paths warning on the result view, got:\n%s", view)
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	model = updated.(Model)
+	if view := model.View(); !model.showingScanErrors || !strings.Contains(view, "broken.go: ") {
+		t.Errorf("Expected W to list the skipped path with its error, got:\n%s", view)
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.showingScanErrors {
+		t.Error("Expected ESC to close the skipped paths list")
+	}
+}
+
+func TestCancelLoadingOperation(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	
+	model := NewModel()
+	model.scanRoot = tempDir
+	model.loadingState = StateScanning
+	model.scanner = model.newScanner(tempDir)
+	scan := model.startFolderScan(tempDir)
+	
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.loadingState != StateMenu || model.scanner != nil {
+		t.Errorf("Expected ESC to cancel the scan and return to the menu, got state %v", model.loadingState)
+	}
+	if !strings.Contains(model.toastManager.View(), "Scan cancelled") {
+		t.Error("Expected a scan cancelled toast")
+	}
+	
+	// The cancelled scan's result arrives late and is dropped
+	scanMsg := scan().(ScanCompleteMsg)
+	if !errors.Is(scanMsg.Error, stdcontext.Canceled) {
+		t.Fatalf("Expected the cancelled scan to report context.Canceled, got %v", scanMsg.Error)
+	}
+	updated, _ = model.Update(scanMsg)
+	model = updated.(Model)
+	if model.loadingState != StateMenu || model.lastError != nil {
+		t.Error("Expected the cancelled scan's result to be ignored")
+	}
+	
+	// Later operations run under a fresh context
+	if scanMsg := model.startFolderScan(tempDir)().(ScanCompleteMsg); scanMsg.Error != nil {
+		t.Errorf("Expected a new scan after cancelling to succeed, got %v", scanMsg.Error)
+	}
+}
+
+func TestCompareCurrentWithLastSaved(t *testing.T) {
+	configDir := t.TempDir()
+	cfg, err := config.LoadProfile(configDir, config.DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	saved := &context.ContextResult{
+		ProjectName:   "api",
+		TokenEstimate: 10,
+		Sections:      []context.ContextSection{{Title: "GO Files Content", Content: "main.go", Files: []string{"main.go"}}},
+	}
+	if _, err := library.Open(configDir).Add(saved, "before", "markdown", ""); err != nil {
+		t.Fatalf("Failed to save context: %v", err)
+	}
+	
+	model := NewModel().WithConfig(cfg)
+	model, _ = model.openCompare()
+	if model.compare != nil {
+		t.Fatal("Expected comparing with a single context to be refused")
+	}
+	
+	model.contextResult = &context.ContextResult{
+		ProjectName:   "api",
+		TokenEstimate: 25,
+		Sections: []context.ContextSection{{Title: "GO Files Content", Content: "main.go server.go",
+			Files: []string{"main.go", "server.go"}}},
+	}
+	model.cursor = 9
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if model.compare == nil || model.compare.choices() != 2 {
+		t.Fatalf("Expected the current and saved contexts to be offered, got %+v", model.compare)
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	model = updated.(Model)
+	view := model.View()
+	for _, want := range []string{"before (", "→ Current context", "+15", "~ GO Files Content", "+ server.go"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the comparison, got:\n%s", want, view)
+		}
+	}
+	
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.compare != nil {
+		t.Error("Expected ESC to leave the comparison, then close the screen")
+	}
+}
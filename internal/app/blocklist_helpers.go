@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/gitdiff"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// projectRoot returns the canonical root whose blocklist applies to the current session
+func (m Model) projectRoot() string {
+	root := m.scanRoot
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		return resolved
+	}
+	return root
+}
+
+// blocklistProject returns the project whose blocklist covers a scan root: its git
+// toplevel, else the directory the app runs in when the root lies inside it, else
+// the root itself. Scans of subfolders thereby share the project's list.
+func blocklistProject(root string) string {
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if top, err := gitdiff.Root(root); err == nil {
+		if resolved, err := filepath.EvalSymlinks(top); err == nil {
+			return resolved
+		}
+		return top
+	}
+	
+	if cwd, err := os.Getwd(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+			cwd = resolved
+		}
+		if insideRoot(cwd, root) {
+			return cwd
+		}
+	}
+	return root
+}
+
+// insideRoot reports whether path is root or lies below it
+func insideRoot(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// blocklistPatterns loads the persisted "do not include" patterns of the project
+// containing a scan root
+func blocklistPatterns(cfg *config.Config, rootPath string) []string {
+	if cfg == nil || cfg.ConfigDir == "" {
+		return nil
+	}
+	
+	blocklist, err := config.LoadBlocklist(cfg.ConfigDir, blocklistProject(rootPath))
+	if err != nil {
+		return nil
+	}
+	return blocklist.Patterns()
+}
+
+// toggleNeverInclude adds a path to the project's persisted blocklist, or takes
+// it off again if it is already there
+func (m Model) toggleNeverInclude(path string) (Model, tea.Cmd) {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	root := blocklistProject(m.projectRoot())
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	entry, err := filepath.Rel(root, path)
+	if err != nil || entry == "." || !insideRoot(root, path) {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("%s is outside the project", path), feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	message := fmt.Sprintf("%s will never be included", entry)
+	blocklist, err := config.LoadBlocklist(m.appConfig.ConfigDir, root)
+	if err == nil {
+		if blocklist.Remove(entry) {
+			message = fmt.Sprintf("%s can be included again", entry)
+		} else {
+			blocklist.Add(entry)
+		}
+		err = config.SaveBlocklist(m.appConfig.ConfigDir, blocklist)
+	}
+	if err != nil {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("Failed to update blocklist: %v", err), feedback.ToastError)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	toastManager, toastCmd := m.toastManager.AddToast(message, feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
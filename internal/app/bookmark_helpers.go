@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/folder"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newFolderBrowser opens a folder browser at path offering the saved bookmarks and recent folders
+func (m Model) newFolderBrowser(path string) (*folder.BrowserModel, error) {
+	browser, err := folder.NewBrowserModel(path)
+	if err != nil {
+		return nil, err
+	}
+	if m.appConfig != nil {
+		browser.SetQuickPicks(m.appConfig.Bookmarks, m.appConfig.RecentFolders)
+	}
+	browser.SetMinimalChrome(m.minimalChrome)
+	return browser, nil
+}
+
+// closeFolderBrowser dismisses the folder browser, stopping its background work
+func (m Model) closeFolderBrowser() Model {
+	if m.folderBrowser != nil {
+		m.folderBrowser.Close()
+	}
+	m.showingBrowser = false
+	m.folderBrowser = nil
+	return m
+}
+
+// rememberFolder records a folder picked for scanning; failing to save only
+// loses the history, so it is logged rather than shown
+func (m Model) rememberFolder(path string) {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" {
+		return
+	}
+	
+	m.appConfig.AddRecentFolder(path)
+	if err := m.appConfig.Save(); err != nil {
+		m.eventLog.Record(events.EventError, "Failed to save recent folders: %v", err)
+	}
+}
+
+// toggleBookmark stars or unstars a folder and refreshes the browser's quick picks
+func (m Model) toggleBookmark(path string) (Model, tea.Cmd) {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	message := fmt.Sprintf("Removed bookmark %s", filepath.Base(path))
+	if m.appConfig.ToggleBookmark(path) {
+		message = fmt.Sprintf("⭐ Bookmarked %s", filepath.Base(path))
+	}
+	if m.folderBrowser != nil {
+		m.folderBrowser.SetQuickPicks(m.appConfig.Bookmarks, m.appConfig.RecentFolders)
+	}
+	
+	toastType := feedback.ToastSuccess
+	if err := m.appConfig.Save(); err != nil {
+		toastType = feedback.ToastWarning
+		message = fmt.Sprintf("%s (not saved: %v)", message, err)
+	}
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
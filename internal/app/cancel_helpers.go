@@ -0,0 +1,76 @@
+package app
+
+import (
+	stdcontext "context"
+	"errors"
+	"sync"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cancelScope hands out the context scans and generations run under. It is
+// shared by every copy of the model so a key press can stop work started by
+// an earlier update.
+type cancelScope struct {
+	mu     sync.Mutex
+	ctx    stdcontext.Context
+	cancel stdcontext.CancelFunc
+}
+
+func newCancelScope() *cancelScope {
+	return &cancelScope{}
+}
+
+// context returns the context for new work, starting a fresh one once the
+// previous has been cancelled
+func (s *cancelScope) context() stdcontext.Context {
+	if s == nil {
+		return stdcontext.Background()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx == nil || s.ctx.Err() != nil {
+		s.ctx, s.cancel = stdcontext.WithCancel(stdcontext.Background())
+	}
+	return s.ctx
+}
+
+// cancelAll stops everything running under the current context
+func (s *cancelScope) cancelAll() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// isCancelled reports whether err comes from a cancelled operation, whose
+// late result should be dropped
+func isCancelled(err error) bool {
+	return errors.Is(err, stdcontext.Canceled)
+}
+
+// cancelOperation stops the running scan or generation and returns to the menu
+func (m Model) cancelOperation() (Model, tea.Cmd) {
+	m.operations.cancelAll()
+
+	message := "Generation cancelled"
+	eventType := events.EventGeneration
+	if m.loadingState == StateScanning {
+		message = "Scan cancelled"
+		eventType = events.EventScanComplete
+	}
+	m.scanner = nil
+	m.eventLog.Record(eventType, "%s: %s", message, m.scanRoot)
+
+	m = m.resetToMenu()
+	m.showingResult = false
+	toastManager, toastCmd := m.toastManager.AddToast(message, feedback.ToastWarning)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
@@ -0,0 +1,348 @@
+package app
+
+import (
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/chat"
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/keyring"
+	"ai-context-cli/internal/navigation"
+	"ai-context-cli/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chatTimeout bounds a single chat request
+const chatTimeout = 2 * time.Minute
+
+// chatVisibleMessages is how many recent messages the chat screen shows
+const chatVisibleMessages = 12
+
+// chatState is the open chat screen: the conversation, the line being typed
+// and the reply being streamed in, if any
+type chatState struct {
+	session         *types.ChatSession
+	client          chat.Client
+	input           string
+	waiting         bool
+	contextAttached bool
+	
+	// The reply streamed so far, which request it answers and how to abort it
+	reply    string
+	streamID int
+	cancel   stdcontext.CancelFunc
+}
+
+// ChatStreamMsg carries the next chunk of a streamed reply
+type ChatStreamMsg struct {
+	Chunk    chat.Chunk
+	streamID int
+	updates  <-chan chat.Chunk
+}
+
+// chatClientFor returns the chat client for a model, honouring a test override
+func (m Model) chatClientFor(model types.AIModel) (chat.Client, error) {
+	if m.newChatClient != nil {
+		return m.newChatClient(model)
+	}
+	return chat.NewClient(model, nil)
+}
+
+// openChat starts a conversation with the active model, attaching the current context if any
+func (m Model) openChat() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No model configured. Select a model first.", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	model, ok := m.appConfig.ActiveModel()
+	if !ok {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No model configured. Select a model first.", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+
+	model = keyring.WithKey(m.keyring, model)
+	client, err := m.chatClientFor(model)
+	if err != nil {
+		return m.reportError("Cannot start chat", err, "Model: "+model.Name)
+	}
+
+	state := &chatState{
+		session: chat.NewSession(model, ""),
+		client:  client,
+	}
+	m.chat = state
+	m.navStack = m.navStack.Push(navigation.ChatScreen)
+	m.currentScreen = "chat"
+	m.eventLog.Record(events.EventNavigation, "Opened %s", m.currentScreen)
+
+	if m.contextResult != nil {
+		return m.toggleChatContext()
+	}
+	return m, nil
+}
+
+// toggleChatContext attaches the current ContextResult as the system message, or detaches it
+func (m Model) toggleChatContext() (Model, tea.Cmd) {
+	state := *m.chat
+	session := *state.session
+
+	if state.contextAttached {
+		session.Context = ""
+		state.contextAttached = false
+	} else {
+		if m.contextResult == nil {
+			toastManager, toastCmd := m.toastManager.AddToast(
+				"No context available. Please scan files first.", feedback.ToastWarning)
+			m.toastManager = toastManager
+			return m, toastCmd
+		}
+		formatted, err := m.configuredFormatter().Format(m.contextResult)
+		if err != nil {
+			return m.reportError("Failed to attach context", err)
+		}
+		if formatted, err = m.applyPrompt(formatted, m.contextResult); err != nil {
+			return m.reportError("Failed to attach context", err, "Prompt template: "+m.activePrompt.template.Name)
+		}
+		session.Context = formatted
+		state.contextAttached = true
+	}
+
+	state.session = &session
+	m.chat = &state
+	return m, nil
+}
+
+// handleChatKeys edits the message being typed, sends it on enter and closes the chat on esc
+func (m Model) handleChatKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	state := *m.chat
+
+	switch msg.String() {
+	case "esc":
+		if state.waiting {
+			return m.stopChatStream("stopped"), nil
+		}
+		m.chat = nil
+		return m.resetToMenu(), nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "ctrl+t":
+		if state.waiting {
+			return m, nil
+		}
+		return m.toggleChatContext()
+	case "enter":
+		text := strings.TrimSpace(state.input)
+		if text == "" || state.waiting {
+			return m, nil
+		}
+		session := *state.session
+		session.Messages = append(append([]types.ChatMessage(nil), session.Messages...),
+			types.ChatMessage{Role: chat.RoleUser, Content: text})
+		state.session = &session
+		state.input = ""
+		state.waiting = true
+		state.reply = ""
+		state.streamID++
+		
+		ctx, cancel := stdcontext.WithTimeout(stdcontext.Background(), chatTimeout)
+		state.cancel = cancel
+		m.chat = &state
+		m.eventLog.Record(events.EventChat, "Sent message to %s", session.Model.Name)
+		m.recordUsage(chatRequestChars(&session), session.Model)
+		m = m.recordModelUse(session.Model.Name)
+
+		m.spinner = m.spinner.SetMessage(fmt.Sprintf("Waiting for %s...", session.Model.Name)).Start()
+		return m, tea.Batch(m.spinner.InitSpinner(), startChatStream(ctx, state.client, state.session, state.streamID))
+	case "backspace":
+		if len(state.input) > 0 {
+			runes := []rune(state.input)
+			state.input = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			state.input += string(msg.Runes)
+		}
+	}
+
+	m.chat = &state
+	return m, nil
+}
+
+// chatRequestChars measures what a send transmits: the attached context and
+// the whole conversation, which every request repeats
+func chatRequestChars(session *types.ChatSession) int {
+	chars := len(session.Context)
+	for _, message := range session.Messages {
+		chars += len(message.Content)
+	}
+	return chars
+}
+
+// startChatStream opens a streamed reply to the session and waits for its first chunk
+func startChatStream(ctx stdcontext.Context, client chat.Client, session *types.ChatSession, streamID int) tea.Cmd {
+	return func() tea.Msg {
+		updates, err := client.Stream(ctx, session)
+		if err != nil {
+			return ChatStreamMsg{Chunk: chat.Chunk{Err: err}, streamID: streamID}
+		}
+		return readChatChunk(updates, streamID)
+	}
+}
+
+// listenForChatChunk waits for the next chunk of a streamed reply
+func listenForChatChunk(updates <-chan chat.Chunk, streamID int) tea.Cmd {
+	return func() tea.Msg {
+		return readChatChunk(updates, streamID)
+	}
+}
+
+// readChatChunk blocks for one chunk; a closed channel reads as the end of the reply
+func readChatChunk(updates <-chan chat.Chunk, streamID int) tea.Msg {
+	chunk, ok := <-updates
+	if !ok {
+		chunk = chat.Chunk{Done: true}
+	}
+	return ChatStreamMsg{Chunk: chunk, streamID: streamID, updates: updates}
+}
+
+// handleChatStream appends a streamed chunk to the reply and keeps listening until it ends
+func (m Model) handleChatStream(msg ChatStreamMsg) (Model, tea.Cmd) {
+	// Chunks from a stopped stream arrive late and are dropped
+	if m.chat == nil || !m.chat.waiting || msg.streamID != m.chat.streamID {
+		return m, nil
+	}
+	state := *m.chat
+	
+	if msg.Chunk.Err != nil {
+		m.chat = &state
+		m = m.stopChatStream("")
+		// Keys can appear in request URLs echoed by transport errors
+		err := errors.New(keyring.MaskIn(msg.Chunk.Err.Error(), state.session.Model.APIKey))
+		return m.reportError("Chat request failed", err, "Model: "+state.session.Model.Name)
+	}
+	
+	state.reply += msg.Chunk.Text
+	m.chat = &state
+	if msg.Chunk.Done {
+		m = m.stopChatStream("")
+		m.eventLog.Record(events.EventChat, "Reply received from %s", state.session.Model.Name)
+		return m, nil
+	}
+	return m, listenForChatChunk(msg.updates, msg.streamID)
+}
+
+// stopChatStream ends the reply in progress, keeping whatever text has arrived.
+// A non-empty note is appended to the kept reply to show it was cut short.
+func (m Model) stopChatStream(note string) Model {
+	state := *m.chat
+	if state.cancel != nil {
+		state.cancel()
+	}
+	
+	reply := state.reply
+	if note != "" && reply != "" {
+		reply = strings.TrimRight(reply, " ") + fmt.Sprintf(" [%s]", note)
+	}
+	if reply != "" {
+		session := *state.session
+		session.Messages = append(append([]types.ChatMessage(nil), session.Messages...),
+			types.ChatMessage{Role: chat.RoleAssistant, Content: reply})
+		state.session = &session
+	}
+	if note != "" {
+		m.eventLog.Record(events.EventChat, "Reply from %s %s", state.session.Model.Name, note)
+	}
+	
+	state.waiting = false
+	state.reply = ""
+	state.cancel = nil
+	m.chat = &state
+	m.spinner = m.spinner.Stop()
+	return m
+}
+
+// renderChat shows the conversation, the message being typed and the context status
+func (m Model) renderChat() string {
+	var result strings.Builder
+	state := m.chat
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	userStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	assistantStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#10B981"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151")).
+		Width(m.boxWidth(80))
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3B82F6")).
+		Padding(0, 1).
+		Width(m.boxWidth(80))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	model := state.session.Model
+	result.WriteString(titleStyle.Render(fmt.Sprintf("💬 Chat with %s (%s)", model.Name, model.Provider)))
+	result.WriteString("\n")
+	if state.contextAttached && m.contextResult != nil {
+		result.WriteString(instructionStyle.Render(fmt.Sprintf("📎 Context attached: %s (~%s tokens)",
+			m.contextResult.ProjectName, context.FormatNumber(m.contextResult.TokenEstimate))))
+	} else {
+		result.WriteString(instructionStyle.Render("No context attached"))
+	}
+	result.WriteString("\n\n")
+
+	messages := state.session.Messages
+	if len(messages) > chatVisibleMessages {
+		result.WriteString(instructionStyle.Render(fmt.Sprintf("… %d earlier messages", len(messages)-chatVisibleMessages)))
+		result.WriteString("\n\n")
+		messages = messages[len(messages)-chatVisibleMessages:]
+	}
+	for _, message := range messages {
+		if message.Role == chat.RoleUser {
+			result.WriteString(userStyle.Render("You"))
+		} else {
+			result.WriteString(assistantStyle.Render(model.Name))
+		}
+		result.WriteString("\n")
+		result.WriteString(textStyle.Render(message.Content))
+		result.WriteString("\n\n")
+	}
+
+	if state.waiting {
+		if state.reply == "" {
+			result.WriteString(m.spinner.View())
+		} else {
+			result.WriteString(assistantStyle.Render(model.Name))
+			result.WriteString("\n")
+			result.WriteString(textStyle.Render(state.reply + "▌"))
+		}
+		result.WriteString("\n\n")
+	}
+
+	result.WriteString(inputStyle.Render(state.input + "█"))
+	result.WriteString("\n")
+	if state.waiting {
+		result.WriteString(instructionStyle.Render("Receiving reply • ESC: stop"))
+	} else {
+		result.WriteString(instructionStyle.Render("Enter: send • Ctrl+T: attach/detach context • ESC: back to menu"))
+	}
+
+	return result.String()
+}
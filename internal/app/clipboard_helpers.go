@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+
+	"ai-context-cli/internal/clipboard"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/usage"
+	"ai-context-cli/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// copyContext copies the generated context wrapped in the active prompt,
+// saving it to a file when no clipboard exists
+func (m Model) copyContext() (Model, tea.Cmd) {
+	if m.contextResult == nil {
+		return m, nil
+	}
+	text, err := m.applyPrompt(m.contextResult.Markdown(), m.contextResult)
+	if err != nil {
+		return m.reportError("Copy failed", err, "Prompt template: "+m.activePrompt.template.Name)
+	}
+	return m.copyText(text, "Context copied to clipboard")
+}
+
+// copyFencedContext copies the context wrapped in a single fenced block for chat UIs
+func (m Model) copyFencedContext() (Model, tea.Cmd) {
+	if m.contextResult == nil {
+		return m, nil
+	}
+	return m.copyText(m.contextResult.FencedMarkdown(), "Context copied as a single fenced block")
+}
+
+// copyText copies text and records its tokens, falling back to a file without a clipboard
+func (m Model) copyText(text, successMessage string) (Model, tea.Cmd) {
+	result, err := clipboard.Copy(m.clipboard, text, "")
+	
+	var message string
+	toastType := feedback.ToastSuccess
+	switch {
+	case err != nil:
+		message = fmt.Sprintf("Copy failed: %v", err)
+		toastType = feedback.ToastError
+	case result.Fallback:
+		message = fmt.Sprintf("Clipboard unavailable, saved to %s", result.Path)
+		toastType = feedback.ToastInfo
+	default:
+		message = successMessage
+	}
+	m.lastCopy = result
+	if err == nil {
+		m.recordUsage(len(text), m.activeModel())
+	}
+	
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// activeModel returns the configured active model, or the zero model without one
+func (m Model) activeModel() types.AIModel {
+	if m.appConfig == nil {
+		return types.AIModel{}
+	}
+	model, _ := m.appConfig.ActiveModel()
+	return model
+}
+
+// recordUsage adds chars of copied, exported or sent text to the session totals,
+// at 4 characters per token like the generator's estimate and priced for model
+func (m Model) recordUsage(chars int, model types.AIModel) {
+	if m.usage == nil {
+		return
+	}
+	m.usage.SetCostPer1KTokens(model.TokenCost(usage.DefaultCostPer1KTokens))
+	m.usage.Record(chars / 4)
+}
+
+// renderSessionUsage renders the session token/cost line once something was copied
+func (m Model) renderSessionUsage() string {
+	if m.usage == nil || m.usage.Operations() == 0 {
+		return ""
+	}
+	
+	usageStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	return "\n" + centerText(usageStyle.Render(m.usage.Summary()), m.viewWidth())
+}
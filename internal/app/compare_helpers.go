@@ -0,0 +1,303 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/library"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compareVisibleLines is how many lines of a comparison are shown at once
+const compareVisibleLines = 20
+
+// compareScreen picks two contexts, newest first with the current context on
+// top, then shows how the older one became the newer
+type compareScreen struct {
+	entries    []library.Entry
+	hasCurrent bool // the current context is the first choice
+	cursor     int
+	base       int // first choice picked, -1 until one is
+
+	comparison *context.ContextComparison
+	oldName    string
+	newName    string
+	offset     int // first comparison line shown
+}
+
+// choices is how many contexts can be picked
+func (s compareScreen) choices() int {
+	if s.hasCurrent {
+		return len(s.entries) + 1
+	}
+	return len(s.entries)
+}
+
+// openCompare lists the current and saved contexts to compare
+func (m Model) openCompare() (Model, tea.Cmd) {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	entries, err := library.Open(m.appConfig.ConfigDir).List()
+	if err != nil {
+		return m.reportError("Failed to open context library", err)
+	}
+
+	screen := &compareScreen{entries: entries, hasCurrent: m.contextResult != nil, base: -1}
+	if screen.choices() < 2 {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"Nothing to compare: export a context, then generate or export another", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	m.compare = screen
+	m.eventLog.Record(events.EventNavigation, "Opened context comparison")
+	return m, nil
+}
+
+// handleCompareKeys picks the two contexts, then scrolls their comparison
+func (m Model) handleCompareKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	screen := *m.compare
+
+	if screen.comparison != nil {
+		switch msg.String() {
+		case "esc":
+			screen.comparison = nil
+			screen.base = -1
+			screen.offset = 0
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if screen.offset > 0 {
+				screen.offset--
+			}
+		case "down", "j":
+			if screen.offset < len(compareLines(*screen.comparison))-compareVisibleLines {
+				screen.offset++
+			}
+		}
+		m.compare = &screen
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		if screen.base >= 0 {
+			screen.base = -1
+			break
+		}
+		m.compare = nil
+		return m, nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		if screen.cursor > 0 {
+			screen.cursor--
+		}
+	case "down", "j":
+		if screen.cursor < screen.choices()-1 {
+			screen.cursor++
+		}
+	case "l":
+		// Current context against the last saved one
+		if screen.hasCurrent && len(screen.entries) > 0 {
+			m.compare = &screen
+			return m.compareChoices(1, 0)
+		}
+	case "enter", " ":
+		switch {
+		case screen.base < 0:
+			screen.base = screen.cursor
+		case screen.base == screen.cursor:
+			screen.base = -1
+		default:
+			// Choices are newest first, so the later one is the older context
+			older, newer := screen.base, screen.cursor
+			if older < newer {
+				older, newer = newer, older
+			}
+			m.compare = &screen
+			return m.compareChoices(older, newer)
+		}
+	}
+
+	m.compare = &screen
+	return m, nil
+}
+
+// compareChoices loads two choices and shows how older became newer
+func (m Model) compareChoices(older, newer int) (Model, tea.Cmd) {
+	screen := *m.compare
+	oldResult, oldName, err := m.compareChoice(screen, older)
+	if err != nil {
+		return m.reportError("Failed to load saved context", err, "Entry: "+oldName)
+	}
+	newResult, newName, err := m.compareChoice(screen, newer)
+	if err != nil {
+		return m.reportError("Failed to load saved context", err, "Entry: "+newName)
+	}
+
+	comparison := context.CompareContexts(oldResult, newResult)
+	screen.comparison = &comparison
+	screen.oldName = oldName
+	screen.newName = newName
+	screen.offset = 0
+	m.compare = &screen
+	m.eventLog.Record(events.EventNavigation, "Compared %s with %s", oldName, newName)
+	return m, nil
+}
+
+// compareChoice returns the context behind a choice and its display name
+func (m Model) compareChoice(screen compareScreen, index int) (*context.ContextResult, string, error) {
+	if screen.hasCurrent {
+		if index == 0 {
+			return m.contextResult, "Current context", nil
+		}
+		index--
+	}
+	entry := screen.entries[index]
+	name := fmt.Sprintf("%s (%s)", entry.Name, entry.SavedAt.Format("Jan 2 15:04"))
+	result, err := library.Open(m.appConfig.ConfigDir).Load(entry.ID)
+	return result, name, err
+}
+
+// compareLine is one styled line of a rendered comparison
+type compareLine struct {
+	text  string
+	color string
+	bold  bool
+}
+
+// compareLines lays out a comparison: each section with its token change,
+// then the files it gained, lost or changed
+func compareLines(comparison context.ContextComparison) []compareLine {
+	var lines []compareLine
+	for _, section := range comparison.Sections {
+		marker, color := "~", "#F59E0B"
+		switch section.Status {
+		case context.SectionAdded:
+			marker, color = "+", "#10B981"
+		case context.SectionRemoved:
+			marker, color = "-", "#EF4444"
+		case context.SectionUnchanged:
+			marker, color = "=", "#6B7280"
+		}
+		lines = append(lines, compareLine{
+			text: fmt.Sprintf("%s %-32s ~%s → ~%s tokens (%+d)", marker, section.Title,
+				context.FormatNumber(section.OldTokens), context.FormatNumber(section.NewTokens), section.TokenDelta()),
+			color: color,
+			bold:  section.Status != context.SectionUnchanged,
+		})
+		for _, file := range section.AddedFiles {
+			lines = append(lines, compareLine{text: "    + " + file, color: "#10B981"})
+		}
+		for _, file := range section.RemovedFiles {
+			lines = append(lines, compareLine{text: "    - " + file, color: "#EF4444"})
+		}
+		for _, file := range section.ChangedFiles {
+			lines = append(lines, compareLine{text: "    ~ " + file, color: "#F59E0B"})
+		}
+	}
+	return lines
+}
+
+// renderCompare lists the contexts to pick from, or the comparison once two are picked
+func (m Model) renderCompare() string {
+	var result strings.Builder
+	screen := m.compare
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	result.WriteString(titleStyle.Render("🔍 Compare Contexts"))
+	result.WriteString("\n\n")
+
+	if screen.comparison != nil {
+		comparison := *screen.comparison
+		result.WriteString(textStyle.Render(fmt.Sprintf("%s → %s", screen.oldName, screen.newName)))
+		result.WriteString("\n")
+		result.WriteString(mutedStyle.Render(fmt.Sprintf("~%s → ~%s tokens (%+d) • %d files added • %d removed",
+			context.FormatNumber(comparison.OldTokens), context.FormatNumber(comparison.NewTokens), comparison.TokenDelta(),
+			len(comparison.AddedFiles), len(comparison.RemovedFiles))))
+		result.WriteString("\n\n")
+
+		if !comparison.Changed() {
+			result.WriteString(mutedStyle.Render("The contexts are identical."))
+			result.WriteString("\n\n")
+		} else {
+			lines := compareLines(comparison)
+			end := screen.offset + compareVisibleLines
+			if end > len(lines) {
+				end = len(lines)
+			}
+			for _, line := range lines[screen.offset:end] {
+				style := lipgloss.NewStyle().Foreground(lipgloss.Color(line.color)).Bold(line.bold)
+				result.WriteString(style.Render(line.text))
+				result.WriteString("\n")
+			}
+			if end < len(lines) {
+				result.WriteString(mutedStyle.Render(fmt.Sprintf("↓ %d more", len(lines)-end)))
+				result.WriteString("\n")
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString(instructionStyle.Render("↑↓: scroll • ESC: pick again"))
+		return result.String()
+	}
+
+	for i := 0; i < screen.choices(); i++ {
+		var line string
+		if screen.hasCurrent && i == 0 {
+			line = fmt.Sprintf("%-32s %-20s %-12s ~%s tokens", "Current context (not saved)", m.contextResult.ProjectName,
+				"now", context.FormatNumber(m.contextResult.TokenEstimate))
+		} else {
+			index := i
+			if screen.hasCurrent {
+				index--
+			}
+			entry := screen.entries[index]
+			line = fmt.Sprintf("%-32s %-20s %-12s ~%s tokens", entry.Name, entry.Project,
+				entry.SavedAt.Format("Jan 2 15:04"), context.FormatNumber(entry.Tokens))
+		}
+
+		mark := "  "
+		if i == screen.base {
+			mark = "✓ "
+		}
+		if i == screen.cursor {
+			result.WriteString(selectedStyle.Render("▶ " + mark + line))
+		} else {
+			result.WriteString(textStyle.Render("  " + mark + line))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+
+	instructions := "↑↓: select • Enter: pick first context • ESC: back"
+	if screen.base >= 0 {
+		instructions = "↑↓: select • Enter: compare with ✓ • ESC: clear pick"
+	}
+	if screen.hasCurrent && len(screen.entries) > 0 {
+		instructions += " • L: current vs last saved"
+	}
+	result.WriteString(instructionStyle.Render(instructions))
+
+	return result.String()
+}
@@ -0,0 +1,282 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/keyring"
+	"ai-context-cli/internal/providers"
+	"ai-context-cli/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ModelStatusMsg reports the latest connection test result for a model
+type ModelStatusMsg struct {
+	Result providers.TestResult
+	
+	manual  bool                          // asked for with T, so the result is announced
+	updates <-chan providers.TestResult // background refresh results still to come
+}
+
+// handleModelStatus remembers a model's connection status for the dashboard,
+// announces the result of a test the user asked for and keeps listening for
+// background refreshes
+func (m Model) handleModelStatus(msg ModelStatusMsg) (Model, tea.Cmd) {
+	statuses := make(map[string]providers.TestResult, len(m.modelStatuses)+1)
+	for name, status := range m.modelStatuses {
+		statuses[name] = status
+	}
+	statuses[msg.Result.ModelName] = msg.Result
+	m.modelStatuses = statuses
+	
+	if msg.updates != nil {
+		return m, listenForModelStatus(msg.updates)
+	}
+	if !msg.manual {
+		return m, nil
+	}
+	if m.statusRefresher != nil {
+		m.statusRefresher.Record(msg.Result)
+	}
+	
+	result := msg.Result
+	message, toastType := fmt.Sprintf("%s connected (%dms)", result.ModelName, result.Latency.Milliseconds()), feedback.ToastSuccess
+	if !result.Success {
+		message, toastType = fmt.Sprintf("%s unreachable", result.ModelName), feedback.ToastWarning
+		if hint := result.Hint(); hint != "" {
+			message += ": " + hint
+		}
+	}
+	m.eventLog.Record(events.EventSettings, "Connection test: %s", message)
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// modelTester returns the connection test, honouring a test override
+func (m Model) modelTester() func(types.AIModel) providers.TestResult {
+	if m.testModel != nil {
+		return m.testModel
+	}
+	return providers.NewConnectionTester().Test
+}
+
+// testActiveModel checks the active model's connection in the background
+func (m Model) testActiveModel() (Model, tea.Cmd) {
+	var model types.AIModel
+	ok := false
+	if m.appConfig != nil {
+		model, ok = m.appConfig.ActiveModel()
+	}
+	if !ok {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No model configured. Select a model first.", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	model = keyring.WithKey(m.keyring, model)
+	test := m.modelTester()
+	toastManager, toastCmd := m.toastManager.AddToast(fmt.Sprintf("Testing %s...", model.Name), feedback.ToastInfo)
+	m.toastManager = toastManager
+	return m, tea.Batch(toastCmd, func() tea.Msg {
+		return ModelStatusMsg{Result: test(model), manual: true}
+	})
+}
+
+// newStatusRefresher re-tests the profile's models on its refresh interval,
+// or returns nil when background refresh is off
+func (m Model) newStatusRefresher(cfg *config.Config) *providers.StatusRefresher {
+	interval := cfg.StatusRefreshInterval()
+	if interval <= 0 || len(cfg.Models) == 0 {
+		return nil
+	}
+	keys, test := m.keyring, m.modelTester()
+	return providers.NewStatusRefresher(func(model types.AIModel) providers.TestResult {
+		return test(keyring.WithKey(keys, model))
+	}, cfg.Models, interval)
+}
+
+// startStatusRefresher starts background refreshes, if configured, and listens for them
+func (m Model) startStatusRefresher() tea.Cmd {
+	if m.statusRefresher == nil {
+		return nil
+	}
+	m.statusRefresher.Start()
+	return listenForModelStatus(m.statusRefresher.Updates())
+}
+
+// restartStatusRefresher replaces the background refresher once the profile or
+// its models change, stopping the old one
+func (m Model) restartStatusRefresher() (Model, tea.Cmd) {
+	m.Close()
+	m.statusRefresher = nil
+	if m.appConfig != nil {
+		m.statusRefresher = m.newStatusRefresher(m.appConfig)
+	}
+	return m, m.startStatusRefresher()
+}
+
+// Close stops the background status refresher; call it once the program exits
+func (m Model) Close() {
+	if m.statusRefresher != nil {
+		m.statusRefresher.Close()
+	}
+}
+
+// listenForModelStatus waits for the next background refresh result
+func listenForModelStatus(updates <-chan providers.TestResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return ModelStatusMsg{Result: result, updates: updates}
+	}
+}
+
+// toggleDashboard switches the home screen between the menu and the dashboard
+func (m Model) toggleDashboard() (Model, tea.Cmd) {
+	m.showingDashboard = !m.showingDashboard
+	if m.appConfig != nil {
+		m.appConfig.DashboardHome = m.showingDashboard
+		if err := m.appConfig.Save(); err != nil {
+			return m.reportError("Failed to save home screen setting", err)
+		}
+	}
+	
+	message := "Home screen: menu"
+	if m.showingDashboard {
+		message = "Home screen: dashboard"
+	}
+	toastManager, toastCmd := m.toastManager.AddToast(message, feedback.ToastInfo)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// modelIndicator describes the selected model and its last known connection status
+func (m Model) modelIndicator() string {
+	if m.appConfig == nil {
+		return "🤖 Model: none configured"
+	}
+	model, ok := m.appConfig.ActiveModel()
+	if !ok {
+		return "🤖 Model: none configured"
+	}
+	
+	status := "○ untested"
+	if result, tested := m.modelStatuses[model.Name]; tested {
+		status = result.Badge(time.Now())
+	}
+	return fmt.Sprintf("🤖 Model: %s (%s) %s", model.Name, model.Provider, status)
+}
+
+// modelFailureDetails explains why the active model's last connection test
+// failed and how to fix it, or returns "" when it did not fail
+func (m Model) modelFailureDetails() string {
+	if m.appConfig == nil {
+		return ""
+	}
+	model, ok := m.appConfig.ActiveModel()
+	if !ok {
+		return ""
+	}
+	result, tested := m.modelStatuses[model.Name]
+	if !tested || result.Success {
+		return ""
+	}
+	return result.Details()
+}
+
+// renderDashboard renders the single-screen overview used as an alternative home screen
+func (m Model) renderDashboard() string {
+	var result strings.Builder
+	
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#7D56F4")).
+		Padding(0, 2).
+		Width(m.boxWidth(70))
+	
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	
+	// Project
+	var project strings.Builder
+	project.WriteString(headingStyle.Render("Project"))
+	project.WriteString("\n")
+	project.WriteString(fmt.Sprintf("📁 %s", filepath.Base(m.projectRoot())))
+	
+	var types []string
+	if m.scanResult != nil {
+		types = m.scanResult.ProjectTypes
+	}
+	if len(types) > 0 {
+		project.WriteString(fmt.Sprintf(" • 🏷️ %s", strings.Join(types, ", ")))
+	}
+	project.WriteString("\n")
+	
+	switch {
+	case m.contextResult != nil:
+		project.WriteString(fmt.Sprintf("📊 %d files • 📄 %s • 🧠 ~%s tokens",
+			m.contextResult.TotalFiles,
+			context.FormatSize(m.contextResult.TotalSize),
+			context.FormatNumber(m.contextResult.TokenEstimate)))
+	case m.scanResult != nil:
+		project.WriteString(fmt.Sprintf("📊 %d files • 📄 %s",
+			m.scanResult.TotalFiles, context.FormatSize(m.scanResult.TotalSize)))
+	default:
+		project.WriteString(mutedStyle.Render("Not scanned yet"))
+	}
+	project.WriteString("\n\n")
+	
+	// Model
+	project.WriteString(headingStyle.Render("Model"))
+	project.WriteString("\n")
+	project.WriteString(m.modelIndicator())
+	project.WriteString("\n")
+	if details := m.modelFailureDetails(); details != "" {
+		project.WriteString(mutedStyle.Render(details))
+		project.WriteString("\n")
+	}
+	project.WriteString("\n")
+	
+	// Last generated context
+	project.WriteString(headingStyle.Render("Last Context"))
+	project.WriteString("\n")
+	if m.contextResult != nil {
+		project.WriteString(fmt.Sprintf("✨ %d sections • generated %s",
+			len(m.contextResult.Sections), m.contextResult.GeneratedAt.Format("15:04:05")))
+	} else {
+		project.WriteString(mutedStyle.Render("No context generated this session"))
+	}
+	project.WriteString("\n\n")
+	
+	// Quick actions reuse the main menu shortcuts
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	var actions []string
+	for _, action := range defaultQuickActions() {
+		if action.MenuIndex >= 0 {
+			actions = append(actions, fmt.Sprintf("%s %s", keyStyle.Render(action.Key), mutedStyle.Render(action.Label)))
+		}
+	}
+	project.WriteString(strings.Join(actions, mutedStyle.Render(" • ")))
+	
+	result.WriteString(centerText(boxStyle.Render(project.String()), m.viewWidth()))
+	result.WriteString("\n")
+	
+	return result.String()
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrorDetail is the full record of the last reported error
+type ErrorDetail struct {
+	Operation string
+	Err       error
+	Context   []string // extra lines such as the scan root
+	Time      time.Time
+}
+
+// reportError records err as the last error, logs it and shows a toast pointing at the detail panel
+func (m Model) reportError(operation string, err error, context ...string) (Model, tea.Cmd) {
+	m.lastError = &ErrorDetail{
+		Operation: operation,
+		Err:       err,
+		Context:   context,
+		Time:      time.Now(),
+	}
+	m.eventLog.Record(events.EventError, "%s: %v", operation, err)
+	
+	toastManager, toastCmd := m.toastManager.AddToast(
+		fmt.Sprintf("%s: %v (!: details)", operation, err), feedback.ToastError)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// LastError returns the most recently reported error, or nil
+func (m Model) LastError() *ErrorDetail {
+	return m.lastError
+}
+
+// renderErrorDetail renders the full text and context of the last error
+func (m Model) renderErrorDetail() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#EF4444"))
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#374151"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151")).
+		Width(m.boxWidth(90))
+	
+	result.WriteString(titleStyle.Render("❌ Error Details"))
+	result.WriteString("\n\n")
+	
+	detail := m.lastError
+	result.WriteString(labelStyle.Render("Operation: ") + detail.Operation + "\n")
+	result.WriteString(labelStyle.Render("Time: ") + detail.Time.Format("15:04:05") + "\n\n")
+	result.WriteString(labelStyle.Render("Error:"))
+	result.WriteString("\n")
+	result.WriteString(textStyle.Render(detail.Err.Error()))
+	result.WriteString("\n")
+	
+	// Wrapped errors show where the failure originated
+	var causes []string
+	for cause := errors.Unwrap(detail.Err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	if len(causes) > 0 {
+		result.WriteString("\n")
+		result.WriteString(labelStyle.Render("Caused by:"))
+		result.WriteString("\n")
+		for _, cause := range causes {
+			result.WriteString(textStyle.Render("↳ " + cause))
+			result.WriteString("\n")
+		}
+	}
+	
+	if len(detail.Context) > 0 {
+		result.WriteString("\n")
+		result.WriteString(labelStyle.Render("Context:"))
+		result.WriteString("\n")
+		for _, line := range detail.Context {
+			result.WriteString(textStyle.Render("• " + line))
+			result.WriteString("\n")
+		}
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("!/ESC: close error details"))
+	
+	return result.String()
+}
+
+// renderLimitExceeded explains which hard cap blocked generation and how to get under it
+func (m Model) renderLimitExceeded() string {
+	var result strings.Builder
+	limitErr := m.limitExceeded
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#F59E0B"))
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#374151"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	
+	result.WriteString(titleStyle.Render("🛑 Context Too Large"))
+	result.WriteString("\n\n")
+	result.WriteString(textStyle.Render("Generation was blocked because the context would exceed the configured hard cap."))
+	result.WriteString("\n\n")
+	
+	result.WriteString(labelStyle.Render("Exceeded:"))
+	result.WriteString("\n")
+	for _, violation := range limitErr.Violations {
+		result.WriteString(textStyle.Render("• " + violation))
+		result.WriteString("\n")
+	}
+	
+	result.WriteString("\n")
+	result.WriteString(labelStyle.Render("Try:"))
+	result.WriteString("\n")
+	for _, suggestion := range limitErr.Suggestions() {
+		result.WriteString(textStyle.Render("• " + suggestion))
+		result.WriteString("\n")
+	}
+	
+	// Point at the largest files, the usual culprits
+	if m.scanResult != nil && len(m.scanResult.LargestFiles) > 0 {
+		result.WriteString("\n")
+		result.WriteString(labelStyle.Render("Largest files:"))
+		result.WriteString("\n")
+		root := m.projectRoot()
+		for i, file := range m.scanResult.LargestFiles {
+			if i >= 5 {
+				break
+			}
+			name := file.Path
+			if relativePath, err := filepath.Rel(root, file.Path); err == nil {
+				name = relativePath
+			}
+			result.WriteString(textStyle.Render(fmt.Sprintf("• %s (%s)", name, context.FormatSize(file.Size))))
+			result.WriteString("\n")
+		}
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("Limits: max_context_files, max_context_bytes, max_context_tokens in config • ESC: back to menu"))
+	
+	return result.String()
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"strings"
+
+	"ai-context-cli/internal/events"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EventLog returns the in-app event log
+func (m Model) EventLog() *events.EventLog {
+	return m.eventLog
+}
+
+// renderEventLog renders the most recent events from the event log
+func (m Model) renderEventLog() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	result.WriteString(titleStyle.Render("🪵 Event Log"))
+	result.WriteString("\n\n")
+	
+	eventStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+	
+	recorded := m.eventLog.Events()
+	if len(recorded) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Italic(true)
+		result.WriteString(emptyStyle.Render("No events recorded yet"))
+		result.WriteString("\n")
+	}
+	
+	// Show the latest events that fit on screen
+	maxEvents := 20
+	if len(recorded) > maxEvents {
+		recorded = recorded[len(recorded)-maxEvents:]
+	}
+	
+	for _, event := range recorded {
+		style := eventStyle
+		if event.Type == events.EventError {
+			style = errorStyle
+		}
+		result.WriteString(style.Render(event.String()))
+		result.WriteString("\n")
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("Ctrl+L/ESC: close event log"))
+	
+	return result.String()
+}
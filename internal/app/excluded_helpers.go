@@ -0,0 +1,164 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// exclusionFilters lists the reason filters cycled in the inspector ("" shows all)
+var exclusionFilters = []string{"", "too large", "pattern", "empty", "binary", "lockfile", "generated", "duplicate", "unreadable", "other"}
+
+// excludedFiles returns the last scan's excluded files after filtering and sorting
+func (m Model) excludedFiles() []context.FileInfo {
+	var files []context.FileInfo
+	if m.scanResult == nil {
+		return files
+	}
+	
+	for _, file := range m.scanResult.Excluded {
+		if m.excludedFilter == "" || context.ExclusionKind(file.ExcludeReason) == m.excludedFilter {
+			files = append(files, file)
+		}
+	}
+	
+	sort.SliceStable(files, func(i, j int) bool {
+		if m.excludedBySize {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	
+	return files
+}
+
+// nextExclusionFilter returns the reason filter after the current one
+func (m Model) nextExclusionFilter() string {
+	for i, filter := range exclusionFilters {
+		if filter == m.excludedFilter {
+			return exclusionFilters[(i+1)%len(exclusionFilters)]
+		}
+	}
+	return ""
+}
+
+// handleExcludedKeys processes input in the excluded files inspector
+func (m Model) handleExcludedKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	files := m.excludedFiles()
+	
+	switch msg.String() {
+	case "esc", "e":
+		m.showingExcluded = false
+	case "up", "k":
+		if m.excludedCursor > 0 {
+			m.excludedCursor--
+		}
+	case "down", "j":
+		if m.excludedCursor < len(files)-1 {
+			m.excludedCursor++
+		}
+	case "s":
+		m.excludedBySize = !m.excludedBySize
+		m.excludedCursor = 0
+	case "r":
+		m.excludedFilter = m.nextExclusionFilter()
+		m.excludedCursor = 0
+	case "enter", " ":
+		if m.excludedCursor >= len(files) {
+			return m, nil
+		}
+		
+		file := files[m.excludedCursor]
+		m.forceIncluded = append(m.forceIncluded, file.Path)
+		m.showingExcluded = false
+		return m.startRescan(fmt.Sprintf("Force-including %s, regenerating...", filepath.Base(file.Path)))
+	}
+	
+	return m, nil
+}
+
+// renderExcludedInspector renders the excluded files inspector
+func (m Model) renderExcludedInspector() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	result.WriteString(centerText(titleStyle.Render("🚫 Excluded Files"), m.viewWidth()))
+	result.WriteString("\n")
+	
+	filter := m.excludedFilter
+	if filter == "" {
+		filter = "all reasons"
+	}
+	order := "path"
+	if m.excludedBySize {
+		order = "size"
+	}
+	metaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	result.WriteString(centerText(metaStyle.Render(fmt.Sprintf("Filter: %s • Sorted by %s", filter, order)), m.viewWidth()))
+	result.WriteString("\n\n")
+	
+	files := m.excludedFiles()
+	if len(files) == 0 {
+		result.WriteString(centerText(metaStyle.Render("No excluded files match"), m.viewWidth()))
+		result.WriteString("\n")
+	}
+	
+	for i, file := range files {
+		line := fmt.Sprintf("%-40s %10s  %s", filepath.Base(file.Path),
+			context.FormatSize(file.Size), context.ExclusionKind(file.ExcludeReason))
+		
+		var style lipgloss.Style
+		if i == m.excludedCursor {
+			style = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			style = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		result.WriteString(centerText(style.Render(line), m.viewWidth()))
+		result.WriteString("\n")
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(centerText(instructionStyle.Render("↑↓: select • s: sort by size • r: filter reason • Enter: force-include & regenerate • ESC: close"), m.viewWidth()))
+	
+	return result.String()
+}
+
+// explainExclusion reports whether a scan of the project would exclude path, and why
+func (m Model) explainExclusion(path string) (Model, tea.Cmd) {
+	root := m.projectRoot()
+	excluded, reason := m.scanConfig(root).ExplainExclusion(path)
+	
+	name := path
+	if relativePath, err := filepath.Rel(root, path); err == nil {
+		name = relativePath
+	}
+	
+	message := fmt.Sprintf("%s is included", name)
+	toastType := feedback.ToastInfo
+	if excluded {
+		message = fmt.Sprintf("%s is excluded: %s", name, reason)
+		toastType = feedback.ToastWarning
+	}
+	
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
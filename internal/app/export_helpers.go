@@ -0,0 +1,337 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/library"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// exportPrompt asks for the file name of an export and confirms overwrites
+type exportPrompt struct {
+	result           *context.ContextResult
+	formatter        context.Formatter
+	name             string
+	confirmOverwrite bool
+}
+
+// outputFormatter returns the format chosen in the preview, else the configured one
+func (m Model) outputFormatter() context.Formatter {
+	if m.contextPreview != nil {
+		return m.contextPreview.Formatter()
+	}
+	return m.configuredFormatter()
+}
+
+// configuredFormatter returns the output format from the config, Markdown if unset or unknown
+func (m Model) configuredFormatter() context.Formatter {
+	if m.appConfig == nil {
+		return context.MarkdownFormatter{}
+	}
+	formatter, _ := context.ParseFormat(m.appConfig.OutputFormat)
+	return formatter
+}
+
+// outputDir returns the directory exports are written to: the configured one,
+// else the contexts folder in the config directory, else the working directory
+func (m Model) outputDir() string {
+	if m.appConfig != nil {
+		if m.appConfig.OutputDir != "" {
+			return m.appConfig.OutputDir
+		}
+		if m.appConfig.ConfigDir != "" {
+			return filepath.Join(m.appConfig.ConfigDir, "contexts")
+		}
+	}
+	wd, _ := os.Getwd()
+	return wd
+}
+
+// checkWritable verifies that files can be created in dir by creating and removing one
+func checkWritable(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	
+	probe, err := os.CreateTemp(dir, ".ai-context-write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// exportFileName derives a safe file name from the project name and format extension
+func exportFileName(projectName, extension string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, projectName)
+	if name == "" {
+		name = "project"
+	}
+	return name + "-context" + extension
+}
+
+// exportPath resolves a typed name: absolute paths are used as given, anything
+// else is placed in the output directory
+func (m Model) exportPath(name string) string {
+	if strings.HasPrefix(name, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			name = filepath.Join(home, name[2:])
+		}
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(m.outputDir(), name)
+}
+
+// openExportPrompt checks the output directory and asks for a file name
+func (m Model) openExportPrompt(result *context.ContextResult) (Model, tea.Cmd) {
+	dir := m.outputDir()
+	if m.appConfig != nil && m.appConfig.OutputDir == "" {
+		// The default contexts folder is created on first use
+		os.MkdirAll(dir, 0755)
+	}
+	if err := checkWritable(dir); err != nil {
+		return m.outputDirNotWritable(result, dir, err)
+	}
+	
+	formatter := m.outputFormatter()
+	m.exportPrompt = &exportPrompt{
+		result:    result,
+		formatter: formatter,
+		name:      exportFileName(result.ProjectName, formatter.Extension()),
+	}
+	return m, nil
+}
+
+// outputDirNotWritable reports an unwritable directory and offers to pick another
+func (m Model) outputDirNotWritable(result *context.ContextResult, dir string, err error) (Model, tea.Cmd) {
+	m.pendingExport = result
+	m.eventLog.Record(events.EventError, "Output directory not writable: %s (%v)", dir, err)
+	
+	toastManager, toastCmd := m.toastManager.AddToast(
+		fmt.Sprintf("Output directory not writable: %s", dir), feedback.ToastError)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// handleExportPromptKeys edits the file name and confirms overwriting existing files
+func (m Model) handleExportPromptKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	prompt := *m.exportPrompt
+	
+	if prompt.confirmOverwrite {
+		switch msg.String() {
+		case "y", "Y":
+			m.exportPrompt = nil
+			return m.writeExport(prompt.result, prompt.formatter, m.exportPath(prompt.name))
+		case "n", "N", "esc":
+			prompt.confirmOverwrite = false
+			m.exportPrompt = &prompt
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+	
+	switch msg.String() {
+	case "enter":
+		if strings.TrimSpace(prompt.name) == "" {
+			return m, nil
+		}
+		if _, err := os.Stat(m.exportPath(prompt.name)); err == nil {
+			prompt.confirmOverwrite = true
+			m.exportPrompt = &prompt
+			return m, nil
+		}
+		m.exportPrompt = nil
+		return m.writeExport(prompt.result, prompt.formatter, m.exportPath(prompt.name))
+	case "esc":
+		m.exportPrompt = nil
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "backspace":
+		if len(prompt.name) > 0 {
+			runes := []rune(prompt.name)
+			prompt.name = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			prompt.name += string(msg.Runes)
+		}
+	}
+	
+	m.exportPrompt = &prompt
+	return m, nil
+}
+
+// writeExport formats the context and writes it to path after checking its directory is writable
+func (m Model) writeExport(result *context.ContextResult, formatter context.Formatter, path string) (Model, tea.Cmd) {
+	dir := filepath.Dir(path)
+	if err := checkWritable(dir); err != nil {
+		return m.outputDirNotWritable(result, dir, err)
+	}
+	
+	formatted, err := formatter.Format(result)
+	if err != nil {
+		return m.reportError("Export failed", err, "Format: "+formatter.Name())
+	}
+	if formatter.Name() == "markdown" || formatter.Name() == "text" {
+		// JSON and XML exports stay machine-readable, so prompts only wrap prose formats
+		if formatted, err = m.applyPrompt(formatted, result); err != nil {
+			return m.reportError("Export failed", err, "Prompt template: "+m.activePrompt.template.Name)
+		}
+	}
+	if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+		return m.reportError("Export failed", err, "Output file: "+path)
+	}
+	m.eventLog.Record(events.EventExport, "Context exported to %s", path)
+	m.recordUsage(len(formatted), m.activeModel())
+	
+	// Keep a copy in the library; the export itself already succeeded, so a failure is only logged
+	if m.appConfig != nil && m.appConfig.ConfigDir != "" {
+		if _, err := library.Open(m.appConfig.ConfigDir).Add(result, filepath.Base(path), formatter.Name(), path); err != nil {
+			m.eventLog.Record(events.EventError, "Context not added to library: %v", err)
+		}
+	}
+	
+	toastManager, toastCmd := m.toastManager.AddToast(fmt.Sprintf("Context saved to %s", path), feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// handleUnwritableDirKeys offers to pick another output directory after a failed export
+func (m Model) handleUnwritableDirKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y", "enter":
+		start, _ := os.Getwd()
+		browser, err := m.newFolderBrowser(start)
+		if err != nil {
+			m.pendingExport = nil
+			return m.reportError("Error initializing folder browser", err)
+		}
+		m.pickingOutputDir = true
+		m.folderBrowser = browser
+		m.showingBrowser = true
+		m = m.applyWindowSize()
+		return m, browser.Init()
+	case "n", "N", "esc":
+		m.pendingExport = nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// setOutputDir stores a picked output directory and asks for the file name again
+func (m Model) setOutputDir(path string) (Model, tea.Cmd) {
+	m.pickingOutputDir = false
+	m = m.closeFolderBrowser()
+	
+	pending := m.pendingExport
+	m.pendingExport = nil
+	if m.appConfig == nil {
+		// Without a config the directory can't be remembered; write straight there
+		if pending == nil {
+			return m, nil
+		}
+		formatter := m.outputFormatter()
+		return m.writeExport(pending, formatter, filepath.Join(path, exportFileName(pending.ProjectName, formatter.Extension())))
+	}
+	
+	m.appConfig.OutputDir = path
+	if err := m.appConfig.Save(); err != nil {
+		return m.reportError("Failed to save output directory", err)
+	}
+	if pending == nil {
+		return m, nil
+	}
+	return m.openExportPrompt(pending)
+}
+
+// renderExportPrompt shows the file name being entered or the overwrite question
+func (m Model) renderExportPrompt() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3B82F6")).
+		Padding(0, 1).
+		Width(m.boxWidth(60))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	
+	path := m.exportPath(m.exportPrompt.name)
+	
+	if m.exportPrompt.confirmOverwrite {
+		warningStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#F59E0B"))
+		result.WriteString(warningStyle.Render("⚠️ File Already Exists"))
+		result.WriteString("\n\n")
+		result.WriteString(textStyle.Render(path))
+		result.WriteString("\n\n")
+		result.WriteString(instructionStyle.Render("Y: overwrite • N/ESC: choose another name"))
+		return result.String()
+	}
+	
+	result.WriteString(titleStyle.Render(fmt.Sprintf("💾 Save Context (%s)", m.exportPrompt.formatter.Name())))
+	result.WriteString("\n\n")
+	result.WriteString(inputStyle.Render(m.exportPrompt.name + "█"))
+	result.WriteString("\n")
+	result.WriteString(textStyle.Render("→ " + path))
+	result.WriteString("\n\n")
+	result.WriteString(instructionStyle.Render("Type a file name or absolute path • Enter: save • ESC: cancel"))
+	
+	return result.String()
+}
+
+// renderUnwritableDir explains the failed export and offers another directory
+func (m Model) renderUnwritableDir() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#EF4444"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	pathStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	
+	result.WriteString(titleStyle.Render("📁 Output Directory Not Writable"))
+	result.WriteString("\n\n")
+	result.WriteString(pathStyle.Render(m.outputDir()))
+	result.WriteString("\n\n")
+	result.WriteString(textStyle.Render("The context was not saved. Pick another directory to save it there;\nthe choice is remembered as output_dir in your config."))
+	result.WriteString("\n\n")
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString(instructionStyle.Render("Y: pick another directory • N/ESC: cancel"))
+	
+	return result.String()
+}
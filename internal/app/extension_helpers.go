@@ -0,0 +1,263 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ExtensionContribution represents how much content an extension adds to the context
+type ExtensionContribution struct {
+	Extension string
+	Files     int
+	Chars     int
+	Excluded  bool
+}
+
+// ScanConfig builds the scan configuration the user's config file and project
+// blocklist imply for a root, shared by the TUI and the command line
+func ScanConfig(cfg *config.Config, rootPath string) context.ScanConfig {
+	scanConfig := context.DefaultScanConfig(rootPath)
+	if cfg == nil {
+		return scanConfig
+	}
+	scanConfig.ExcludePatterns = append(scanConfig.ExcludePatterns, blocklistPatterns(cfg, scanConfig.RootPath)...)
+	if cfg.MaxFileSize > 0 {
+		scanConfig.MaxFileSize = cfg.MaxFileSize
+	}
+	if cfg.IncludeGenerated {
+		scanConfig.SkipGenerated = false
+	}
+	return scanConfig
+}
+
+// scanConfig builds the scan configuration including user exclusions
+func (m Model) scanConfig(rootPath string) context.ScanConfig {
+	config := ScanConfig(m.appConfig, rootPath)
+	config.ExcludeExtensions = append(config.ExcludeExtensions, m.excludedExtensions...)
+	config.IncludeExtensions = m.includeExtensions
+	if rootPath == m.scanRoot {
+		config.Paths = m.scanPaths
+	}
+	config.ForceInclude = append(config.ForceInclude, m.forceIncluded...)
+	return config
+}
+
+// extensionContributions lists extensions by their share of the generated content
+func (m Model) extensionContributions() []ExtensionContribution {
+	var contributions []ExtensionContribution
+	
+	if m.contextResult != nil {
+		// Sections can mix extensions (directory grouping, test separation),
+		// so attribute each file to its own extension
+		byExtension := make(map[string]*ExtensionContribution)
+		var order []string
+		contribution := func(path string) *ExtensionContribution {
+			ext := strings.ToLower(filepath.Ext(path))
+			if byExtension[ext] == nil {
+				byExtension[ext] = &ExtensionContribution{Extension: ext}
+				order = append(order, ext)
+			}
+			return byExtension[ext]
+		}
+		
+		for _, section := range m.contextResult.Sections {
+			if len(section.Files) == 0 {
+				continue
+			}
+			for _, file := range section.Files {
+				contribution(file).Files++
+			}
+			if len(section.Documents) > 0 {
+				for _, document := range section.Documents {
+					contribution(document.Path).Chars += len(document.Content)
+				}
+				continue
+			}
+			// Without per-file documents, split the section evenly
+			share := len(section.Content) / len(section.Files)
+			for _, file := range section.Files {
+				contribution(file).Chars += share
+			}
+		}
+		
+		for _, ext := range order {
+			contributions = append(contributions, *byExtension[ext])
+		}
+	}
+	
+	sort.Slice(contributions, func(i, j int) bool {
+		return contributions[i].Chars > contributions[j].Chars
+	})
+	
+	// Keep excluded extensions listed so they can be toggled back on
+	for _, ext := range m.excludedExtensions {
+		contributions = append(contributions, ExtensionContribution{Extension: ext, Excluded: true})
+	}
+	
+	return contributions
+}
+
+// toggleExcludedExtension adds or removes an extension from the exclusion list
+func (m Model) toggleExcludedExtension(ext string) Model {
+	var remaining []string
+	removed := false
+	for _, excluded := range m.excludedExtensions {
+		if excluded == ext {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, excluded)
+	}
+	
+	if !removed {
+		remaining = append(remaining, ext)
+	}
+	m.excludedExtensions = remaining
+	
+	return m
+}
+
+// handleExtensionKeys processes input in the extension exclusion list
+func (m Model) handleExtensionKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	contributions := m.extensionContributions()
+	
+	switch msg.String() {
+	case "esc", "x":
+		m.showingExtensions = false
+	case "up", "k":
+		if m.extensionCursor > 0 {
+			m.extensionCursor--
+		}
+	case "down", "j":
+		if m.extensionCursor < len(contributions)-1 {
+			m.extensionCursor++
+		}
+	case "enter", " ":
+		if m.extensionCursor >= len(contributions) {
+			return m, nil
+		}
+		
+		ext := contributions[m.extensionCursor].Extension
+		m = m.toggleExcludedExtension(ext)
+		m.showingExtensions = false
+		return m.startRescan(fmt.Sprintf("Excluded extensions updated (%s), regenerating...", displayExtension(ext)))
+	}
+	
+	return m, nil
+}
+
+// startRescan rescans the last scanned root with the current configuration
+func (m Model) startRescan(message string) (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	
+	root := m.scanRoot
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			toastManager, toastCmd := m.toastManager.AddToast(
+				fmt.Sprintf("Error getting current directory: %v", err), feedback.ToastError)
+			m.toastManager = toastManager
+			return m, toastCmd
+		}
+		root = wd
+	}
+	
+	m.showingResult = false
+	m.loadingState = StateScanning
+	m.spinner = m.spinner.SetMessage(message).Start()
+	m.progress = feedback.NewProgress(0, "Scanning project files")
+	
+	m.scanner = m.newScanner(root)
+	
+	return m, tea.Batch(
+		m.spinner.InitSpinner(),
+		m.runScan(m.scanner),
+	)
+}
+
+// toggleIncludeContent flips content sections on or off and regenerates from the last scan
+func (m Model) toggleIncludeContent() (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	
+	m.structureOnly = !m.structureOnly
+	
+	message := "Including file content, regenerating..."
+	if m.structureOnly {
+		message = "Structure only, regenerating..."
+	}
+	
+	m.showingResult = false
+	m.loadingState = StateProcessing
+	m.spinner = m.spinner.SetMessage(message).Start()
+	
+	return m, tea.Batch(m.spinner.InitSpinner(), m.generateContext())
+}
+
+// renderExtensionList renders the extension exclusion list
+func (m Model) renderExtensionList() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	result.WriteString(centerText(titleStyle.Render("🧩 Content by Extension"), m.viewWidth()))
+	result.WriteString("\n\n")
+	
+	for i, contribution := range m.extensionContributions() {
+		line := fmt.Sprintf("%-12s %4d files  %10s chars", displayExtension(contribution.Extension),
+			contribution.Files, context.FormatNumber(contribution.Chars))
+		if contribution.Excluded {
+			line = fmt.Sprintf("%-12s %s", displayExtension(contribution.Extension), "excluded")
+		}
+		
+		var style lipgloss.Style
+		if i == m.extensionCursor {
+			style = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else if contribution.Excluded {
+			style = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#6B7280")).
+				Strikethrough(true).
+				Padding(0, 1)
+		} else {
+			style = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		result.WriteString(centerText(style.Render(line), m.viewWidth()))
+		result.WriteString("\n")
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(centerText(instructionStyle.Render("↑↓: select • Enter: toggle & regenerate • ESC: close"), m.viewWidth()))
+	
+	return result.String()
+}
+
+// displayExtension returns a printable extension name
+func displayExtension(ext string) string {
+	if ext == "" {
+		return "(no extension)"
+	}
+	return ext
+}
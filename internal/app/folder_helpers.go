@@ -1,23 +1,83 @@
 package app
 
 import (
+	stdcontext "context"
+	"path/filepath"
+	
 	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// startFolderScan starts scanning a specific folder
+// newScanner creates a scanner for a folder with the current scan configuration
+func (m Model) newScanner(folderPath string) *context.ProjectScanner {
+	scanner := context.NewProjectScanner(m.scanConfig(folderPath))
+	scanner.SetCache(m.scanCache)
+	if m.appConfig != nil && m.appConfig.ConfigDir != "" {
+		scanner.SetCacheDir(filepath.Join(m.appConfig.ConfigDir, "cache"))
+	}
+	return scanner
+}
+
+// startFolderScan scans a specific folder, reporting only completion
 func (m Model) startFolderScan(folderPath string) tea.Cmd {
+	return scanCmd(m.operations.context(), m.newScanner(folderPath))
+}
+
+// runScan performs a scan with a scanner the model may hold on to for pausing,
+// forwarding its progress updates to the update loop
+func (m Model) runScan(scanner *context.ProjectScanner) tea.Cmd {
+	return tea.Batch(scanCmd(m.operations.context(), scanner), listenForScanProgress(scanner.GetProgressChannel()))
+}
+
+// scanCmd runs a scan to completion, or until ctx is cancelled
+func scanCmd(ctx stdcontext.Context, scanner *context.ProjectScanner) tea.Cmd {
 	return func() tea.Msg {
-		// Create scanner with folder-specific config
-		config := context.DefaultScanConfig(folderPath)
-		scanner := context.NewProjectScanner(config)
-		
-		// Perform the scan
-		result, err := scanner.Scan()
+		result, err := scanner.Scan(ctx)
+		if err == nil {
+			// A scan finishing just as it is cancelled is still discarded
+			err = ctx.Err()
+		}
 		if err != nil {
 			return ScanCompleteMsg{Error: err}
 		}
 		
 		return ScanCompleteMsg{Result: result}
 	}
-}
\ No newline at end of file
+}
+
+// listenForScanProgress waits for the next progress update; it yields nothing once
+// the scanner closes the channel
+func listenForScanProgress(updates <-chan context.ScanProgress) tea.Cmd {
+	if updates == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		progress, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return ScanProgressMsg{Progress: progress, updates: updates}
+	}
+}
+
+// togglePause pauses or resumes the running scan
+func (m Model) togglePause() Model {
+	if m.loadingState != StateScanning || m.scanner == nil {
+		return m
+	}
+	
+	if m.scanner.IsPaused() {
+		m.scanner.Resume()
+		m.eventLog.Record(events.EventScanPause, "Scan resumed")
+	} else {
+		m.scanner.Pause()
+		m.eventLog.Record(events.EventScanPause, "Scan paused")
+	}
+	return m
+}
+
+// scanPaused reports whether the running scan is paused
+func (m Model) scanPaused() bool {
+	return m.loadingState == StateScanning && m.scanner != nil && m.scanner.IsPaused()
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/gitdiff"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gitDiffRecentCommits is how many recent commits the base picker offers
+const gitDiffRecentCommits = 10
+
+// gitDiffPicker chooses the ref a git diff context is taken against; typing
+// switches to a free-form ref such as a tag or origin/main
+type gitDiffPicker struct {
+	root   string
+	refs   []gitdiff.Ref
+	cursor int
+	typing bool
+	input  string
+}
+
+// openGitDiffPicker lists the bases to diff the project's repository against
+func (m Model) openGitDiffPicker() (Model, tea.Cmd) {
+	root, err := gitdiff.Root(m.projectRoot())
+	if err != nil {
+		toastManager, toastCmd := m.toastManager.AddToast(err.Error(), feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	refs, err := gitdiff.Refs(root, gitDiffRecentCommits)
+	if err != nil {
+		return m.reportError("Failed to list git refs", err, "Repository: "+root)
+	}
+
+	m.gitDiffPicker = &gitDiffPicker{root: root, refs: refs}
+	m.eventLog.Record(events.EventNavigation, "Opened git diff base picker for %s", root)
+	return m, nil
+}
+
+// handleGitDiffPickerKeys moves between refs, takes a typed ref and starts generation on enter
+func (m Model) handleGitDiffPickerKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	picker := *m.gitDiffPicker
+
+	if picker.typing {
+		switch msg.String() {
+		case "enter":
+			ref := strings.TrimSpace(picker.input)
+			if ref == "" {
+				return m, nil
+			}
+			m.gitDiffPicker = nil
+			return m.startGitDiffContext(picker.root, ref)
+		case "esc":
+			picker.typing = false
+			picker.input = ""
+		case "ctrl+c":
+			return m, tea.Quit
+		case "backspace":
+			if runes := []rune(picker.input); len(runes) > 0 {
+				picker.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				picker.input += string(msg.Runes)
+			}
+		}
+		m.gitDiffPicker = &picker
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.gitDiffPicker = nil
+		return m, nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		if picker.cursor > 0 {
+			picker.cursor--
+		}
+	case "down", "j":
+		if picker.cursor < len(picker.refs)-1 {
+			picker.cursor++
+		}
+	case "/", "r":
+		picker.typing = true
+	case "enter":
+		m.gitDiffPicker = nil
+		return m.startGitDiffContext(picker.root, picker.refs[picker.cursor].Name)
+	}
+
+	m.gitDiffPicker = &picker
+	return m, nil
+}
+
+// startGitDiffContext generates context from the changes since ref
+func (m Model) startGitDiffContext(root, ref string) (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	m.scanRoot = root
+	m.eventLog.Record(events.EventGeneration, "Generating context from changes since %s", ref)
+	m.loadingState = StateProcessing
+	m.spinner = m.spinner.SetMessage(fmt.Sprintf("Collecting changes since %s...", ref)).Start()
+	m.showingResult = false
+	return m, tea.Batch(m.spinner.InitSpinner(), m.generateGitDiffContext(root, ref))
+}
+
+// generateGitDiffContext collects the changed files and builds their context
+func (m Model) generateGitDiffContext(root, ref string) tea.Cmd {
+	ctx := m.operations.context()
+	return func() tea.Msg {
+		changes, err := gitdiff.Changes(root, ref)
+		if err != nil {
+			return ContextGeneratedMsg{Error: err, DiffBase: ref}
+		}
+		if len(changes) == 0 {
+			return ContextGeneratedMsg{Error: fmt.Errorf("no changes since %s", ref), DiffBase: ref}
+		}
+
+		result, err := m.contextGenerator().GenerateDiffContext(ctx, m.scanConfig(root), ref, changes, filepath.Base(root))
+		return ContextGeneratedMsg{Result: result, Error: err, DiffBase: ref}
+	}
+}
+
+// renderGitDiffPicker lists HEAD, the branches and recent commits to diff against
+func (m Model) renderGitDiffPicker() string {
+	var result strings.Builder
+	picker := m.gitDiffPicker
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3B82F6")).
+		Padding(0, 1).
+		Width(m.boxWidth(60))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	result.WriteString(titleStyle.Render("🔀 Context from Git Diff"))
+	result.WriteString("\n")
+	result.WriteString(mutedStyle.Render(picker.root))
+	result.WriteString("\n\n")
+
+	if picker.typing {
+		result.WriteString(textStyle.Render("Base ref (branch, tag or commit):"))
+		result.WriteString("\n")
+		result.WriteString(inputStyle.Render(picker.input + "█"))
+		result.WriteString("\n\n")
+		result.WriteString(instructionStyle.Render("Enter: generate • ESC: back to list"))
+		return result.String()
+	}
+
+	for i, ref := range picker.refs {
+		line := fmt.Sprintf("%-8s %s", ref.Kind, ref.Name)
+		if ref.Subject != "" {
+			line += "  " + ref.Subject
+		}
+		if i == picker.cursor {
+			result.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			result.WriteString(textStyle.Render("  " + line))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("↑↓: select base • Enter: generate • /: type a ref • ESC: cancel"))
+
+	return result.String()
+}
@@ -0,0 +1,285 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/library"
+	"ai-context-cli/internal/navigation"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// libraryPreviewLines is how much of a saved context the inline preview shows
+const libraryPreviewLines = 15
+
+// libraryScreen lists the saved contexts; renaming and confirmDelete apply to
+// the entry under the cursor
+type libraryScreen struct {
+	entries       []library.Entry
+	cursor        int
+	renaming      bool
+	input         string
+	confirmDelete bool
+	preview       string // start of the selected context, loaded when asked for
+}
+
+// openLibrary shows the saved context library
+func (m Model) openLibrary() (Model, tea.Cmd) {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	entries, err := library.Open(m.appConfig.ConfigDir).List()
+	if err != nil {
+		return m.reportError("Failed to open context library", err)
+	}
+	m.library = &libraryScreen{entries: entries}
+	m.eventLog.Record(events.EventNavigation, "Opened context library")
+	return m, nil
+}
+
+// handleLibraryKeys moves between saved contexts and previews, loads, renames or deletes them
+func (m Model) handleLibraryKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	screen := *m.library
+
+	if screen.renaming {
+		switch msg.String() {
+		case "enter":
+			m.library = &screen
+			return m.renameLibraryEntry()
+		case "esc":
+			screen.renaming = false
+			screen.input = ""
+		case "ctrl+c":
+			return m, tea.Quit
+		case "backspace":
+			if runes := []rune(screen.input); len(runes) > 0 {
+				screen.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				screen.input += string(msg.Runes)
+			}
+		}
+		m.library = &screen
+		return m, nil
+	}
+
+	if screen.confirmDelete {
+		switch msg.String() {
+		case "y", "Y":
+			m.library = &screen
+			return m.deleteLibraryEntry()
+		case "n", "N", "esc":
+			screen.confirmDelete = false
+		case "ctrl+c":
+			return m, tea.Quit
+		}
+		m.library = &screen
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.library = nil
+		return m, nil
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "up", "k":
+		if screen.cursor > 0 {
+			screen.cursor--
+			screen.preview = ""
+		}
+	case "down", "j":
+		if screen.cursor < len(screen.entries)-1 {
+			screen.cursor++
+			screen.preview = ""
+		}
+	}
+
+	if len(screen.entries) == 0 {
+		m.library = &screen
+		return m, nil
+	}
+	entry := screen.entries[screen.cursor]
+
+	switch msg.String() {
+	case "v", " ":
+		if screen.preview != "" {
+			screen.preview = ""
+			break
+		}
+		result, err := library.Open(m.appConfig.ConfigDir).Load(entry.ID)
+		if err != nil {
+			return m.reportError("Failed to load saved context", err, "Entry: "+entry.Name)
+		}
+		screen.preview = libraryPreview(result)
+	case "enter":
+		return m.loadLibraryEntry(entry)
+	case "r":
+		screen.renaming = true
+		screen.input = entry.Name
+	case "d":
+		screen.confirmDelete = true
+	}
+
+	m.library = &screen
+	return m, nil
+}
+
+// libraryPreview returns the first lines of a saved context
+func libraryPreview(result *context.ContextResult) string {
+	lines := strings.Split(strings.TrimSpace(result.Markdown()), "\n")
+	if len(lines) <= libraryPreviewLines {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:libraryPreviewLines], "\n") +
+		fmt.Sprintf("\n… %d more lines", len(lines)-libraryPreviewLines)
+}
+
+// loadLibraryEntry makes a saved context the current one and opens it in the
+// preview screen. The current scan is dropped since it need not match the
+// saved context's project.
+func (m Model) loadLibraryEntry(entry library.Entry) (Model, tea.Cmd) {
+	result, err := library.Open(m.appConfig.ConfigDir).Load(entry.ID)
+	if err != nil {
+		return m.reportError("Failed to load saved context", err, "Entry: "+entry.Name)
+	}
+
+	m.library = nil
+	m.contextResult = result
+	m.scanResult = nil
+	m.navStack = m.navStack.Push(navigation.ContextPreviewScreen)
+	m.currentScreen = "context_preview"
+	m.eventLog.Record(events.EventNavigation, "Loaded %s from the context library", entry.Name)
+	return m.openContextPreview()
+}
+
+// renameLibraryEntry saves the typed name for the selected entry
+func (m Model) renameLibraryEntry() (Model, tea.Cmd) {
+	screen := *m.library
+	entry := screen.entries[screen.cursor]
+	name := strings.TrimSpace(screen.input)
+	if name == "" {
+		toastManager, toastCmd := m.toastManager.AddToast("Name cannot be empty", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+
+	if err := library.Open(m.appConfig.ConfigDir).Rename(entry.ID, name); err != nil {
+		return m.reportError("Failed to rename saved context", err, "Entry: "+entry.Name)
+	}
+	screen.entries = append([]library.Entry(nil), screen.entries...)
+	screen.entries[screen.cursor].Name = name
+	screen.renaming = false
+	screen.input = ""
+	m.library = &screen
+	m.eventLog.Record(events.EventSettings, "Renamed saved context %s to %s", entry.Name, name)
+	return m, nil
+}
+
+// deleteLibraryEntry removes the selected entry; exported files are kept
+func (m Model) deleteLibraryEntry() (Model, tea.Cmd) {
+	screen := *m.library
+	entry := screen.entries[screen.cursor]
+	if err := library.Open(m.appConfig.ConfigDir).Delete(entry.ID); err != nil {
+		return m.reportError("Failed to delete saved context", err, "Entry: "+entry.Name)
+	}
+
+	entries := append([]library.Entry(nil), screen.entries[:screen.cursor]...)
+	screen.entries = append(entries, screen.entries[screen.cursor+1:]...)
+	if screen.cursor >= len(screen.entries) && screen.cursor > 0 {
+		screen.cursor--
+	}
+	screen.confirmDelete = false
+	screen.preview = ""
+	m.library = &screen
+	m.eventLog.Record(events.EventSettings, "Deleted saved context %s", entry.Name)
+
+	toastManager, toastCmd := m.toastManager.AddToast(fmt.Sprintf("Deleted %s", entry.Name), feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// renderLibrary lists the saved contexts with their project, date and size
+func (m Model) renderLibrary() string {
+	var result strings.Builder
+	screen := m.library
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B"))
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#3B82F6")).
+		Padding(0, 1).
+		Width(m.boxWidth(60))
+	previewStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#6B7280")).
+		Padding(0, 1)
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	result.WriteString(titleStyle.Render(fmt.Sprintf("📚 Context Library (%d)", len(screen.entries))))
+	result.WriteString("\n\n")
+
+	if len(screen.entries) == 0 {
+		result.WriteString(mutedStyle.Render("No saved contexts yet. Exported contexts are added here automatically."))
+		result.WriteString("\n\n")
+		result.WriteString(instructionStyle.Render("ESC: back"))
+		return result.String()
+	}
+
+	for i, entry := range screen.entries {
+		line := fmt.Sprintf("%-32s %-20s %s  ~%s tokens", entry.Name, entry.Project,
+			entry.SavedAt.Format("Jan 2 15:04"), context.FormatNumber(entry.Tokens))
+		if i == screen.cursor {
+			result.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			result.WriteString(textStyle.Render("  " + line))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+
+	entry := screen.entries[screen.cursor]
+	if entry.ExportPath != "" {
+		result.WriteString(mutedStyle.Render(fmt.Sprintf("%d files • %s • exported to %s", entry.Files, entry.Format, entry.ExportPath)))
+		result.WriteString("\n\n")
+	}
+	if screen.preview != "" {
+		result.WriteString(previewStyle.Render(screen.preview))
+		result.WriteString("\n\n")
+	}
+
+	switch {
+	case screen.renaming:
+		result.WriteString(textStyle.Render("New name:"))
+		result.WriteString("\n")
+		result.WriteString(inputStyle.Render(screen.input + "█"))
+		result.WriteString("\n")
+		result.WriteString(instructionStyle.Render("Enter: save • ESC: cancel"))
+	case screen.confirmDelete:
+		result.WriteString(warningStyle.Render(fmt.Sprintf("Delete %s from the library? The exported file is kept. (y/n)", entry.Name)))
+	default:
+		result.WriteString(instructionStyle.Render("↑↓: select • V/Space: preview • Enter: load • R: rename • D: delete • ESC: back"))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,269 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/keyring"
+	"ai-context-cli/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// modelEditorField is one row of the model settings form; key matches the
+// Field reported by config.ModelConfigError
+type modelEditorField struct {
+	key   string
+	label string
+	hint  string
+}
+
+// modelEditorFields are the editable model settings, in form order
+var modelEditorFields = []modelEditorField{
+	{key: "base_url", label: "Base URL", hint: "API endpoint; empty uses the provider default where there is one"},
+	{key: "api_key", label: "API key", hint: "Stored in the keychain; leave empty to keep the current key"},
+	{key: "timeout", label: "Timeout (s)", hint: "Request timeout in seconds; 0 uses the default"},
+	{key: "retries", label: "Retries", hint: "Extra attempts after connection errors, rate limits and server errors"},
+	{key: "temperature", label: "Temperature", hint: "Sampling temperature from 0 to 2; empty uses the provider default"},
+	{key: "max_tokens", label: "Max tokens", hint: "Context window used to check whether the context fits"},
+}
+
+// modelEditor is the open settings form for one configured model
+type modelEditor struct {
+	model      types.AIModel
+	values     []string
+	cursor     int
+	errField   string
+	errMsg     string
+	currentKey string // masked key and its source, looked up once when opened
+}
+
+// hasField reports whether the form has a row for a setting
+func (e *modelEditor) hasField(key string) bool {
+	for _, field := range modelEditorFields {
+		if field.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// openModelEditor edits the active model's settings
+func (m Model) openModelEditor() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No model configured. Select a model first.", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	model, ok := m.appConfig.ActiveModel()
+	if !ok {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No model configured. Select a model first.", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+
+	values := make([]string, len(modelEditorFields))
+	values[0] = model.APIEndpoint
+	values[2] = strconv.Itoa(model.TimeoutSecs)
+	values[3] = strconv.Itoa(model.Retries)
+	if model.Temperature != nil {
+		values[4] = strconv.FormatFloat(*model.Temperature, 'f', -1, 64)
+	}
+	values[5] = strconv.Itoa(model.MaxTokens)
+
+	editor := &modelEditor{model: model, values: values}
+	if key, source := keyring.Lookup(m.keyring, model.Provider, model.APIKey); source != keyring.SourceNone {
+		editor.currentKey = fmt.Sprintf("%s (%s)", keyring.Mask(key), source)
+	}
+	m.modelEditor = editor
+	m.eventLog.Record(events.EventNavigation, "Opened settings for %s", model.Name)
+	return m, nil
+}
+
+// handleModelEditorKeys moves between fields, edits the current one and saves on enter
+func (m Model) handleModelEditorKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	editor := *m.modelEditor
+	editor.values = append([]string(nil), editor.values...)
+
+	switch msg.String() {
+	case "esc":
+		m.modelEditor = nil
+		return m, nil
+	case "ctrl+c":
+		return m, tea.Quit
+	case "up", "shift+tab":
+		if editor.cursor > 0 {
+			editor.cursor--
+		}
+	case "down", "tab":
+		if editor.cursor < len(modelEditorFields)-1 {
+			editor.cursor++
+		}
+	case "enter":
+		m.modelEditor = &editor
+		return m.saveModelEditor()
+	case "backspace":
+		if value := []rune(editor.values[editor.cursor]); len(value) > 0 {
+			editor.values[editor.cursor] = string(value[:len(value)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			editor.values[editor.cursor] += string(msg.Runes)
+		}
+	}
+
+	m.modelEditor = &editor
+	return m, nil
+}
+
+// editedModel parses the form into a model, reporting the first field that does not parse
+func (e *modelEditor) editedModel() (types.AIModel, error) {
+	model := e.model
+	model.APIEndpoint = strings.TrimSpace(e.values[0])
+
+	integer := func(field int) (int, error) {
+		value := strings.TrimSpace(e.values[field])
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, &config.ModelConfigError{Field: modelEditorFields[field].key, Message: "must be a whole number"}
+		}
+		return n, nil
+	}
+	var err error
+	if model.TimeoutSecs, err = integer(2); err != nil {
+		return model, err
+	}
+	if model.Retries, err = integer(3); err != nil {
+		return model, err
+	}
+	if model.MaxTokens, err = integer(5); err != nil {
+		return model, err
+	}
+
+	model.Temperature = nil
+	if value := strings.TrimSpace(e.values[4]); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return model, &config.ModelConfigError{Field: "temperature", Message: "must be a number"}
+		}
+		model.Temperature = &temperature
+	}
+	return model, config.ValidateModelConfig(model)
+}
+
+// saveModelEditor validates the form and persists it; a new API key goes to
+// the keychain and any plaintext copy is dropped from the config file
+func (m Model) saveModelEditor() (Model, tea.Cmd) {
+	editor := *m.modelEditor
+	editor.errField, editor.errMsg = "", ""
+
+	model, err := editor.editedModel()
+	apiKey := strings.TrimSpace(editor.values[1])
+	if err == nil && apiKey != "" && (m.keyring == nil || !m.keyring.Available()) {
+		err = &config.ModelConfigError{Field: "api_key",
+			Message: fmt.Sprintf("no system keychain available; set %s instead", keyring.EnvVar(model.Provider))}
+	}
+	var fieldErr *config.ModelConfigError
+	if errors.As(err, &fieldErr) {
+		editor.errField, editor.errMsg = fieldErr.Field, fieldErr.Message
+		m.modelEditor = &editor
+		return m, nil
+	}
+
+	if apiKey != "" {
+		if err := m.keyring.Set(model.Provider, apiKey); err != nil {
+			return m.reportError("Failed to store API key", err, "Provider: "+model.Provider)
+		}
+		model.APIKey = ""
+	}
+	if err := m.appConfig.SaveModelConfig(model); err != nil {
+		return m.reportError("Failed to save model settings", err, "Model: "+model.Name)
+	}
+	if apiKey != "" {
+		if err := m.clearConfiguredKeys(model.Provider); err != nil {
+			return m.reportError("Failed to save model settings", err, "Model: "+model.Name)
+		}
+	}
+
+	m.modelEditor = nil
+	m.eventLog.Record(events.EventSettings, "Saved settings for %s", model.Name)
+	m, refreshCmd := m.restartStatusRefresher()
+	toastManager, toastCmd := m.toastManager.AddToast(
+		fmt.Sprintf("Saved settings for %s", model.Name), feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, tea.Batch(toastCmd, refreshCmd)
+}
+
+// renderModelEditor renders the settings form with the current field's hint and any error
+func (m Model) renderModelEditor() string {
+	var result strings.Builder
+	editor := m.modelEditor
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151")).
+		Width(14)
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6")).
+		Width(14)
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	placeholderStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	result.WriteString(titleStyle.Render(fmt.Sprintf("⚙️ Model Settings: %s (%s)", editor.model.Name, editor.model.Provider)))
+	result.WriteString("\n\n")
+
+	for i, field := range modelEditorFields {
+		value := editor.values[i]
+		if field.key == "api_key" {
+			value = strings.Repeat("•", len([]rune(value)))
+		}
+
+		marker, label := "  ", labelStyle.Render(field.label)
+		if i == editor.cursor {
+			marker, label = "▶ ", selectedStyle.Render(field.label)
+			value += "█"
+		}
+		line := marker + label + valueStyle.Render(value)
+		if field.key == "api_key" && editor.values[i] == "" && editor.currentKey != "" {
+			line += placeholderStyle.Render(" current: " + editor.currentKey)
+		}
+		result.WriteString(line)
+		result.WriteString("\n")
+		if field.key == editor.errField {
+			result.WriteString(errorStyle.Render("    ✗ " + field.label + " " + editor.errMsg))
+			result.WriteString("\n")
+		}
+	}
+
+	if editor.errField != "" && !editor.hasField(editor.errField) {
+		// Settings the form does not edit, such as an Azure deployment, are fixed in the config file
+		result.WriteString(errorStyle.Render(fmt.Sprintf("    ✗ %s %s (edit it in the config file)", editor.errField, editor.errMsg)))
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render(modelEditorFields[editor.cursor].hint))
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("↑↓/Tab: field • Enter: save • ESC: cancel"))
+
+	return result.String()
+}
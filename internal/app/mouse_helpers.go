@@ -0,0 +1,93 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleMouse routes mouse events to the browser, the preview or the main menu,
+// following the order View draws screens in. Other screens are keyboard-only.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// The browser and preview measure rows from the top of their own view
+	msg.Y -= strings.Count(m.renderTopBar(), "\n")
+	
+	if m.keyboardDialogOpen() {
+		return m, nil
+	}
+	if m.pickingOutputDir && m.folderBrowser != nil {
+		browser, cmd := m.folderBrowser.Update(msg)
+		m.folderBrowser = browser
+		return m, cmd
+	}
+	if m.pendingExport != nil || m.exportPrompt != nil || m.chat != nil || m.limitExceeded != nil || m.showingHelp {
+		return m, nil
+	}
+	
+	switch {
+	case m.showingPreview && m.contextPreview != nil:
+		contextPreview, cmd := m.contextPreview.Update(msg)
+		m.contextPreview = contextPreview
+		return m, cmd
+	case m.showingBrowser && m.folderBrowser != nil:
+		browser, cmd := m.folderBrowser.Update(msg)
+		m.folderBrowser = browser
+		return m, cmd
+	case !m.showingResult && m.loadingState == StateMenu && !m.showingDashboard:
+		return m.handleMenuMouse(msg)
+	}
+	return m, nil
+}
+
+// handleMenuMouse selects the clicked menu button, activating it when it was
+// already selected, and moves the selection with the wheel
+func (m Model) handleMenuMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case msg.Button == tea.MouseButtonWheelDown:
+		if m.cursor < len(m.menuItems)-1 {
+			m.cursor++
+		}
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		index, ok := m.menuItemAt(msg.Y)
+		if !ok {
+			return m, nil
+		}
+		if index == m.cursor {
+			return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+		m.cursor = index
+	}
+	return m, nil
+}
+
+// menuItemAt returns the menu button drawn on row y below the top bar
+func (m Model) menuItemAt(y int) (int, bool) {
+	start, end := m.visibleMenuItems()
+	y -= strings.Count(m.renderMenuHeader(), "\n")
+	if start > 0 {
+		y-- // the "↑ n more" line
+	}
+	
+	// Buttons are as tall as their wrapped descriptions
+	for i := start; i < end && y >= 0; i++ {
+		height := lipgloss.Height(m.createButton(m.menuItems[i], i, i == m.cursor))
+		if y < height {
+			return i, true
+		}
+		y -= height
+	}
+	return 0, false
+}
+
+// keyboardDialogOpen reports whether a keyboard-only screen covers the browser,
+// preview and menu
+func (m Model) keyboardDialogOpen() bool {
+	return m.showingEventLog || m.showingErrorDetail || m.pendingScan != nil || m.confirmingReset ||
+		m.apiKeys != nil || m.modelEditor != nil || m.library != nil || m.compare != nil || m.gitDiffPicker != nil ||
+		m.promptScreen != nil || m.extensionPicker != nil
+}
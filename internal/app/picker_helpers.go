@@ -0,0 +1,207 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ExtensionCountsMsg is sent when the count-only pass before a picked scan completes
+type ExtensionCountsMsg struct {
+	Root   string
+	Counts map[string]int
+	Error  error
+}
+
+// extensionPicker lets the user choose which extensions a scan includes
+type extensionPicker struct {
+	root     string
+	counts   []context.ExtensionCount
+	selected map[string]bool
+	cursor   int
+}
+
+// openExtensionPicker starts the count-only pass for the current directory
+func (m Model) openExtensionPicker() (Model, tea.Cmd) {
+	if m.busy() {
+		return m.operationInProgress()
+	}
+	
+	root, err := os.Getwd()
+	if err != nil {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("Error getting current directory: %v", err), feedback.ToastError)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	// Even a count-only pass over a home or root directory is slow
+	m, held := m.guardSensitiveScan(root, func(m Model) (Model, tea.Cmd) {
+		return m.openExtensionPicker()
+	})
+	if held {
+		return m, nil
+	}
+	
+	m.loadingState = StateScanning
+	m.spinner = m.spinner.SetMessage("Detecting file types...").Start()
+	return m, tea.Batch(m.spinner.InitSpinner(), m.countExtensions(root))
+}
+
+// countExtensions runs the count-only pass with the current exclusion rules
+func (m Model) countExtensions(root string) tea.Cmd {
+	config := m.scanConfig(root)
+	return func() tea.Msg {
+		counts, err := context.CountExtensions(config)
+		return ExtensionCountsMsg{Root: config.RootPath, Counts: counts, Error: err}
+	}
+}
+
+// handleExtensionCounts shows the picker with every detected extension selected
+func (m Model) handleExtensionCounts(msg ExtensionCountsMsg) (Model, tea.Cmd) {
+	m.loadingState = StateMenu
+	m.spinner = m.spinner.Stop()
+	
+	if msg.Error != nil {
+		return m.reportError("File type detection failed", msg.Error, "Scan root: "+msg.Root)
+	}
+	if len(msg.Counts) == 0 {
+		toastManager, toastCmd := m.toastManager.AddToast("No files found to scan", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	picker := &extensionPicker{root: msg.Root, selected: make(map[string]bool)}
+	for ext, count := range msg.Counts {
+		picker.counts = append(picker.counts, context.ExtensionCount{Extension: ext, Count: count})
+		picker.selected[ext] = true
+	}
+	sort.Slice(picker.counts, func(i, j int) bool {
+		if picker.counts[i].Count != picker.counts[j].Count {
+			return picker.counts[i].Count > picker.counts[j].Count
+		}
+		return picker.counts[i].Extension < picker.counts[j].Extension
+	})
+	
+	m.extensionPicker = picker
+	return m, nil
+}
+
+// handleExtensionPickerKeys toggles extensions and starts the scan
+func (m Model) handleExtensionPickerKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	picker := m.extensionPicker
+	
+	switch msg.String() {
+	case "esc":
+		m.extensionPicker = nil
+	case "up", "k":
+		if picker.cursor > 0 {
+			picker.cursor--
+		}
+	case "down", "j":
+		if picker.cursor < len(picker.counts)-1 {
+			picker.cursor++
+		}
+	case " ", "x":
+		ext := picker.counts[picker.cursor].Extension
+		picker.selected[ext] = !picker.selected[ext]
+	case "a":
+		// Select all, or none when everything is already selected
+		all := len(picker.selectedExtensions()) == len(picker.counts)
+		for _, count := range picker.counts {
+			picker.selected[count.Extension] = !all
+		}
+	case "enter":
+		selected := picker.selectedExtensions()
+		if len(selected) == 0 {
+			toastManager, toastCmd := m.toastManager.AddToast("Select at least one file type", feedback.ToastWarning)
+			m.toastManager = toastManager
+			return m, toastCmd
+		}
+		m.extensionPicker = nil
+		return m.startPickedScan(picker.root, selected)
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	}
+	
+	return m, nil
+}
+
+// selectedExtensions returns the ticked extensions in list order
+func (p *extensionPicker) selectedExtensions() []string {
+	var selected []string
+	for _, count := range p.counts {
+		if p.selected[count.Extension] {
+			selected = append(selected, count.Extension)
+		}
+	}
+	return selected
+}
+
+// startPickedScan scans root including only the chosen extensions
+func (m Model) startPickedScan(root string, extensions []string) (Model, tea.Cmd) {
+	m.includeExtensions = extensions
+	m.scanRoot = root
+	m.showingResult = false
+	m.eventLog.Record(events.EventScanStart, "Scan started for %s: %s", strings.Join(extensions, ", "), root)
+	m.loadingState = StateScanning
+	m.spinner = m.spinner.SetMessage(fmt.Sprintf("Scanning %d file types...", len(extensions))).Start()
+	m.progress = feedback.NewProgress(0, "Scanning project files")
+	m.scanner = m.newScanner(root)
+	
+	return m, tea.Batch(m.spinner.InitSpinner(), m.runScan(m.scanner))
+}
+
+// renderExtensionPicker renders the detected extensions with checkboxes
+func (m Model) renderExtensionPicker() string {
+	var result strings.Builder
+	picker := m.extensionPicker
+	
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	
+	result.WriteString(headerStyle.Render("🔠 Choose File Types to Scan"))
+	result.WriteString("\n")
+	result.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(picker.root))
+	result.WriteString("\n\n")
+	
+	for i, count := range picker.counts {
+		checkbox := "[ ]"
+		if picker.selected[count.Extension] {
+			checkbox = "[x]"
+		}
+		
+		var lineStyle lipgloss.Style
+		if i == picker.cursor {
+			lineStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			lineStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		line := fmt.Sprintf("%s %-12s %6d files", checkbox, displayExtension(count.Extension), count.Count)
+		result.WriteString(lineStyle.Render(line))
+		result.WriteString("\n")
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(instructionStyle.Render("↑↓: select • Space: toggle • A: all/none • Enter: scan • ESC: cancel"))
+	
+	return result.String()
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"ai-context-cli/internal/config"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithConfig attaches a loaded configuration profile to the model
+func (m Model) WithConfig(cfg *config.Config) Model {
+	m.appConfig = cfg
+	if cfg != nil {
+		m.minimalChrome = cfg.MinimalChrome
+		m.showingDashboard = cfg.DashboardHome
+		m.Close()
+		m.statusRefresher = m.newStatusRefresher(cfg)
+	}
+	return m
+}
+
+// switchToNextProfile activates the profile after the current one and reloads its models
+func (m Model) switchToNextProfile() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	profiles, err := config.ListProfiles(m.appConfig.ConfigDir)
+	if err != nil || len(profiles) < 2 {
+		toastManager, toastCmd := m.toastManager.AddToast("No other profiles available", feedback.ToastInfo)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	next := profiles[0]
+	for i, profile := range profiles {
+		if profile == m.appConfig.Profile {
+			next = profiles[(i+1)%len(profiles)]
+			break
+		}
+	}
+	
+	cfg, err := config.SwitchProfile(m.appConfig.ConfigDir, next)
+	if err != nil {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			fmt.Sprintf("Failed to switch profile: %v", err), feedback.ToastError)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	m.appConfig = cfg
+	m, refreshCmd := m.restartStatusRefresher()
+	toastManager, toastCmd := m.toastManager.AddToast(
+		fmt.Sprintf("Switched to profile '%s' (%d models)", cfg.Profile, len(cfg.Models)), feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, tea.Batch(toastCmd, refreshCmd)
+}
+
+// switchToNextRecentModel cycles the active model through recently used models
+func (m Model) switchToNextRecentModel() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	model, ok := m.appConfig.NextRecentModel()
+	if !ok {
+		toastManager, toastCmd := m.toastManager.AddToast("No recently used models", feedback.ToastInfo)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	
+	toastType := feedback.ToastSuccess
+	message := fmt.Sprintf("Active model: %s", model.Name)
+	if err := m.appConfig.Save(); err != nil {
+		toastType = feedback.ToastWarning
+		message = fmt.Sprintf("Active model: %s (not saved: %v)", model.Name, err)
+	}
+	
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// recordModelUse marks a model as just used, so the recent-model switch offers
+// it, and saves the profile. Failing to save only costs the history, so it is
+// logged rather than shown.
+func (m Model) recordModelUse(name string) Model {
+	if m.appConfig == nil || !m.appConfig.MarkModelUsed(name, time.Now()) {
+		return m
+	}
+	if err := m.appConfig.Save(); err != nil {
+		m.eventLog.Record(events.EventError, "Failed to save last use of %s: %v", name, err)
+	}
+	return m
+}
@@ -0,0 +1,485 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/prompt"
+	"ai-context-cli/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// promptMode is the part of the prompt template screen that has the keys
+type promptMode int
+
+const (
+	promptList promptMode = iota
+	promptEdit
+	promptValues
+	promptPreview
+	promptConfirmDelete
+)
+
+// promptFormFields are the editable parts of a template, in form order
+var promptFormFields = []string{"Name", "Description", "Template"}
+
+// promptScreen manages the prompt templates stored in the config
+type promptScreen struct {
+	mode       promptMode
+	cursor     int
+	form       []string
+	formCursor int
+	editingID  string // ID of the template being edited, empty for a new one
+	formErr    string
+	variables  []string
+	values     map[string]string
+	valueIndex int
+	rendered   string
+}
+
+// activePrompt is the template applied to exports and chat, with its values
+type activePrompt struct {
+	template types.ContextTemplate
+	values   map[string]string
+}
+
+// openPromptTemplates shows the prompt template screen
+func (m Model) openPromptTemplates() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	m.promptScreen = &promptScreen{}
+	m.eventLog.Record(events.EventNavigation, "Opened prompt templates")
+	return m, nil
+}
+
+// handlePromptKeys routes keys to the list, the edit form, the variable values or the preview
+func (m Model) handlePromptKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	screen := *m.promptScreen
+	templates := m.appConfig.ContextTemplates
+
+	switch screen.mode {
+	case promptEdit:
+		return m.handlePromptFormKeys(msg)
+	case promptValues:
+		return m.handlePromptValueKeys(msg)
+	case promptPreview:
+		switch msg.String() {
+		case "a":
+			m.activePrompt = &activePrompt{template: templates[screen.cursor], values: screen.values}
+			m.promptScreen = nil
+			m.eventLog.Record(events.EventSettings, "Applied prompt template %s", templates[screen.cursor].Name)
+			toastManager, toastCmd := m.toastManager.AddToast(
+				fmt.Sprintf("Prompt template %s applies to exports and chat", templates[screen.cursor].Name), feedback.ToastSuccess)
+			m.toastManager = toastManager
+			return m, toastCmd
+		case "c":
+			return m.copyText(screen.rendered, "Rendered prompt copied to clipboard")
+		case "esc":
+			screen.mode = promptList
+		}
+		m.promptScreen = &screen
+		return m, nil
+	case promptConfirmDelete:
+		switch msg.String() {
+		case "y", "Y":
+			template := templates[screen.cursor]
+			if err := m.appConfig.DeleteTemplate(template.ID); err != nil {
+				return m.reportError("Failed to delete template", err, "Template: "+template.Name)
+			}
+			if m.activePrompt != nil && m.activePrompt.template.ID == template.ID {
+				m.activePrompt = nil
+			}
+			if screen.cursor >= len(m.appConfig.ContextTemplates) && screen.cursor > 0 {
+				screen.cursor--
+			}
+			m.eventLog.Record(events.EventSettings, "Deleted prompt template %s", template.Name)
+			screen.mode = promptList
+		case "n", "N", "esc":
+			screen.mode = promptList
+		}
+		m.promptScreen = &screen
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.promptScreen = nil
+		return m, nil
+	case "up", "k":
+		if screen.cursor > 0 {
+			screen.cursor--
+		}
+	case "down", "j":
+		if screen.cursor < len(templates)-1 {
+			screen.cursor++
+		}
+	case "n":
+		screen.mode = promptEdit
+		screen.form = []string{"", "", "You are reviewing {{.ProjectName}}.\n\n{{.Context}}"}
+		screen.formCursor = 0
+		screen.editingID = ""
+		screen.formErr = ""
+	case "x":
+		if m.activePrompt != nil {
+			m.eventLog.Record(events.EventSettings, "Cleared prompt template %s", m.activePrompt.template.Name)
+			m.activePrompt = nil
+		}
+	}
+	if len(templates) == 0 {
+		m.promptScreen = &screen
+		return m, nil
+	}
+
+	template := templates[screen.cursor]
+	switch msg.String() {
+	case "e":
+		screen.mode = promptEdit
+		screen.form = []string{template.Name, template.Description, template.Template}
+		if template.Template == "" {
+			screen.form[2] = template.Preamble
+		}
+		screen.formCursor = 0
+		screen.editingID = template.ID
+		screen.formErr = ""
+	case "d":
+		screen.mode = promptConfirmDelete
+	case "enter":
+		screen.variables = prompt.Variables(template)
+		screen.values = make(map[string]string)
+		if m.activePrompt != nil && m.activePrompt.template.ID == template.ID {
+			for name, value := range m.activePrompt.values {
+				screen.values[name] = value
+			}
+		}
+		if len(screen.variables) > 0 {
+			screen.mode = promptValues
+			screen.valueIndex = 0
+			break
+		}
+		m.promptScreen = &screen
+		return m.previewPrompt()
+	}
+
+	m.promptScreen = &screen
+	return m, nil
+}
+
+// handlePromptFormKeys edits a template's name, description and text; enter saves
+func (m Model) handlePromptFormKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	screen := *m.promptScreen
+	screen.form = append([]string(nil), screen.form...)
+
+	switch msg.String() {
+	case "esc":
+		screen.mode = promptList
+	case "up", "shift+tab":
+		if screen.formCursor > 0 {
+			screen.formCursor--
+		}
+	case "down", "tab":
+		if screen.formCursor < len(promptFormFields)-1 {
+			screen.formCursor++
+		}
+	case "ctrl+n":
+		// Templates are usually several lines long
+		if screen.formCursor == 2 {
+			screen.form[2] += "\n"
+		}
+	case "enter":
+		m.promptScreen = &screen
+		return m.savePromptForm()
+	case "backspace":
+		if value := []rune(screen.form[screen.formCursor]); len(value) > 0 {
+			screen.form[screen.formCursor] = string(value[:len(value)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			screen.form[screen.formCursor] += string(msg.Runes)
+		}
+	}
+
+	m.promptScreen = &screen
+	return m, nil
+}
+
+// savePromptForm validates the form and stores the template in the config
+func (m Model) savePromptForm() (Model, tea.Cmd) {
+	screen := *m.promptScreen
+	name := strings.TrimSpace(screen.form[0])
+	text := strings.TrimSpace(screen.form[2])
+	screen.formErr = ""
+	switch {
+	case name == "":
+		screen.formErr = "Name is required"
+	case text == "":
+		screen.formErr = "Template is required"
+	default:
+		if err := prompt.Validate(text); err != nil {
+			screen.formErr = err.Error()
+		}
+	}
+	if screen.formErr != "" {
+		m.promptScreen = &screen
+		return m, nil
+	}
+
+	template := types.ContextTemplate{ID: screen.editingID, Name: name, Description: strings.TrimSpace(screen.form[1]), Template: text}
+	for _, existing := range m.appConfig.ContextTemplates {
+		if existing.ID == screen.editingID && screen.editingID != "" {
+			// Keep the settings the form does not edit
+			template.Icon, template.SizeFactor = existing.Icon, existing.SizeFactor
+		}
+	}
+	template.Variables = prompt.Variables(template)
+
+	saved, err := m.appConfig.SaveTemplate(template)
+	if err != nil {
+		return m.reportError("Failed to save template", err, "Template: "+name)
+	}
+	for i, existing := range m.appConfig.ContextTemplates {
+		if existing.ID == saved.ID {
+			screen.cursor = i
+		}
+	}
+	if m.activePrompt != nil && m.activePrompt.template.ID == saved.ID {
+		m.activePrompt = &activePrompt{template: saved, values: m.activePrompt.values}
+	}
+	screen.mode = promptList
+	m.promptScreen = &screen
+	m.eventLog.Record(events.EventSettings, "Saved prompt template %s", saved.Name)
+
+	toastManager, toastCmd := m.toastManager.AddToast(fmt.Sprintf("Saved template %s", saved.Name), feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
+
+// handlePromptValueKeys collects the template's variable values, then previews it
+func (m Model) handlePromptValueKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	screen := *m.promptScreen
+	values := make(map[string]string, len(screen.values))
+	for name, value := range screen.values {
+		values[name] = value
+	}
+	screen.values = values
+	name := screen.variables[screen.valueIndex]
+
+	switch msg.String() {
+	case "esc":
+		screen.mode = promptList
+	case "up", "shift+tab":
+		if screen.valueIndex > 0 {
+			screen.valueIndex--
+		}
+	case "down", "tab":
+		if screen.valueIndex < len(screen.variables)-1 {
+			screen.valueIndex++
+		}
+	case "enter":
+		m.promptScreen = &screen
+		return m.previewPrompt()
+	case "backspace":
+		if value := []rune(values[name]); len(value) > 0 {
+			values[name] = string(value[:len(value)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			values[name] += string(msg.Runes)
+		}
+	}
+
+	m.promptScreen = &screen
+	return m, nil
+}
+
+// previewPrompt renders the selected template against the current context
+func (m Model) previewPrompt() (Model, tea.Cmd) {
+	screen := *m.promptScreen
+	template := m.appConfig.ContextTemplates[screen.cursor]
+
+	formatted := "(no context generated yet)"
+	if m.contextResult != nil {
+		var err error
+		if formatted, err = m.configuredFormatter().Format(m.contextResult); err != nil {
+			return m.reportError("Failed to preview template", err)
+		}
+	}
+	rendered, err := prompt.Render(template, m.promptData(formatted, m.contextResult), screen.values)
+	if err != nil {
+		return m.reportError("Failed to preview template", err, "Template: "+template.Name)
+	}
+
+	screen.rendered = rendered
+	screen.mode = promptPreview
+	m.promptScreen = &screen
+	return m, nil
+}
+
+// promptData describes the context a template is rendered with
+func (m Model) promptData(formatted string, result *context.ContextResult) prompt.Data {
+	data := prompt.Data{Context: formatted, Date: time.Now()}
+	if result != nil {
+		data.ProjectName = result.ProjectName
+		data.Files = result.TotalFiles
+		data.Tokens = result.TokenEstimate
+	}
+	if m.appConfig != nil {
+		if model, ok := m.appConfig.ActiveModel(); ok {
+			data.Model = model.Name
+		}
+	}
+	return data
+}
+
+// applyPrompt wraps formatted context in the active prompt template, if any
+func (m Model) applyPrompt(formatted string, result *context.ContextResult) (string, error) {
+	if m.activePrompt == nil {
+		return formatted, nil
+	}
+	return prompt.Render(m.activePrompt.template, m.promptData(formatted, result), m.activePrompt.values)
+}
+
+// renderPromptTemplates renders the template list, edit form, variable values or preview
+func (m Model) renderPromptTemplates() string {
+	var result strings.Builder
+	screen := m.promptScreen
+	templates := m.appConfig.ContextTemplates
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#EF4444"))
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B"))
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#6B7280")).
+		Padding(0, 1).
+		Width(m.boxWidth(76))
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+
+	switch screen.mode {
+	case promptEdit:
+		title := "📝 New Prompt Template"
+		if screen.editingID != "" {
+			title = "📝 Edit Prompt Template"
+		}
+		result.WriteString(titleStyle.Render(title))
+		result.WriteString("\n\n")
+		for i, field := range promptFormFields {
+			label := fmt.Sprintf("  %-12s", field)
+			value := screen.form[i]
+			if i == screen.formCursor {
+				label = selectedStyle.Render(fmt.Sprintf("▶ %-12s", field))
+				value += "█"
+			} else {
+				label = textStyle.Render(label)
+			}
+			if field == "Template" {
+				result.WriteString(label)
+				result.WriteString("\n")
+				result.WriteString(boxStyle.Render(value))
+			} else {
+				result.WriteString(label + textStyle.Render(value))
+			}
+			result.WriteString("\n")
+		}
+		if screen.formErr != "" {
+			result.WriteString(errorStyle.Render("✗ " + screen.formErr))
+			result.WriteString("\n")
+		}
+		result.WriteString("\n")
+		result.WriteString(mutedStyle.Render(fmt.Sprintf("Variables: {{.%s}}; any other {{.Name}} is asked for when the template is used",
+			strings.Join(prompt.Builtins(), "}}, {{."))))
+		result.WriteString("\n")
+		result.WriteString(instructionStyle.Render("↑↓/Tab: field • Ctrl+N: new line • Enter: save • ESC: cancel"))
+		return result.String()
+
+	case promptValues:
+		template := templates[screen.cursor]
+		result.WriteString(titleStyle.Render(fmt.Sprintf("📝 %s: variables", template.Name)))
+		result.WriteString("\n\n")
+		for i, name := range screen.variables {
+			value := screen.values[name]
+			if i == screen.valueIndex {
+				result.WriteString(selectedStyle.Render(fmt.Sprintf("▶ %-16s", name)) + textStyle.Render(value+"█"))
+			} else {
+				result.WriteString(textStyle.Render(fmt.Sprintf("  %-16s%s", name, value)))
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString("\n")
+		result.WriteString(instructionStyle.Render("↑↓/Tab: variable • Enter: preview • ESC: back"))
+		return result.String()
+
+	case promptPreview:
+		template := templates[screen.cursor]
+		result.WriteString(titleStyle.Render(fmt.Sprintf("📝 %s: preview", template.Name)))
+		result.WriteString("\n\n")
+		lines := strings.Split(screen.rendered, "\n")
+		if len(lines) > 20 {
+			lines = append(lines[:20], fmt.Sprintf("… %d more lines", len(lines)-20))
+		}
+		result.WriteString(boxStyle.Render(strings.Join(lines, "\n")))
+		result.WriteString("\n\n")
+		result.WriteString(mutedStyle.Render(fmt.Sprintf("~%d tokens", len(screen.rendered)/4)))
+		result.WriteString("\n")
+		result.WriteString(instructionStyle.Render("A: apply to exports and chat • C: copy • ESC: back"))
+		return result.String()
+	}
+
+	result.WriteString(titleStyle.Render("📝 Prompt Templates"))
+	result.WriteString("\n\n")
+	if len(templates) == 0 {
+		result.WriteString(mutedStyle.Render("No templates yet. Press N to write one."))
+		result.WriteString("\n")
+	}
+	for i, template := range templates {
+		marker := "  "
+		if m.activePrompt != nil && m.activePrompt.template.ID == template.ID {
+			marker = "● "
+		}
+		line := marker + template.Name
+		if template.Description != "" {
+			line += " - " + template.Description
+		}
+		if i == screen.cursor {
+			result.WriteString(selectedStyle.Render("▶ " + line))
+		} else {
+			result.WriteString(textStyle.Render("  " + line))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+
+	if screen.mode == promptConfirmDelete {
+		result.WriteString(warningStyle.Render(fmt.Sprintf("Delete template %s? (y/n)", templates[screen.cursor].Name)))
+		return result.String()
+	}
+	if m.activePrompt != nil {
+		result.WriteString(mutedStyle.Render(fmt.Sprintf("● %s is applied to exports and chat (X: clear)", m.activePrompt.template.Name)))
+		result.WriteString("\n")
+	}
+	result.WriteString(instructionStyle.Render("↑↓: select • Enter: preview • N: new • E: edit • D: delete • ESC: back"))
+	return result.String()
+}
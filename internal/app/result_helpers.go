@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// resultSectionsShown is how many sections the result view lists at once
+	// before the terminal height is known
+	resultSectionsShown = 5
+	// resultViewChrome is roughly how many lines the result view uses besides the section list
+	resultViewChrome = 30
+	// sectionSnippetLines is how many lines an expanded section reveals
+	sectionSnippetLines = 8
+)
+
+// handleResultSectionKeys moves through the result view's sections and expands the highlighted one
+func (m Model) handleResultSectionKeys(msg tea.KeyMsg) (Model, bool) {
+	switch msg.String() {
+	case "up", "k":
+		if m.resultCursor > 0 {
+			m.resultCursor--
+			m.sectionExpanded = false
+		}
+	case "down", "j":
+		if m.resultCursor < len(m.contextResult.Sections)-1 {
+			m.resultCursor++
+			m.sectionExpanded = false
+		}
+	case "o":
+		m.sectionExpanded = !m.sectionExpanded
+	default:
+		return m, false
+	}
+	return m, true
+}
+
+// resultSectionsVisible is how many sections fit the result view at the terminal's height
+func (m Model) resultSectionsVisible() int {
+	if m.height <= 0 {
+		return resultSectionsShown
+	}
+	shown := m.height - resultViewChrome
+	if shown < 3 {
+		return 3
+	}
+	if shown > 15 {
+		return 15
+	}
+	return shown
+}
+
+// sectionSnippet returns the first lines of a section's content
+func sectionSnippet(content string, lines int) string {
+	all := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(all) <= lines {
+		return strings.Join(all, "\n")
+	}
+	return strings.Join(all[:lines], "\n") + fmt.Sprintf("\n… %d more lines", len(all)-lines)
+}
+
+// renderResultSections lists generated sections, expanding the highlighted one in place
+func (m Model) renderResultSections() string {
+	var result strings.Builder
+	sections := m.contextResult.Sections
+	
+	sectionTitle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6")).
+		Render("📋 Generated Sections:")
+	
+	result.WriteString(centerText(sectionTitle, m.viewWidth()))
+	result.WriteString("\n\n")
+	
+	// Keep the cursor inside the visible window
+	shown := m.resultSectionsVisible()
+	start := 0
+	if m.resultCursor >= shown {
+		start = m.resultCursor - shown + 1
+	}
+	end := start + shown
+	if end > len(sections) {
+		end = len(sections)
+	}
+	
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	snippetStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#374151")).
+		Foreground(lipgloss.Color("#6B7280")).
+		Padding(0, 1).
+		Width(m.boxWidth(80))
+	
+	for i := start; i < end; i++ {
+		section := sections[i]
+		sectionItem := fmt.Sprintf("• %s", section.Title)
+		if len(section.Files) > 0 {
+			sectionItem += fmt.Sprintf(" (%d files)", len(section.Files))
+		}
+		
+		style := sectionStyle
+		if i == m.resultCursor {
+			style = selectedStyle
+			sectionItem = "▶ " + strings.TrimPrefix(sectionItem, "• ")
+		}
+		result.WriteString(centerText(style.Render(sectionItem), m.viewWidth()))
+		result.WriteString("\n")
+		
+		if i == m.resultCursor && m.sectionExpanded {
+			snippet := snippetStyle.Render(sectionSnippet(section.Content, sectionSnippetLines))
+			result.WriteString(centerText(snippet, m.viewWidth()))
+			result.WriteString("\n")
+		}
+	}
+	
+	if remaining := len(sections) - end; remaining > 0 {
+		moreText := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")).
+			Italic(true).
+			Render(fmt.Sprintf("... and %d more sections", remaining))
+		result.WriteString(centerText(moreText, m.viewWidth()))
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+	
+	return result.String()
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// skippedSummary describes how many paths the last scan could not read
+func skippedSummary(count int) string {
+	if count == 1 {
+		return "1 path skipped due to an error"
+	}
+	return fmt.Sprintf("%d paths skipped due to errors", count)
+}
+
+// scanErrorCount returns how many paths the last scan skipped
+func (m Model) scanErrorCount() int {
+	if m.scanResult == nil {
+		return 0
+	}
+	return len(m.scanResult.Errors)
+}
+
+// handleScanErrorKeys processes input in the skipped paths list
+func (m Model) handleScanErrorKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "w":
+		m.showingScanErrors = false
+	case "up", "k":
+		if m.scanErrorCursor > 0 {
+			m.scanErrorCursor--
+		}
+	case "down", "j":
+		if m.scanErrorCursor < m.scanErrorCount()-1 {
+			m.scanErrorCursor++
+		}
+	case "r":
+		// Permissions may have been fixed since
+		m.showingScanErrors = false
+		return m.startRescan("Rescanning skipped paths...")
+	}
+	
+	return m, nil
+}
+
+// renderScanErrors renders the paths the last scan skipped and why
+func (m Model) renderScanErrors() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#F59E0B"))
+	result.WriteString(centerText(titleStyle.Render("⚠️ "+skippedSummary(m.scanErrorCount())), m.viewWidth()))
+	result.WriteString("\n\n")
+	
+	// Keep the cursor's entry in a window that fits the terminal
+	errors := m.scanResult.Errors
+	start, end := 0, len(errors)
+	if visible := m.resultSectionsVisible(); end > visible {
+		start = m.scanErrorCursor - visible/2
+		if start < 0 {
+			start = 0
+		}
+		if start > len(errors)-visible {
+			start = len(errors) - visible
+		}
+		end = start + visible
+	}
+	
+	root := m.scanResult.RootPath
+	width := m.boxWidth(80)
+	for i := start; i < end; i++ {
+		path := errors[i].Path
+		if relativePath, err := filepath.Rel(root, path); err == nil {
+			path = relativePath
+		}
+		line := fmt.Sprintf("%s: %v", path, errors[i].Err)
+		if runes := []rune(line); len(runes) > width {
+			line = string(runes[:width-1]) + "…"
+		}
+		
+		var style lipgloss.Style
+		if i == m.scanErrorCursor {
+			style = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			style = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		result.WriteString(centerText(style.Render(line), m.viewWidth()))
+		result.WriteString("\n")
+	}
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString("\n")
+	result.WriteString(centerText(instructionStyle.Render("↑↓: select • r: rescan • ESC: close"), m.viewWidth()))
+	
+	return result.String()
+}
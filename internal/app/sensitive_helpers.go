@@ -0,0 +1,124 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// volumeParents hold mount points whose children are whole disks
+var volumeParents = []string{"/Volumes", "/mnt", "/media"}
+
+// pendingScan is a scan waiting for confirmation because its root is huge or sensitive
+type pendingScan struct {
+	root   string
+	reason string
+	start  func(Model) (Model, tea.Cmd)
+}
+
+// sensitiveRootReason describes why scanning path could sweep up far too much, or "" if it is fine
+func sensitiveRootReason(path string) string {
+	resolved := canonicalPath(path)
+	
+	if filepath.Dir(resolved) == resolved {
+		return "the filesystem root"
+	}
+	if home, err := os.UserHomeDir(); err == nil && canonicalPath(home) == resolved {
+		return "your home directory"
+	}
+	
+	// Removable media mounts under /run/media/<user>/<volume>
+	parent := filepath.Dir(resolved)
+	if filepath.Dir(parent) == "/run/media" {
+		return "a volume root"
+	}
+	for _, volumeParent := range volumeParents {
+		if parent == volumeParent {
+			return "a volume root"
+		}
+	}
+	return ""
+}
+
+// canonicalPath returns the absolute, symlink-free form of path where possible
+func canonicalPath(path string) string {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
+
+// guardSensitiveScan holds back a scan of a sensitive root until the user confirms it.
+// It returns false when the scan may go ahead.
+func (m Model) guardSensitiveScan(root string, start func(Model) (Model, tea.Cmd)) (Model, bool) {
+	if m.confirmedRoot != "" && m.confirmedRoot == canonicalPath(root) {
+		m.confirmedRoot = ""
+		return m, false
+	}
+	
+	reason := sensitiveRootReason(root)
+	if reason == "" {
+		return m, false
+	}
+	
+	m.pendingScan = &pendingScan{root: root, reason: reason, start: start}
+	m.eventLog.Record(events.EventScanStart, "Scan of %s held for confirmation (%s)", root, reason)
+	return m, true
+}
+
+// handlePendingScanKeys confirms or cancels a held scan
+func (m Model) handlePendingScanKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	pending := m.pendingScan
+	switch msg.String() {
+	case "y", "Y":
+		m.pendingScan = nil
+		m.confirmedRoot = canonicalPath(pending.root)
+		return pending.start(m)
+	case "n", "N", "esc":
+		m.pendingScan = nil
+		toastManager, toastCmd := m.toastManager.AddToast("Scan cancelled", feedback.ToastInfo)
+		m.toastManager = toastManager
+		return m, toastCmd
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// renderPendingScan warns about a sensitive scan root and asks for confirmation
+func (m Model) renderPendingScan() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#EF4444"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	pathStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	
+	result.WriteString(titleStyle.Render("⚠️  Scan " + m.pendingScan.reason + "?"))
+	result.WriteString("\n\n")
+	result.WriteString(pathStyle.Render(m.pendingScan.root))
+	result.WriteString("\n\n")
+	result.WriteString(textStyle.Render("This location usually holds far more than one project. Scanning it can take a very\nlong time, read private files and produce a context too large to use."))
+	result.WriteString("\n\n")
+	result.WriteString(textStyle.Render("Consider \"Add Context (Folder)\" to pick a project directory instead."))
+	result.WriteString("\n\n")
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString(instructionStyle.Render("Y: scan anyway • N/ESC: cancel"))
+	
+	return result.String()
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"ai-context-cli/internal/events"
+	"ai-context-cli/internal/feedback"
+	"ai-context-cli/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SessionSavedMsg reports whether the session was written after a generation
+type SessionSavedMsg struct {
+	Error error
+}
+
+// saveSession writes the current scan and context to the config directory so
+// the next run can resume them
+func (m Model) saveSession() tea.Cmd {
+	if m.appConfig == nil || m.appConfig.ConfigDir == "" || m.scanResult == nil || m.contextResult == nil {
+		return nil
+	}
+	saved := &session.Session{
+		ScanRoot:      m.scanRoot,
+		ScanPaths:     m.scanPaths,
+		StructureOnly: m.structureOnly,
+		ScanResult:    m.scanResult,
+		ContextResult: m.contextResult,
+	}
+	if model, ok := m.appConfig.ActiveModel(); ok {
+		saved.Model = model.Name
+	}
+	path := session.Path(m.appConfig.ConfigDir)
+	now := m.contextResult.GeneratedAt
+
+	return func() tea.Msg {
+		saved.SavedAt = now
+		return SessionSavedMsg{Error: session.Save(path, saved)}
+	}
+}
+
+// handleSessionSaved notes a failed save; losing the session is not worth interrupting for
+func (m Model) handleSessionSaved(msg SessionSavedMsg) (Model, tea.Cmd) {
+	if msg.Error != nil {
+		m.eventLog.Record(events.EventError, "Session not saved: %v", msg.Error)
+	}
+	return m, nil
+}
+
+// resumeSession restores the last saved scan and context and opens the result view
+func (m Model) resumeSession() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	if m.busy() {
+		return m.operationInProgress()
+	}
+
+	saved, err := session.Load(session.Path(m.appConfig.ConfigDir))
+	if errors.Is(err, session.ErrNoSession) {
+		toastManager, toastCmd := m.toastManager.AddToast(
+			"No saved session yet. Generate a context first.", feedback.ToastInfo)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	if err != nil {
+		return m.reportError("Failed to resume session", err)
+	}
+
+	m.scanResult = saved.ScanResult
+	m.contextResult = saved.ContextResult
+	m.scanRoot = saved.ScanRoot
+	m.scanPaths = saved.ScanPaths
+	m.structureOnly = saved.StructureOnly
+	for _, model := range m.appConfig.Models {
+		if model.Name == saved.Model {
+			m.appConfig.DefaultModel = model.Name
+			m = m.recordModelUse(model.Name)
+		}
+	}
+
+	m.resultCursor = 0
+	m.sectionExpanded = false
+	m.loadingState = StateComplete
+	m.showingResult = true
+	m.eventLog.Record(events.EventNavigation, "Resumed session from %s", saved.SavedAt.Format("2006-01-02 15:04"))
+
+	toastManager, toastCmd := m.toastManager.AddToast(
+		fmt.Sprintf("Resumed session from %s: %d files, ~%d tokens",
+			saved.SavedAt.Format("Jan 2 15:04"), saved.ScanResult.TotalFiles, saved.ContextResult.TokenEstimate),
+		feedback.ToastSuccess)
+	m.toastManager = toastManager
+	return m, toastCmd
+}
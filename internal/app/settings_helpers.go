@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"ai-context-cli/internal/feedback"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openResetConfirm asks which settings to restore to their defaults
+func (m Model) openResetConfirm() (Model, tea.Cmd) {
+	if m.appConfig == nil {
+		toastManager, toastCmd := m.toastManager.AddToast("No configuration loaded", feedback.ToastWarning)
+		m.toastManager = toastManager
+		return m, toastCmd
+	}
+	m.confirmingReset = true
+	return m, nil
+}
+
+// handleResetKeys applies the chosen reset, persisting it to the active profile
+func (m Model) handleResetKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	var reset []string
+	switch msg.String() {
+	case "m":
+		m.appConfig.ResetModels()
+		reset = append(reset, "models")
+	case "s":
+		m = m.resetScanSettings()
+		reset = append(reset, "scan settings")
+	case "a":
+		m.appConfig.ResetModels()
+		m = m.resetScanSettings()
+		reset = append(reset, "models", "scan settings")
+	case "esc", "n":
+		m.confirmingReset = false
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.confirmingReset = false
+	
+	toastType := feedback.ToastSuccess
+	message := fmt.Sprintf("Reset %s to defaults", strings.Join(reset, " and "))
+	if err := m.appConfig.Save(); err != nil {
+		toastType = feedback.ToastWarning
+		message = fmt.Sprintf("%s (not saved: %v)", message, err)
+	}
+	m, refreshCmd := m.restartStatusRefresher()
+	toastManager, toastCmd := m.toastManager.AddToast(message, toastType)
+	m.toastManager = toastManager
+	return m, tea.Batch(toastCmd, refreshCmd)
+}
+
+// resetScanSettings clears persisted scan overrides and this session's exclusions
+func (m Model) resetScanSettings() Model {
+	m.appConfig.ResetScanSettings()
+	m.excludedExtensions = nil
+	m.forceIncluded = nil
+	m.includeExtensions = nil
+	m.structureOnly = false
+	return m
+}
+
+// renderResetConfirm renders the reset choices with a warning
+func (m Model) renderResetConfirm() string {
+	var result strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#F59E0B"))
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	
+	result.WriteString(titleStyle.Render("↺ Reset to Defaults"))
+	result.WriteString("\n\n")
+	result.WriteString(textStyle.Render(fmt.Sprintf("This overwrites the %q profile and cannot be undone.", m.appConfig.Profile)))
+	result.WriteString("\n\n")
+	result.WriteString(keyStyle.Render("M") + textStyle.Render("  Models: restore the built-in model list"))
+	result.WriteString("\n")
+	result.WriteString(keyStyle.Render("S") + textStyle.Render("  Scan settings: file size limit, size caps, path style, exclusions"))
+	result.WriteString("\n")
+	result.WriteString(keyStyle.Render("A") + textStyle.Render("  Both"))
+	result.WriteString("\n\n")
+	
+	instructionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Italic(true)
+	result.WriteString(instructionStyle.Render("M/S/A: reset • ESC: cancel"))
+	
+	return result.String()
+}
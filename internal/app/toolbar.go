@@ -0,0 +1,57 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// QuickAction represents a one-key shortcut shown in the main menu toolbar
+type QuickAction struct {
+	Key       string
+	Icon      string
+	Label     string
+	MenuIndex int // Menu item triggered by the key, -1 for keys handled outside the menu
+}
+
+// defaultQuickActions returns the shortcuts shown under the banner
+func defaultQuickActions() []QuickAction {
+	return []QuickAction{
+		{Key: "s", Icon: "📂", Label: "scan", MenuIndex: 0},
+		{Key: "f", Icon: "📁", Label: "folder", MenuIndex: 1},
+		{Key: "m", Icon: "🤖", Label: "model", MenuIndex: 3},
+		{Key: "p", Icon: "📋", Label: "preview", MenuIndex: 2},
+		{Key: "t", Icon: "🔠", Label: "types", MenuIndex: -1},
+		{Key: "?", Icon: "❓", Label: "help", MenuIndex: -1},
+	}
+}
+
+// findQuickAction returns the quick action bound to a key
+func findQuickAction(key string) (QuickAction, bool) {
+	for _, action := range defaultQuickActions() {
+		if action.Key == key {
+			return action, true
+		}
+	}
+	return QuickAction{}, false
+}
+
+// renderToolbar renders the quick actions row with key hints
+func (m Model) renderToolbar() string {
+	keyStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280"))
+	
+	var hints []string
+	for _, action := range defaultQuickActions() {
+		hint := fmt.Sprintf("%s %s %s", action.Icon, keyStyle.Render(action.Key), labelStyle.Render(action.Label))
+		hints = append(hints, hint)
+	}
+	
+	separator := labelStyle.Render(" │ ")
+	return centerText(strings.Join(hints, separator), m.viewWidth())
+}
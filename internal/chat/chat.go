@@ -0,0 +1,593 @@
+package chat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/providers"
+	"ai-context-cli/pkg/types"
+)
+
+// Roles used in chat messages
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// defaultOllamaEndpoint is used when an Ollama model has no endpoint configured
+const defaultOllamaEndpoint = "http://localhost:11434/api/chat"
+
+// defaultMaxReplyTokens caps replies for providers that require a limit
+const defaultMaxReplyTokens = 4096
+
+// maxStreamLine bounds a single line of a streamed response
+const maxStreamLine = 1 << 20
+
+// Client sends a conversation to a model. Send waits for the whole reply;
+// Stream delivers it in chunks as the model produces them.
+type Client interface {
+	Send(ctx context.Context, session *types.ChatSession) (types.ChatMessage, error)
+	Stream(ctx context.Context, session *types.ChatSession) (<-chan Chunk, error)
+}
+
+// Chunk is one piece of a streamed reply. The last chunk on a stream either
+// has Done set or carries the error that ended it; the channel is then closed.
+type Chunk struct {
+	Text string
+	Done bool
+	Err  error
+}
+
+// NewSession starts a conversation with a model, optionally carrying project context
+func NewSession(model types.AIModel, projectContext string) *types.ChatSession {
+	return &types.ChatSession{
+		ID:      fmt.Sprintf("chat-%d", time.Now().UnixNano()),
+		Model:   model,
+		Context: projectContext,
+	}
+}
+
+// NewClient returns the client for a model's provider, applying the model's
+// timeout and retry settings
+func NewClient(model types.AIModel, httpClient *http.Client) (Client, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: model.Timeout(2 * time.Minute)}
+	}
+	if model.Retries > 0 {
+		retrying := *httpClient
+		retrying.Transport = &retryTransport{base: httpClient.Transport, retries: model.Retries}
+		httpClient = &retrying
+	}
+
+	switch model.Provider {
+	case "openai", "openrouter", "lmstudio":
+		return &openAIClient{http: httpClient}, nil
+	case "azure":
+		return &openAIClient{http: httpClient, azure: true}, nil
+	case "anthropic":
+		return &anthropicClient{http: httpClient}, nil
+	case "google":
+		return &googleClient{http: httpClient}, nil
+	case "ollama":
+		return &ollamaClient{http: httpClient}, nil
+	}
+	return nil, fmt.Errorf("chat is not supported for provider %q", model.Provider)
+}
+
+// retryBackoff is the wait before the first retry; later retries wait longer
+const retryBackoff = 500 * time.Millisecond
+
+// retryTransport repeats requests that fail to connect or come back rate
+// limited or with a server error
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for attempt := 0; ; attempt++ {
+		response, err := base.RoundTrip(request)
+		retryable := err != nil || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+		if !retryable || attempt >= t.retries || request.GetBody == nil {
+			return response, err
+		}
+		if response != nil {
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(retryBackoff * time.Duration(attempt+1)):
+		}
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		request = request.Clone(request.Context())
+		request.Body = body
+	}
+}
+
+// withTemperature sets the sampling temperature under key when the model has one
+func withTemperature(payload map[string]interface{}, key string, model types.AIModel) {
+	if model.Temperature != nil {
+		payload[key] = *model.Temperature
+	}
+}
+
+// systemPrompt returns the project context sent ahead of the conversation
+func systemPrompt(session *types.ChatSession) string {
+	if strings.TrimSpace(session.Context) == "" {
+		return ""
+	}
+	return "Use the following project context to answer questions.\n\n" + session.Context
+}
+
+// post sends payload to endpoint and returns the response once its status is known to be successful
+func post(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, payload interface{}) (*http.Response, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("model has no API endpoint")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if response.StatusCode >= 400 {
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		return nil, fmt.Errorf("endpoint returned %s: %s", response.Status, errorMessage(data))
+	}
+	return response, nil
+}
+
+// postJSON sends payload to endpoint and decodes a successful response into reply
+func postJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, payload, reply interface{}) error {
+	response, err := post(ctx, client, endpoint, headers, payload)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if err := json.Unmarshal(data, reply); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// streamJSON sends payload to endpoint and feeds each line of the response to
+// parse, which returns the text it carries and whether the reply is complete.
+// Lines are read on a goroutine that stops when ctx is cancelled.
+func streamJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, payload interface{}, parse func(line string) (string, bool, error)) (<-chan Chunk, error) {
+	response, err := post(ctx, client, endpoint, headers, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan Chunk)
+	go func() {
+		defer close(chunks)
+		defer response.Body.Close()
+
+		emit := func(chunk Chunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		scanner := bufio.NewScanner(response.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			text, done, err := parse(line)
+			if err != nil {
+				emit(Chunk{Err: err})
+				return
+			}
+			if text != "" && !emit(Chunk{Text: text}) {
+				return
+			}
+			if done {
+				emit(Chunk{Done: true})
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			emit(Chunk{Err: fmt.Errorf("reading stream: %w", err)})
+			return
+		}
+		// Some servers close the stream without an explicit end marker
+		emit(Chunk{Done: true})
+	}()
+	return chunks, nil
+}
+
+// sseData returns the payload of a server-sent event data line
+func sseData(line string) (string, bool) {
+	data, ok := strings.CutPrefix(line, "data:")
+	return strings.TrimSpace(data), ok
+}
+
+// errorMessage extracts the provider's error message from a response body
+func errorMessage(data []byte) string {
+	var body struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && len(body.Error) > 0 {
+		var detail struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body.Error, &detail) == nil && detail.Message != "" {
+			return detail.Message
+		}
+		var text string
+		if json.Unmarshal(body.Error, &text) == nil && text != "" {
+			return text
+		}
+	}
+	message := strings.TrimSpace(string(data))
+	if len(message) > 200 {
+		message = message[:200] + "..."
+	}
+	return message
+}
+
+// roleMessages converts the session to role/content pairs, with the context as a system message
+func roleMessages(session *types.ChatSession) []map[string]string {
+	var messages []map[string]string
+	if prompt := systemPrompt(session); prompt != "" {
+		messages = append(messages, map[string]string{"role": RoleSystem, "content": prompt})
+	}
+	for _, message := range session.Messages {
+		messages = append(messages, map[string]string{"role": message.Role, "content": message.Content})
+	}
+	return messages
+}
+
+// openAIClient talks to the Chat Completions API: OpenAI's, an Azure OpenAI
+// deployment's, or a compatible one such as OpenRouter or LM Studio
+type openAIClient struct {
+	http  *http.Client
+	azure bool
+}
+
+func (c *openAIClient) request(session *types.ChatSession, stream bool) (string, map[string]string, map[string]interface{}) {
+	payload := map[string]interface{}{
+		"model":    session.Model.Name,
+		"messages": roleMessages(session),
+	}
+	withTemperature(payload, "temperature", session.Model)
+	if stream {
+		payload["stream"] = true
+	}
+	if c.azure {
+		return providers.AzureURL(session.Model), map[string]string{"api-key": session.Model.APIKey}, payload
+	}
+	// Local OpenAI-compatible servers such as LM Studio take no key
+	headers := map[string]string{}
+	if session.Model.APIKey != "" {
+		headers["Authorization"] = "Bearer " + session.Model.APIKey
+	}
+	return session.Model.APIEndpoint, headers, payload
+}
+
+func (c *openAIClient) Send(ctx context.Context, session *types.ChatSession) (types.ChatMessage, error) {
+	var reply struct {
+		Choices []struct {
+			Message types.ChatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	endpoint, headers, payload := c.request(session, false)
+	if err := postJSON(ctx, c.http, endpoint, headers, payload, &reply); err != nil {
+		return types.ChatMessage{}, err
+	}
+	if len(reply.Choices) == 0 {
+		return types.ChatMessage{}, fmt.Errorf("response contained no choices")
+	}
+	return types.ChatMessage{Role: RoleAssistant, Content: reply.Choices[0].Message.Content}, nil
+}
+
+func (c *openAIClient) Stream(ctx context.Context, session *types.ChatSession) (<-chan Chunk, error) {
+	endpoint, headers, payload := c.request(session, true)
+	return streamJSON(ctx, c.http, endpoint, headers, payload, func(line string) (string, bool, error) {
+		data, ok := sseData(line)
+		if !ok {
+			return "", false, nil
+		}
+		if data == "[DONE]" {
+			return "", true, nil
+		}
+		var event struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return "", false, fmt.Errorf("decoding stream: %w", err)
+		}
+		if len(event.Choices) == 0 {
+			return "", false, nil
+		}
+		return event.Choices[0].Delta.Content, false, nil
+	})
+}
+
+// anthropicClient talks to the Messages API, which takes the context as a separate system field
+type anthropicClient struct {
+	http *http.Client
+}
+
+func (c *anthropicClient) request(session *types.ChatSession, stream bool) (string, map[string]string, map[string]interface{}) {
+	var messages []map[string]string
+	for _, message := range session.Messages {
+		messages = append(messages, map[string]string{"role": message.Role, "content": message.Content})
+	}
+	payload := map[string]interface{}{
+		"model":      session.Model.Name,
+		"max_tokens": defaultMaxReplyTokens,
+		"messages":   messages,
+	}
+	if prompt := systemPrompt(session); prompt != "" {
+		payload["system"] = prompt
+	}
+	withTemperature(payload, "temperature", session.Model)
+	if stream {
+		payload["stream"] = true
+	}
+	headers := map[string]string{
+		"x-api-key":         session.Model.APIKey,
+		"anthropic-version": "2023-06-01",
+	}
+	return session.Model.APIEndpoint, headers, payload
+}
+
+func (c *anthropicClient) Send(ctx context.Context, session *types.ChatSession) (types.ChatMessage, error) {
+	var reply struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	endpoint, headers, payload := c.request(session, false)
+	if err := postJSON(ctx, c.http, endpoint, headers, payload, &reply); err != nil {
+		return types.ChatMessage{}, err
+	}
+
+	var text strings.Builder
+	for _, block := range reply.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return types.ChatMessage{Role: RoleAssistant, Content: text.String()}, nil
+}
+
+func (c *anthropicClient) Stream(ctx context.Context, session *types.ChatSession) (<-chan Chunk, error) {
+	endpoint, headers, payload := c.request(session, true)
+	return streamJSON(ctx, c.http, endpoint, headers, payload, func(line string) (string, bool, error) {
+		data, ok := sseData(line)
+		if !ok {
+			return "", false, nil
+		}
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return "", false, fmt.Errorf("decoding stream: %w", err)
+		}
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				return event.Delta.Text, false, nil
+			}
+		case "message_stop":
+			return "", true, nil
+		case "error":
+			return "", false, fmt.Errorf("stream error: %s", event.Error.Message)
+		}
+		return "", false, nil
+	})
+}
+
+// googleClient talks to the Gemini generateContent API
+type googleClient struct {
+	http *http.Client
+}
+
+type googlePart struct {
+	Text string `json:"text"`
+}
+
+type googleContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []googlePart `json:"parts"`
+}
+
+// googleReply is the generateContent response, also sent per event when streaming
+type googleReply struct {
+	Candidates []struct {
+		Content googleContent `json:"content"`
+	} `json:"candidates"`
+}
+
+// text joins the parts of the first candidate
+func (r googleReply) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String()
+}
+
+func (c *googleClient) payload(session *types.ChatSession) map[string]interface{} {
+	var contents []googleContent
+	for _, message := range session.Messages {
+		role := "user"
+		if message.Role == RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, googleContent{Role: role, Parts: []googlePart{{Text: message.Content}}})
+	}
+	payload := map[string]interface{}{"contents": contents}
+	if prompt := systemPrompt(session); prompt != "" {
+		payload["systemInstruction"] = googleContent{Parts: []googlePart{{Text: prompt}}}
+	}
+	if session.Model.Temperature != nil {
+		payload["generationConfig"] = map[string]interface{}{"temperature": *session.Model.Temperature}
+	}
+	return payload
+}
+
+func (c *googleClient) Send(ctx context.Context, session *types.ChatSession) (types.ChatMessage, error) {
+	var reply googleReply
+	if err := postJSON(ctx, c.http, providers.GoogleURL(session.Model), googleHeaders(session.Model), c.payload(session), &reply); err != nil {
+		return types.ChatMessage{}, err
+	}
+	if len(reply.Candidates) == 0 {
+		return types.ChatMessage{}, fmt.Errorf("response contained no candidates")
+	}
+	return types.ChatMessage{Role: RoleAssistant, Content: reply.text()}, nil
+}
+
+func (c *googleClient) Stream(ctx context.Context, session *types.ChatSession) (<-chan Chunk, error) {
+	endpoint, err := googleStreamURL(session.Model)
+	if err != nil {
+		return nil, err
+	}
+	return streamJSON(ctx, c.http, endpoint, googleHeaders(session.Model), c.payload(session), func(line string) (string, bool, error) {
+		data, ok := sseData(line)
+		if !ok {
+			return "", false, nil
+		}
+		var reply googleReply
+		if err := json.Unmarshal([]byte(data), &reply); err != nil {
+			return "", false, fmt.Errorf("decoding stream: %w", err)
+		}
+		return reply.text(), false, nil
+	})
+}
+
+// googleHeaders carries the API key, kept out of the URL so request errors never show it
+func googleHeaders(model types.AIModel) map[string]string {
+	if model.APIKey == "" {
+		return nil
+	}
+	return map[string]string{"x-goog-api-key": model.APIKey}
+}
+
+// googleStreamURL turns the generateContent URL into its server-sent events variant
+func googleStreamURL(model types.AIModel) (string, error) {
+	endpoint, err := url.Parse(providers.GoogleURL(model))
+	if err != nil {
+		return "", err
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, ":generateContent") + ":streamGenerateContent"
+	query := endpoint.Query()
+	query.Set("alt", "sse")
+	endpoint.RawQuery = query.Encode()
+	return endpoint.String(), nil
+}
+
+// ollamaClient talks to a local Ollama server's chat API
+type ollamaClient struct {
+	http *http.Client
+}
+
+// ollamaReply is the chat response, also sent once per line when streaming
+type ollamaReply struct {
+	Message types.ChatMessage `json:"message"`
+	Done    bool              `json:"done"`
+	Error   string            `json:"error"`
+}
+
+func (c *ollamaClient) request(session *types.ChatSession, stream bool) (string, map[string]interface{}) {
+	endpoint := session.Model.APIEndpoint
+	if endpoint == "" {
+		endpoint = defaultOllamaEndpoint
+	}
+	payload := map[string]interface{}{
+		"model":    session.Model.Name,
+		"messages": roleMessages(session),
+		"stream":   stream,
+	}
+	if session.Model.Temperature != nil {
+		payload["options"] = map[string]interface{}{"temperature": *session.Model.Temperature}
+	}
+	return endpoint, payload
+}
+
+func (c *ollamaClient) Send(ctx context.Context, session *types.ChatSession) (types.ChatMessage, error) {
+	var reply ollamaReply
+	endpoint, payload := c.request(session, false)
+	if err := postJSON(ctx, c.http, endpoint, nil, payload, &reply); err != nil {
+		return types.ChatMessage{}, err
+	}
+	return types.ChatMessage{Role: RoleAssistant, Content: reply.Message.Content}, nil
+}
+
+func (c *ollamaClient) Stream(ctx context.Context, session *types.ChatSession) (<-chan Chunk, error) {
+	endpoint, payload := c.request(session, true)
+	return streamJSON(ctx, c.http, endpoint, nil, payload, func(line string) (string, bool, error) {
+		var reply ollamaReply
+		if err := json.Unmarshal([]byte(line), &reply); err != nil {
+			return "", false, fmt.Errorf("decoding stream: %w", err)
+		}
+		if reply.Error != "" {
+			return "", false, fmt.Errorf("stream error: %s", reply.Error)
+		}
+		return reply.Message.Content, reply.Done, nil
+	})
+}
@@ -0,0 +1,269 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ai-context-cli/pkg/types"
+)
+
+// captureServer answers every request with reply and records the last request
+func captureServer(t *testing.T, status int, reply string) (*httptest.Server, *http.Request, map[string]interface{}) {
+	t.Helper()
+	var request http.Request
+	body := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = *r
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return server, &request, body
+}
+
+func testSession(model types.AIModel) *types.ChatSession {
+	session := NewSession(model, "# Project\n\nA demo project")
+	session.Messages = []types.ChatMessage{
+		{Role: RoleUser, Content: "What does it do?"},
+		{Role: RoleAssistant, Content: "It demos."},
+		{Role: RoleUser, Content: "Anything else?"},
+	}
+	return session
+}
+
+func send(t *testing.T, model types.AIModel) types.ChatMessage {
+	t.Helper()
+	client, err := NewClient(model, nil)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	reply, err := client.Send(context.Background(), testSession(model))
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	return reply
+}
+
+func TestOpenAIClient(t *testing.T) {
+	server, request, body := captureServer(t, http.StatusOK,
+		`{"choices":[{"message":{"role":"assistant","content":"Nothing else."}}]}`)
+	reply := send(t, types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL, APIKey: "sk-test"})
+
+	if reply.Role != RoleAssistant || reply.Content != "Nothing else." {
+		t.Errorf("Unexpected reply: %+v", reply)
+	}
+	if request.Header.Get("Authorization") != "Bearer sk-test" {
+		t.Errorf("Expected bearer auth, got %q", request.Header.Get("Authorization"))
+	}
+	messages := body["messages"].([]interface{})
+	if len(messages) != 4 {
+		t.Fatalf("Expected context plus 3 messages, got %d", len(messages))
+	}
+	first := messages[0].(map[string]interface{})
+	if first["role"] != RoleSystem || !strings.Contains(first["content"].(string), "A demo project") {
+		t.Errorf("Expected the context as the system message, got %v", first)
+	}
+}
+
+func TestAnthropicClient(t *testing.T) {
+	server, request, body := captureServer(t, http.StatusOK,
+		`{"content":[{"type":"text","text":"Nothing "},{"type":"text","text":"else."}]}`)
+	reply := send(t, types.AIModel{Name: "claude", Provider: "anthropic", APIEndpoint: server.URL, APIKey: "key"})
+
+	if reply.Content != "Nothing else." {
+		t.Errorf("Expected text blocks to be joined, got %q", reply.Content)
+	}
+	if request.Header.Get("x-api-key") != "key" || request.Header.Get("anthropic-version") == "" {
+		t.Error("Expected Anthropic auth headers")
+	}
+	if !strings.Contains(body["system"].(string), "A demo project") {
+		t.Error("Expected the context in the system field")
+	}
+	if len(body["messages"].([]interface{})) != 3 {
+		t.Error("Expected the context to be kept out of the messages")
+	}
+}
+
+func TestGoogleClient(t *testing.T) {
+	server, request, body := captureServer(t, http.StatusOK,
+		`{"candidates":[{"content":{"parts":[{"text":"Nothing else."}]}}]}`)
+	reply := send(t, types.AIModel{Name: "gemini-pro", Provider: "google", APIEndpoint: server.URL, APIKey: "secret"})
+
+	if reply.Content != "Nothing else." {
+		t.Errorf("Unexpected reply: %q", reply.Content)
+	}
+	if request.Header.Get("x-goog-api-key") != "secret" || strings.Contains(request.URL.String(), "secret") {
+		t.Errorf("Expected the key in the header and not the URL, got %s", request.URL)
+	}
+	contents := body["contents"].([]interface{})
+	if role := contents[1].(map[string]interface{})["role"]; role != "model" {
+		t.Errorf("Expected assistant turns to use the model role, got %v", role)
+	}
+	if _, ok := body["systemInstruction"]; !ok {
+		t.Error("Expected the context as the system instruction")
+	}
+}
+
+func TestOllamaClient(t *testing.T) {
+	server, _, body := captureServer(t, http.StatusOK,
+		`{"message":{"role":"assistant","content":"Nothing else."}}`)
+	reply := send(t, types.AIModel{Name: "llama3", Provider: "ollama", APIEndpoint: server.URL})
+
+	if reply.Content != "Nothing else." {
+		t.Errorf("Unexpected reply: %q", reply.Content)
+	}
+	if body["stream"] != false {
+		t.Error("Expected a non-streaming request")
+	}
+}
+
+func TestClientReportsProviderErrors(t *testing.T) {
+	server, _, _ := captureServer(t, http.StatusUnauthorized, `{"error":{"message":"invalid api key"}}`)
+	client, _ := NewClient(types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL}, nil)
+
+	_, err := client.Send(context.Background(), testSession(types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL}))
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("Expected the provider's error message, got %v", err)
+	}
+
+	if _, err := NewClient(types.AIModel{Provider: "unknown"}, nil); err == nil {
+		t.Error("Expected an error for an unsupported provider")
+	}
+}
+
+func TestStreamDeliversChunks(t *testing.T) {
+	tests := []struct {
+		provider string
+		stream   string
+	}{
+		{"openai", "data: {\"choices\":[{\"delta\":{\"content\":\"Nothing \"}}]}\n\ndata: {\"choices\":[{\"delta\":{\"content\":\"else.\"}}]}\n\ndata: [DONE]\n\n"},
+		{"anthropic", "event: message_start\ndata: {\"type\":\"message_start\"}\n\nevent: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Nothing \"}}\n\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"else.\"}}\n\ndata: {\"type\":\"message_stop\"}\n\n"},
+		{"google", "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Nothing \"}]}}]}\n\ndata: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"else.\"}]}}]}\n\n"},
+		{"ollama", "{\"message\":{\"content\":\"Nothing \"},\"done\":false}\n{\"message\":{\"content\":\"else.\"},\"done\":false}\n{\"message\":{\"content\":\"\"},\"done\":true}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			server, request, body := captureServer(t, http.StatusOK, tt.stream)
+			model := types.AIModel{Name: "model", Provider: tt.provider, APIEndpoint: server.URL}
+			client, err := NewClient(model, nil)
+			if err != nil {
+				t.Fatalf("NewClient failed: %v", err)
+			}
+
+			chunks, err := client.Stream(context.Background(), testSession(model))
+			if err != nil {
+				t.Fatalf("Stream failed: %v", err)
+			}
+			var texts []string
+			var last Chunk
+			for chunk := range chunks {
+				if chunk.Text != "" {
+					texts = append(texts, chunk.Text)
+				}
+				last = chunk
+			}
+
+			if strings.Join(texts, "|") != "Nothing |else." {
+				t.Errorf("Expected two text chunks, got %q", texts)
+			}
+			if !last.Done || last.Err != nil {
+				t.Errorf("Expected the stream to end with a done chunk, got %+v", last)
+			}
+			if tt.provider == "google" {
+				if !strings.HasSuffix(request.URL.Path, ":streamGenerateContent") || request.URL.Query().Get("alt") != "sse" {
+					t.Errorf("Expected the SSE streaming endpoint, got %s", request.URL)
+				}
+			} else if body["stream"] != true {
+				t.Error("Expected a streaming request")
+			}
+		})
+	}
+}
+
+func TestStreamStopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Nothing \"}}]}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	model := types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL}
+	client, _ := NewClient(model, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, err := client.Stream(ctx, testSession(model))
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	if first := <-chunks; first.Text != "Nothing " {
+		t.Fatalf("Expected the first chunk before cancelling, got %+v", first)
+	}
+	cancel()
+	for range chunks {
+	}
+}
+
+func TestClientAppliesModelSettings(t *testing.T) {
+	attempts := 0
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Retried."}}]}`))
+	}))
+	defer server.Close()
+
+	temperature := 0.3
+	model := types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL, Retries: 1, Temperature: &temperature}
+	reply := send(t, model)
+
+	if reply.Content != "Retried." || attempts != 2 {
+		t.Errorf("Expected one retry after the server error, got %d attempts and %q", attempts, reply.Content)
+	}
+	if body["temperature"] != 0.3 {
+		t.Errorf("Expected the model's temperature in the request, got %v", body["temperature"])
+	}
+}
+
+func TestAzureClient(t *testing.T) {
+	server, request, _ := captureServer(t, http.StatusOK,
+		`{"choices":[{"message":{"role":"assistant","content":"Nothing else."}}]}`)
+	reply := send(t, types.AIModel{Name: "gpt-4o", Provider: "azure", APIEndpoint: server.URL, Deployment: "prod", APIVersion: "2024-06-01", APIKey: "azure-key"})
+
+	if reply.Content != "Nothing else." {
+		t.Errorf("Unexpected reply: %q", reply.Content)
+	}
+	if request.Header.Get("api-key") != "azure-key" || request.Header.Get("Authorization") != "" {
+		t.Error("Expected Azure api-key auth instead of a bearer token")
+	}
+	if request.URL.Path != "/openai/deployments/prod/chat/completions" || request.URL.Query().Get("api-version") != "2024-06-01" {
+		t.Errorf("Expected the deployment URL, got %s", request.URL)
+	}
+}
+
+func TestLMStudioClientSendsNoKey(t *testing.T) {
+	server, request, body := captureServer(t, http.StatusOK,
+		`{"choices":[{"message":{"role":"assistant","content":"Local reply."}}]}`)
+	reply := send(t, types.AIModel{Name: "local-model", Provider: "lmstudio", APIEndpoint: server.URL})
+
+	if reply.Content != "Local reply." {
+		t.Errorf("Unexpected reply: %q", reply.Content)
+	}
+	if request.Header.Get("Authorization") != "" {
+		t.Error("Expected no auth header without a key")
+	}
+	if body["model"] != "local-model" {
+		t.Errorf("Expected the OpenAI-compatible payload, got %v", body)
+	}
+}
@@ -0,0 +1,94 @@
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Clipboard writes text to a system clipboard
+type Clipboard interface {
+	Available() bool
+	Write(text string) error
+}
+
+// Result describes where copied text ended up
+type Result struct {
+	Fallback bool   // true when the clipboard was unavailable
+	Path     string // file holding the text when Fallback is set
+}
+
+// commandClipboard pipes text into a platform clipboard command
+type commandClipboard struct {
+	name string
+	args []string
+}
+
+// System detects the platform clipboard command. The returned clipboard
+// reports unavailable in headless environments such as CI or SSH without X.
+func System() Clipboard {
+	switch runtime.GOOS {
+	case "darwin":
+		return commandClipboard{name: "pbcopy"}
+	case "windows":
+		return commandClipboard{name: "clip"}
+	}
+	
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		return commandClipboard{name: "wl-copy"}
+	}
+	if os.Getenv("DISPLAY") != "" {
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return commandClipboard{name: "xclip", args: []string{"-selection", "clipboard"}}
+		}
+		return commandClipboard{name: "xsel", args: []string{"--clipboard", "--input"}}
+	}
+	return commandClipboard{}
+}
+
+// Available reports whether the clipboard command exists
+func (c commandClipboard) Available() bool {
+	if c.name == "" {
+		return false
+	}
+	_, err := exec.LookPath(c.name)
+	return err == nil
+}
+
+// Write sends text to the clipboard command's stdin
+func (c commandClipboard) Write(text string) error {
+	if !c.Available() {
+		return fmt.Errorf("no clipboard available")
+	}
+	cmd := exec.Command(c.name, c.args...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// Copy writes text to the clipboard, falling back to a file in dir (the
+// system temp directory when empty) if the clipboard is missing or fails
+func Copy(cb Clipboard, text, dir string) (Result, error) {
+	if cb != nil && cb.Available() {
+		if err := cb.Write(text); err == nil {
+			return Result{}, nil
+		}
+	}
+	
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	file, err := os.CreateTemp(dir, fmt.Sprintf("ai-context-%s-*.md", time.Now().Format("20060102-150405")))
+	if err != nil {
+		return Result{}, fmt.Errorf("clipboard unavailable and fallback file failed: %w", err)
+	}
+	defer file.Close()
+	
+	if _, err := file.WriteString(text); err != nil {
+		return Result{}, fmt.Errorf("clipboard unavailable and fallback file failed: %w", err)
+	}
+	
+	return Result{Fallback: true, Path: file.Name()}, nil
+}
@@ -0,0 +1,51 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// failingClipboard claims to be available but fails every write
+type failingClipboard struct{}
+
+func (failingClipboard) Available() bool         { return true }
+func (failingClipboard) Write(text string) error { return errors.New("no display") }
+
+// recordingClipboard keeps the last written text
+type recordingClipboard struct {
+	text string
+}
+
+func (r *recordingClipboard) Available() bool { return true }
+func (r *recordingClipboard) Write(text string) error {
+	r.text = text
+	return nil
+}
+
+func TestCopyFallsBackToFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "clipboard_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	result, err := Copy(failingClipboard{}, "context body", tempDir)
+	if err != nil {
+		t.Fatalf("Expected fallback instead of error, got %v", err)
+	}
+	if !result.Fallback || result.Path == "" {
+		t.Fatalf("Expected fallback file to be reported, got %+v", result)
+	}
+	
+	content, err := os.ReadFile(result.Path)
+	if err != nil || string(content) != "context body" {
+		t.Errorf("Expected fallback file to hold the copied text, got %q (%v)", content, err)
+	}
+	
+	recorder := &recordingClipboard{}
+	result, err = Copy(recorder, "context body", tempDir)
+	if err != nil || result.Fallback || recorder.text != "context body" {
+		t.Errorf("Expected working clipboard to be used directly, got %+v (%v)", result, err)
+	}
+}
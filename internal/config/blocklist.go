@@ -0,0 +1,88 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Blocklist holds the paths and globs a project never wants in its context.
+// Entries are relative to the project root, so they apply to scans of any
+// folder inside it.
+type Blocklist struct {
+	Project string   `json:"project"`
+	Entries []string `json:"entries"`
+}
+
+// blocklistFile returns the file storing a project's blocklist
+func blocklistFile(configDir, projectRoot string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectRoot)))
+	return filepath.Join(configDir, "blocklists", hex.EncodeToString(sum[:8])+".json")
+}
+
+// LoadBlocklist returns the saved blocklist for a project, empty if none exists
+func LoadBlocklist(configDir, projectRoot string) (*Blocklist, error) {
+	blocklist := &Blocklist{Project: filepath.Clean(projectRoot)}
+
+	data, err := os.ReadFile(blocklistFile(configDir, projectRoot))
+	if os.IsNotExist(err) {
+		return blocklist, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, blocklist); err != nil {
+		return nil, err
+	}
+	return blocklist, nil
+}
+
+// Add appends an entry unless it is already present, reporting whether it was added
+func (b *Blocklist) Add(entry string) bool {
+	entry = filepath.ToSlash(filepath.Clean(entry))
+	for _, existing := range b.Entries {
+		if existing == entry {
+			return false
+		}
+	}
+	b.Entries = append(b.Entries, entry)
+	return true
+}
+
+// Remove drops an entry, reporting whether it was present
+func (b *Blocklist) Remove(entry string) bool {
+	entry = filepath.ToSlash(filepath.Clean(entry))
+	for i, existing := range b.Entries {
+		if existing == entry {
+			b.Entries = append(b.Entries[:i], b.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns returns the entries as absolute exclude patterns for a scan
+func (b *Blocklist) Patterns() []string {
+	patterns := make([]string, 0, len(b.Entries))
+	for _, entry := range b.Entries {
+		patterns = append(patterns, filepath.Join(b.Project, filepath.FromSlash(entry)))
+	}
+	return patterns
+}
+
+// SaveBlocklist persists a project's blocklist
+func SaveBlocklist(configDir string, blocklist *Blocklist) error {
+	file := blocklistFile(configDir, blocklist.Project)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(blocklist, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
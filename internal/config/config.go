@@ -2,35 +2,77 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"ai-context-cli/pkg/types"
 )
 
+// DefaultProfile is the profile stored in config.json
+const DefaultProfile = "default"
+
 type Config struct {
 	DefaultModel      string                    `json:"default_model"`
 	Models            []types.AIModel           `json:"models"`
 	ContextTemplates  []types.ContextTemplate   `json:"context_templates"`
+	MinimalChrome     bool                      `json:"minimal_chrome,omitempty"`
+	DashboardHome     bool                      `json:"dashboard_home,omitempty"`
+	NoHighlight       bool                      `json:"no_highlight,omitempty"` // show code in the preview without syntax colors
+	Mouse             bool                      `json:"mouse,omitempty"` // capture the mouse for clicks and wheel scrolling; blocks terminal text selection
+	MaxFileSize       int64                     `json:"max_file_size,omitempty"`
+	IncludeGenerated  bool                      `json:"include_generated,omitempty"` // scan lockfiles, generated code and duplicate copies
+	FenceLanguages    map[string]string         `json:"fence_languages,omitempty"`
+	StatusRefreshSecs int                       `json:"status_refresh_seconds,omitempty"`
+	MaxContextFiles   int                       `json:"max_context_files,omitempty"`
+	MaxContextBytes   int64                     `json:"max_context_bytes,omitempty"`
+	MaxContextTokens  int                       `json:"max_context_tokens,omitempty"`
+	PathStyle         string                    `json:"path_style,omitempty"` // "relative" (default) or "absolute"
+	Outline           string                    `json:"outline,omitempty"` // "off" (default), "add" or "only": API outline for Go files
+	FocusKeywords     []string                  `json:"focus_keywords,omitempty"` // files mentioning these are picked first
+	LargeFiles        string                    `json:"large_files,omitempty"` // "structural" (default), "head", "tail", "both" or "skip"
+	LargeFileLines    int                       `json:"large_file_lines,omitempty"` // lines kept from each oversized file (default 200)
+	ContentGrouping   string                    `json:"content_grouping,omitempty"` // "type" (default) or "directory": one content section per extension or top-level directory
+	SeparateTests     bool                      `json:"separate_tests,omitempty"` // put test files in their own "Tests" section
+	MaxSections       int                       `json:"max_content_sections,omitempty"` // keep only the highest-priority content sections (0: no cap)
+	DirectoryReadmes  bool                      `json:"directory_readmes,omitempty"` // add each subdirectory's README after the project structure
+	HeaderLevel       int                       `json:"header_level,omitempty"` // markdown level of section headers (default 1)
+	KeepLineEndings   bool                      `json:"keep_line_endings,omitempty"` // leave CRLF/CR line endings instead of converting them to LF
+	ReadBufferSize    int                       `json:"read_buffer_size,omitempty"` // bytes buffered when streaming file content (default 32768)
+	OutputDir         string                    `json:"output_dir,omitempty"` // where exports are written (default: cwd)
+	OutputFormat      string                    `json:"output_format,omitempty"` // "markdown" (default), "text", "json" or "xml"
+	NoRedaction       bool                      `json:"no_redaction,omitempty"` // include secrets in generated context unmasked
+	RedactSkip        []string                  `json:"redact_skip,omitempty"` // secret patterns to leave unmasked, e.g. "high-entropy"
+	RecentFolders     []string                  `json:"recent_folders,omitempty"` // folders picked in the browser, most recent first
+	Bookmarks         []string                  `json:"bookmarks,omitempty"`      // starred folders offered above the tree
 	ConfigDir         string                    `json:"-"`
+	Profile           string                    `json:"-"`
 }
 
-func Load() (*Config, error) {
+// DefaultConfigDir returns the directory holding config files and profiles
+func DefaultConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return filepath.Join(homeDir, ".ai-context-cli"), nil
+}
 
-	configDir := filepath.Join(homeDir, ".ai-context-cli")
-	configFile := filepath.Join(configDir, "config.json")
-
-	config := &Config{
+// defaultConfig returns the built-in configuration for a profile
+func defaultConfig(configDir, profile string) *Config {
+	return &Config{
 		ConfigDir: configDir,
+		Profile:   profile,
 		Models: []types.AIModel{
 			{
 				Name:     "gpt-3.5-turbo",
 				Provider: "openai",
 				APIEndpoint: "https://api.openai.com/v1/chat/completions",
+				MaxTokens:   16385,
 			},
 		},
 		ContextTemplates: []types.ContextTemplate{
@@ -43,9 +85,32 @@ func Load() (*Config, error) {
 			},
 		},
 	}
+}
+
+// Load loads the active profile from the default config directory
+func Load() (*Config, error) {
+	configDir, err := DefaultConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadProfile(configDir, ActiveProfile(configDir))
+}
+
+// LoadProfile loads a named profile, creating it with defaults if missing
+func LoadProfile(configDir, profile string) (*Config, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	if err := validateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	config := defaultConfig(configDir, profile)
+	configFile := profileFile(configDir, profile)
 
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		os.MkdirAll(configDir, 0755)
+		os.MkdirAll(filepath.Dir(configFile), 0755)
 		return config, config.Save()
 	}
 
@@ -59,15 +124,275 @@ func Load() (*Config, error) {
 	}
 
 	config.ConfigDir = configDir
+	config.Profile = profile
+	return config, nil
+}
+
+// CreateProfile creates a new profile seeded from base (or defaults if nil)
+func CreateProfile(configDir, profile string, base *Config) (*Config, error) {
+	if err := validateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	configFile := profileFile(configDir, profile)
+	if _, err := os.Stat(configFile); err == nil {
+		return nil, fmt.Errorf("profile %q already exists", profile)
+	}
+
+	config := defaultConfig(configDir, profile)
+	if base != nil {
+		copied := *base
+		copied.Models = append([]types.AIModel(nil), base.Models...)
+		copied.ContextTemplates = append([]types.ContextTemplate(nil), base.ContextTemplates...)
+		copied.ConfigDir = configDir
+		copied.Profile = profile
+		config = &copied
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		return nil, err
+	}
+	return config, config.Save()
+}
+
+// ListProfiles returns the default profile followed by named profiles
+func ListProfiles(configDir string) ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(configDir, "profiles"))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var named []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		named = append(named, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
+
+// ActiveProfile returns the persisted active profile name
+func ActiveProfile(configDir string) string {
+	data, err := os.ReadFile(filepath.Join(configDir, "active_profile"))
+	if err != nil {
+		return DefaultProfile
+	}
+
+	profile := strings.TrimSpace(string(data))
+	if validateProfileName(profile) != nil {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// SwitchProfile makes an existing profile active and returns it
+func SwitchProfile(configDir, profile string) (*Config, error) {
+	if err := validateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	if profile != DefaultProfile {
+		if _, err := os.Stat(profileFile(configDir, profile)); err != nil {
+			return nil, fmt.Errorf("profile %q not found", profile)
+		}
+	}
+
+	config, err := LoadProfile(configDir, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	activeFile := filepath.Join(configDir, "active_profile")
+	if err := os.WriteFile(activeFile, []byte(profile+"\n"), 0644); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// ActiveModel returns the default model, falling back to the first configured one
+func (c *Config) ActiveModel() (types.AIModel, bool) {
+	for _, model := range c.Models {
+		if model.Name == c.DefaultModel {
+			return model, true
+		}
+	}
+	if len(c.Models) > 0 {
+		return c.Models[0], true
+	}
+	return types.AIModel{}, false
+}
+
+// ModelConfigError reports an invalid model setting
+type ModelConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e *ModelConfigError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateModelConfig checks a model's editable settings
+func ValidateModelConfig(model types.AIModel) error {
+	if model.APIEndpoint != "" {
+		endpoint, err := url.Parse(model.APIEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return &ModelConfigError{Field: "base_url", Message: "must be an http or https URL"}
+		}
+	} else if model.Provider != "google" && model.Provider != "ollama" {
+		return &ModelConfigError{Field: "base_url", Message: "is required for " + model.Provider}
+	}
+	if model.Provider == "azure" && model.Deployment == "" && !strings.Contains(model.APIEndpoint, "/openai/deployments/") {
+		return &ModelConfigError{Field: "deployment", Message: "is required for azure"}
+	}
+	if model.TimeoutSecs < 0 || model.TimeoutSecs > 600 {
+		return &ModelConfigError{Field: "timeout", Message: "must be between 0 and 600 seconds"}
+	}
+	if model.Retries < 0 || model.Retries > 10 {
+		return &ModelConfigError{Field: "retries", Message: "must be between 0 and 10"}
+	}
+	if model.Temperature != nil && (*model.Temperature < 0 || *model.Temperature > 2) {
+		return &ModelConfigError{Field: "temperature", Message: "must be between 0 and 2"}
+	}
+	if model.MaxTokens < 0 {
+		return &ModelConfigError{Field: "max_tokens", Message: "must not be negative"}
+	}
+	return nil
+}
+
+// SaveModelConfig validates a model's settings, replaces the configured model
+// of the same name and saves the profile
+func (c *Config) SaveModelConfig(model types.AIModel) error {
+	if err := ValidateModelConfig(model); err != nil {
+		return err
+	}
+	for i, existing := range c.Models {
+		if existing.Name == model.Name {
+			c.Models[i] = model
+			return c.Save()
+		}
+	}
+	return fmt.Errorf("model %q is not configured", model.Name)
+}
+
+// RecentModels returns previously used models, most recent first
+func (c *Config) RecentModels() []types.AIModel {
+	var recent []types.AIModel
+	for _, model := range c.Models {
+		if !model.LastUsed.IsZero() {
+			recent = append(recent, model)
+		}
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].LastUsed.After(recent[j].LastUsed)
+	})
+	return recent
+}
+
+// MarkModelUsed records that the named model was used at the given time, so
+// RecentModels offers it; it reports whether the model is configured
+func (c *Config) MarkModelUsed(name string, at time.Time) bool {
+	for i := range c.Models {
+		if c.Models[i].Name == name {
+			c.Models[i].LastUsed = at
+			return true
+		}
+	}
+	return false
+}
+
+// NextRecentModel makes the next recently used model active and returns it
+func (c *Config) NextRecentModel() (types.AIModel, bool) {
+	recent := c.RecentModels()
+	if len(recent) == 0 {
+		return types.AIModel{}, false
+	}
+
+	current, _ := c.ActiveModel()
+	next := recent[0]
+	for i, model := range recent {
+		if model.Name == current.Name {
+			next = recent[(i+1)%len(recent)]
+			break
+		}
+	}
+
+	c.DefaultModel = next.Name
+	return next, true
+}
+
+// StatusRefreshInterval returns how often model connections are re-tested; zero means off
+func (c *Config) StatusRefreshInterval() time.Duration {
+	if c.StatusRefreshSecs <= 0 {
+		return 0
+	}
+	return time.Duration(c.StatusRefreshSecs) * time.Second
+}
+
+// ResetModels restores the built-in model set and clears the default model
+func (c *Config) ResetModels() {
+	c.Models = defaultConfig(c.ConfigDir, c.Profile).Models
+	c.DefaultModel = ""
+}
+
+// ResetScanSettings clears scan and output overrides so DefaultScanConfig applies again
+func (c *Config) ResetScanSettings() {
+	c.MaxFileSize = 0
+	c.IncludeGenerated = false
+	c.FenceLanguages = nil
+	c.MaxContextFiles = 0
+	c.MaxContextBytes = 0
+	c.MaxContextTokens = 0
+	c.PathStyle = ""
+	c.Outline = ""
+	c.FocusKeywords = nil
+	c.LargeFiles = ""
+	c.LargeFileLines = 0
+	c.OutputDir = ""
+	c.OutputFormat = ""
+	c.NoRedaction = false
+	c.RedactSkip = nil
+}
+
 func (c *Config) Save() error {
-	configFile := filepath.Join(c.ConfigDir, "config.json")
+	profile := c.Profile
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	configFile := profileFile(c.ConfigDir, profile)
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
 
 	return os.WriteFile(configFile, data, 0644)
-}
\ No newline at end of file
+}
+
+// profileFile returns the file backing a profile
+func profileFile(configDir, profile string) string {
+	if profile == DefaultProfile {
+		return filepath.Join(configDir, "config.json")
+	}
+	return filepath.Join(configDir, "profiles", profile+".json")
+}
+
+// validateProfileName rejects names that would escape the profiles directory
+func validateProfileName(profile string) error {
+	if profile == "" || profile == "." || profile == ".." ||
+		strings.ContainsAny(profile, `/\`) {
+		return fmt.Errorf("invalid profile name %q", profile)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ai-context-cli/pkg/types"
+)
+
+func TestLoadProfileCreatesDefaults(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "config_defaults_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+
+	config, err := LoadProfile(configDir, "")
+	if err != nil {
+		t.Fatalf("Failed to load default profile: %v", err)
+	}
+
+	if config.Profile != DefaultProfile {
+		t.Errorf("Expected profile %q, got %q", DefaultProfile, config.Profile)
+	}
+
+	if len(config.Models) == 0 {
+		t.Error("Expected default models")
+	}
+
+	if _, err := os.Stat(filepath.Join(configDir, "config.json")); err != nil {
+		t.Errorf("Expected config.json to be written: %v", err)
+	}
+}
+
+func TestCreateAndSwitchProfile(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "config_profile_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+
+	base, err := LoadProfile(configDir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load default profile: %v", err)
+	}
+
+	work, err := CreateProfile(configDir, "work", base)
+	if err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	work.Models = []types.AIModel{
+		{Name: "claude-3-sonnet", Provider: "anthropic"},
+		{Name: "gpt-4", Provider: "openai"},
+	}
+	if err := work.Save(); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	if _, err := CreateProfile(configDir, "work", nil); err == nil {
+		t.Error("Expected error creating duplicate profile")
+	}
+
+	profiles, err := ListProfiles(configDir)
+	if err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+	if len(profiles) != 2 || profiles[0] != DefaultProfile || profiles[1] != "work" {
+		t.Errorf("Expected profiles [default work], got %v", profiles)
+	}
+
+	switched, err := SwitchProfile(configDir, "work")
+	if err != nil {
+		t.Fatalf("Failed to switch profile: %v", err)
+	}
+
+	if ActiveProfile(configDir) != "work" {
+		t.Errorf("Expected active profile 'work', got %q", ActiveProfile(configDir))
+	}
+
+	if len(switched.Models) != 2 || switched.Models[0].Name != "claude-3-sonnet" {
+		t.Errorf("Expected work models after switch, got %v", switched.Models)
+	}
+
+	// Switching back restores the default model set
+	switched, err = SwitchProfile(configDir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to switch back: %v", err)
+	}
+	if len(switched.Models) != len(base.Models) || switched.Models[0].Name != base.Models[0].Name {
+		t.Errorf("Expected default models after switching back, got %v", switched.Models)
+	}
+}
+
+func TestSwitchProfileErrors(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "config_switch_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+
+	if _, err := SwitchProfile(configDir, "missing"); err == nil {
+		t.Error("Expected error switching to missing profile")
+	}
+
+	if _, err := CreateProfile(configDir, "../escape", nil); err == nil {
+		t.Error("Expected error for invalid profile name")
+	}
+}
+
+func TestCustomTemplatesLoadAndRoundTrip(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "config_templates_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(configDir)
+
+	data := `{
+  "context_templates": [
+    {"id": "security", "name": "Security Audit", "icon": "🔐", "description": "Look for vulnerabilities",
+     "preamble": "Audit the following code for security issues.", "size_factor": 0.6}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadProfile(configDir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(config.ContextTemplates) != 1 {
+		t.Fatalf("Expected 1 custom template, got %d", len(config.ContextTemplates))
+	}
+	security := config.ContextTemplates[0]
+	if security.Icon != "🔐" || security.SizeFactor != 0.6 || security.Instructions() != "Audit the following code for security issues." {
+		t.Errorf("Unexpected custom template %+v", security)
+	}
+
+	// Export and import into another profile, merging by ID
+	exported := filepath.Join(configDir, "templates.json")
+	if err := ExportTemplates(exported, config.ContextTemplates); err != nil {
+		t.Fatalf("Failed to export templates: %v", err)
+	}
+	imported, err := ImportTemplates(exported)
+	if err != nil {
+		t.Fatalf("Failed to import templates: %v", err)
+	}
+
+	other, err := LoadProfile(configDir, "other")
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+	if added, replaced := other.MergeTemplates(imported); added != 1 || replaced != 0 {
+		t.Errorf("Expected 1 added template, got %d added and %d replaced", added, replaced)
+	}
+	if added, replaced := other.MergeTemplates(imported); added != 0 || replaced != 1 {
+		t.Errorf("Expected re-import to replace, got %d added and %d replaced", added, replaced)
+	}
+
+	// Bare arrays work and IDs are derived from names
+	bare := filepath.Join(configDir, "bare.json")
+	os.WriteFile(bare, []byte(`[{"name": "API Docs!", "template": "Document this API: {{.context}}"}]`), 0644)
+	imported, err = ImportTemplates(bare)
+	if err != nil {
+		t.Fatalf("Failed to import bare array: %v", err)
+	}
+	if imported[0].ID != "api-docs" || imported[0].Instructions() != "Document this API:" {
+		t.Errorf("Unexpected imported template %+v", imported[0])
+	}
+}
+
+func TestSaveModelConfigValidatesAndPersists(t *testing.T) {
+	configDir := t.TempDir()
+	config, err := LoadProfile(configDir, "")
+	if err != nil {
+		t.Fatalf("Failed to load default profile: %v", err)
+	}
+
+	model := config.Models[0]
+	temperature := 0.2
+	model.TimeoutSecs = 30
+	model.Retries = 2
+	model.Temperature = &temperature
+	if err := config.SaveModelConfig(model); err != nil {
+		t.Fatalf("Failed to save model config: %v", err)
+	}
+
+	reloaded, err := LoadProfile(configDir, "")
+	if err != nil {
+		t.Fatalf("Failed to reload profile: %v", err)
+	}
+	saved := reloaded.Models[0]
+	if saved.TimeoutSecs != 30 || saved.Retries != 2 || saved.Temperature == nil || *saved.Temperature != 0.2 {
+		t.Errorf("Expected the edited settings to persist, got %+v", saved)
+	}
+
+	tooHot := 3.0
+	invalid := []struct {
+		field  string
+		change func(*types.AIModel)
+	}{
+		{"base_url", func(m *types.AIModel) { m.APIEndpoint = "not a url" }},
+		{"timeout", func(m *types.AIModel) { m.TimeoutSecs = -1 }},
+		{"retries", func(m *types.AIModel) { m.Retries = 11 }},
+		{"temperature", func(m *types.AIModel) { m.Temperature = &tooHot }},
+		{"max_tokens", func(m *types.AIModel) { m.MaxTokens = -5 }},
+	}
+	for _, tt := range invalid {
+		edited := model
+		tt.change(&edited)
+		err := config.SaveModelConfig(edited)
+		fieldErr, ok := err.(*ModelConfigError)
+		if !ok || fieldErr.Field != tt.field {
+			t.Errorf("Expected a %s error, got %v", tt.field, err)
+		}
+	}
+
+	if err := config.SaveModelConfig(types.AIModel{Name: "missing", Provider: "ollama"}); err == nil {
+		t.Error("Expected saving an unconfigured model to fail")
+	}
+}
+
+func TestSaveAndDeleteTemplate(t *testing.T) {
+	configDir := t.TempDir()
+	config, err := LoadProfile(configDir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if _, err := config.SaveTemplate(types.ContextTemplate{Name: "  "}); err == nil {
+		t.Error("Expected a template without a name to be rejected")
+	}
+	saved, err := config.SaveTemplate(types.ContextTemplate{Name: "Code Review", Template: "Review {{.ProjectName}}"})
+	if err != nil {
+		t.Fatalf("Failed to save template: %v", err)
+	}
+	if saved.ID != "code-review" {
+		t.Errorf("Expected an ID derived from the name, got %q", saved.ID)
+	}
+	saved.Template = "Review {{.ProjectName}} for {{.Focus}}"
+	if _, err := config.SaveTemplate(saved); err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+
+	reloaded, err := LoadProfile(configDir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	defaults := len(config.ContextTemplates) - 1
+	if len(reloaded.ContextTemplates) != defaults+1 || reloaded.ContextTemplates[defaults].Template != saved.Template {
+		t.Fatalf("Expected the updated template to persist, got %+v", reloaded.ContextTemplates)
+	}
+
+	if err := reloaded.DeleteTemplate("code-review"); err != nil {
+		t.Fatalf("Failed to delete template: %v", err)
+	}
+	if err := reloaded.DeleteTemplate("code-review"); err == nil {
+		t.Error("Expected deleting a missing template to fail")
+	}
+	if reloaded, _ = LoadProfile(configDir, DefaultProfile); len(reloaded.ContextTemplates) != defaults {
+		t.Errorf("Expected no templates after delete, got %+v", reloaded.ContextTemplates)
+	}
+}
+
+func TestRecentFoldersAndBookmarks(t *testing.T) {
+	config := &Config{}
+	for i := 0; i < MaxRecentFolders+2; i++ {
+		config.AddRecentFolder(fmt.Sprintf("/work/project%d", i))
+	}
+	config.AddRecentFolder("/work/project5/")
+	if len(config.RecentFolders) != MaxRecentFolders {
+		t.Fatalf("Expected %d recent folders, got %v", MaxRecentFolders, config.RecentFolders)
+	}
+	if config.RecentFolders[0] != "/work/project5" || config.RecentFolders[1] != "/work/project11" {
+		t.Errorf("Expected a repeat to move to the front without duplicating, got %v", config.RecentFolders)
+	}
+
+	if !config.ToggleBookmark("/work/api") || !config.IsBookmarked("/work/api/") {
+		t.Error("Expected the folder to be bookmarked")
+	}
+	if config.ToggleBookmark("/work/api") || config.IsBookmarked("/work/api") || len(config.Bookmarks) != 0 {
+		t.Errorf("Expected a second toggle to remove the bookmark, got %v", config.Bookmarks)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "path/filepath"
+
+// MaxRecentFolders is how many recently picked folders are remembered
+const MaxRecentFolders = 10
+
+// AddRecentFolder moves path to the front of the recent folders, dropping the oldest beyond MaxRecentFolders
+func (c *Config) AddRecentFolder(path string) {
+	path = filepath.Clean(path)
+	recent := []string{path}
+	for _, existing := range c.RecentFolders {
+		if existing != path && len(recent) < MaxRecentFolders {
+			recent = append(recent, existing)
+		}
+	}
+	c.RecentFolders = recent
+}
+
+// IsBookmarked reports whether path is starred
+func (c *Config) IsBookmarked(path string) bool {
+	path = filepath.Clean(path)
+	for _, bookmark := range c.Bookmarks {
+		if bookmark == path {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleBookmark stars or unstars path, reporting whether it is now bookmarked
+func (c *Config) ToggleBookmark(path string) bool {
+	path = filepath.Clean(path)
+	for i, bookmark := range c.Bookmarks {
+		if bookmark == path {
+			c.Bookmarks = append(c.Bookmarks[:i], c.Bookmarks[i+1:]...)
+			return false
+		}
+	}
+	c.Bookmarks = append(c.Bookmarks, path)
+	return true
+}
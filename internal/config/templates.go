@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"ai-context-cli/pkg/types"
+)
+
+// templateFile is the JSON document used to share context templates
+type templateFile struct {
+	Templates []types.ContextTemplate `json:"templates"`
+}
+
+// ExportTemplates writes templates to a JSON file that ImportTemplates can read
+func ExportTemplates(path string, templates []types.ContextTemplate) error {
+	data, err := json.MarshalIndent(templateFile{Templates: templates}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ImportTemplates reads templates from a JSON file, either an exported document
+// or a bare array. Templates without an ID get one derived from their name.
+func ImportTemplates(path string) ([]types.ContextTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	
+	var document templateFile
+	if err := json.Unmarshal(data, &document); err != nil {
+		if arrayErr := json.Unmarshal(data, &document.Templates); arrayErr != nil {
+			return nil, fmt.Errorf("invalid template file %s: %w", path, err)
+		}
+	}
+	
+	for i, template := range document.Templates {
+		if strings.TrimSpace(template.Name) == "" {
+			return nil, fmt.Errorf("template %d in %s has no name", i+1, path)
+		}
+		if template.ID == "" {
+			document.Templates[i].ID = templateID(template.Name)
+		}
+	}
+	return document.Templates, nil
+}
+
+// MergeTemplates adds templates to the config, replacing any with the same ID
+func (c *Config) MergeTemplates(templates []types.ContextTemplate) (added, replaced int) {
+	for _, template := range templates {
+		found := false
+		for i, existing := range c.ContextTemplates {
+			if existing.ID == template.ID {
+				c.ContextTemplates[i] = template
+				found = true
+				replaced++
+				break
+			}
+		}
+		if !found {
+			c.ContextTemplates = append(c.ContextTemplates, template)
+			added++
+		}
+	}
+	return added, replaced
+}
+
+// templateID derives a stable identifier from a template name
+func templateID(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	return strings.Join(fields, "-")
+}
+
+// SaveTemplate adds or replaces a template by ID and writes the config.
+// A template without an ID gets one derived from its name.
+func (c *Config) SaveTemplate(template types.ContextTemplate) (types.ContextTemplate, error) {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return template, fmt.Errorf("template has no name")
+	}
+	if template.ID == "" {
+		template.ID = templateID(template.Name)
+	}
+	c.MergeTemplates([]types.ContextTemplate{template})
+	return template, c.Save()
+}
+
+// DeleteTemplate removes a template by ID and writes the config
+func (c *Config) DeleteTemplate(id string) error {
+	for i, existing := range c.ContextTemplates {
+		if existing.ID == id {
+			c.ContextTemplates = append(c.ContextTemplates[:i:i], c.ContextTemplates[i+1:]...)
+			return c.Save()
+		}
+	}
+	return fmt.Errorf("template %q not found", id)
+}
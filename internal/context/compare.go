@@ -0,0 +1,173 @@
+package context
+
+import "sort"
+
+// Section statuses in a ContextComparison
+const (
+	SectionAdded     = "added"
+	SectionRemoved   = "removed"
+	SectionChanged   = "changed"
+	SectionUnchanged = "unchanged"
+)
+
+// SectionChange describes how one section differs between two contexts.
+// Sections are matched by title.
+type SectionChange struct {
+	Title        string
+	Status       string // SectionAdded, SectionRemoved, SectionChanged or SectionUnchanged
+	OldTokens    int
+	NewTokens    int
+	AddedFiles   []string
+	RemovedFiles []string
+	ChangedFiles []string // in both, with different contents
+}
+
+// TokenDelta is how many tokens the section gained, negative if it shrank
+func (c SectionChange) TokenDelta() int {
+	return c.NewTokens - c.OldTokens
+}
+
+// ContextComparison is a section by section diff of two generated contexts
+type ContextComparison struct {
+	Sections     []SectionChange
+	AddedFiles   []string // files in the new context only, sorted
+	RemovedFiles []string // files in the old context only, sorted
+	OldTokens    int
+	NewTokens    int
+}
+
+// TokenDelta is how many tokens the new context gained over the old one
+func (c ContextComparison) TokenDelta() int {
+	return c.NewTokens - c.OldTokens
+}
+
+// Changed reports whether the contexts differ at all
+func (c ContextComparison) Changed() bool {
+	for _, section := range c.Sections {
+		if section.Status != SectionUnchanged {
+			return true
+		}
+	}
+	return len(c.AddedFiles) > 0 || len(c.RemovedFiles) > 0
+}
+
+// CompareContexts diffs old against new: sections in new's order followed by
+// the sections only old has, with the files each gained, lost or changed
+func CompareContexts(old, new *ContextResult) ContextComparison {
+	comparison := ContextComparison{
+		OldTokens: old.TokenEstimate,
+		NewTokens: new.TokenEstimate,
+	}
+
+	oldSections := make(map[string]ContextSection, len(old.Sections))
+	for _, section := range old.Sections {
+		if _, exists := oldSections[section.Title]; !exists {
+			oldSections[section.Title] = section
+		}
+	}
+	seen := make(map[string]bool, len(new.Sections))
+	for _, section := range new.Sections {
+		if seen[section.Title] {
+			continue
+		}
+		seen[section.Title] = true
+		if previous, ok := oldSections[section.Title]; ok {
+			comparison.Sections = append(comparison.Sections, compareSection(previous, section))
+			continue
+		}
+		comparison.Sections = append(comparison.Sections, SectionChange{
+			Title:      section.Title,
+			Status:     SectionAdded,
+			NewTokens:  sectionTokens(section),
+			AddedFiles: sortedFiles(section.Files),
+		})
+	}
+	for _, section := range old.Sections {
+		if seen[section.Title] {
+			continue
+		}
+		seen[section.Title] = true
+		comparison.Sections = append(comparison.Sections, SectionChange{
+			Title:        section.Title,
+			Status:       SectionRemoved,
+			OldTokens:    sectionTokens(section),
+			RemovedFiles: sortedFiles(section.Files),
+		})
+	}
+
+	oldFiles, newFiles := contextFiles(old), contextFiles(new)
+	comparison.AddedFiles = missingFrom(newFiles, oldFiles)
+	comparison.RemovedFiles = missingFrom(oldFiles, newFiles)
+	return comparison
+}
+
+// compareSection diffs two sections sharing a title
+func compareSection(old, new ContextSection) SectionChange {
+	change := SectionChange{
+		Title:     new.Title,
+		Status:    SectionUnchanged,
+		OldTokens: sectionTokens(old),
+		NewTokens: sectionTokens(new),
+	}
+	oldFiles, newFiles := fileSet(old.Files), fileSet(new.Files)
+	change.AddedFiles = missingFrom(newFiles, oldFiles)
+	change.RemovedFiles = missingFrom(oldFiles, newFiles)
+
+	oldDocuments := make(map[string]string, len(old.Documents))
+	for _, document := range old.Documents {
+		oldDocuments[document.Path] = document.Content
+	}
+	for _, document := range new.Documents {
+		if content, ok := oldDocuments[document.Path]; ok && content != document.Content {
+			change.ChangedFiles = append(change.ChangedFiles, document.Path)
+		}
+	}
+	sort.Strings(change.ChangedFiles)
+
+	if old.Content != new.Content {
+		change.Status = SectionChanged
+	}
+	return change
+}
+
+// sectionTokens estimates a section's tokens the way the generator does
+func sectionTokens(section ContextSection) int {
+	return len(section.Content) / 4
+}
+
+// contextFiles returns every file whose contents a context includes
+func contextFiles(result *ContextResult) map[string]bool {
+	files := make(map[string]bool)
+	for _, section := range result.Sections {
+		for _, file := range section.Files {
+			files[file] = true
+		}
+	}
+	return files
+}
+
+func fileSet(files []string) map[string]bool {
+	set := make(map[string]bool, len(files))
+	for _, file := range files {
+		set[file] = true
+	}
+	return set
+}
+
+// missingFrom returns the files in have that other lacks, sorted
+func missingFrom(have, other map[string]bool) []string {
+	var missing []string
+	for file := range have {
+		if !other[file] {
+			missing = append(missing, file)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func sortedFiles(files []string) []string {
+	sorted := append([]string(nil), files...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,463 @@
+package context
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Compression is a set of ways to shrink file contents, each trading some
+// fidelity for tokens
+type Compression uint8
+
+const (
+	CompressLicense    Compression = 1 << iota // license headers at the top of files
+	CompressComments                           // comments
+	CompressBlankLines                         // runs of blank lines, kept to one
+	CompressData                               // insignificant whitespace in JSON and YAML
+)
+
+// CompressionModes lists every compression mode in the order they are applied
+var CompressionModes = []Compression{CompressLicense, CompressComments, CompressData, CompressBlankLines}
+
+var compressionNames = map[Compression]string{
+	CompressLicense:    "license",
+	CompressComments:   "comments",
+	CompressBlankLines: "blank-lines",
+	CompressData:       "data",
+}
+
+var compressionDescriptions = map[Compression]string{
+	CompressLicense:    "Strip license headers",
+	CompressComments:   "Strip comments",
+	CompressBlankLines: "Collapse blank lines",
+	CompressData:       "Minify JSON and YAML",
+}
+
+// Has reports whether every mode in mode is part of c
+func (c Compression) Has(mode Compression) bool {
+	return c&mode == mode
+}
+
+// String returns the flag value for c: its mode names joined by commas, or "none"
+func (c Compression) String() string {
+	var names []string
+	for _, mode := range CompressionModes {
+		if c.Has(mode) {
+			names = append(names, compressionNames[mode])
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
+// Description describes a single compression mode
+func (c Compression) Description() string {
+	return compressionDescriptions[c]
+}
+
+// ParseCompression parses a comma-separated list of modes (license, comments,
+// blank-lines, data), "all" or "none"
+func ParseCompression(value string) (Compression, error) {
+	var compression Compression
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "", "none":
+			continue
+		case "all":
+			for _, mode := range CompressionModes {
+				compression |= mode
+			}
+			continue
+		}
+		found := false
+		for mode, modeName := range compressionNames {
+			if name == modeName {
+				compression |= mode
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown compression %q (use license, comments, blank-lines, data, all or none)", name)
+		}
+	}
+	return compression, nil
+}
+
+// ApplyCompression returns a copy of result whose files are compressed by
+// every mode in compression. Files that lost text are noted with the modes
+// that removed it; whitespace alone changes no meaning and goes unnoted. The
+// input is left unchanged.
+func ApplyCompression(result *ContextResult, compression Compression) *ContextResult {
+	if compression == 0 {
+		transformed := *result
+		return &transformed
+	}
+	return rewriteDocuments(result, func(document FileDocument) FileDocument {
+		var applied []string
+		for _, mode := range CompressionModes {
+			if !compression.Has(mode) {
+				continue
+			}
+			compressed := compressContent(document.Content, document.Language, mode)
+			if compressed != document.Content && (mode == CompressLicense || mode == CompressComments) {
+				applied = append(applied, compressionNames[mode])
+			}
+			document.Content = compressed
+		}
+		if len(applied) > 0 {
+			document.Note = appendNote(document.Note, "Compressed: "+strings.Join(applied, ", "))
+		}
+		return document
+	})
+}
+
+// CompressionSaving is how many tokens one compression mode would save
+type CompressionSaving struct {
+	Mode   Compression
+	Tokens int
+}
+
+// CompressionSavings estimates the tokens each mode saves on its own
+func CompressionSavings(result *ContextResult) []CompressionSaving {
+	chars := len(result.Summary)
+	for _, section := range result.Sections {
+		chars += len(section.Content)
+	}
+
+	savings := make([]CompressionSaving, 0, len(CompressionModes))
+	for _, mode := range CompressionModes {
+		savings = append(savings, CompressionSaving{
+			Mode:   mode,
+			Tokens: chars/4 - ApplyCompression(result, mode).TokenEstimate,
+		})
+	}
+	return savings
+}
+
+// apply runs every mode in c over one file's content, in CompressionModes order
+func (c Compression) apply(content, language string) string {
+	for _, mode := range CompressionModes {
+		if c.Has(mode) {
+			content = compressContent(content, language, mode)
+		}
+	}
+	return content
+}
+
+// compressContent applies a single mode to one file's content
+func compressContent(content, language string, mode Compression) string {
+	switch mode {
+	case CompressLicense:
+		return stripLicenseHeader(content)
+	case CompressComments:
+		return stripComments(content, language)
+	case CompressBlankLines:
+		return collapseBlankLines(content)
+	case CompressData:
+		switch language {
+		case "json":
+			var compacted bytes.Buffer
+			if err := json.Compact(&compacted, []byte(content)); err == nil {
+				return compacted.String()
+			}
+		case "yaml":
+			return minifyYAML(content)
+		}
+	}
+	return content
+}
+
+// licenseMarkers are words that make a leading comment a license header
+var licenseMarkers = []string{"copyright", "license", "licence", "spdx-license-identifier"}
+
+// stripLicenseHeader removes a license comment at the top of a file, after
+// any shebang, along with the blank lines that follow it
+func stripLicenseHeader(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	start := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+		start = 1
+	}
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) {
+		return content
+	}
+
+	// The header is one block comment or a run of line comments
+	end := start
+	first := strings.TrimSpace(lines[start])
+	var opener, closer string
+	for _, pair := range [][2]string{{"/*", "*/"}, {"<!--", "-->"}, {`"""`, `"""`}} {
+		if strings.HasPrefix(first, pair[0]) {
+			opener, closer = pair[0], pair[1]
+		}
+	}
+	if opener != "" {
+		if !strings.Contains(first[len(opener):], closer) {
+			end++
+			for end < len(lines) && !strings.Contains(lines[end], closer) {
+				end++
+			}
+		}
+		end++
+	} else {
+		prefix := ""
+		for _, candidate := range []string{"//", "#", "--", ";"} {
+			if strings.HasPrefix(first, candidate) {
+				prefix = candidate
+			}
+		}
+		if prefix == "" {
+			return content
+		}
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), prefix) {
+			end++
+		}
+	}
+	if end > len(lines) {
+		return content
+	}
+
+	header := strings.ToLower(strings.Join(lines[start:end], ""))
+	isLicense := false
+	for _, marker := range licenseMarkers {
+		isLicense = isLicense || strings.Contains(header, marker)
+	}
+	if !isLicense {
+		return content
+	}
+	for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+		end++
+	}
+	return strings.Join(lines[:start], "") + strings.Join(lines[end:], "")
+}
+
+// hashCommentLanguages start comments with "#"
+var hashCommentLanguages = map[string]bool{
+	"python": true, "ruby": true, "bash": true, "zsh": true, "fish": true,
+	"yaml": true, "toml": true, "r": true, "powershell": true,
+}
+
+// stripComments removes comments, dropping lines left empty by it. Go
+// directives and shebangs are kept; languages without a known comment
+// syntax are returned unchanged. Like the symbol extractors this is lexical,
+// so a "//" inside a JavaScript regex literal is taken for a comment.
+func stripComments(content, language string) string {
+	switch {
+	case language == "css":
+		return dropEmptied(content, blockComments(content, "/*", "*/"))
+	case language == "html" || language == "xml" || language == "markdown":
+		return dropEmptied(content, blockComments(content, "<!--", "-->"))
+	case braceLanguages[language]:
+		return dropEmptied(content, slashComments(content))
+	case hashCommentLanguages[language]:
+		return dropEmptied(content, hashComments(content, language == "python"))
+	}
+	return content
+}
+
+// commentSpan is the byte range of one comment
+type commentSpan struct{ start, end int }
+
+// dropEmptied removes the comment spans from content, then drops lines that
+// held only comments
+func dropEmptied(content string, spans []commentSpan) string {
+	if len(spans) == 0 {
+		return content
+	}
+	var out, line strings.Builder
+	hadComment := false
+	flush := func(newline bool) {
+		text := line.String()
+		if hadComment {
+			text = strings.TrimRight(text, " \t")
+		}
+		if !hadComment || strings.TrimSpace(text) != "" {
+			out.WriteString(text)
+			if newline {
+				out.WriteByte('\n')
+			}
+		}
+		line.Reset()
+		hadComment = false
+	}
+
+	next := 0
+	for i := 0; i < len(content); i++ {
+		if next < len(spans) && i == spans[next].start {
+			i = spans[next].end - 1
+			next++
+			hadComment = true
+			continue
+		}
+		if content[i] == '\n' {
+			flush(true)
+			continue
+		}
+		line.WriteByte(content[i])
+	}
+	flush(false)
+	return out.String()
+}
+
+// slashComments finds "//" and "/* */" comments outside strings, keeping Go
+// build and compiler directives
+func slashComments(content string) []commentSpan {
+	var spans []commentSpan
+	for i := 0; i < len(content); {
+		if end, ok := skipComment(content, i); ok {
+			comment := content[i:end]
+			if !strings.HasPrefix(comment, "//go:") && !strings.HasPrefix(comment, "// +build") {
+				spans = append(spans, commentSpan{i, end})
+			}
+			i = end
+			continue
+		}
+		switch content[i] {
+		case '"', '\'', '`':
+			i = skipString(content, i)
+			continue
+		}
+		i++
+	}
+	return spans
+}
+
+// blockComments finds comments between open and close
+func blockComments(content, open, close string) []commentSpan {
+	var spans []commentSpan
+	for from := 0; ; {
+		start := strings.Index(content[from:], open)
+		if start < 0 {
+			return spans
+		}
+		start += from
+		end := strings.Index(content[start+len(open):], close)
+		if end < 0 {
+			return append(spans, commentSpan{start, len(content)})
+		}
+		end += start + len(open) + len(close)
+		spans = append(spans, commentSpan{start, end})
+		from = end
+	}
+}
+
+// hashComments finds "#" comments that start a line or follow whitespace,
+// outside quoted strings and, for Python, triple-quoted strings. A leading
+// shebang is kept.
+func hashComments(content string, python bool) []commentSpan {
+	var spans []commentSpan
+	offset := 0
+	inString := ""
+	for number, line := range strings.SplitAfter(content, "\n") {
+		if inString != "" || (number == 0 && strings.HasPrefix(line, "#!")) {
+			if python {
+				inString = tripleQuoteState(line, inString)
+			}
+			offset += len(line)
+			continue
+		}
+
+		quote := byte(0)
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+			case python && (strings.HasPrefix(line[j:], `"""`) || strings.HasPrefix(line[j:], `'''`)):
+				// Triple-quoted strings are tracked line by line below
+				j = len(line)
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#' && (j == 0 || line[j-1] == ' ' || line[j-1] == '\t'):
+				end := len(strings.TrimRight(line, "\n"))
+				spans = append(spans, commentSpan{offset + j, offset + end})
+				j = len(line)
+			}
+		}
+		if python {
+			inString = tripleQuoteState(line, inString)
+		}
+		offset += len(line)
+	}
+	return spans
+}
+
+// collapseBlankLines keeps at most one blank line in a row and strips
+// trailing whitespace from blank lines
+func collapseBlankLines(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+			kept = append(kept, "")
+			continue
+		}
+		blank = false
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// minifyYAML drops blank lines and trailing whitespace, except inside block
+// scalars (values introduced by "|" or ">"), whose text is kept exactly
+func minifyYAML(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := make([]string, 0, len(lines))
+	scalarIndent := -1 // indentation of the key owning the open block scalar
+	for _, line := range lines {
+		if scalarIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indentWidth(line) > scalarIndent {
+				kept = append(kept, line)
+				continue
+			}
+			scalarIndent = -1
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		kept = append(kept, trimmed)
+		if opensBlockScalar(trimmed) {
+			scalarIndent = indentWidth(trimmed)
+		}
+	}
+	// Trailing blank lines kept inside a final block scalar are not content
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+	return strings.Join(kept, "\n")
+}
+
+// opensBlockScalar reports whether a YAML line ends in a block scalar indicator
+func opensBlockScalar(line string) bool {
+	if index := strings.Index(line, " #"); index >= 0 {
+		line = line[:index]
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return false
+	}
+	owner, indicator := fields[len(fields)-2], fields[len(fields)-1]
+	if !strings.HasSuffix(owner, ":") && owner != "-" {
+		return false
+	}
+	return (indicator[0] == '|' || indicator[0] == '>') &&
+		strings.Trim(indicator[1:], "+-0123456789") == ""
+}
@@ -0,0 +1,175 @@
+package context
+
+import (
+	stdcontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChangedFile is a file that differs from a base revision, with its unified diff
+type ChangedFile struct {
+	Path   string // absolute path in the working tree
+	Status string // added, modified, deleted, renamed or untracked
+	Diff   string // empty for untracked files, whose contents are the whole change
+}
+
+// GenerateDiffContext creates context for a change set: a list of the changed
+// files, their diffs against base and the current contents of files that still
+// exist. Files the scan configuration would exclude (binaries, ignored patterns,
+// generated code) are only named, and redaction, path style, the size limit and
+// cancellation apply as for a scan.
+func (cg *ContextGenerator) GenerateDiffContext(ctx stdcontext.Context, config ScanConfig, base string, files []ChangedFile, projectName string) (*ContextResult, error) {
+	cg.root = config.RootPath
+	cg.redactions = nil
+	cg.truncations = nil
+	cg.written = 0
+	
+	var excluded []string
+	files, excluded = cg.filterChanges(config, files)
+	
+	if cg.sizeLimit.MaxFiles > 0 && len(files) > cg.sizeLimit.MaxFiles {
+		return nil, &LimitExceededError{
+			Limit: cg.sizeLimit,
+			Files: len(files),
+			Violations: []string{
+				fmt.Sprintf("%s files (limit %s)", FormatNumber(len(files)), FormatNumber(cg.sizeLimit.MaxFiles)),
+			},
+		}
+	}
+	
+	result := &ContextResult{
+		ProjectName: projectName,
+		GeneratedAt: cg.now(),
+		TotalFiles:  len(files),
+		Sections:    make([]ContextSection, 0),
+	}
+	
+	// Changed file list
+	var overview strings.Builder
+	overview.WriteString(cg.heading(1, fmt.Sprintf("Changes since %s", base)))
+	overview.WriteString(fmt.Sprintf("**Changed files:** %d\n\n", len(files)))
+	for _, file := range files {
+		overview.WriteString(fmt.Sprintf("- %s (%s)\n", cg.getRelativePath(file.Path), file.Status))
+	}
+	overview.WriteString("\n")
+	if len(excluded) > 0 {
+		overview.WriteString(fmt.Sprintf("**Left out by the scan rules:** %d\n\n", len(excluded)))
+		for _, line := range excluded {
+			overview.WriteString(fmt.Sprintf("- %s\n", line))
+		}
+		overview.WriteString("\n")
+	}
+	result.Sections = append(result.Sections, ContextSection{Title: "Changes", Content: overview.String()})
+	
+	// Diffs, one document per file
+	diffs := ContextSection{Title: "Diff", IsContent: true}
+	var diffContent strings.Builder
+	diffContent.WriteString(cg.heading(1, "Diff"))
+	for _, file := range files {
+		if file.Diff == "" {
+			continue
+		}
+		relativePath := cg.getRelativePath(file.Path)
+		diff := cg.redact(relativePath, strings.TrimRight(file.Diff, "\n"))
+		diffContent.WriteString(cg.heading(2, relativePath))
+		diffContent.WriteString(fmt.Sprintf("```diff\n%s\n```\n\n", diff))
+		diffs.Files = append(diffs.Files, relativePath)
+		diffs.Documents = append(diffs.Documents, FileDocument{Path: relativePath, Language: "diff", Content: diff})
+	}
+	diffs.Content = diffContent.String()
+	if len(diffs.Documents) > 0 {
+		result.Sections = append(result.Sections, diffs)
+	}
+	
+	// Current contents of files that were not deleted
+	if cg.includeContent {
+		var current []FileInfo
+		for _, file := range files {
+			if file.Status == "deleted" {
+				continue
+			}
+			info, err := os.Stat(file.Path)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			current = append(current, FileInfo{Path: file.Path, Size: info.Size(), Extension: strings.ToLower(filepath.Ext(file.Path))})
+			result.TotalSize += info.Size()
+		}
+		if len(current) > 0 {
+			section, err := cg.generateContentSection(ctx, "Changed Files", current)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate content sections: %w", err)
+			}
+			result.Sections = append(result.Sections, section)
+		}
+	}
+	result.Redactions = cg.redactions
+	result.Truncations = cg.truncations
+	
+	if cg.includeSummary {
+		result.Summary = cg.generateDiffSummary(base, files, result)
+	}
+	result.TokenEstimate = cg.estimateTokens(result)
+	
+	if err := cg.sizeLimit.checkOutput(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// filterChanges drops the changed files a scan with config would exclude,
+// returning the rest and a description of each file dropped. Deleted files
+// can only be checked against the path rules.
+func (cg *ContextGenerator) filterChanges(config ScanConfig, files []ChangedFile) ([]ChangedFile, []string) {
+	var kept []ChangedFile
+	var excluded []string
+	for _, file := range files {
+		var reason string
+		if file.Status == "deleted" {
+			reason = config.pathExclusion(file.Path)
+		} else if skip, why := config.ExplainExclusion(file.Path); skip {
+			reason = why
+		}
+		if reason != "" {
+			excluded = append(excluded, fmt.Sprintf("%s (%s, %s)", cg.getRelativePath(file.Path), file.Status, reason))
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept, excluded
+}
+
+// generateDiffSummary counts the changes by status
+func (cg *ContextGenerator) generateDiffSummary(base string, files []ChangedFile, result *ContextResult) string {
+	var summary strings.Builder
+	
+	counts := make(map[string]int)
+	for _, file := range files {
+		counts[file.Status]++
+	}
+	var parts []string
+	for _, status := range []string{"added", "modified", "renamed", "deleted", "untracked"} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	
+	summary.WriteString(cg.heading(2, "Context Summary"))
+	summary.WriteString(fmt.Sprintf("This context contains the %d files changed since %s", len(files), base))
+	if len(parts) > 0 {
+		summary.WriteString(fmt.Sprintf(" (%s)", strings.Join(parts, ", ")))
+	}
+	summary.WriteString(", with their diffs and current contents.")
+	
+	if len(result.Redactions) > 0 {
+		summary.WriteString(fmt.Sprintf("\n\n%d possible secrets were redacted:\n\n", len(result.Redactions)))
+		for _, redaction := range result.Redactions {
+			summary.WriteString(fmt.Sprintf("- %s:%d (%s)\n", redaction.Path, redaction.Line, redaction.Pattern))
+		}
+	}
+	writeTruncations(&summary, result.Truncations)
+	
+	return summary.String()
+}
@@ -0,0 +1,145 @@
+package context
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// sniffSize is how much of a file is read to detect its encoding
+const sniffSize = 8 * 1024
+
+// Encodings recorded in FileInfo.Encoding
+const (
+	EncodingUTF8   = "utf-8"
+	EncodingLatin1 = "iso-8859-1" // text that is not valid UTF-8
+	EncodingUTF16  = "utf-16"     // text with a UTF-16 byte order mark; excluded like binaries
+	EncodingBinary = "binary"
+)
+
+// DetectEncoding classifies the first bytes of a file. NUL bytes or many
+// control characters mean binary; otherwise the sample is UTF-8 if valid,
+// allowing for a character cut off at the end of the sample.
+func DetectEncoding(sample []byte) string {
+	if len(sample) > sniffSize {
+		sample = sample[:sniffSize]
+	}
+	if bytes.HasPrefix(sample, []byte{0xFF, 0xFE}) || bytes.HasPrefix(sample, []byte{0xFE, 0xFF}) {
+		return EncodingUTF16
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return EncodingBinary
+	}
+
+	control := 0
+	for _, b := range sample {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\v' && b != 0x1B {
+			control++
+		}
+	}
+	if control*10 > len(sample) {
+		return EncodingBinary
+	}
+
+	if utf8.Valid(sample) || utf8.Valid(trimPartialRune(sample)) {
+		return EncodingUTF8
+	}
+	return EncodingLatin1
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence at the end of a sample
+func trimPartialRune(sample []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		if utf8.RuneStart(sample[len(sample)-i]) {
+			if !utf8.FullRune(sample[len(sample)-i:]) {
+				return sample[:len(sample)-i]
+			}
+			break
+		}
+	}
+	return sample
+}
+
+// isText reports whether an encoding's content can go into a context
+func isText(encoding string) bool {
+	return encoding == EncodingUTF8 || encoding == EncodingLatin1
+}
+
+// sniffedFile is an open file whose encoding was detected from its first
+// sniffSize bytes; content reads it from the start without opening it again
+type sniffedFile struct {
+	*os.File
+	encoding string
+	head     []byte
+}
+
+// openSniffed opens a file and detects its encoding; the caller closes it
+func openSniffed(path string) (*sniffedFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, sample)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		file.Close()
+		return nil, err
+	}
+	return &sniffedFile{File: file, encoding: DetectEncoding(sample[:n]), head: sample[:n]}, nil
+}
+
+// content reads the whole file, starting with the bytes already sniffed
+func (f *sniffedFile) content() io.Reader {
+	return io.MultiReader(bytes.NewReader(f.head), f.File)
+}
+
+// sniffFile detects a file's encoding from its first sniffSize bytes, which it
+// returns too so other checks on the start of the file need not read it again
+func sniffFile(path string) (string, []byte, error) {
+	file, err := openSniffed(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+	return file.encoding, file.head, nil
+}
+
+// decodeText returns a reader yielding UTF-8 for text in encoding
+func decodeText(r io.Reader, encoding string) io.Reader {
+	if encoding == EncodingLatin1 {
+		return &latin1Reader{source: r}
+	}
+	return r
+}
+
+// latin1Reader converts ISO-8859-1 to UTF-8 as it reads; every Latin-1 byte
+// is the code point of the same value
+type latin1Reader struct {
+	source  io.Reader
+	raw     []byte
+	pending []byte // converted bytes not yet returned
+	err     error
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	for len(l.pending) == 0 {
+		if l.err != nil {
+			return 0, l.err
+		}
+		if l.raw == nil {
+			l.raw = make([]byte, 4096)
+		}
+		var n int
+		n, l.err = l.source.Read(l.raw)
+		converted := make([]byte, 0, 2*n)
+		for _, b := range l.raw[:n] {
+			converted = utf8.AppendRune(converted, rune(b))
+		}
+		l.pending = converted
+	}
+	n := copy(p, l.pending)
+	l.pending = l.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,110 @@
+package context
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileCache remembers per-file scan results between runs. A file whose size
+// and modification time are unchanged is not read again; a touched file whose
+// content hash is unchanged still counts as unchanged.
+type FileCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]fileCacheEntry
+	seen    map[string]fileCacheEntry // entries confirmed or refreshed by this scan
+}
+
+// fileCacheEntry is what the cache stores for one file
+type fileCacheEntry struct {
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	Hash      string    `json:"hash,omitempty"` // empty for files whose lines are not counted
+	Lines     int       `json:"lines"`
+	Encoding  string    `json:"encoding,omitempty"`
+	Generated *bool     `json:"generated,omitempty"` // starts with a generator marker; nil in older entries
+}
+
+// FileCachePath returns the cache file for a scan root inside cacheDir
+func FileCachePath(cacheDir, root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// LoadFileCache reads the cache for a scan root; a missing or unreadable
+// cache starts empty, since it only saves work
+func LoadFileCache(cacheDir, root string) *FileCache {
+	cache := &FileCache{
+		path:    FileCachePath(cacheDir, root),
+		entries: make(map[string]fileCacheEntry),
+		seen:    make(map[string]fileCacheEntry),
+	}
+	if data, err := os.ReadFile(cache.path); err == nil {
+		json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+// Save writes the entries seen by the last scan, dropping files that are gone
+func (c *FileCache) Save() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c.seen)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// scan returns a file's cache entry and whether its content is unchanged since
+// the cached scan; text files are hashed and counted in one read. Entries cached
+// before encodings or generator markers were recorded are read again.
+func (c *FileCache) scan(path string, info os.FileInfo) (fileCacheEntry, bool, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[path]
+	c.mu.Unlock()
+
+	if ok && cached.Encoding != "" && cached.Generated != nil && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		c.remember(path, cached)
+		return cached, true, nil
+	}
+
+	file, err := openSniffed(path)
+	if err != nil {
+		return fileCacheEntry{}, false, err
+	}
+	defer file.Close()
+
+	generated := isText(file.encoding) && hasGeneratedMarker(file.head)
+	entry := fileCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Encoding: file.encoding, Generated: &generated}
+	if isText(file.encoding) {
+		data, err := io.ReadAll(file.content())
+		if err != nil {
+			return entry, false, err
+		}
+		sum := sha256.Sum256(data)
+		entry.Hash = hex.EncodeToString(sum[:])
+		if lines, err := countReaderLines(bytes.NewReader(data)); err == nil {
+			entry.Lines = lines
+		}
+	}
+	c.remember(path, entry)
+	return entry, ok && entry.Hash != "" && entry.Hash == cached.Hash, nil
+}
+
+// remember records an entry to keep when the cache is saved
+func (c *FileCache) remember(path string, entry fileCacheEntry) {
+	c.mu.Lock()
+	c.seen[path] = entry
+	c.mu.Unlock()
+}
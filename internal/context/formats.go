@@ -0,0 +1,242 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Formatter renders a ContextResult in a layout suited to a target model
+type Formatter interface {
+	Name() string
+	Extension() string // file extension for exports, including the dot
+	Format(result *ContextResult) (string, error)
+}
+
+// Formatters returns the available formatters in display order
+func Formatters() []Formatter {
+	return []Formatter{MarkdownFormatter{}, TextFormatter{}, JSONFormatter{}, XMLFormatter{}}
+}
+
+// ParseFormat finds a formatter by config or flag value; empty means Markdown
+func ParseFormat(value string) (Formatter, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	switch name {
+	case "", "md":
+		return MarkdownFormatter{}, nil
+	case "txt", "plain":
+		return TextFormatter{}, nil
+	}
+	for _, formatter := range Formatters() {
+		if formatter.Name() == name {
+			return formatter, nil
+		}
+	}
+	return MarkdownFormatter{}, fmt.Errorf("unknown output format %q (use markdown, text, json or xml)", value)
+}
+
+// NextFormatter returns the formatter after current, wrapping around
+func NextFormatter(current Formatter) Formatter {
+	formatters := Formatters()
+	for i, formatter := range formatters {
+		if current != nil && formatter.Name() == current.Name() {
+			return formatters[(i+1)%len(formatters)]
+		}
+	}
+	return formatters[0]
+}
+
+// MarkdownFormatter emits the sections as generated, with fenced file contents
+type MarkdownFormatter struct{}
+
+func (MarkdownFormatter) Name() string      { return "markdown" }
+func (MarkdownFormatter) Extension() string { return ".md" }
+
+func (MarkdownFormatter) Format(result *ContextResult) (string, error) {
+	return result.Markdown(), nil
+}
+
+// TextFormatter emits plain text without Markdown headings or fences
+type TextFormatter struct{}
+
+func (TextFormatter) Name() string      { return "text" }
+func (TextFormatter) Extension() string { return ".txt" }
+
+func (TextFormatter) Format(result *ContextResult) (string, error) {
+	var out strings.Builder
+	for _, section := range result.Sections {
+		out.WriteString(strings.ToUpper(section.Title))
+		out.WriteString("\n")
+		out.WriteString(strings.Repeat("=", len(section.Title)))
+		out.WriteString("\n\n")
+
+		if len(section.Documents) > 0 {
+			for _, document := range section.Documents {
+				out.WriteString(fmt.Sprintf("--- %s ---\n", document.Path))
+				if document.Note != "" {
+					out.WriteString(document.Note)
+					out.WriteString("\n")
+				}
+				out.WriteString(strings.TrimRight(document.Content, "\n"))
+				out.WriteString("\n\n")
+			}
+			if section.Note != "" {
+				out.WriteString(section.Note)
+				out.WriteString("\n\n")
+			}
+			continue
+		}
+		out.WriteString(plainText(section.Content))
+		out.WriteString("\n")
+	}
+	if result.Summary != "" {
+		out.WriteString(plainText(result.Summary))
+		out.WriteString("\n")
+	}
+	return out.String(), nil
+}
+
+// plainText strips Markdown heading markers, fences and emphasis from section
+// content, skipping the leading title heading already written by TextFormatter
+func plainText(markdown string) string {
+	lines := strings.Split(strings.TrimRight(markdown, "\n"), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:]
+	}
+
+	var plain []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			line = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+		plain = append(plain, strings.ReplaceAll(line, "**", ""))
+	}
+	return strings.TrimLeft(strings.Join(plain, "\n"), "\n") + "\n"
+}
+
+// JSONFormatter emits the result as a JSON document with per-file contents
+type JSONFormatter struct{}
+
+func (JSONFormatter) Name() string      { return "json" }
+func (JSONFormatter) Extension() string { return ".json" }
+
+// jsonContext is the JSON layout written by JSONFormatter
+type jsonContext struct {
+	Project       string        `json:"project"`
+	GeneratedAt   time.Time     `json:"generated_at"`
+	TotalFiles    int           `json:"total_files"`
+	TotalSize     int64         `json:"total_size"`
+	TokenEstimate int           `json:"token_estimate"`
+	ProjectTypes  []string      `json:"project_types,omitempty"`
+	Sections      []jsonSection `json:"sections"`
+	Summary       string        `json:"summary,omitempty"`
+}
+
+type jsonSection struct {
+	Title   string     `json:"title"`
+	Content string     `json:"content,omitempty"`
+	Files   []jsonFile `json:"files,omitempty"`
+	Note    string     `json:"note,omitempty"`
+}
+
+type jsonFile struct {
+	Path     string `json:"path"`
+	Language string `json:"language,omitempty"`
+	Note     string `json:"note,omitempty"`
+	Content  string `json:"content"`
+}
+
+func (JSONFormatter) Format(result *ContextResult) (string, error) {
+	document := jsonContext{
+		Project:       result.ProjectName,
+		GeneratedAt:   result.GeneratedAt,
+		TotalFiles:    result.TotalFiles,
+		TotalSize:     result.TotalSize,
+		TokenEstimate: result.TokenEstimate,
+		ProjectTypes:  result.ProjectTypes,
+		Sections:      make([]jsonSection, 0, len(result.Sections)),
+		Summary:       result.Summary,
+	}
+	for _, section := range result.Sections {
+		entry := jsonSection{Title: section.Title}
+		if len(section.Documents) > 0 {
+			entry.Note = section.Note
+		} else {
+			entry.Content = section.Content
+		}
+		for _, file := range section.Documents {
+			entry.Files = append(entry.Files, jsonFile{
+				Path:     file.Path,
+				Language: file.Language,
+				Note:     file.Note,
+				Content:  file.Content,
+			})
+		}
+		document.Sections = append(document.Sections, entry)
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// XMLFormatter emits sections as tags and file contents as <file path="..."> tags
+type XMLFormatter struct{}
+
+func (XMLFormatter) Name() string      { return "xml" }
+func (XMLFormatter) Extension() string { return ".xml" }
+
+func (XMLFormatter) Format(result *ContextResult) (string, error) {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("<context project=%s>\n", xmlAttr(result.ProjectName)))
+	for _, section := range result.Sections {
+		out.WriteString(fmt.Sprintf("<section title=%s", xmlAttr(section.Title)))
+		if section.Note != "" && len(section.Documents) > 0 {
+			out.WriteString(fmt.Sprintf(" note=%s", xmlAttr(section.Note)))
+		}
+		out.WriteString(">\n")
+		if len(section.Documents) == 0 {
+			out.WriteString(xmlText(strings.TrimRight(section.Content, "\n")))
+			out.WriteString("\n")
+		}
+		for _, document := range section.Documents {
+			out.WriteString(fmt.Sprintf("<file path=%s", xmlAttr(document.Path)))
+			if document.Language != "" {
+				out.WriteString(fmt.Sprintf(" language=%s", xmlAttr(document.Language)))
+			}
+			if document.Note != "" {
+				out.WriteString(fmt.Sprintf(" note=%s", xmlAttr(document.Note)))
+			}
+			out.WriteString(">\n")
+			out.WriteString(xmlText(strings.TrimRight(document.Content, "\n")))
+			out.WriteString("\n</file>\n")
+		}
+		out.WriteString("</section>\n")
+	}
+	if result.Summary != "" {
+		out.WriteString("<summary>\n")
+		out.WriteString(xmlText(strings.TrimRight(result.Summary, "\n")))
+		out.WriteString("\n</summary>\n")
+	}
+	out.WriteString("</context>\n")
+	return out.String(), nil
+}
+
+// xmlEscaper escapes markup characters but keeps newlines readable
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// xmlText escapes character data
+func xmlText(text string) string {
+	return xmlEscaper.Replace(text)
+}
+
+// xmlAttr returns a quoted, escaped attribute value
+func xmlAttr(value string) string {
+	return `"` + strings.ReplaceAll(xmlText(value), `"`, "&quot;") + `"`
+}
@@ -0,0 +1,156 @@
+package context
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Exclusion reasons for files that would only waste tokens
+const (
+	reasonLockfile  = "lockfile"
+	reasonGenerated = "generated"
+	reasonDuplicate = "duplicate of " // followed by the path of the copy kept
+)
+
+// lockfileNames are dependency lockfiles, written by package managers
+var lockfileNames = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"go.sum":              true,
+	"cargo.lock":          true,
+	"composer.lock":       true,
+	"gemfile.lock":        true,
+	"poetry.lock":         true,
+	"pipfile.lock":        true,
+	"uv.lock":             true,
+	"pubspec.lock":        true,
+	"podfile.lock":        true,
+	"mix.lock":            true,
+	"flake.lock":          true,
+	"packages.lock.json":  true,
+}
+
+// generatedSuffixes end the names of files written by code generators
+var generatedSuffixes = []string{
+	".pb.go", ".pb.gw.go", "_pb2.py", "_pb2_grpc.py", ".pb.cc", ".pb.h", "_pb.js", "_pb.d.ts",
+	".g.dart", ".freezed.dart",
+}
+
+// generatedMarkers match the header comments code generators write. They are
+// anchored to comment lines, so code or prose that merely mentions a marker,
+// such as a generator's own template string, is not mistaken for output.
+var generatedMarkers = []*regexp.Regexp{
+	// Go's convention: https://go.dev/s/generatedcode
+	regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.\r?$`),
+	// Other generators name themselves and ask not to edit, as protoc does
+	regexp.MustCompile(`(?m)^[ \t]*(//|#|/?\*+|--|;)[ \t]*(Code )?[Gg]enerated by .*\bDO NOT EDIT\b`),
+	// The @generated tag and .NET's <auto-generated> header
+	regexp.MustCompile(`(?m)^[ \t]*(//|#|/?\*+|--|<!--)[ \t]*(@generated\b|<auto-generated\b)`),
+}
+
+// duplicateMinSize is the smallest file checked for copies; identical stubs
+// such as empty package inits cost little and say where packages are
+const duplicateMinSize = 512
+
+// generatedMarkerWindow is how much of the start of a file is searched for markers
+const generatedMarkerWindow = 1024
+
+// generatedReason returns reasonLockfile or reasonGenerated for a file named
+// like a lockfile or generator output, or whose start carries a generator's
+// marker (see hasGeneratedMarker), or ""
+func generatedReason(path string, marked bool) string {
+	name := strings.ToLower(filepath.Base(path))
+	if lockfileNames[name] {
+		return reasonLockfile
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return reasonGenerated
+		}
+	}
+	if marked {
+		return reasonGenerated
+	}
+	return ""
+}
+
+// hasGeneratedMarker reports whether the first bytes of a file, as read to
+// detect its encoding, carry a code generator's header comment
+func hasGeneratedMarker(head []byte) bool {
+	if len(head) > generatedMarkerWindow {
+		head = head[:generatedMarkerWindow]
+	}
+	for _, marker := range generatedMarkers {
+		if marker.Match(head) {
+			return true
+		}
+	}
+	return false
+}
+
+// markDuplicates excludes files whose content matches a file earlier in path
+// order, so vendored and copied files are included once. Only files of at
+// least duplicateMinSize that share a size are hashed; forced files are never
+// excluded.
+func (ps *ProjectScanner) markDuplicates(infos []FileInfo) {
+	bySize := make(map[int64][]int)
+	for i, info := range infos {
+		if info.IsDirectory || info.IsExcluded || info.Size < duplicateMinSize {
+			continue
+		}
+		bySize[info.Size] = append(bySize[info.Size], i)
+	}
+
+	for _, group := range bySize {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(a, b int) bool {
+			return infos[group[a]].Path < infos[group[b]].Path
+		})
+		kept := make(map[[sha256.Size]byte]string)
+		for _, i := range group {
+			sum, err := hashFile(infos[i].Path)
+			if err != nil {
+				continue
+			}
+			original, seen := kept[sum]
+			if !seen {
+				kept[sum] = infos[i].Path
+				continue
+			}
+			if ps.isForceIncluded(infos[i].Path) {
+				continue
+			}
+			if rel, err := filepath.Rel(ps.config.RootPath, original); err == nil {
+				original = rel
+			}
+			infos[i].IsExcluded = true
+			infos[i].ExcludeReason = reasonDuplicate + original
+		}
+	}
+}
+
+// hashFile returns the SHA-256 of a file's contents
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	file, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return sum, err
+	}
+	copy(sum[:], hash.Sum(nil))
+	return sum, nil
+}
@@ -1,6 +1,8 @@
 package context
 
 import (
+	"bufio"
+	stdcontext "context"
 	"fmt"
 	"io"
 	"os"
@@ -12,9 +14,20 @@ import (
 
 // ContextSection represents a section of the generated context
 type ContextSection struct {
-	Title   string
-	Content string
-	Files   []string
+	Title     string
+	Content   string
+	Files     []string
+	IsContent bool // holds file contents rather than overview or structure
+	Documents []FileDocument // per-file contents, used by non-Markdown formats
+	Note      string         // e.g. how many file groups were left out; already in Content
+}
+
+// FileDocument is one file's contents as included in a content section
+type FileDocument struct {
+	Path     string
+	Language string
+	Content  string
+	Note     string // e.g. why a large file was trimmed
 }
 
 // ContextResult represents the generated context
@@ -26,6 +39,190 @@ type ContextResult struct {
 	Sections       []ContextSection
 	Summary        string
 	TokenEstimate  int
+	ProjectTypes   []string
+	Redactions     []Redaction // secrets masked out of file contents
+	Truncations    []Truncation // oversized files cut at a structural boundary
+}
+
+// Markdown joins the sections and summary into a single document
+func (cr *ContextResult) Markdown() string {
+	var content strings.Builder
+	for _, section := range cr.Sections {
+		content.WriteString(section.Content)
+	}
+	if cr.Summary != "" {
+		content.WriteString(cr.Summary)
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// FencedMarkdown wraps the whole document in a single ```text fence for
+// pasting into chat UIs, escaping inner fences so they cannot close it early
+func (cr *ContextResult) FencedMarkdown() string {
+	content := strings.ReplaceAll(cr.Markdown(), "```", "\\`\\`\\`")
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return "```text\n" + content + "```"
+}
+
+// SplitMarkdown separates overview and structure sections (plus the summary) from file contents
+func (cr *ContextResult) SplitMarkdown() (structure, content string) {
+	var structureBuilder, contentBuilder strings.Builder
+	for _, section := range cr.Sections {
+		if section.IsContent {
+			contentBuilder.WriteString(section.Content)
+		} else {
+			structureBuilder.WriteString(section.Content)
+		}
+	}
+	if cr.Summary != "" {
+		structureBuilder.WriteString(cr.Summary)
+		structureBuilder.WriteString("\n")
+	}
+	return structureBuilder.String(), contentBuilder.String()
+}
+
+// WithoutFiles returns a copy of a content section with the given files'
+// blocks removed from its content, file list and documents. Blocks that no
+// longer match their document, such as after hand edits, are left in place.
+func (s ContextSection) WithoutFiles(excluded map[string]bool) ContextSection {
+	if len(excluded) == 0 {
+		return s
+	}
+	
+	result := s
+	result.Files = nil
+	result.Documents = nil
+	removed := make(map[string]bool)
+	
+	var content strings.Builder
+	pos := 0
+	for _, document := range s.Documents {
+		if excluded[document.Path] {
+			if start, end, ok := documentSpan(s.Content, pos, document); ok {
+				content.WriteString(s.Content[pos:start])
+				pos = end
+				removed[document.Path] = true
+				continue
+			}
+		}
+		result.Documents = append(result.Documents, document)
+	}
+	content.WriteString(s.Content[pos:])
+	result.Content = content.String()
+	
+	for _, file := range s.Files {
+		if !removed[file] {
+			result.Files = append(result.Files, file)
+		}
+	}
+	return result
+}
+
+// documentSpan finds a document's heading and fenced block in content at or after from
+func documentSpan(content string, from int, document FileDocument) (int, int, bool) {
+	var block strings.Builder
+	if document.Note != "" {
+		block.WriteString(fmt.Sprintf("*%s*\n\n", document.Note))
+	}
+	block.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", document.Language, document.Content))
+	
+	title := "# " + document.Path + "\n\n"
+	for from < len(content) {
+		index := strings.Index(content[from:], title)
+		if index < 0 {
+			return 0, 0, false
+		}
+		index += from
+		
+		// Walk back over the rest of the heading's hashes to the start of the line
+		start := index
+		for start > 0 && content[start-1] == '#' {
+			start--
+		}
+		after := index + len(title)
+		if (start == 0 || content[start-1] == '\n') && strings.HasPrefix(content[after:], block.String()) {
+			return start, after + block.Len(), true
+		}
+		from = index + 1
+	}
+	return 0, 0, false
+}
+
+// LargeFileMode defines which portion of an oversized file is included
+type LargeFileMode int
+
+const (
+	LargeFileSkip LargeFileMode = iota
+	LargeFileHead
+	LargeFileTail
+	LargeFileBoth
+	LargeFileStructural // as much as fits, cut at the end of a declaration or section
+)
+
+// ParseLargeFileMode parses a config or flag value ("skip", "head", "tail",
+// "both" or "structural")
+func ParseLargeFileMode(value string) (LargeFileMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "structural":
+		return LargeFileStructural, nil
+	case "skip":
+		return LargeFileSkip, nil
+	case "head":
+		return LargeFileHead, nil
+	case "tail":
+		return LargeFileTail, nil
+	case "both":
+		return LargeFileBoth, nil
+	}
+	return LargeFileStructural, fmt.Errorf("unknown large file mode %q (use skip, head, tail, both or structural)", value)
+}
+
+// PathStyle controls how file paths appear in the generated context
+type PathStyle int
+
+const (
+	PathRelative PathStyle = iota // relative to the scan root
+	PathAbsolute
+)
+
+// ParsePathStyle parses a config or flag value ("relative" or "absolute")
+func ParsePathStyle(value string) (PathStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "relative":
+		return PathRelative, nil
+	case "absolute":
+		return PathAbsolute, nil
+	}
+	return PathRelative, fmt.Errorf("unknown path style %q (use relative or absolute)", value)
+}
+
+// ContentGrouping decides how file contents are split into sections
+type ContentGrouping int
+
+const (
+	GroupByType      ContentGrouping = iota // one section per extension
+	GroupByDirectory                        // one section per top-level directory
+)
+
+// ParseContentGrouping parses a config or flag value ("type" or "directory")
+func ParseContentGrouping(value string) (ContentGrouping, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "type":
+		return GroupByType, nil
+	case "directory", "dir":
+		return GroupByDirectory, nil
+	}
+	return GroupByType, fmt.Errorf("unknown content grouping %q (use type or directory)", value)
+}
+
+// LargeFilePolicy controls how files above the size limit contribute content
+type LargeFilePolicy struct {
+	Mode       LargeFileMode
+	LineBudget int
+	Overrides  map[string]LargeFileMode // per-extension mode overrides
 }
 
 // ContextGenerator generates comprehensive context from scan results
@@ -35,6 +232,28 @@ type ContextGenerator struct {
 	includeContent  bool
 	includeSummary  bool
 	priorityExtensions []string
+	largeFilePolicy LargeFilePolicy
+	normalizeNewlines bool
+	includeDirReadmes bool
+	readBufferSize    int
+	headerBaseLevel   int
+	now               func() time.Time
+	focusKeywords     []string
+	languageOverrides map[string]string
+	separateTests     bool
+	sizeLimit         SizeLimit
+	pathStyle         PathStyle
+	contentGrouping   ContentGrouping
+	maxContentSections int
+	outlineMode       OutlineMode
+	contentDetail     ContentDetail
+	compression       Compression
+	redactor          *Redactor // nil leaves secrets in place
+	root              string // scan root of the result being generated
+	redactions        []Redaction // secrets masked while generating the current result
+	truncations       []Truncation // oversized files cut short in the current result
+	outlined          map[string]bool // Go files the outline stands in for in the current result
+	written           int64 // bytes of the current result's sections so far, for the hard byte cap
 }
 
 // NewContextGenerator creates a new context generator
@@ -48,6 +267,15 @@ func NewContextGenerator() *ContextGenerator {
 			".go", ".js", ".ts", ".py", ".java", ".c", ".cpp",
 			".md", ".txt", ".json", ".yaml", ".yml",
 		},
+		largeFilePolicy: LargeFilePolicy{
+			Mode:       LargeFileStructural,
+			LineBudget: 200,
+		},
+		normalizeNewlines: true,
+		readBufferSize:    32 * 1024,
+		headerBaseLevel:   1,
+		now:               time.Now,
+		redactor:          NewRedactor(nil),
 	}
 }
 
@@ -59,14 +287,189 @@ func (cg *ContextGenerator) SetOptions(maxFileSize, maxTotalSize int64, includeC
 	cg.includeSummary = includeSummary
 }
 
-// GenerateContext creates comprehensive context from scan results
-func (cg *ContextGenerator) GenerateContext(scanResult *ScanResult, projectName string) (*ContextResult, error) {
+// SetFocusKeywords ranks files mentioning any keyword in their path or
+// content ahead of the usual breadth-first scoring
+func (cg *ContextGenerator) SetFocusKeywords(keywords []string) {
+	cg.focusKeywords = nil
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" {
+			cg.focusKeywords = append(cg.focusKeywords, keyword)
+		}
+	}
+}
+
+// SetLanguageOverrides sets code fence languages by extension, taking
+// precedence over the built-in map; keys may omit the leading dot
+func (cg *ContextGenerator) SetLanguageOverrides(overrides map[string]string) {
+	cg.languageOverrides = make(map[string]string, len(overrides))
+	for ext, language := range overrides {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		cg.languageOverrides[ext] = strings.TrimSpace(language)
+	}
+}
+
+// SetSeparateTests routes test files into a dedicated "Tests" section
+// instead of their language section
+func (cg *ContextGenerator) SetSeparateTests(separate bool) {
+	cg.separateTests = separate
+}
+
+// SetPathStyle chooses between scan-root-relative and absolute paths
+func (cg *ContextGenerator) SetPathStyle(style PathStyle) {
+	cg.pathStyle = style
+}
+
+// SetContentGrouping chooses whether content sections follow file types or directories
+func (cg *ContextGenerator) SetContentGrouping(grouping ContentGrouping) {
+	cg.contentGrouping = grouping
+}
+
+// SetMaxContentSections caps how many content sections are produced, keeping the
+// highest-priority groups; zero or less means no cap
+func (cg *ContextGenerator) SetMaxContentSections(max int) {
+	cg.maxContentSections = max
+}
+
+// SetOutlineMode adds an API Outline section for Go files, optionally in
+// place of their contents
+func (cg *ContextGenerator) SetOutlineMode(mode OutlineMode) {
+	cg.outlineMode = mode
+}
+
+// SetContentDetail shows code files whole, as signatures only, or with
+// function bodies elided
+func (cg *ContextGenerator) SetContentDetail(detail ContentDetail) {
+	cg.contentDetail = detail
+}
+
+// SetCompression strips license headers, comments or blank lines from file
+// contents, or minifies JSON and YAML, as compression selects
+func (cg *ContextGenerator) SetCompression(compression Compression) {
+	cg.compression = compression
+}
+
+// SetRedaction toggles masking secrets in file contents; patterns named in
+// skip (see RedactionPatterns) are left unmasked
+func (cg *ContextGenerator) SetRedaction(enabled bool, skip []string) {
+	cg.redactor = nil
+	if enabled {
+		cg.redactor = NewRedactor(skip)
+	}
+}
+
+// redact masks secrets in one file's content and remembers what was masked
+func (cg *ContextGenerator) redact(relativePath, text string) string {
+	if cg.redactor == nil {
+		return text
+	}
+	masked, redactions := cg.redactor.Redact(relativePath, text)
+	cg.redactions = append(cg.redactions, redactions...)
+	return masked
+}
+
+// SetSizeLimit sets a hard cap; GenerateContext returns a *LimitExceededError above it
+func (cg *ContextGenerator) SetSizeLimit(limit SizeLimit) {
+	cg.sizeLimit = limit
+}
+
+// SetClock overrides the time source used for timestamps
+func (cg *ContextGenerator) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	cg.now = now
+}
+
+// SetIncludeContent toggles the file content sections, leaving structure only when off
+func (cg *ContextGenerator) SetIncludeContent(include bool) {
+	cg.includeContent = include
+}
+
+// SetLargeFilePolicy configures how oversized files are included
+func (cg *ContextGenerator) SetLargeFilePolicy(policy LargeFilePolicy) {
+	if policy.LineBudget <= 0 {
+		policy.LineBudget = 200
+	}
+	cg.largeFilePolicy = policy
+}
+
+// SetNormalizeNewlines toggles conversion of CRLF/CR line endings to LF
+func (cg *ContextGenerator) SetNormalizeNewlines(normalize bool) {
+	cg.normalizeNewlines = normalize
+}
+
+// SetReadBufferSize sets the buffer size used when streaming file content
+func (cg *ContextGenerator) SetReadBufferSize(size int) {
+	if size <= 0 {
+		size = 32 * 1024
+	}
+	cg.readBufferSize = size
+}
+
+// SetHeaderBaseLevel sets the markdown level of top-level section headers;
+// nested headers shift by the same amount
+func (cg *ContextGenerator) SetHeaderBaseLevel(level int) {
+	if level < 1 {
+		level = 1
+	}
+	if level > 5 {
+		level = 5
+	}
+	cg.headerBaseLevel = level
+}
+
+// heading renders a markdown header at a level relative to the base level
+func (cg *ContextGenerator) heading(level int, text string) string {
+	depth := cg.headerBaseLevel + level - 1
+	if depth > 6 {
+		depth = 6
+	}
+	return fmt.Sprintf("%s %s\n\n", strings.Repeat("#", depth), text)
+}
+
+// SetIncludeDirectoryReadmes toggles inlining per-directory READMEs next to their folders
+func (cg *ContextGenerator) SetIncludeDirectoryReadmes(include bool) {
+	cg.includeDirReadmes = include
+}
+
+// largeFileMode returns the inclusion mode for an oversized file
+func (cg *ContextGenerator) largeFileMode(file FileInfo) LargeFileMode {
+	if mode, ok := cg.largeFilePolicy.Overrides[file.Extension]; ok {
+		return mode
+	}
+	return cg.largeFilePolicy.Mode
+}
+
+// GenerateContext creates comprehensive context from scan results. Cancelling
+// ctx stops it between files and returns ctx.Err().
+func (cg *ContextGenerator) GenerateContext(ctx stdcontext.Context, scanResult *ScanResult, projectName string) (*ContextResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cg.root = scanResult.RootPath
+	cg.redactions = nil
+	cg.truncations = nil
+	cg.outlined = nil
+	
+	// Refuse oversized scans before reading any file contents
+	if err := cg.sizeLimit.checkFiles(scanResult); err != nil {
+		return nil, err
+	}
+	
 	result := &ContextResult{
 		ProjectName: projectName,
-		GeneratedAt: time.Now(),
+		GeneratedAt: cg.now(),
 		TotalFiles:  scanResult.TotalFiles,
 		TotalSize:   scanResult.TotalSize,
 		Sections:    make([]ContextSection, 0),
+		ProjectTypes: scanResult.ProjectTypes,
 	}
 	
 	// Generate project overview section
@@ -75,17 +478,50 @@ func (cg *ContextGenerator) GenerateContext(scanResult *ScanResult, projectName
 	// Generate directory structure section
 	result.Sections = append(result.Sections, cg.generateStructureSection(scanResult))
 	
+	// Generate directory README section (if enabled)
+	if cg.includeDirReadmes {
+		if section, ok := cg.generateDirectoryReadmeSection(scanResult); ok {
+			result.Sections = append(result.Sections, section)
+		}
+	}
+	
 	// Generate file type analysis section
 	result.Sections = append(result.Sections, cg.generateFileTypeSection(scanResult))
 	
+	// Generate Go API outline section (if enabled)
+	if cg.outlineMode != OutlineOff {
+		section, covered, err := cg.generateOutlineSection(ctx, scanResult.Files)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate API outline: %w", err)
+		}
+		if section.Content != "" {
+			result.Sections = append(result.Sections, section)
+		}
+		if cg.outlineMode == OutlineOnly {
+			cg.outlined = covered
+		}
+	}
+	
 	// Generate file content sections (if enabled)
 	if cg.includeContent {
-		contentSections, err := cg.generateContentSections(scanResult)
+		cg.written = 0
+		for _, section := range result.Sections {
+			cg.written += int64(len(section.Content))
+		}
+		contentSections, err := cg.generateContentSections(ctx, scanResult)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate content sections: %w", err)
 		}
 		result.Sections = append(result.Sections, contentSections...)
 	}
+	if cg.contentDetail != DetailFull {
+		result.Sections = ApplyContentDetail(result, cg.contentDetail).Sections
+	}
+	if cg.compression != 0 {
+		result.Sections = ApplyCompression(result, cg.compression).Sections
+	}
+	result.Redactions = cg.redactions
+	result.Truncations = cg.truncations
 	
 	// Generate summary
 	if cg.includeSummary {
@@ -95,6 +531,10 @@ func (cg *ContextGenerator) GenerateContext(scanResult *ScanResult, projectName
 	// Estimate tokens
 	result.TokenEstimate = cg.estimateTokens(result)
 	
+	if err := cg.sizeLimit.checkOutput(result); err != nil {
+		return nil, err
+	}
+	
 	return result, nil
 }
 
@@ -102,7 +542,10 @@ func (cg *ContextGenerator) GenerateContext(scanResult *ScanResult, projectName
 func (cg *ContextGenerator) generateOverviewSection(scanResult *ScanResult) ContextSection {
 	var content strings.Builder
 	
-	content.WriteString(fmt.Sprintf("# Project Overview\n\n"))
+	content.WriteString(cg.heading(1, "Project Overview"))
+	if len(scanResult.ProjectTypes) > 0 {
+		content.WriteString(fmt.Sprintf("**Project type:** %s\n", strings.Join(scanResult.ProjectTypes, ", ")))
+	}
 	content.WriteString(fmt.Sprintf("**Scan completed:** %s\n", scanResult.ScanDuration.Round(time.Millisecond)))
 	content.WriteString(fmt.Sprintf("**Total files:** %d\n", scanResult.TotalFiles))
 	content.WriteString(fmt.Sprintf("**Total directories:** %d\n", scanResult.TotalDirectories))
@@ -110,8 +553,45 @@ func (cg *ContextGenerator) generateOverviewSection(scanResult *ScanResult) Cont
 	content.WriteString(fmt.Sprintf("**Total lines:** %s\n", FormatNumber(scanResult.TotalLines)))
 	content.WriteString(fmt.Sprintf("**Excluded files:** %d\n\n", scanResult.ExcludedFiles))
 	
+	// Files and folders excluded by each project ignore rule
+	if len(scanResult.IgnoreRuleHits) > 0 || len(scanResult.IgnoreRuleDirs) > 0 {
+		content.WriteString(cg.heading(2, IgnoreFileName+" Exclusions"))
+		hits, dirs := scanResult.IgnoreRuleHits, scanResult.IgnoreRuleDirs
+		rules := make([]string, 0, len(hits)+len(dirs))
+		for rule := range hits {
+			rules = append(rules, rule)
+		}
+		for rule := range dirs {
+			if _, ok := hits[rule]; !ok {
+				rules = append(rules, rule)
+			}
+		}
+		sort.Slice(rules, func(i, j int) bool {
+			if dirs[rules[i]] != dirs[rules[j]] {
+				return dirs[rules[i]] > dirs[rules[j]]
+			}
+			if hits[rules[i]] != hits[rules[j]] {
+				return hits[rules[i]] > hits[rules[j]]
+			}
+			return rules[i] < rules[j]
+		})
+		for _, rule := range rules {
+			var counts []string
+			if hits[rule] > 0 {
+				counts = append(counts, fmt.Sprintf("%d files", hits[rule]))
+			}
+			if dirs[rule] == 1 {
+				counts = append(counts, "1 folder")
+			} else if dirs[rule] > 1 {
+				counts = append(counts, fmt.Sprintf("%d folders", dirs[rule]))
+			}
+			content.WriteString(fmt.Sprintf("- **%s**: %s\n", rule, strings.Join(counts, ", ")))
+		}
+		content.WriteString("\n")
+	}
+	
 	// Top file extensions
-	content.WriteString("## File Extensions\n\n")
+	content.WriteString(cg.heading(2, "File Extensions"))
 	sortedExts := cg.sortExtensionsByCount(scanResult.Extensions)
 	for i, ext := range sortedExts {
 		if i >= 10 { // Show top 10
@@ -125,9 +605,18 @@ func (cg *ContextGenerator) generateOverviewSection(scanResult *ScanResult) Cont
 	}
 	content.WriteString("\n")
 	
+	// Language share by lines and bytes
+	if shares := LanguageShares(scanResult); len(shares) > 0 {
+		content.WriteString(cg.heading(2, "Languages"))
+		byLines := func(share LanguageShare) float64 { return share.LinePercent }
+		byBytes := func(share LanguageShare) float64 { return share.BytePercent }
+		content.WriteString(fmt.Sprintf("- **By LOC:** %s\n", formatLanguageBreakdown(shares, byLines, 5)))
+		content.WriteString(fmt.Sprintf("- **By size:** %s\n\n", formatLanguageBreakdown(shares, byBytes, 5)))
+	}
+	
 	// Largest files
 	if len(scanResult.LargestFiles) > 0 {
-		content.WriteString("## Largest Files\n\n")
+		content.WriteString(cg.heading(2, "Largest Files"))
 		for i, file := range scanResult.LargestFiles {
 			if i >= 5 { // Show top 5
 				break
@@ -150,7 +639,7 @@ func (cg *ContextGenerator) generateOverviewSection(scanResult *ScanResult) Cont
 func (cg *ContextGenerator) generateStructureSection(scanResult *ScanResult) ContextSection {
 	var content strings.Builder
 	
-	content.WriteString("# Directory Structure\n\n")
+	content.WriteString(cg.heading(1, "Directory Structure"))
 	content.WriteString("```\n")
 	
 	// Build directory tree
@@ -166,11 +655,69 @@ func (cg *ContextGenerator) generateStructureSection(scanResult *ScanResult) Con
 	}
 }
 
+// generateDirectoryReadmeSection inlines each directory's README under that directory's heading
+func (cg *ContextGenerator) generateDirectoryReadmeSection(scanResult *ScanResult) (ContextSection, bool) {
+	readmes := make(map[string]FileInfo)
+	var dirs []string
+	for _, file := range scanResult.Files {
+		if !isReadmeFile(file.Path) {
+			continue
+		}
+		dir := filepath.Dir(file.Path)
+		if _, exists := readmes[dir]; exists {
+			continue
+		}
+		readmes[dir] = file
+		dirs = append(dirs, dir)
+	}
+	
+	if len(dirs) == 0 {
+		return ContextSection{}, false
+	}
+	sort.Strings(dirs)
+	
+	var content strings.Builder
+	var includedFiles []string
+	var documents []FileDocument
+	content.WriteString(cg.heading(1, "Directory READMEs"))
+	
+	for _, dir := range dirs {
+		readme := readmes[dir]
+		readmeContent, err := cg.readFileContent(readme, cg.maxFileSize)
+		if err != nil {
+			continue
+		}
+		
+		relativePath := cg.getRelativePath(readme.Path)
+		readmeContent = cg.redact(relativePath, readmeContent)
+		content.WriteString(cg.heading(2, cg.getRelativePath(dir)+"/"))
+		content.WriteString(fmt.Sprintf("*From %s*\n\n", relativePath))
+		language := cg.getLanguageFromExtension(readme.Extension)
+		content.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", language, readmeContent))
+		includedFiles = append(includedFiles, relativePath)
+		documents = append(documents, FileDocument{Path: relativePath, Language: language, Content: readmeContent})
+	}
+	
+	return ContextSection{
+		Title:     "Directory READMEs",
+		Content:   content.String(),
+		Files:     includedFiles,
+		IsContent: true,
+		Documents: documents,
+	}, len(includedFiles) > 0
+}
+
+// isReadmeFile reports whether a path names a README file (README, README.md, readme.txt, ...)
+func isReadmeFile(path string) bool {
+	base := strings.ToUpper(filepath.Base(path))
+	return base == "README" || strings.HasPrefix(base, "README.")
+}
+
 // generateFileTypeSection creates the file type analysis section
 func (cg *ContextGenerator) generateFileTypeSection(scanResult *ScanResult) ContextSection {
 	var content strings.Builder
 	
-	content.WriteString("# File Type Analysis\n\n")
+	content.WriteString(cg.heading(1, "File Type Analysis"))
 	
 	// Group files by extension
 	filesByExt := make(map[string][]FileInfo)
@@ -191,7 +738,7 @@ func (cg *ContextGenerator) generateFileTypeSection(scanResult *ScanResult) Cont
 			continue
 		}
 		
-		content.WriteString(fmt.Sprintf("## %s Files (%d files)\n\n", ext, len(files)))
+		content.WriteString(cg.heading(2, fmt.Sprintf("%s Files (%d files)", ext, len(files))))
 		
 		// Calculate statistics
 		totalSize := int64(0)
@@ -237,12 +784,60 @@ func (cg *ContextGenerator) generateFileTypeSection(scanResult *ScanResult) Cont
 	}
 }
 
+// testsGroup is the content group holding test files when they are kept
+// separate; the slash keeps it from matching a directory or extension group
+const testsGroup = "/tests"
+
+// rootGroup is the directory group holding files directly in the scan root
+const rootGroup = "."
+
+// IsTestFile reports whether a file looks like a test by common naming conventions
+func IsTestFile(path string) bool {
+	name := filepath.Base(path)
+	lower := strings.ToLower(name)
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	
+	switch {
+	case strings.HasSuffix(lower, "_test.go"):
+		return true
+	case strings.Contains(lower, ".test.") || strings.Contains(lower, ".spec."):
+		return true
+	case strings.HasSuffix(lower, ".py") && (strings.HasPrefix(lower, "test_") || strings.HasSuffix(lower, "_test.py")):
+		return true
+	case strings.HasSuffix(lower, "_spec.rb") || strings.HasSuffix(lower, "_test.rb"):
+		return true
+	case (strings.HasSuffix(lower, ".java") || strings.HasSuffix(lower, ".kt") || strings.HasSuffix(lower, ".cs")) &&
+		(strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")):
+		return true
+	}
+	
+	return strings.Contains(filepath.ToSlash(path), "/__tests__/")
+}
+
 // generateContentSections creates sections with actual file content
-func (cg *ContextGenerator) generateContentSections(scanResult *ScanResult) ([]ContextSection, error) {
+func (cg *ContextGenerator) generateContentSections(ctx stdcontext.Context, scanResult *ScanResult) ([]ContextSection, error) {
 	var sections []ContextSection
 	
 	// Select files to include based on priority and size constraints
 	selectedFiles := cg.selectFilesForContent(scanResult.Files)
+	if len(cg.outlined) > 0 {
+		// The API outline stands in for these files
+		kept := selectedFiles[:0]
+		for _, file := range selectedFiles {
+			if !cg.outlined[file.Path] {
+				kept = append(kept, file)
+			}
+		}
+		selectedFiles = kept
+	}
+	
+	if cg.contentGrouping == GroupByDirectory {
+		sections, ranks, err := cg.generateDirectoryContentSections(ctx, selectedFiles)
+		if err != nil {
+			return nil, err
+		}
+		return cg.capContentSections(sections, ranks), nil
+	}
 	
 	// Group files by type for better organization
 	filesByType := make(map[string][]FileInfo)
@@ -251,12 +846,15 @@ func (cg *ContextGenerator) generateContentSections(scanResult *ScanResult) ([]C
 		if ext == "" {
 			ext = "other"
 		}
+		if cg.separateTests && IsTestFile(file.Path) {
+			ext = testsGroup
+		}
 		filesByType[ext] = append(filesByType[ext], file)
 	}
 	
-	// Generate content sections for each file type
-	for ext, files := range filesByType {
-		section, err := cg.generateFileContentSection(ext, files)
+	// Generate content sections for each file type, highest priority first
+	for _, ext := range cg.sortExtensionsByPriority(filesByType) {
+		section, err := cg.generateFileContentSection(ctx, ext, filesByType[ext])
 		if err != nil {
 			return nil, err
 		}
@@ -265,57 +863,229 @@ func (cg *ContextGenerator) generateContentSections(scanResult *ScanResult) ([]C
 		}
 	}
 	
-	return sections, nil
+	return cg.capContentSections(sections, nil), nil
 }
 
-// generateFileContentSection creates a section with file contents for a specific type
-func (cg *ContextGenerator) generateFileContentSection(extension string, files []FileInfo) (ContextSection, error) {
-	var content strings.Builder
-	var includedFiles []string
+// capContentSections keeps the maxContentSections highest-priority sections and notes
+// how many file groups were left out. ranks gives each section's priority (lower is
+// better) when the sections are not already in priority order; kept sections keep
+// their order either way.
+func (cg *ContextGenerator) capContentSections(sections []ContextSection, ranks []int) []ContextSection {
+	if cg.maxContentSections <= 0 || len(sections) <= cg.maxContentSections {
+		return sections
+	}
+	
+	omitted := len(sections) - cg.maxContentSections
+	if ranks == nil {
+		sections = sections[:cg.maxContentSections]
+	} else {
+		byRank := make([]int, len(sections))
+		for i := range byRank {
+			byRank[i] = i
+		}
+		sort.SliceStable(byRank, func(i, j int) bool { return ranks[byRank[i]] < ranks[byRank[j]] })
+		keep := make(map[int]bool)
+		for _, i := range byRank[:cg.maxContentSections] {
+			keep[i] = true
+		}
+		kept := make([]ContextSection, 0, cg.maxContentSections)
+		for i, section := range sections {
+			if keep[i] {
+				kept = append(kept, section)
+			}
+		}
+		sections = kept
+	}
+	last := &sections[len(sections)-1]
+	last.Note = fmt.Sprintf("%d more file groups omitted (max %d content sections)", omitted, cg.maxContentSections)
+	last.Content += fmt.Sprintf("*%s*\n\n", last.Note)
+	return sections
+}
+
+// generateDirectoryContentSections creates one content section per top-level directory,
+// with files in the scan root first and the rest in path order. Each section's rank is
+// the position of its best-scoring file among selectedFiles, which come highest score
+// first, so capping can keep the most relevant directories rather than the first ones.
+func (cg *ContextGenerator) generateDirectoryContentSections(ctx stdcontext.Context, selectedFiles []FileInfo) ([]ContextSection, []int, error) {
+	var sections []ContextSection
+	var ranks []int
+	
+	filesByDir := make(map[string][]FileInfo)
+	groupRank := make(map[string]int)
+	for i, file := range selectedFiles {
+		group := cg.topLevelDir(file.Path)
+		if cg.separateTests && IsTestFile(file.Path) {
+			group = testsGroup
+		}
+		if _, seen := groupRank[group]; !seen {
+			groupRank[group] = i
+		}
+		filesByDir[group] = append(filesByDir[group], file)
+	}
+	
+	groups := make([]string, 0, len(filesByDir))
+	for group := range filesByDir {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		// Root files lead and tests trail, like a directory listing
+		rank := func(group string) int {
+			switch group {
+			case rootGroup:
+				return 0
+			case testsGroup:
+				return 2
+			}
+			return 1
+		}
+		if rank(groups[i]) != rank(groups[j]) {
+			return rank(groups[i]) < rank(groups[j])
+		}
+		return groups[i] < groups[j]
+	})
 	
+	for _, group := range groups {
+		files := filesByDir[group]
+		sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+		
+		title := group + "/ Files Content"
+		switch group {
+		case rootGroup:
+			title = "Root Files Content"
+		case testsGroup:
+			title = "Tests"
+		}
+		
+		section, err := cg.generateContentSection(ctx, title, files)
+		if err != nil {
+			return nil, nil, err
+		}
+		if section.Content != "" {
+			sections = append(sections, section)
+			ranks = append(ranks, groupRank[group])
+		}
+	}
+	
+	return sections, ranks, nil
+}
+
+// topLevelDir returns the first directory of a path below the scan root, or rootGroup
+// for files directly in the root
+func (cg *ContextGenerator) topLevelDir(path string) string {
+	root := cg.root
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	
+	relativePath, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return rootGroup
+	}
+	parts := strings.SplitN(filepath.ToSlash(relativePath), "/", 2)
+	if len(parts) < 2 {
+		return rootGroup
+	}
+	return parts[0]
+}
+
+// generateFileContentSection creates a section with file contents for a specific type
+func (cg *ContextGenerator) generateFileContentSection(ctx stdcontext.Context, extension string, files []FileInfo) (ContextSection, error) {
 	sectionTitle := fmt.Sprintf("%s Files Content", strings.ToUpper(strings.TrimPrefix(extension, ".")))
 	if extension == "other" {
 		sectionTitle = "Other Files Content"
 	}
+	if extension == testsGroup {
+		sectionTitle = "Tests"
+	}
 	
-	content.WriteString(fmt.Sprintf("# %s\n\n", sectionTitle))
+	return cg.generateContentSection(ctx, sectionTitle, files)
+}
+
+// generateContentSection writes the contents of files under a section heading
+func (cg *ContextGenerator) generateContentSection(ctx stdcontext.Context, sectionTitle string, files []FileInfo) (ContextSection, error) {
+	var content strings.Builder
+	var includedFiles []string
+	var documents []FileDocument
+	saved := 0 // bytes compression will remove, which the size budget does not count
+	
+	content.WriteString(cg.heading(1, sectionTitle))
 	
 	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return ContextSection{}, err
+		}
+		
 		// Check size constraints
-		if file.Size > cg.maxFileSize {
+		oversized := file.Size > cg.maxFileSize
+		if oversized && cg.largeFileMode(file) == LargeFileSkip {
 			continue
 		}
 		
+		// Refuse before reading a file that would take the context past the hard
+		// byte cap; only full, uncompressed content is known to keep its size
+		if !oversized && cg.compression == 0 && cg.contentDetail == DetailFull {
+			if err := cg.sizeLimit.checkBytes(cg.written + int64(content.Len()) + file.Size); err != nil {
+				return ContextSection{}, err
+			}
+		}
+		
 		relativePath := cg.getRelativePath(file.Path)
-		content.WriteString(fmt.Sprintf("## %s\n\n", relativePath))
+		content.WriteString(cg.heading(2, relativePath))
 		
-		// Read file content
-		fileContent, err := cg.readFileContent(file.Path)
+		// Read file content, trimming oversized files down to their most relevant lines
+		var fileContent, note string
+		var err error
+		if oversized {
+			fileContent, note, err = cg.readLargeFile(file, relativePath)
+		} else {
+			fileContent, err = cg.readFileContent(file, cg.maxFileSize)
+		}
 		if err != nil {
 			content.WriteString(fmt.Sprintf("*Error reading file: %v*\n\n", err))
 			continue
 		}
+		if note != "" {
+			content.WriteString(fmt.Sprintf("*%s*\n\n", note))
+		}
+		fileContent = cg.redact(relativePath, fileContent)
 		
 		// Add file content with syntax highlighting hint
 		language := cg.getLanguageFromExtension(file.Extension)
 		content.WriteString(fmt.Sprintf("```%s\n%s\n```\n\n", language, fileContent))
+		if cg.compression != 0 {
+			saved += len(fileContent) - len(cg.compression.apply(fileContent, language))
+		}
 		
 		includedFiles = append(includedFiles, relativePath)
+		documents = append(documents, FileDocument{Path: relativePath, Language: language, Content: fileContent, Note: note})
 		
 		// Check total size constraint
-		if int64(content.Len()) > cg.maxTotalSize {
+		if int64(content.Len()-saved) > cg.maxTotalSize {
 			content.WriteString("*Context truncated due to size limits*\n\n")
 			break
 		}
 	}
 	
+	cg.written += int64(content.Len())
 	return ContextSection{
-		Title:   sectionTitle,
-		Content: content.String(),
-		Files:   includedFiles,
+		Title:     sectionTitle,
+		Content:   content.String(),
+		Files:     includedFiles,
+		IsContent: true,
+		Documents: documents,
 	}, nil
 }
 
+// IncludedFiles returns the files whose content would be included, sorted by path
+func (cg *ContextGenerator) IncludedFiles(scanResult *ScanResult) []FileInfo {
+	cg.root = scanResult.RootPath
+	files := cg.selectFilesForContent(scanResult.Files)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
 // selectFilesForContent selects which files to include in the content sections
 func (cg *ContextGenerator) selectFilesForContent(files []FileInfo) []FileInfo {
 	var selected []FileInfo
@@ -335,27 +1105,50 @@ func (cg *ContextGenerator) selectFilesForContent(files []FileInfo) []FileInfo {
 		}
 	}
 	
-	// Sort by score (highest first)
+	// Sort by score (highest first), then by path so equal scores keep a stable order
 	sort.Slice(scoredFiles, func(i, j int) bool {
-		return scoredFiles[i].score > scoredFiles[j].score
+		if scoredFiles[i].score != scoredFiles[j].score {
+			return scoredFiles[i].score > scoredFiles[j].score
+		}
+		return scoredFiles[i].file.Path < scoredFiles[j].file.Path
 	})
 	
 	// Select files within size constraints
 	totalSize := int64(0)
 	for _, sf := range scoredFiles {
-		if totalSize+sf.file.Size > cg.maxTotalSize {
-			break
+		size := sf.file.Size
+		if size > cg.maxFileSize {
+			if cg.largeFileMode(sf.file) == LargeFileSkip {
+				continue
+			}
+			// Oversized files only contribute a bounded portion
+			size = cg.maxFileSize
+		} else {
+			size = cg.compressedSize(sf.file, size)
 		}
-		if sf.file.Size > cg.maxFileSize {
-			continue
+		if totalSize+size > cg.maxTotalSize {
+			break
 		}
 		selected = append(selected, sf.file)
-		totalSize += sf.file.Size
+		totalSize += size
 	}
 	
 	return selected
 }
 
+// compressedSize is how much of the total size budget a file takes once
+// compressed, since compression is applied after files are selected
+func (cg *ContextGenerator) compressedSize(file FileInfo, size int64) int64 {
+	if cg.compression == 0 || !cg.isTextFile(file.Extension) {
+		return size
+	}
+	content, err := cg.readFileContent(file, cg.maxFileSize)
+	if err != nil {
+		return size
+	}
+	return int64(len(cg.compression.apply(content, cg.getLanguageFromExtension(file.Extension))))
+}
+
 // calculateFileScore calculates a priority score for a file
 func (cg *ContextGenerator) calculateFileScore(file FileInfo) int {
 	score := 0
@@ -396,6 +1189,47 @@ func (cg *ContextGenerator) calculateFileScore(file FileInfo) int {
 		}
 	}
 	
+	score += cg.focusScore(file)
+	
+	return score
+}
+
+// focusScore rewards files whose path or content mentions the focus keywords
+func (cg *ContextGenerator) focusScore(file FileInfo) int {
+	if len(cg.focusKeywords) == 0 {
+		return 0
+	}
+	
+	// Match against the root-relative path so a keyword in the checkout
+	// directory's own name doesn't boost every file
+	path := file.Path
+	if cg.root != "" {
+		if rel, err := filepath.Rel(cg.root, file.Path); err == nil {
+			path = rel
+		}
+	}
+	path = strings.ToLower(path)
+	var content string
+	if cg.isTextFile(file.Extension) {
+		if text, err := cg.readFileContent(file, cg.maxFileSize); err == nil {
+			content = strings.ToLower(text)
+		}
+	}
+	
+	score := 0
+	for _, keyword := range cg.focusKeywords {
+		if strings.Contains(path, keyword) {
+			score += 100
+		}
+		if content != "" {
+			// Cap content matches so one noisy file can't dominate
+			matches := strings.Count(content, keyword)
+			if matches > 5 {
+				matches = 5
+			}
+			score += matches * 10
+		}
+	}
 	return score
 }
 
@@ -418,48 +1252,211 @@ func (cg *ContextGenerator) isTextFile(ext string) bool {
 	return false
 }
 
+// fenceLanguages maps extensions to code fence language hints
+var fenceLanguages = map[string]string{
+	".go":     "go",
+	".js":     "javascript",
+	".mjs":    "javascript",
+	".cjs":    "javascript",
+	".jsx":    "jsx",
+	".ts":     "typescript",
+	".tsx":    "tsx",
+	".vue":    "vue",
+	".svelte": "svelte",
+	".py":     "python",
+	".java":   "java",
+	".kt":     "kotlin",
+	".kts":    "kotlin",
+	".scala":  "scala",
+	".c":      "c",
+	".h":      "c",
+	".cpp":    "cpp",
+	".hpp":    "cpp",
+	".cs":     "csharp",
+	".rs":     "rust",
+	".rb":     "ruby",
+	".php":    "php",
+	".swift":  "swift",
+	".dart":   "dart",
+	".r":      "r",
+	".m":      "objectivec",
+	".html":   "html",
+	".css":    "css",
+	".scss":   "scss",
+	".json":   "json",
+	".xml":    "xml",
+	".yaml":   "yaml",
+	".yml":    "yaml",
+	".toml":   "toml",
+	".ini":    "ini",
+	".cfg":    "ini",
+	".md":     "markdown",
+	".sh":     "bash",
+	".bash":   "bash",
+	".zsh":    "zsh",
+	".fish":   "fish",
+	".ps1":    "powershell",
+	".bat":    "batch",
+	".sql":    "sql",
+}
+
+// getLanguageFromExtension returns the code fence language for an extension
 func (cg *ContextGenerator) getLanguageFromExtension(ext string) string {
-	langMap := map[string]string{
-		".go":   "go",
-		".js":   "javascript",
-		".ts":   "typescript",
-		".py":   "python",
-		".java": "java",
-		".c":    "c",
-		".cpp":  "cpp",
-		".html": "html",
-		".css":  "css",
-		".json": "json",
-		".yaml": "yaml",
-		".yml":  "yaml",
-		".md":   "markdown",
-		".sh":   "bash",
-		".sql":  "sql",
-	}
-	
-	if lang, ok := langMap[ext]; ok {
+	ext = strings.ToLower(ext)
+	if lang, ok := cg.languageOverrides[ext]; ok {
+		return lang
+	}
+	if lang, ok := fenceLanguages[ext]; ok {
 		return lang
 	}
 	return ""
 }
 
-func (cg *ContextGenerator) readFileContent(path string) (string, error) {
-	file, err := os.Open(path)
+// readFileContent streams at most limit bytes of a file as UTF-8; limit <= 0
+// reads it all
+func (cg *ContextGenerator) readFileContent(info FileInfo, limit int64) (string, error) {
+	file, err := os.Open(info.Path)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 	
-	content, err := io.ReadAll(file)
-	if err != nil {
+	return cg.readLimited(decodeText(file, info.Encoding), limit)
+}
+
+// readLimited reads up to limit bytes through a buffered reader so content
+// beyond the cap is never pulled from the source
+func (cg *ContextGenerator) readLimited(r io.Reader, limit int64) (string, error) {
+	if limit > 0 {
+		r = io.LimitReader(r, limit)
+	}
+	
+	var content strings.Builder
+	if _, err := io.Copy(&content, bufio.NewReaderSize(r, cg.readBufferSize)); err != nil {
 		return "", err
 	}
 	
-	return string(content), nil
+	if cg.normalizeNewlines {
+		return normalizeText(content.String()), nil
+	}
+	return content.String(), nil
+}
+
+// normalizeText strips a UTF-8 BOM and converts CRLF/CR line endings to LF
+func normalizeText(text string) string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
 }
 
+// readLargeFile opens an oversized file and applies the large file policy to
+// it, recording files cut at a structural boundary for the summary
+func (cg *ContextGenerator) readLargeFile(info FileInfo, relativePath string) (string, string, error) {
+	file, err := os.Open(info.Path)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+	
+	text := decodeText(file, info.Encoding)
+	mode := cg.largeFileMode(info)
+	if mode != LargeFileStructural {
+		return cg.streamLargeFile(text, mode)
+	}
+	
+	content, truncation, err := cg.truncateAtBoundary(text, cg.getLanguageFromExtension(info.Extension))
+	if err != nil || truncation.Shown >= truncation.Total {
+		return content, "", err
+	}
+	truncation.Path = relativePath
+	cg.truncations = append(cg.truncations, truncation)
+	return content, fmt.Sprintf("Truncated to %d of %d lines", truncation.Shown, truncation.Total), nil
+}
+
+// streamLargeFile keeps the head, tail or both ends of a file within the line
+// budget, holding no more than the budget's lines in memory
+func (cg *ContextGenerator) streamLargeFile(r io.Reader, mode LargeFileMode) (string, string, error) {
+	budget := cg.largeFilePolicy.LineBudget
+	headCount, tailCount := 0, 0
+	switch mode {
+	case LargeFileHead:
+		headCount = budget
+	case LargeFileTail:
+		tailCount = budget
+	case LargeFileBoth:
+		headCount = budget / 2
+		tailCount = budget - headCount
+	}
+	
+	var head []string
+	tail := make([]string, 0, tailCount)
+	tailStart := 0
+	total := 0
+	
+	reader := bufio.NewReaderSize(r, cg.readBufferSize)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", "", err
+		}
+		
+		line = strings.TrimSuffix(line, "\n")
+		if cg.normalizeNewlines {
+			line = normalizeText(strings.TrimSuffix(line, "\r"))
+		}
+		total++
+		
+		switch {
+		case len(head) < headCount:
+			head = append(head, line)
+		case tailCount == 0:
+			// Head-only mode just counts the remaining lines
+		case len(tail) < tailCount:
+			tail = append(tail, line)
+		default:
+			tail[tailStart] = line
+			tailStart = (tailStart + 1) % tailCount
+		}
+		
+		if err == io.EOF {
+			break
+		}
+	}
+	tail = append(tail[tailStart:], tail[:tailStart]...)
+	
+	if total <= budget {
+		return strings.Join(append(head, tail...), "\n"), "", nil
+	}
+	
+	switch mode {
+	case LargeFileHead:
+		return strings.Join(head, "\n"),
+			fmt.Sprintf("Showing first %d of %d lines", budget, total), nil
+	case LargeFileTail:
+		return strings.Join(tail, "\n"),
+			fmt.Sprintf("Showing last %d of %d lines", budget, total), nil
+	default:
+		return fmt.Sprintf("%s\n... (%d lines omitted) ...\n%s",
+				strings.Join(head, "\n"), total-budget, strings.Join(tail, "\n")),
+			fmt.Sprintf("Showing first %d and last %d of %d lines", headCount, tailCount, total), nil
+	}
+}
+
+// getRelativePath formats a path for display according to the path style
 func (cg *ContextGenerator) getRelativePath(fullPath string) string {
-	// Try to get relative path, fallback to basename
+	if cg.pathStyle == PathAbsolute {
+		if abs, err := filepath.Abs(fullPath); err == nil {
+			return abs
+		}
+		return fullPath
+	}
+	if cg.root != "" {
+		if rel, err := filepath.Rel(cg.root, fullPath); err == nil {
+			return rel
+		}
+	}
+	
+	// Results without a root fall back to cwd-relative, then basename
 	if wd, err := os.Getwd(); err == nil {
 		if rel, err := filepath.Rel(wd, fullPath); err == nil {
 			return rel
@@ -480,7 +1477,10 @@ func (cg *ContextGenerator) sortExtensionsByCount(extensions map[string]int) []E
 	}
 	
 	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Count > sorted[j].Count
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Extension < sorted[j].Extension
 	})
 	
 	return sorted
@@ -516,8 +1516,11 @@ func (cg *ContextGenerator) sortExtensionsByPriority(filesByExt map[string][]Fil
 			return false
 		}
 		
-		// Then by count
-		return len(filesByExt[extI]) > len(filesByExt[extJ])
+		// Then by count, and by name so ties come out in the same order every run
+		if len(filesByExt[extI]) != len(filesByExt[extJ]) {
+			return len(filesByExt[extI]) > len(filesByExt[extJ])
+		}
+		return extI < extJ
 	})
 	
 	return extensions
@@ -527,7 +1530,7 @@ func (cg *ContextGenerator) buildDirectoryTree(files []FileInfo) string {
 	// Build a simple directory tree representation
 	var tree strings.Builder
 	
-	// Get unique directories
+	// Get unique directories; directories without included files never appear
 	dirs := make(map[string]bool)
 	for _, file := range files {
 		dir := filepath.Dir(file.Path)
@@ -561,7 +1564,7 @@ func (cg *ContextGenerator) buildDirectoryTree(files []FileInfo) string {
 func (cg *ContextGenerator) generateSummary(scanResult *ScanResult, result *ContextResult) string {
 	var summary strings.Builder
 	
-	summary.WriteString("## Context Summary\n\n")
+	summary.WriteString(cg.heading(2, "Context Summary"))
 	summary.WriteString(fmt.Sprintf("This context contains information about a project with %d files ", scanResult.TotalFiles))
 	summary.WriteString(fmt.Sprintf("totaling %s across %d directories. ", FormatSize(scanResult.TotalSize), scanResult.TotalDirectories))
 	
@@ -570,7 +1573,7 @@ func (cg *ContextGenerator) generateSummary(scanResult *ScanResult, result *Cont
 		maxExt := ""
 		maxCount := 0
 		for ext, count := range scanResult.Extensions {
-			if count > maxCount {
+			if count > maxCount || (count == maxCount && ext < maxExt) {
 				maxCount = count
 				maxExt = ext
 			}
@@ -587,9 +1590,31 @@ func (cg *ContextGenerator) generateSummary(scanResult *ScanResult, result *Cont
 	
 	summary.WriteString(fmt.Sprintf("The context includes %d sections with detailed information about the project structure and contents.", len(result.Sections)))
 	
+	if len(result.Redactions) > 0 {
+		summary.WriteString(fmt.Sprintf("\n\n%d possible secrets were redacted:\n\n", len(result.Redactions)))
+		for _, redaction := range result.Redactions {
+			summary.WriteString(fmt.Sprintf("- %s:%d (%s)\n", redaction.Path, redaction.Line, redaction.Pattern))
+		}
+	}
+	writeTruncations(&summary, result.Truncations)
+	
 	return summary.String()
 }
 
+// writeTruncations lists the oversized files a summary's context cut short
+func writeTruncations(summary *strings.Builder, truncations []Truncation) {
+	if len(truncations) == 0 {
+		return
+	}
+	if !strings.HasSuffix(summary.String(), "\n") {
+		summary.WriteString("\n")
+	}
+	summary.WriteString(fmt.Sprintf("\n%d oversized files were truncated:\n\n", len(truncations)))
+	for _, truncation := range truncations {
+		summary.WriteString(fmt.Sprintf("- %s (%d of %d lines)\n", truncation.Path, truncation.Shown, truncation.Total))
+	}
+}
+
 func (cg *ContextGenerator) estimateTokens(result *ContextResult) int {
 	totalChars := 0
 	
@@ -0,0 +1,174 @@
+package context
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IgnoreFileName is the project-level file controlling what a scan includes
+const IgnoreFileName = ".aicontextignore"
+
+// IgnoreRule is one glob line of an ignore file; Include rules start with "!"
+// and bring back paths the built-in exclusions would skip
+type IgnoreRule struct {
+	Pattern string
+	Include bool
+	Line    int
+}
+
+// String returns the rule as written in the ignore file
+func (r IgnoreRule) String() string {
+	if r.Include {
+		return "!" + r.Pattern
+	}
+	return r.Pattern
+}
+
+// IgnoreFile holds the rules of an .aicontextignore file. Lines are globs
+// relative to the scan root (last match wins), "!glob" re-includes, and
+// "max-size 200KB" excludes larger files.
+type IgnoreFile struct {
+	Rules       []IgnoreRule
+	MaxFileSize int64    // 0 when the file sets no size limit
+	SizeRule    string   // the max-size line as written
+	Warnings    []string // lines that could not be parsed
+}
+
+// LoadIgnoreFile reads the ignore file in root, returning nil if there is none
+func LoadIgnoreFile(root string) (*IgnoreFile, error) {
+	file, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseIgnoreFile(file)
+}
+
+// ParseIgnoreFile parses ignore rules; blank lines and "#" comments are skipped
+func ParseIgnoreFile(r io.Reader) (*IgnoreFile, error) {
+	ignore := &IgnoreFile{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(line, "max-size"); ok && (value == "" || value[0] == ' ' || value[0] == ':') {
+			size, err := ParseSize(strings.TrimSpace(strings.TrimPrefix(value, ":")))
+			if err != nil {
+				ignore.Warnings = append(ignore.Warnings, fmt.Sprintf("line %d: %v", lineNumber, err))
+				continue
+			}
+			ignore.MaxFileSize = size
+			ignore.SizeRule = line
+			continue
+		}
+
+		rule := IgnoreRule{Pattern: line, Line: lineNumber}
+		if strings.HasPrefix(line, "!") {
+			rule.Include = true
+			rule.Pattern = strings.TrimSpace(line[1:])
+		}
+		rule.Pattern = strings.TrimPrefix(rule.Pattern, "/")
+		if rule.Pattern == "" {
+			ignore.Warnings = append(ignore.Warnings, fmt.Sprintf("line %d: empty pattern", lineNumber))
+			continue
+		}
+		ignore.Rules = append(ignore.Rules, rule)
+	}
+	return ignore, scanner.Err()
+}
+
+// ParseSize parses sizes like "512", "200KB", "1.5MB" or "2GB"
+func ParseSize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			multiplier = unit.factor
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(upper, 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
+
+// Match returns the last rule matching a path relative to the scan root, or nil
+func (f *IgnoreFile) Match(relPath string, isDir bool) *IgnoreRule {
+	if f == nil {
+		return nil
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	var matched *IgnoreRule
+	for i := range f.Rules {
+		if matchIgnorePattern(f.Rules[i].Pattern, relPath, isDir) {
+			matched = &f.Rules[i]
+		}
+	}
+	return matched
+}
+
+// matchIgnorePattern matches gitignore-style globs: "dir/" only matches
+// directories, "dir/**" matches everything below dir, a pattern without "/"
+// matches the base name at any depth and "**/" matches any leading directories
+func matchIgnorePattern(pattern, relPath string, isDir bool) bool {
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return matchIgnorePattern(prefix, relPath, true) || matchesAnyParent(prefix, relPath)
+	}
+
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		parts := strings.Split(relPath, "/")
+		for i := range parts {
+			if matched, _ := path.Match(rest, strings.Join(parts[i:], "/")); matched {
+				return true
+			}
+		}
+		return false
+	}
+
+	matched, _ := path.Match(pattern, relPath)
+	return matched
+}
+
+// matchesAnyParent reports whether a directory pattern matches one of the path's parents
+func matchesAnyParent(pattern, relPath string) bool {
+	for dir := path.Dir(relPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if matchIgnorePattern(pattern, dir, true) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// languageNames maps extensions to the language shown in statistics
+var languageNames = map[string]string{
+	".go":   "Go",
+	".js":   "JS",
+	".jsx":  "JS",
+	".ts":   "TS",
+	".tsx":  "TS",
+	".py":   "Python",
+	".java": "Java",
+	".c":    "C",
+	".h":    "C",
+	".cpp":  "C++",
+	".hpp":  "C++",
+	".cs":   "C#",
+	".rb":   "Ruby",
+	".php":  "PHP",
+	".rs":   "Rust",
+	".kt":   "Kotlin",
+	".html": "HTML",
+	".css":  "CSS",
+	".scss": "CSS",
+	".json": "JSON",
+	".yaml": "YAML",
+	".yml":  "YAML",
+	".toml": "TOML",
+	".md":   "Markdown",
+	".sh":   "Shell",
+	".sql":  "SQL",
+}
+
+// LanguageShare is one language's portion of the scanned lines and bytes
+type LanguageShare struct {
+	Language    string
+	Lines       int
+	Bytes       int64
+	LinePercent float64
+	BytePercent float64
+}
+
+// languageName returns the display language for an extension
+func languageName(ext string) string {
+	if name, ok := languageNames[strings.ToLower(ext)]; ok {
+		return name
+	}
+	if ext == "" {
+		return "Other"
+	}
+	return strings.ToUpper(strings.TrimPrefix(ext, "."))
+}
+
+// LanguageShares groups per-extension lines and bytes by language, largest by lines first
+func LanguageShares(scanResult *ScanResult) []LanguageShare {
+	byLanguage := make(map[string]*LanguageShare)
+	var totalLines int
+	var totalBytes int64
+	
+	add := func(ext string) *LanguageShare {
+		name := languageName(ext)
+		if byLanguage[name] == nil {
+			byLanguage[name] = &LanguageShare{Language: name}
+		}
+		return byLanguage[name]
+	}
+	for ext, lines := range scanResult.LinesByExtension {
+		add(ext).Lines += lines
+		totalLines += lines
+	}
+	for ext, size := range scanResult.SizeByExtension {
+		add(ext).Bytes += size
+		totalBytes += size
+	}
+	
+	shares := make([]LanguageShare, 0, len(byLanguage))
+	for _, share := range byLanguage {
+		if totalLines > 0 {
+			share.LinePercent = float64(share.Lines) * 100 / float64(totalLines)
+		}
+		if totalBytes > 0 {
+			share.BytePercent = float64(share.Bytes) * 100 / float64(totalBytes)
+		}
+		shares = append(shares, *share)
+	}
+	
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Lines != shares[j].Lines {
+			return shares[i].Lines > shares[j].Lines
+		}
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Language < shares[j].Language
+	})
+	return shares
+}
+
+// formatLanguageBreakdown renders shares compactly, largest first, e.g.
+// "Go 62% / JS 21% / Markdown 17%", folding everything past limit into "other"
+func formatLanguageBreakdown(shares []LanguageShare, percent func(LanguageShare) float64, limit int) string {
+	shares = append([]LanguageShare(nil), shares...)
+	sort.SliceStable(shares, func(i, j int) bool {
+		return percent(shares[i]) > percent(shares[j])
+	})
+	
+	var parts []string
+	var rest float64
+	for i, share := range shares {
+		if i >= limit {
+			rest += percent(share)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", share.Language, percent(share)))
+	}
+	if rest > 0 {
+		parts = append(parts, fmt.Sprintf("other %.0f%%", rest))
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,106 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SizeLimit is a hard cap on generated context; zero fields are unlimited
+type SizeLimit struct {
+	MaxFiles  int
+	MaxBytes  int64
+	MaxTokens int
+}
+
+// IsZero reports whether no cap is set
+func (l SizeLimit) IsZero() bool {
+	return l.MaxFiles <= 0 && l.MaxBytes <= 0 && l.MaxTokens <= 0
+}
+
+// LimitExceededError reports which caps a context broke and by how much
+type LimitExceededError struct {
+	Limit      SizeLimit
+	Files      int
+	Bytes      int64
+	Tokens     int
+	Violations []string
+}
+
+// Error summarizes the violated caps
+func (e *LimitExceededError) Error() string {
+	return "context exceeds hard cap: " + strings.Join(e.Violations, "; ")
+}
+
+// Suggestions lists ways to bring the context back under the cap
+func (e *LimitExceededError) Suggestions() []string {
+	suggestions := []string{
+		"Tighten exclude patterns or never-include large directories",
+		"Exclude bulky extensions from the result view",
+	}
+	if e.Limit.MaxBytes > 0 || e.Limit.MaxTokens > 0 {
+		suggestions = append(suggestions,
+			"Lower max_file_size so large files are skipped",
+			"Generate structure only (without file contents)")
+	}
+	if e.Limit.MaxFiles > 0 {
+		suggestions = append(suggestions, "Scan a subdirectory instead of the whole project")
+	}
+	return suggestions
+}
+
+// checkFiles rejects scans with more files than allowed, before any content is read
+func (l SizeLimit) checkFiles(scanResult *ScanResult) error {
+	if l.MaxFiles <= 0 || scanResult.TotalFiles <= l.MaxFiles {
+		return nil
+	}
+	return &LimitExceededError{
+		Limit: l,
+		Files: scanResult.TotalFiles,
+		Violations: []string{
+			fmt.Sprintf("%s files (limit %s)", FormatNumber(scanResult.TotalFiles), FormatNumber(l.MaxFiles)),
+		},
+	}
+}
+
+// checkBytes rejects content that would reach bytes, so generation stops before
+// reading the file that takes it past the byte cap
+func (l SizeLimit) checkBytes(bytes int64) error {
+	if l.MaxBytes <= 0 || bytes <= l.MaxBytes {
+		return nil
+	}
+	return &LimitExceededError{
+		Limit: l,
+		Bytes: bytes,
+		Violations: []string{
+			fmt.Sprintf("at least %s (limit %s)", FormatSize(bytes), FormatSize(l.MaxBytes)),
+		},
+	}
+}
+
+// checkOutput rejects generated context larger than the byte or token cap
+func (l SizeLimit) checkOutput(result *ContextResult) error {
+	var bytes int64
+	for _, section := range result.Sections {
+		bytes += int64(len(section.Content))
+	}
+	bytes += int64(len(result.Summary))
+	
+	var violations []string
+	if l.MaxBytes > 0 && bytes > l.MaxBytes {
+		violations = append(violations, fmt.Sprintf("%s (limit %s)", FormatSize(bytes), FormatSize(l.MaxBytes)))
+	}
+	if l.MaxTokens > 0 && result.TokenEstimate > l.MaxTokens {
+		violations = append(violations, fmt.Sprintf("~%s tokens (limit %s)",
+			FormatNumber(result.TokenEstimate), FormatNumber(l.MaxTokens)))
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &LimitExceededError{
+		Limit:      l,
+		Files:      result.TotalFiles,
+		Bytes:      bytes,
+		Tokens:     result.TokenEstimate,
+		Violations: violations,
+	}
+}
@@ -0,0 +1,185 @@
+package context
+
+import (
+	"bytes"
+	stdcontext "context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OutlineMode chooses whether Go files are summarized in an API Outline section
+type OutlineMode int
+
+const (
+	OutlineOff  OutlineMode = iota // file contents only
+	OutlineAdd                     // the outline as well as the contents
+	OutlineOnly                    // the outline replaces the contents of the Go files it covers
+)
+
+// outlineTitle is the title of the API Outline section
+const outlineTitle = "API Outline"
+
+// ParseOutlineMode parses a config or flag value ("off", "add" or "only")
+func ParseOutlineMode(value string) (OutlineMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off":
+		return OutlineOff, nil
+	case "add":
+		return OutlineAdd, nil
+	case "only":
+		return OutlineOnly, nil
+	}
+	return OutlineOff, fmt.Errorf("unknown outline mode %q (use off, add or only)", value)
+}
+
+// generateOutlineSection lists each Go package's exported constants,
+// variables, functions and types with their doc comments, one document per
+// package. Test files are left out. It also returns the files it covered.
+func (cg *ContextGenerator) generateOutlineSection(ctx stdcontext.Context, files []FileInfo) (ContextSection, map[string]bool, error) {
+	filesByDir := make(map[string][]string)
+	for _, file := range files {
+		if file.Extension == ".go" && !strings.HasSuffix(file.Path, "_test.go") {
+			dir := filepath.Dir(file.Path)
+			filesByDir[dir] = append(filesByDir[dir], file.Path)
+		}
+	}
+	dirs := make([]string, 0, len(filesByDir))
+	for dir := range filesByDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var content strings.Builder
+	var includedFiles []string
+	var documents []FileDocument
+	covered := make(map[string]bool)
+	content.WriteString(cg.heading(1, outlineTitle))
+
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return ContextSection{}, nil, err
+		}
+
+		paths := filesByDir[dir]
+		sort.Strings(paths)
+		fset := token.NewFileSet()
+		byPackage := make(map[string][]*ast.File)
+		filePaths := make(map[string][]string)
+		unparsed := 0
+		for _, path := range paths {
+			file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+			if err != nil {
+				unparsed++
+				continue
+			}
+			byPackage[file.Name.Name] = append(byPackage[file.Name.Name], file)
+			filePaths[file.Name.Name] = append(filePaths[file.Name.Name], path)
+		}
+
+		names := make([]string, 0, len(byPackage))
+		for name := range byPackage {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		relativeDir := cg.getRelativePath(dir)
+		for _, name := range names {
+			pkg, err := doc.NewFromFiles(fset, byPackage[name], filepath.ToSlash(relativeDir))
+			if err != nil {
+				unparsed += len(byPackage[name])
+				continue
+			}
+
+			outline := cg.redact(relativeDir, packageOutline(fset, pkg))
+			var note string
+			if unparsed > 0 {
+				note = fmt.Sprintf("%d files in this directory could not be parsed", unparsed)
+			}
+
+			content.WriteString(cg.heading(2, fmt.Sprintf("package %s (%s)", name, relativeDir)))
+			if synopsis := pkg.Synopsis(pkg.Doc); synopsis != "" {
+				content.WriteString(synopsis + "\n\n")
+			}
+			if note != "" {
+				content.WriteString(fmt.Sprintf("*%s*\n\n", note))
+			}
+			content.WriteString(fmt.Sprintf("```go\n%s```\n\n", outline))
+
+			for _, path := range filePaths[name] {
+				covered[path] = true
+				includedFiles = append(includedFiles, cg.getRelativePath(path))
+			}
+			documents = append(documents, FileDocument{Path: relativeDir, Language: "go", Content: outline, Note: note})
+		}
+	}
+
+	if len(documents) == 0 {
+		return ContextSection{}, covered, nil
+	}
+	return ContextSection{
+		Title:     outlineTitle,
+		Content:   content.String(),
+		Files:     includedFiles,
+		IsContent: true,
+		Documents: documents,
+	}, covered, nil
+}
+
+// packageOutline prints a package's exported declarations, each type followed
+// by its constructors and methods, with the first paragraph of each doc comment
+func packageOutline(fset *token.FileSet, pkg *doc.Package) string {
+	var out strings.Builder
+	for _, value := range pkg.Consts {
+		writeOutlineDecl(&out, fset, value.Doc, value.Decl)
+	}
+	for _, value := range pkg.Vars {
+		writeOutlineDecl(&out, fset, value.Doc, value.Decl)
+	}
+	for _, function := range pkg.Funcs {
+		writeOutlineDecl(&out, fset, function.Doc, function.Decl)
+	}
+	for _, typ := range pkg.Types {
+		writeOutlineDecl(&out, fset, typ.Doc, typ.Decl)
+		for _, value := range typ.Consts {
+			writeOutlineDecl(&out, fset, value.Doc, value.Decl)
+		}
+		for _, value := range typ.Vars {
+			writeOutlineDecl(&out, fset, value.Doc, value.Decl)
+		}
+		for _, function := range typ.Funcs {
+			writeOutlineDecl(&out, fset, function.Doc, function.Decl)
+		}
+		for _, method := range typ.Methods {
+			writeOutlineDecl(&out, fset, method.Doc, method.Decl)
+		}
+	}
+	return out.String()
+}
+
+// writeOutlineDecl writes a declaration, without any function body, under its doc comment
+func writeOutlineDecl(out *strings.Builder, fset *token.FileSet, docText string, decl ast.Node) {
+	if paragraph, _, _ := strings.Cut(strings.TrimSpace(docText), "\n\n"); paragraph != "" {
+		for _, line := range strings.Split(paragraph, "\n") {
+			out.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+		}
+	}
+	if function, ok := decl.(*ast.FuncDecl); ok && function.Body != nil {
+		bodiless := *function
+		bodiless.Body = nil
+		decl = &bodiless
+	}
+
+	var printed bytes.Buffer
+	if err := printer.Fprint(&printed, fset, decl); err != nil {
+		return
+	}
+	out.Write(printed.Bytes())
+	out.WriteString("\n\n")
+}
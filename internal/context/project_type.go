@@ -0,0 +1,48 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// projectMarker maps a manifest file to the project type it indicates
+type projectMarker struct {
+	File string
+	Type string
+}
+
+// projectMarkers lists manifest files in detection order
+var projectMarkers = []projectMarker{
+	{File: "go.mod", Type: "Go"},
+	{File: "package.json", Type: "Node"},
+	{File: "requirements.txt", Type: "Python"},
+	{File: "pyproject.toml", Type: "Python"},
+	{File: "setup.py", Type: "Python"},
+	{File: "Pipfile", Type: "Python"},
+	{File: "Cargo.toml", Type: "Rust"},
+	{File: "pom.xml", Type: "Java"},
+	{File: "build.gradle", Type: "Java"},
+	{File: "build.gradle.kts", Type: "Java"},
+	{File: "Gemfile", Type: "Ruby"},
+	{File: "composer.json", Type: "PHP"},
+}
+
+// DetectProjectTypes labels the project stack from manifest files in the root
+func DetectProjectTypes(rootPath string) []string {
+	var types []string
+	seen := make(map[string]bool)
+	
+	for _, marker := range projectMarkers {
+		if seen[marker.Type] {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(rootPath, marker.File))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		seen[marker.Type] = true
+		types = append(types, marker.Type)
+	}
+	
+	return types
+}
@@ -0,0 +1,164 @@
+package context
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redaction records one secret masked out of the generated context
+type Redaction struct {
+	Path    string
+	Line    int
+	Pattern string
+}
+
+// secretPattern finds one kind of secret; group selects the part of the match
+// to mask (0 for all of it) and check, when set, rejects likely false positives
+type secretPattern struct {
+	name  string
+	re    *regexp.Regexp
+	group int
+	check func(string) bool
+}
+
+// secretPatterns are tried in order; when matches overlap the earlier pattern wins
+var secretPatterns = []secretPattern{
+	{name: "private-key", re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{name: "aws-access-key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github-token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{name: "slack-token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{name: "api-key", re: regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35})`)},
+	{name: "jwt", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{
+		name:  "secret-assignment",
+		re:    regexp.MustCompile(`(?i)\w*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key)\w*["']?\s*(?::=|=|:)\s*["']([^"'\s]{6,})["']`),
+		group: 1,
+		check: notPlaceholder,
+	},
+	{
+		name:  "env-secret",
+		re:    regexp.MustCompile(`(?m)^\s*(?:export\s+)?[A-Z0-9_]*(?:PASSWORD|PASSWD|SECRET|TOKEN|API_KEY|ACCESS_KEY|PRIVATE_KEY)[A-Z0-9_]*=([^\s"'#]{6,})`),
+		group: 1,
+		check: notPlaceholder,
+	},
+	{
+		name:  "high-entropy",
+		re:    regexp.MustCompile("[\"'`]([A-Za-z0-9+/=_-]{24,})[\"'`]"),
+		group: 1,
+		check: highEntropy,
+	},
+}
+
+// RedactionPatterns returns the names of the secret patterns, for opting out
+func RedactionPatterns() []string {
+	names := make([]string, len(secretPatterns))
+	for i, pattern := range secretPatterns {
+		names[i] = pattern.name
+	}
+	return names
+}
+
+// notPlaceholder rejects values that are references or examples rather than secrets
+func notPlaceholder(value string) bool {
+	if strings.ContainsAny(value[:1], "$<{%") {
+		return false
+	}
+	lower := strings.ToLower(value)
+	for _, placeholder := range []string{"changeme", "example", "placeholder", "your_", "your-", "xxxx", "****"} {
+		if strings.Contains(lower, placeholder) {
+			return false
+		}
+	}
+	return true
+}
+
+// highEntropy reports whether a string looks random enough to be a key: mixed
+// letters and digits with a Shannon entropy of at least 4 bits per character
+func highEntropy(value string) bool {
+	if !strings.ContainsAny(value, "0123456789") || strings.ToLower(value) == value || strings.ToUpper(value) == value {
+		return false
+	}
+	counts := make(map[rune]int)
+	for _, r := range value {
+		counts[r]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(len(value))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= 4.0
+}
+
+// Redactor masks secrets in file content
+type Redactor struct {
+	patterns []secretPattern
+}
+
+// NewRedactor returns a redactor using every pattern except those named in skip
+func NewRedactor(skip []string) *Redactor {
+	skipped := make(map[string]bool, len(skip))
+	for _, name := range skip {
+		skipped[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	redactor := &Redactor{}
+	for _, pattern := range secretPatterns {
+		if !skipped[pattern.name] {
+			redactor.patterns = append(redactor.patterns, pattern)
+		}
+	}
+	return redactor
+}
+
+// secretMatch is a span of text to mask
+type secretMatch struct {
+	start, end int
+	order      int
+	pattern    string
+}
+
+// Redact replaces each secret in text with a [REDACTED:pattern] marker and
+// returns the masked text with one Redaction per secret, in text order
+func (r *Redactor) Redact(path, text string) (string, []Redaction) {
+	var matches []secretMatch
+	for order, pattern := range r.patterns {
+		for _, loc := range pattern.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*pattern.group], loc[2*pattern.group+1]
+			if start < 0 || (pattern.check != nil && !pattern.check(text[start:end])) {
+				continue
+			}
+			matches = append(matches, secretMatch{start: start, end: end, order: order, pattern: pattern.name})
+		}
+	}
+	if len(matches) == 0 {
+		return text, nil
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].order < matches[j].order
+	})
+
+	var masked strings.Builder
+	var redactions []Redaction
+	last := 0
+	for _, match := range matches {
+		if match.start < last {
+			continue // overlaps a secret already masked
+		}
+		masked.WriteString(text[last:match.start])
+		masked.WriteString(fmt.Sprintf("[REDACTED:%s]", match.pattern))
+		redactions = append(redactions, Redaction{
+			Path:    path,
+			Line:    strings.Count(text[:match.start], "\n") + 1,
+			Pattern: match.pattern,
+		})
+		last = match.end
+	}
+	masked.WriteString(text[last:])
+	return masked.String(), redactions
+}
@@ -0,0 +1,94 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ScanCache reuses scan results while the scanned tree and the effective
+// scan configuration are unchanged
+type ScanCache struct {
+	mu      sync.Mutex
+	entries map[string]scanCacheEntry
+}
+
+// scanCacheEntry pairs a result with the modification times it was built from
+type scanCacheEntry struct {
+	result   *ScanResult
+	modTimes map[string]time.Time
+}
+
+// NewScanCache creates an empty scan cache
+func NewScanCache() *ScanCache {
+	return &ScanCache{entries: make(map[string]scanCacheEntry)}
+}
+
+// ScanCacheKey identifies a scan by its root and a hash of the effective config,
+// so changing excludes or limits never reuses a stale result
+func ScanCacheKey(config ScanConfig) string {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return config.RootPath + "@" + hex.EncodeToString(sum[:8])
+}
+
+// Get returns the cached result for a config if no scanned file or
+// directory has been modified since it was stored
+func (c *ScanCache) Get(config ScanConfig) (*ScanResult, bool) {
+	key := ScanCacheKey(config)
+	if key == "" {
+		return nil, false
+	}
+	
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	
+	for path, modTime := range entry.modTimes {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			c.mu.Lock()
+			delete(c.entries, key)
+			c.mu.Unlock()
+			return nil, false
+		}
+	}
+	return entry.result, true
+}
+
+// Put stores a scan result together with the modification times of its
+// files and their directories
+func (c *ScanCache) Put(config ScanConfig, result *ScanResult) {
+	key := ScanCacheKey(config)
+	if key == "" || result == nil {
+		return
+	}
+	
+	modTimes := make(map[string]time.Time)
+	if info, err := os.Stat(config.RootPath); err == nil {
+		modTimes[config.RootPath] = info.ModTime()
+	}
+	for _, file := range result.Files {
+		modTimes[file.Path] = file.ModTime
+		dir := filepath.Dir(file.Path)
+		if _, seen := modTimes[dir]; !seen {
+			if info, err := os.Stat(dir); err == nil {
+				modTimes[dir] = info.ModTime()
+			}
+		}
+	}
+	
+	c.mu.Lock()
+	c.entries[key] = scanCacheEntry{result: result, modTimes: modTimes}
+	c.mu.Unlock()
+}
@@ -2,12 +2,17 @@ package context
 
 import (
 	"bufio"
+	stdcontext "context"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,10 +26,26 @@ type FileInfo struct {
 	ModTime      time.Time
 	IsExcluded   bool
 	ExcludeReason string
+	IgnoreRule   string // .aicontextignore rule that excluded the file, if any
+	Encoding     string // detected from the content, e.g. EncodingUTF8; empty for directories
+	unchanged    bool   // content matches the persistent file cache
+	generated    bool   // starts with a code generator's marker comment
+	readErr      error  // why the file could not be read, if it couldn't
+}
+
+// ScanError is a path the scan skipped because it could not be read
+type ScanError struct {
+	Path string
+	Err  error
+}
+
+func (e ScanError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
 }
 
 // ScanResult represents the result of a project scan
 type ScanResult struct {
+	RootPath        string
 	TotalFiles      int
 	TotalDirectories int
 	TotalSize       int64
@@ -33,7 +54,16 @@ type ScanResult struct {
 	ScanDuration    time.Duration
 	Files           []FileInfo
 	Extensions      map[string]int
+	LinesByExtension map[string]int
+	SizeByExtension map[string]int64
 	LargestFiles    []FileInfo
+	ProjectTypes    []string
+	Excluded        []FileInfo // excluded files with their reasons
+	IgnoreRuleHits  map[string]int // files excluded per .aicontextignore rule
+	IgnoreRuleDirs  map[string]int // folders excluded whole per rule, whose files are not counted
+	UnchangedFiles  int // included files reused from the persistent file cache
+	RescannedFiles  int // included files read again because they are new or changed
+	Errors          []ScanError // unreadable paths skipped while the rest was scanned
 }
 
 // ScanConfig holds configuration for the scanner
@@ -41,16 +71,28 @@ type ScanConfig struct {
 	RootPath        string
 	ExcludePatterns []string
 	ExcludeExtensions []string
+	IncludeExtensions []string // when set, only files with these extensions are scanned
 	MaxDepth        int
 	MaxFileSize     int64 // in bytes
 	IncludeHidden   bool
 	FollowSymlinks  bool
+	ForceInclude    []string // paths included regardless of exclusion rules
+	SkipEmptyFiles  bool     // exclude zero-byte files
+	SkipGenerated   bool     // exclude lockfiles, generated code and duplicate copies
+	EstimateLimit   int      // stop the pre-scan file estimate after this many files (0 = no limit)
+	IgnoreFile      *IgnoreFile // rules from the root's .aicontextignore, nil if absent
+	Workers         int         // files stat'ed and line-counted concurrently (0 = one per CPU)
+	Paths           []string    // when set, only these files and folders under RootPath are scanned
 }
 
-// DefaultScanConfig returns a sensible default configuration
+// DefaultScanConfig returns a sensible default configuration, including the
+// root's .aicontextignore rules; an unreadable ignore file is skipped
 func DefaultScanConfig(rootPath string) ScanConfig {
+	root := resolveRoot(rootPath)
+	ignoreFile, _ := LoadIgnoreFile(root)
+	
 	return ScanConfig{
-		RootPath: rootPath,
+		RootPath: root,
 		ExcludePatterns: []string{
 			"node_modules/**",
 			".git/**",
@@ -60,6 +102,9 @@ func DefaultScanConfig(rootPath string) ScanConfig {
 			"*.log",
 			"*.tmp",
 			"*.cache",
+			"*.swp",
+			"*.swo",
+			"*~",
 			".DS_Store",
 			"Thumbs.db",
 		},
@@ -74,14 +119,26 @@ func DefaultScanConfig(rootPath string) ScanConfig {
 		MaxFileSize:    10 * 1024 * 1024, // 10MB
 		IncludeHidden:  false,
 		FollowSymlinks: false,
+		SkipEmptyFiles: true,
+		SkipGenerated:  true,
+		EstimateLimit:  10000,
+		IgnoreFile:     ignoreFile,
 	}
 }
 
 // ProjectScanner handles scanning project directories
 type ProjectScanner struct {
-	config   ScanConfig
-	progress chan ScanProgress
-	cancel   chan bool
+	config     ScanConfig
+	progress   chan ScanProgress
+	cancel     chan struct{} // closed by Cancel so every worker sees it
+	cancelOnce sync.Once
+	closeOnce  sync.Once
+	cache      *ScanCache
+	cacheDir   string     // where the persistent file cache lives; empty disables it
+	fileCache  *FileCache
+	
+	pauseMu sync.Mutex
+	resume  chan struct{} // non-nil while paused; closed on resume
 }
 
 // ScanProgress represents progress during scanning
@@ -93,22 +150,66 @@ type ScanProgress struct {
 	ElapsedTime     time.Duration
 }
 
+// resolveRoot returns the absolute, symlink-free form of a scan root so
+// relative paths and exclusions see canonical paths; on failure the path is kept
+func resolveRoot(rootPath string) string {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return rootPath
+	}
+	resolved, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return rootPath
+	}
+	return resolved
+}
+
 // NewProjectScanner creates a new project scanner
 func NewProjectScanner(config ScanConfig) *ProjectScanner {
 	return &ProjectScanner{
 		config:   config,
 		progress: make(chan ScanProgress, 100),
-		cancel:   make(chan bool, 1),
+		cancel:   make(chan struct{}),
 	}
 }
 
-// Scan performs a full project scan
-func (ps *ProjectScanner) Scan() (*ScanResult, error) {
+// Scan performs a full project scan. The progress channel is closed when
+// Scan returns, whether it completed, failed or was cancelled. Cancelling ctx
+// stops the scan like Cancel, and the error then wraps ctx.Err().
+func (ps *ProjectScanner) Scan(ctx stdcontext.Context) (*ScanResult, error) {
+	defer ps.closeProgress()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stop := stdcontext.AfterFunc(ctx, ps.Cancel)
+	defer stop()
+	
 	startTime := time.Now()
 	
+	if ps.cache != nil {
+		if cached, ok := ps.cache.Get(ps.config); ok {
+			ps.sendProgress(ScanProgress{
+				CurrentPhase:   "Using cached scan",
+				ProcessedFiles: cached.TotalFiles,
+				TotalEstimated: cached.TotalFiles,
+				ElapsedTime:    time.Since(startTime),
+			})
+			return cached, nil
+		}
+	}
+	
+	if ps.cacheDir != "" {
+		ps.fileCache = LoadFileCache(ps.cacheDir, ps.config.RootPath)
+	}
+	
 	result := &ScanResult{
+		RootPath:   ps.config.RootPath,
 		Files:      make([]FileInfo, 0),
 		Extensions: make(map[string]int),
+		LinesByExtension: make(map[string]int),
+		SizeByExtension: make(map[string]int64),
+		IgnoreRuleHits:  make(map[string]int),
+		IgnoreRuleDirs:  make(map[string]int),
 	}
 	
 	// Send initial progress
@@ -117,6 +218,10 @@ func (ps *ProjectScanner) Scan() (*ScanResult, error) {
 		ElapsedTime:  time.Since(startTime),
 	})
 	
+	if err := ps.waitIfPaused(); err != nil {
+		return nil, scanFailed(ctx, err)
+	}
+	
 	// First pass: count files for progress estimation
 	estimatedFiles := ps.estimateFileCount()
 	
@@ -126,16 +231,28 @@ func (ps *ProjectScanner) Scan() (*ScanResult, error) {
 		ElapsedTime:    time.Since(startTime),
 	})
 	
-	// Second pass: actual scanning
-	err := ps.scanDirectory(ps.config.RootPath, 0, result, startTime, estimatedFiles)
-	if err != nil {
-		return nil, fmt.Errorf("scan failed: %w", err)
+	// Second pass: walk the tree, then scan its files on the worker pool
+	var jobs []fileJob
+	if err := ps.walk(result, &jobs); err != nil {
+		return nil, scanFailed(ctx, err)
+	}
+	if err := ps.scanFiles(jobs, result, startTime, estimatedFiles); err != nil {
+		return nil, scanFailed(ctx, err)
 	}
 	
 	// Post-process results
 	result.ScanDuration = time.Since(startTime)
+	result.ProjectTypes = DetectProjectTypes(ps.config.RootPath)
 	ps.processResults(result)
 	
+	if ps.cache != nil {
+		ps.cache.Put(ps.config, result)
+	}
+	if ps.fileCache != nil {
+		// The cache only saves work, so a failed write is not a scan failure
+		ps.fileCache.Save()
+	}
+	
 	ps.sendProgress(ScanProgress{
 		CurrentPhase:   "Scan completed!",
 		ProcessedFiles: result.TotalFiles,
@@ -146,6 +263,25 @@ func (ps *ProjectScanner) Scan() (*ScanResult, error) {
 	return result, nil
 }
 
+// scanFailed wraps a scan error, reporting ctx's error when cancelling ctx caused it
+func scanFailed(ctx stdcontext.Context, err error) error {
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return fmt.Errorf("scan failed: %w", err)
+}
+
+// SetCache enables reusing results from a scan cache
+func (ps *ProjectScanner) SetCache(cache *ScanCache) {
+	ps.cache = cache
+}
+
+// SetCacheDir enables the persistent file cache stored in dir, so a rescan
+// only re-reads files that changed since the last scan of the same root
+func (ps *ProjectScanner) SetCacheDir(dir string) {
+	ps.cacheDir = dir
+}
+
 // GetProgressChannel returns the progress channel
 func (ps *ProjectScanner) GetProgressChannel() <-chan ScanProgress {
 	return ps.progress
@@ -153,44 +289,180 @@ func (ps *ProjectScanner) GetProgressChannel() <-chan ScanProgress {
 
 // Cancel stops the scanning process
 func (ps *ProjectScanner) Cancel() {
+	ps.cancelOnce.Do(func() {
+		close(ps.cancel)
+	})
+}
+
+// Pause blocks the scan before its next file system access until Resume is called
+func (ps *ProjectScanner) Pause() {
+	ps.pauseMu.Lock()
+	defer ps.pauseMu.Unlock()
+	if ps.resume == nil {
+		ps.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused scan continue
+func (ps *ProjectScanner) Resume() {
+	ps.pauseMu.Lock()
+	defer ps.pauseMu.Unlock()
+	if ps.resume != nil {
+		close(ps.resume)
+		ps.resume = nil
+	}
+}
+
+// IsPaused reports whether the scan is currently paused
+func (ps *ProjectScanner) IsPaused() bool {
+	ps.pauseMu.Lock()
+	defer ps.pauseMu.Unlock()
+	return ps.resume != nil
+}
+
+// waitIfPaused blocks while the scan is paused; cancelling also ends the wait
+func (ps *ProjectScanner) waitIfPaused() error {
+	ps.pauseMu.Lock()
+	resume := ps.resume
+	ps.pauseMu.Unlock()
+	if resume == nil {
+		return nil
+	}
+	
 	select {
-	case ps.cancel <- true:
-	default:
+	case <-resume:
+		return nil
+	case <-ps.cancel:
+		return fmt.Errorf("scan cancelled")
 	}
 }
 
 // estimateFileCount provides a rough estimate of files to scan
 func (ps *ProjectScanner) estimateFileCount() int {
 	count := 0
-	filepath.WalkDir(ps.config.RootPath, func(path string, d fs.DirEntry, err error) error {
+	for _, path := range ps.config.scanPaths() {
+		info, err := os.Stat(path)
 		if err != nil {
-			return nil // Continue on errors during estimation
+			continue
 		}
-		
-		if ps.shouldExcludePath(path, d.IsDir()) {
-			if d.IsDir() {
-				return fs.SkipDir
+		if info.IsDir() {
+			ps.estimateDirectory(path, pathDepth(ps.config.RootPath, path), &count)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+// scanPaths returns the paths a scan starts from: the root, or the selected
+// paths resolved, sorted and with any path inside another one dropped
+func (c ScanConfig) scanPaths() []string {
+	if len(c.Paths) == 0 {
+		return []string{c.RootPath}
+	}
+	
+	resolved := make([]string, 0, len(c.Paths))
+	for _, path := range c.Paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.RootPath, path)
+		}
+		resolved = append(resolved, resolveRoot(path))
+	}
+	sort.Strings(resolved)
+	
+	var paths []string
+	for _, path := range resolved {
+		if len(paths) > 0 {
+			last := paths[len(paths)-1]
+			if path == last || strings.HasPrefix(path, last+string(filepath.Separator)) {
+				continue
 			}
-			return nil
 		}
-		
-		if !d.IsDir() {
-			count++
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// pathDepth returns how many directories below root a path is
+func pathDepth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return len(strings.Split(rel, string(filepath.Separator)))
+}
+
+// walk queues the files of every scan path, entering selected folders in order
+func (ps *ProjectScanner) walk(result *ScanResult, jobs *[]fileJob) error {
+	if len(ps.config.Paths) == 0 {
+		return ps.scanDirectory(ps.config.RootPath, 0, result, jobs)
+	}
+	
+	for _, path := range ps.config.scanPaths() {
+		info, err := os.Lstat(path)
+		if err != nil {
+			result.Errors = append(result.Errors, ScanError{Path: path, Err: err})
+			continue
+		}
+		if !info.IsDir() {
+			*jobs = append(*jobs, fileJob{path: path, entry: fs.FileInfoToDirEntry(info)})
+			continue
+		}
+		if path != ps.config.RootPath {
+			result.TotalDirectories++
+		}
+		if err := ps.scanDirectory(path, pathDepth(ps.config.RootPath, path), result, jobs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimateDirectory counts files the scan would visit, mirroring scanDirectory's
+// depth limit and exclusion rules so excluded trees are never walked
+func (ps *ProjectScanner) estimateDirectory(dirPath string, depth int, count *int) {
+	if depth > ps.config.MaxDepth || ps.estimateLimitReached(*count) {
+		return
+	}
+	
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return // Continue on errors during estimation
+	}
+	
+	for _, entry := range entries {
+		// A pause cuts the estimate short; the scan itself waits for resume
+		if ps.estimateLimitReached(*count) || ps.IsPaused() {
+			return
 		}
 		
-		// Limit estimation time
-		if count > 10000 {
-			return fs.SkipAll
+		fullPath := filepath.Join(dirPath, entry.Name())
+		if ps.IsExcluded(fullPath, entry.IsDir()) {
+			continue
 		}
 		
-		return nil
-	})
-	
-	return count
+		if entry.IsDir() {
+			ps.estimateDirectory(fullPath, depth+1, count)
+		} else {
+			*count++
+		}
+	}
+}
+
+// estimateLimitReached reports whether estimation should stop to bound its cost
+func (ps *ProjectScanner) estimateLimitReached(count int) bool {
+	return ps.config.EstimateLimit > 0 && count >= ps.config.EstimateLimit
 }
 
-// scanDirectory recursively scans a directory
-func (ps *ProjectScanner) scanDirectory(dirPath string, depth int, result *ScanResult, startTime time.Time, totalEstimated int) error {
+// fileJob is a file found by the directory walk, waiting for a worker
+type fileJob struct {
+	path  string
+	entry fs.DirEntry
+}
+
+// scanDirectory recursively walks a directory, deciding which subdirectories
+// to enter and queueing files in walk order for scanFiles
+func (ps *ProjectScanner) scanDirectory(dirPath string, depth int, result *ScanResult, jobs *[]fileJob) error {
 	if depth > ps.config.MaxDepth {
 		return nil
 	}
@@ -202,47 +474,128 @@ func (ps *ProjectScanner) scanDirectory(dirPath string, depth int, result *ScanR
 	default:
 	}
 	
+	if err := ps.waitIfPaused(); err != nil {
+		return err
+	}
+	
+	// One unreadable folder shouldn't lose the rest of the project; only the
+	// root is fatal. ReadDir still returns what it read before failing.
 	entries, err := os.ReadDir(dirPath)
 	if err != nil {
-		return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+		if dirPath == ps.config.RootPath {
+			return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+		}
+		result.Errors = append(result.Errors, ScanError{Path: dirPath, Err: err})
 	}
 	
 	for _, entry := range entries {
 		fullPath := filepath.Join(dirPath, entry.Name())
 		
-		// Send progress update
-		ps.sendProgress(ScanProgress{
-			CurrentFile:    fullPath,
-			ProcessedFiles: result.TotalFiles + result.ExcludedFiles,
-			TotalEstimated: totalEstimated,
-			CurrentPhase:   "Scanning files...",
-			ElapsedTime:    time.Since(startTime),
-		})
+		if !entry.IsDir() {
+			*jobs = append(*jobs, fileJob{path: fullPath, entry: entry})
+			continue
+		}
 		
 		fileInfo := ps.scanFile(fullPath, entry)
-		
-		if entry.IsDir() {
-			result.TotalDirectories++
-			if !fileInfo.IsExcluded {
-				// Recurse into subdirectory
-				err := ps.scanDirectory(fullPath, depth+1, result, startTime, totalEstimated)
-				if err != nil {
-					return err
-				}
+		result.TotalDirectories++
+		// Excluded folders are never walked, so their files go uncounted
+		if fileInfo.IgnoreRule != "" {
+			result.IgnoreRuleDirs[fileInfo.IgnoreRule]++
+		}
+		if !fileInfo.IsExcluded {
+			// Recurse into subdirectory
+			if err := ps.scanDirectory(fullPath, depth+1, result, jobs); err != nil {
+				return err
 			}
-		} else {
-			if fileInfo.IsExcluded {
-				result.ExcludedFiles++
-			} else {
-				result.TotalFiles++
-				result.TotalSize += fileInfo.Size
-				result.TotalLines += fileInfo.Lines
-				result.Extensions[fileInfo.Extension]++
-				result.Files = append(result.Files, fileInfo)
+		}
+	}
+	
+	return nil
+}
+
+// workers returns the size of the file scanning pool
+func (c ScanConfig) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// scanFiles scans queued files on a bounded worker pool, then adds them to
+// the result in walk order so the output does not depend on scheduling
+func (ps *ProjectScanner) scanFiles(jobs []fileJob, result *ScanResult, startTime time.Time, totalEstimated int) error {
+	infos := make([]FileInfo, len(jobs))
+	work := make(chan int)
+	var processed int64
+	var wg sync.WaitGroup
+	
+	for w := 0; w < ps.config.workers(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				infos[i] = ps.scanFile(jobs[i].path, jobs[i].entry)
+				ps.sendProgress(ScanProgress{
+					CurrentFile:    jobs[i].path,
+					ProcessedFiles: int(atomic.AddInt64(&processed, 1)),
+					TotalEstimated: totalEstimated,
+					CurrentPhase:   "Scanning files...",
+					ElapsedTime:    time.Since(startTime),
+				})
 			}
+		}()
+	}
+	
+	// Feeding stops while paused, so workers go idle once their current file is done
+	var err error
+feed:
+	for i := range jobs {
+		if err = ps.waitIfPaused(); err != nil {
+			break
+		}
+		select {
+		case work <- i:
+		case <-ps.cancel:
+			err = fmt.Errorf("scan cancelled")
+			break feed
 		}
 	}
+	close(work)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	if ps.config.SkipGenerated {
+		ps.markDuplicates(infos)
+	}
 	
+	for _, fileInfo := range infos {
+		if fileInfo.readErr != nil {
+			result.Errors = append(result.Errors, ScanError{Path: fileInfo.Path, Err: fileInfo.readErr})
+		}
+		if fileInfo.IsExcluded {
+			result.ExcludedFiles++
+			result.Excluded = append(result.Excluded, fileInfo)
+			if fileInfo.IgnoreRule != "" {
+				result.IgnoreRuleHits[fileInfo.IgnoreRule]++
+			}
+			continue
+		}
+		if ps.fileCache != nil {
+			if fileInfo.unchanged {
+				result.UnchangedFiles++
+			} else {
+				result.RescannedFiles++
+			}
+		}
+		result.TotalFiles++
+		result.TotalSize += fileInfo.Size
+		result.TotalLines += fileInfo.Lines
+		result.Extensions[fileInfo.Extension]++
+		result.LinesByExtension[fileInfo.Extension] += fileInfo.Lines
+		result.SizeByExtension[fileInfo.Extension] += fileInfo.Size
+		result.Files = append(result.Files, fileInfo)
+	}
 	return nil
 }
 
@@ -255,6 +608,7 @@ func (ps *ProjectScanner) scanFile(path string, entry fs.DirEntry) FileInfo {
 			IsDirectory:   entry.IsDir(),
 			IsExcluded:    true,
 			ExcludeReason: fmt.Sprintf("Cannot read file info: %v", err),
+			readErr:       err,
 		}
 	}
 	
@@ -266,10 +620,29 @@ func (ps *ProjectScanner) scanFile(path string, entry fs.DirEntry) FileInfo {
 		Extension:   strings.ToLower(filepath.Ext(path)),
 	}
 	
+	// Forced paths skip the exclusion rules
+	if ps.isForceIncluded(path) {
+		if !entry.IsDir() {
+			ps.readContent(&fileInfo, info)
+		}
+		return fileInfo
+	}
+	
 	// Check exclusion rules
 	if ps.shouldExcludePath(path, entry.IsDir()) {
 		fileInfo.IsExcluded = true
 		fileInfo.ExcludeReason = "Matches exclude pattern"
+		if rule := ps.config.ignoreRule(path, entry.IsDir()); rule != nil {
+			fileInfo.IgnoreRule = rule.String()
+		}
+		return fileInfo
+	}
+	
+	// The project's own size limit is attributed to its ignore file
+	if ignore := ps.config.IgnoreFile; !entry.IsDir() && ignore != nil && ignore.MaxFileSize > 0 && info.Size() > ignore.MaxFileSize {
+		fileInfo.IsExcluded = true
+		fileInfo.ExcludeReason = fmt.Sprintf("File too large (%d bytes)", info.Size())
+		fileInfo.IgnoreRule = ignore.SizeRule
 		return fileInfo
 	}
 	
@@ -280,88 +653,322 @@ func (ps *ProjectScanner) scanFile(path string, entry fs.DirEntry) FileInfo {
 		return fileInfo
 	}
 	
-	// Count lines for text files
-	if !entry.IsDir() && ps.isTextFile(fileInfo.Extension) {
-		lines, err := ps.countLines(path)
-		if err == nil {
-			fileInfo.Lines = lines
+	// Skip zero-byte files
+	if !entry.IsDir() && ps.config.SkipEmptyFiles && info.Size() == 0 {
+		fileInfo.IsExcluded = true
+		fileInfo.ExcludeReason = "empty"
+		return fileInfo
+	}
+	
+	// Binaries are recognized by content, whatever their extension
+	if !entry.IsDir() {
+		ps.readContent(&fileInfo, info)
+		if !fileInfo.IsExcluded && fileInfo.Encoding != "" && !isText(fileInfo.Encoding) {
+			fileInfo.IsExcluded = true
+			fileInfo.ExcludeReason = "binary"
+		}
+	}
+	
+	// Lockfiles and generated code cost tokens without telling a reader much
+	if !entry.IsDir() && !fileInfo.IsExcluded && ps.config.SkipGenerated {
+		if reason := generatedReason(path, fileInfo.generated); reason != "" {
+			fileInfo.IsExcluded = true
+			fileInfo.ExcludeReason = reason
 		}
 	}
 	
 	return fileInfo
 }
 
+// readContent detects a file's encoding and counts the lines of text files,
+// reusing the file cache for unchanged files. Unreadable files are excluded.
+func (ps *ProjectScanner) readContent(fileInfo *FileInfo, info os.FileInfo) {
+	if ps.fileCache != nil {
+		var entry fileCacheEntry
+		entry, fileInfo.unchanged, fileInfo.readErr = ps.fileCache.scan(fileInfo.Path, info)
+		fileInfo.Lines, fileInfo.Encoding = entry.Lines, entry.Encoding
+		fileInfo.generated = entry.Generated != nil && *entry.Generated
+	} else {
+		var file *sniffedFile
+		if file, fileInfo.readErr = openSniffed(fileInfo.Path); fileInfo.readErr == nil {
+			fileInfo.Encoding = file.encoding
+			if isText(file.encoding) {
+				fileInfo.generated = hasGeneratedMarker(file.head)
+				if lines, err := countReaderLines(file.content()); err == nil {
+					fileInfo.Lines = lines
+				}
+			}
+			file.Close()
+		}
+	}
+	
+	if fileInfo.readErr != nil {
+		fileInfo.IsExcluded = true
+		fileInfo.ExcludeReason = fmt.Sprintf("Cannot read file: %v", fileInfo.readErr)
+	}
+}
+
+// IsExcluded reports whether a path would be skipped by this scanner's rules
+func (ps *ProjectScanner) IsExcluded(path string, isDir bool) bool {
+	return !ps.isForceIncluded(path) && ps.shouldExcludePath(path, isDir)
+}
+
+// isForceIncluded reports whether a path is on the force-include list
+func (ps *ProjectScanner) isForceIncluded(path string) bool {
+	return ps.config.isForceIncluded(path)
+}
+
+// isForceIncluded reports whether a path is on the force-include list
+func (c ScanConfig) isForceIncluded(path string) bool {
+	for _, forced := range c.ForceInclude {
+		if filepath.Clean(forced) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExclusionKind groups an exclusion reason into a filterable category
+func ExclusionKind(reason string) string {
+	switch {
+	case strings.HasPrefix(reason, "File too large"):
+		return "too large"
+	case strings.HasPrefix(reason, "Matches exclude pattern"):
+		return "pattern"
+	case strings.HasPrefix(reason, "Cannot read"):
+		return "unreadable"
+	case reason == "empty":
+		return "empty"
+	case reason == "binary":
+		return "binary"
+	case reason == reasonLockfile:
+		return "lockfile"
+	case reason == reasonGenerated:
+		return "generated"
+	case strings.HasPrefix(reason, reasonDuplicate):
+		return "duplicate"
+	default:
+		return "other"
+	}
+}
+
 // shouldExcludePath checks if a path should be excluded
 func (ps *ProjectScanner) shouldExcludePath(path string, isDir bool) bool {
+	return ps.config.exclusionRule(path, isDir) != ""
+}
+
+// ignoreRule returns the .aicontextignore rule deciding a path, or nil if none matches
+func (c ScanConfig) ignoreRule(path string, isDir bool) *IgnoreRule {
+	if c.IgnoreFile == nil {
+		return nil
+	}
+	rel, err := filepath.Rel(c.RootPath, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	return c.IgnoreFile.Match(rel, isDir)
+}
+
+// exclusionRule describes the ignore file, hidden, extension or pattern rule
+// excluding a path, or "" if none does
+func (c ScanConfig) exclusionRule(path string, isDir bool) string {
+	// Project rules take precedence over the built-in exclusions
+	if rule := c.ignoreRule(path, isDir); rule != nil {
+		if rule.Include {
+			return ""
+		}
+		return fmt.Sprintf("matches %s rule %q (line %d)", IgnoreFileName, rule.Pattern, rule.Line)
+	}
+	
 	// Check hidden files/directories
-	if !ps.config.IncludeHidden {
+	if !c.IncludeHidden {
 		if strings.HasPrefix(filepath.Base(path), ".") {
-			return true
+			return "hidden file or directory"
 		}
 	}
 	
 	// Check extension exclusions
 	if !isDir {
 		ext := strings.ToLower(filepath.Ext(path))
-		for _, excludeExt := range ps.config.ExcludeExtensions {
+		for _, excludeExt := range c.ExcludeExtensions {
 			if ext == excludeExt {
-				return true
+				return fmt.Sprintf("excluded extension %q", excludeExt)
 			}
 		}
+		if len(c.IncludeExtensions) > 0 && !containsExtension(c.IncludeExtensions, ext) {
+			return fmt.Sprintf("extension %q not selected", ext)
+		}
 	}
 	
 	// Check pattern exclusions
-	for _, pattern := range ps.config.ExcludePatterns {
+	for _, pattern := range c.ExcludePatterns {
 		// Handle directory patterns like "node_modules/**"
 		if strings.Contains(pattern, "/**") {
 			dirPattern := strings.TrimSuffix(pattern, "/**")
 			if strings.Contains(path, dirPattern) {
-				return true
+				return fmt.Sprintf("matches exclude pattern %q", pattern)
 			}
 		}
 		
 		// Handle simple file patterns
 		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
-			return true
+			return fmt.Sprintf("matches exclude pattern %q", pattern)
 		}
 		
 		// Handle full path patterns
 		if matched, _ := filepath.Match(pattern, path); matched {
-			return true
+			return fmt.Sprintf("matches exclude pattern %q", pattern)
 		}
 	}
 	
-	return false
+	return ""
 }
 
-// isTextFile determines if a file is likely a text file
-func (ps *ProjectScanner) isTextFile(ext string) bool {
-	textExtensions := []string{
-		".txt", ".md", ".go", ".js", ".ts", ".py", ".java", ".c", ".cpp",
-		".h", ".hpp", ".cs", ".rb", ".php", ".html", ".css", ".scss",
-		".json", ".xml", ".yaml", ".yml", ".toml", ".ini", ".cfg",
-		".sh", ".bat", ".ps1", ".sql", ".r", ".scala", ".kt", ".rs",
-		".jsx", ".tsx", ".vue", ".svelte", ".dart", ".swift", ".m",
+// containsExtension reports whether ext is in a list of extensions, ignoring case
+func containsExtension(extensions []string, ext string) bool {
+	for _, candidate := range extensions {
+		if strings.ToLower(candidate) == ext {
+			return true
+		}
 	}
+	return false
+}
+
+// CountExtensions is a fast pass that counts files per extension without reading
+// them. It honors the exclusion rules but not IncludeExtensions, so the counts
+// can drive a choice of extensions to include.
+func CountExtensions(config ScanConfig) (map[string]int, error) {
+	config.IncludeExtensions = nil
+	counts := make(map[string]int)
 	
-	for _, textExt := range textExtensions {
-		if ext == textExt {
-			return true
+	var walk func(dirPath string, depth int) error
+	walk = func(dirPath string, depth int) error {
+		if depth > config.MaxDepth {
+			return nil
 		}
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			if depth == 0 {
+				return fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+			}
+			return nil // Continue past unreadable subdirectories
+		}
+		
+		for _, entry := range entries {
+			fullPath := filepath.Join(dirPath, entry.Name())
+			if !config.isForceIncluded(fullPath) && config.exclusionRule(fullPath, entry.IsDir()) != "" {
+				continue
+			}
+			if entry.IsDir() {
+				walk(fullPath, depth+1)
+				continue
+			}
+			counts[strings.ToLower(filepath.Ext(entry.Name()))]++
+		}
+		return nil
 	}
 	
-	return ext == "" // Files without extension might be text
+	return counts, walk(config.RootPath, 0)
 }
 
-// countLines counts the number of lines in a file
-func (ps *ProjectScanner) countLines(path string) (int, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return 0, err
+// ExplainExclusion reports whether a scan would exclude path and which rule is responsible
+func (c ScanConfig) ExplainExclusion(path string) (excluded bool, reason string) {
+	path = filepath.Clean(path)
+	if c.isForceIncluded(path) {
+		return false, "force-included"
 	}
-	defer file.Close()
 	
-	scanner := bufio.NewScanner(file)
+	root := filepath.Clean(c.RootPath)
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return true, "outside the scan root"
+	}
+	if rel == "." {
+		return false, "scan root"
+	}
+	
+	// Excluded directories are never descended into
+	parts := strings.Split(rel, string(filepath.Separator))
+	if len(parts)-1 > c.MaxDepth {
+		return true, fmt.Sprintf("deeper than max depth %d", c.MaxDepth)
+	}
+	if rule := c.parentExclusion(root, parts); rule != "" {
+		return true, rule
+	}
+	
+	info, err := os.Lstat(path)
+	if err != nil {
+		return true, fmt.Sprintf("cannot read file info: %v", err)
+	}
+	if rule := c.exclusionRule(path, info.IsDir()); rule != "" {
+		return true, rule
+	}
+	if ignore := c.IgnoreFile; !info.IsDir() && ignore != nil && ignore.MaxFileSize > 0 && info.Size() > ignore.MaxFileSize {
+		return true, fmt.Sprintf("file too large (%d bytes, %s rule %q)", info.Size(), IgnoreFileName, ignore.SizeRule)
+	}
+	if !info.IsDir() && info.Size() > c.MaxFileSize {
+		return true, fmt.Sprintf("file too large (%d bytes, limit %d)", info.Size(), c.MaxFileSize)
+	}
+	if !info.IsDir() && c.SkipEmptyFiles && info.Size() == 0 {
+		return true, "empty file"
+	}
+	if info.IsDir() {
+		return false, "included"
+	}
+	encoding, head, err := sniffFile(path)
+	if err == nil && encoding == EncodingBinary {
+		return true, "binary content"
+	} else if err == nil && !isText(encoding) {
+		return true, fmt.Sprintf("unsupported encoding %s", encoding)
+	}
+	if c.SkipGenerated {
+		switch generatedReason(path, err == nil && hasGeneratedMarker(head)) {
+		case reasonLockfile:
+			return true, "dependency lockfile"
+		case reasonGenerated:
+			return true, "generated code"
+		}
+	}
+	return false, "included"
+}
+
+// pathExclusion describes the rule excluding a path that may no longer exist,
+// judged by its name and its parent directories alone, or ""
+func (c ScanConfig) pathExclusion(path string) string {
+	path = filepath.Clean(path)
+	if c.isForceIncluded(path) {
+		return ""
+	}
+	root := filepath.Clean(c.RootPath)
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "outside the scan root"
+	}
+	if rule := c.parentExclusion(root, strings.Split(rel, string(filepath.Separator))); rule != "" {
+		return rule
+	}
+	return c.exclusionRule(path, false)
+}
+
+// parentExclusion describes the rule excluding one of the directories above a
+// path, given as its parts relative to root, or "" if none is excluded
+func (c ScanConfig) parentExclusion(root string, parts []string) string {
+	current := root
+	for _, dir := range parts[:len(parts)-1] {
+		current = filepath.Join(current, dir)
+		if c.isForceIncluded(current) {
+			continue
+		}
+		if rule := c.exclusionRule(current, true); rule != "" {
+			return fmt.Sprintf("parent directory %s %s", dir, rule)
+		}
+	}
+	return ""
+}
+
+// countReaderLines counts lines, stopping early on very large files
+func countReaderLines(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
 	lines := 0
 	for scanner.Scan() {
 		lines++
@@ -381,7 +988,10 @@ func (ps *ProjectScanner) processResults(result *ScanResult) {
 	copy(sortedFiles, result.Files)
 	
 	sort.Slice(sortedFiles, func(i, j int) bool {
-		return sortedFiles[i].Size > sortedFiles[j].Size
+		if sortedFiles[i].Size != sortedFiles[j].Size {
+			return sortedFiles[i].Size > sortedFiles[j].Size
+		}
+		return sortedFiles[i].Path < sortedFiles[j].Path
 	})
 	
 	// Keep top 10 largest files
@@ -392,6 +1002,13 @@ func (ps *ProjectScanner) processResults(result *ScanResult) {
 	result.LargestFiles = sortedFiles[:maxLargest]
 }
 
+// closeProgress closes the progress channel so readers terminate
+func (ps *ProjectScanner) closeProgress() {
+	ps.closeOnce.Do(func() {
+		close(ps.progress)
+	})
+}
+
 // sendProgress sends a progress update
 func (ps *ProjectScanner) sendProgress(progress ScanProgress) {
 	select {
@@ -0,0 +1,538 @@
+package context
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// ContentDetail chooses how much of each code file a content section shows
+type ContentDetail int
+
+const (
+	DetailFull     ContentDetail = iota // whole files
+	DetailOutline                       // declaration signatures only
+	DetailSkeleton                      // whole files with function bodies elided
+)
+
+// String returns the config and flag name of a detail level
+func (d ContentDetail) String() string {
+	switch d {
+	case DetailOutline:
+		return "outline"
+	case DetailSkeleton:
+		return "skeleton"
+	}
+	return "full"
+}
+
+// ParseContentDetail parses a config or flag value ("full", "outline" or "skeleton")
+func ParseContentDetail(value string) (ContentDetail, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "full":
+		return DetailFull, nil
+	case "outline":
+		return DetailOutline, nil
+	case "skeleton":
+		return DetailSkeleton, nil
+	}
+	return DetailFull, fmt.Errorf("unknown content detail %q (use full, outline or skeleton)", value)
+}
+
+// NextContentDetail cycles full → outline → skeleton → full
+func NextContentDetail(detail ContentDetail) ContentDetail {
+	return (detail + 1) % 3
+}
+
+// note describes a reduced file in the context
+func (d ContentDetail) note() string {
+	if d == DetailOutline {
+		return "Signatures only"
+	}
+	return "Function bodies omitted"
+}
+
+// symbolExtractor reduces a file's source to an outline or skeleton, or
+// returns false when it cannot, leaving the file whole
+type symbolExtractor func(source string, detail ContentDetail) (string, bool)
+
+// symbolExtractors are keyed by fence language. Go is parsed and outlined
+// through go/doc like the API Outline; the others are read lexically (strings, comments, brackets and indentation),
+// so unusual syntax such as regex literals containing braces may leave a
+// file whole or elide too little.
+var symbolExtractors = map[string]symbolExtractor{
+	"go":         goSymbols,
+	"python":     pythonSymbols,
+	"javascript": braceSymbols,
+	"jsx":        braceSymbols,
+	"typescript": braceSymbols,
+	"tsx":        braceSymbols,
+	"java":       braceSymbols,
+}
+
+// ApplyContentDetail returns a copy of result whose code files are reduced to
+// outlines or skeletons. Files in languages without an extractor, and files
+// an extractor cannot read, are kept whole. The input is left unchanged.
+func ApplyContentDetail(result *ContextResult, detail ContentDetail) *ContextResult {
+	if detail == DetailFull {
+		transformed := *result
+		return &transformed
+	}
+	return rewriteDocuments(result, func(document FileDocument) FileDocument {
+		extract, ok := symbolExtractors[document.Language]
+		if !ok {
+			return document
+		}
+		reduced, ok := extract(document.Content, detail)
+		if !ok {
+			return document
+		}
+		document.Content = strings.TrimRight(reduced, "\n")
+		document.Note = appendNote(document.Note, detail.note())
+		return document
+	})
+}
+
+// goSymbols outlines a Go file with goOutline, or elides every function body
+// for a skeleton
+func goSymbols(source string, detail ContentDetail) (string, bool) {
+	if detail != DetailSkeleton {
+		return goOutline(source, true)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return "", false
+	}
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	var skeleton strings.Builder
+	last := 0
+	for _, decl := range file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && function.Body != nil {
+			skeleton.WriteString(source[last:offset(function.Body.Lbrace)])
+			skeleton.WriteString("{ … }")
+			last = offset(function.Body.Rbrace) + 1
+		}
+	}
+	skeleton.WriteString(source[last:])
+	return skeleton.String(), true
+}
+
+// goOutline prints a Go file's package clause and declarations the way the
+// API Outline prints a package: bodiless, each under its doc comment's first
+// paragraph. all keeps unexported declarations too.
+func goOutline(source string, all bool) (string, bool) {
+	// go/doc only takes files named like Go sources
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "source.go", source, parser.ParseComments)
+	if err != nil {
+		return "", false
+	}
+	var mode doc.Mode
+	if all {
+		mode = doc.AllDecls
+	}
+	pkg, err := doc.NewFromFiles(fset, []*ast.File{file}, file.Name.Name, mode)
+	if err != nil {
+		return "", false
+	}
+	outline := packageOutline(fset, pkg)
+	if outline == "" {
+		return "", false
+	}
+	return "package " + file.Name.Name + "\n\n" + outline, true
+}
+
+// pythonSymbols outlines a Python file as its decorators, class and def
+// lines, or replaces each function body with "..." (after any docstring)
+func pythonSymbols(source string, detail ContentDetail) (string, bool) {
+	lines := strings.Split(source, "\n")
+	var out []string
+	found := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		isDef := strings.HasPrefix(trimmed, "def ") || strings.HasPrefix(trimmed, "async def ")
+		isClass := strings.HasPrefix(trimmed, "class ")
+
+		if detail == DetailOutline {
+			switch {
+			case strings.HasPrefix(trimmed, "@"):
+				out = append(out, line)
+			case isDef || isClass:
+				header, end := pythonHeader(lines, i)
+				out = append(out, header...)
+				i = end
+				found = true
+			}
+			continue
+		}
+
+		if !isDef {
+			out = append(out, line)
+			continue
+		}
+		header, end := pythonHeader(lines, i)
+		out = append(out, header...)
+		found = true
+
+		// The body is every following line indented deeper, blank, or inside a
+		// triple-quoted string; trailing blank lines stay with what follows
+		bodyEnd := end + 1
+		inString := ""
+		for bodyEnd < len(lines) {
+			next := lines[bodyEnd]
+			if inString == "" && strings.TrimSpace(next) != "" && indentWidth(next) <= indentWidth(line) {
+				break
+			}
+			inString = tripleQuoteState(next, inString)
+			bodyEnd++
+		}
+		for bodyEnd > end+1 && strings.TrimSpace(lines[bodyEnd-1]) == "" {
+			bodyEnd--
+		}
+
+		body := lines[end+1 : bodyEnd]
+		indent := strings.Repeat(" ", indentWidth(line)+4)
+		for _, bodyLine := range body {
+			if strings.TrimSpace(bodyLine) != "" {
+				indent = bodyLine[:len(bodyLine)-len(strings.TrimLeft(bodyLine, " \t"))]
+				out = append(out, pythonDocstring(body)...)
+				break
+			}
+		}
+		out = append(out, indent+"...")
+		i = bodyEnd - 1
+	}
+
+	if !found {
+		return "", false
+	}
+	return strings.Join(out, "\n"), true
+}
+
+// pythonHeader returns the lines of the def or class statement starting at
+// lines[start], cut after its colon, and the index of its last line
+func pythonHeader(lines []string, start int) ([]string, int) {
+	depth := 0
+	quote := byte(0)
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '#':
+				j = len(line)
+			case c == '(' || c == '[' || c == '{':
+				depth++
+			case c == ')' || c == ']' || c == '}':
+				depth--
+			case c == ':' && depth == 0:
+				header := append([]string(nil), lines[start:i]...)
+				return append(header, line[:j+1]), i
+			}
+		}
+		quote = 0
+	}
+	return []string{lines[start]}, start
+}
+
+// pythonDocstring returns the docstring opening a function body, if any
+func pythonDocstring(body []string) []string {
+	for i, line := range body {
+		trimmed := strings.TrimLeft(strings.TrimSpace(line), "rRuUbB")
+		if trimmed == "" {
+			continue
+		}
+		for _, delimiter := range []string{`"""`, `'''`} {
+			if !strings.HasPrefix(trimmed, delimiter) {
+				continue
+			}
+			if strings.Contains(trimmed[len(delimiter):], delimiter) {
+				return body[i : i+1]
+			}
+			for j := i + 1; j < len(body); j++ {
+				if strings.Contains(body[j], delimiter) {
+					return body[i : j+1]
+				}
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// tripleQuoteState tracks whether a line leaves a triple-quoted string open
+func tripleQuoteState(line, open string) string {
+	for {
+		if open != "" {
+			index := strings.Index(line, open)
+			if index < 0 {
+				return open
+			}
+			line = line[index+3:]
+			open = ""
+			continue
+		}
+		double, single := strings.Index(line, `"""`), strings.Index(line, `'''`)
+		switch {
+		case double < 0 && single < 0:
+			return ""
+		case single < 0 || (double >= 0 && double < single):
+			open, line = `"""`, line[double+3:]
+		default:
+			open, line = `'''`, line[single+3:]
+		}
+	}
+}
+
+// indentWidth counts leading indentation, a tab as four columns
+func indentWidth(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+var (
+	// typeHeader matches declarations whose braces hold members: classes,
+	// interfaces, enums, records and TypeScript namespaces
+	typeHeader = regexp.MustCompile(`(^|\s)(class|interface|enum|record|namespace|module|@interface)(\s|<|$)`)
+	// controlHeader matches statements whose braces hold ordinary code
+	controlHeader = regexp.MustCompile(`^(if|else|for|while|do|switch|case|default|try|catch|finally|with|synchronized|return|new)\b`)
+)
+
+// braceSymbols reads JavaScript, TypeScript and Java. The outline lists type
+// headers with the signatures inside them and top-level functions; the
+// skeleton collapses every function body to "{ … }".
+func braceSymbols(source string, detail ContentDetail) (string, bool) {
+	var skeleton strings.Builder
+	var outline []string
+	var statement strings.Builder // code since the last ; { or }, without comments
+	var blocks []bool             // open braces, true for type bodies
+	emitted := 0
+	found := false
+
+	indent := func() string {
+		depth := 0
+		for _, isType := range blocks {
+			if isType {
+				depth++
+			}
+		}
+		return strings.Repeat("  ", depth)
+	}
+	inType := func() bool {
+		return len(blocks) > 0 && blocks[len(blocks)-1]
+	}
+
+	for i := 0; i < len(source); {
+		if end, ok := skipComment(source, i); ok {
+			if strings.HasPrefix(source[i:], "//") {
+				statement.WriteByte('\n')
+			}
+			i = end
+			continue
+		}
+
+		c := source[i]
+		switch c {
+		case '"', '\'', '`':
+			end := skipString(source, i)
+			statement.WriteString(source[i:end])
+			i = end
+			continue
+		case ';':
+			// Bodiless methods declared inside interfaces and abstract classes
+			if header := braceHeader(statement.String()); inType() && isSignature(header) {
+				outline = append(outline, indent()+header+";")
+				found = true
+			}
+			statement.Reset()
+		case '{':
+			header := braceHeader(statement.String())
+			statement.Reset()
+			if isFunctionHeader(header) {
+				end := matchingBrace(source, i)
+				if end < 0 {
+					return "", false
+				}
+				skeleton.WriteString(source[emitted:i])
+				skeleton.WriteString("{ … }")
+				emitted = end + 1
+				if balanced(header) {
+					outline = append(outline, indent()+header)
+				}
+				found = true
+				i = end + 1
+				continue
+			}
+			isType := typeHeader.MatchString(header)
+			if isType {
+				outline = append(outline, indent()+header+" {")
+			}
+			blocks = append(blocks, isType)
+		case '}':
+			statement.Reset()
+			if len(blocks) > 0 {
+				isType := blocks[len(blocks)-1]
+				blocks = blocks[:len(blocks)-1]
+				if isType {
+					outline = append(outline, indent()+"}")
+				}
+			}
+		default:
+			statement.WriteByte(c)
+		}
+		i++
+	}
+	skeleton.WriteString(source[emitted:])
+
+	if !found {
+		return "", false
+	}
+	if detail == DetailOutline {
+		return strings.Join(outline, "\n"), true
+	}
+	return skeleton.String(), true
+}
+
+// braceHeader returns the declaration part of the code before a brace: from
+// the line opening its parameter list, or the last line when there is none,
+// with whitespace collapsed
+func braceHeader(statement string) string {
+	start := strings.LastIndex(strings.TrimRight(statement, " \t\r\n"), "\n") + 1
+	if closing := strings.LastIndex(statement, ")"); closing >= 0 {
+		depth := 0
+		for i := closing; i >= 0; i-- {
+			switch statement[i] {
+			case ')':
+				depth++
+			case '(':
+				depth--
+			}
+			if depth == 0 {
+				start = strings.LastIndex(statement[:i], "\n") + 1
+				break
+			}
+		}
+	}
+	return strings.Join(strings.Fields(statement[start:]), " ")
+}
+
+// isFunctionHeader reports whether the code before a brace declares a function,
+// method, constructor or lambda whose body the brace opens
+func isFunctionHeader(header string) bool {
+	if header == "" || controlHeader.MatchString(header) || typeHeader.MatchString(header) {
+		return false
+	}
+	if strings.HasSuffix(header, "=>") || strings.HasSuffix(header, "->") {
+		return true
+	}
+	closing := strings.LastIndex(header, ")")
+	if closing < 0 {
+		return false
+	}
+	tail := strings.TrimSpace(header[closing+1:])
+	return tail == "" || strings.HasPrefix(tail, ":") || strings.HasPrefix(tail, "throws ")
+}
+
+// isSignature reports whether a statement ending in ";" declares a method without a body
+func isSignature(header string) bool {
+	closing := strings.LastIndex(header, ")")
+	if closing < 0 || strings.Contains(header, "=") || controlHeader.MatchString(header) {
+		return false
+	}
+	tail := strings.TrimSpace(header[closing+1:])
+	return balanced(header) && (tail == "" || strings.HasPrefix(tail, ":") || strings.HasPrefix(tail, "throws "))
+}
+
+// balanced reports whether every parenthesis in header is closed, which sets
+// declarations apart from callbacks passed as arguments
+func balanced(header string) bool {
+	return strings.Count(header, "(") == strings.Count(header, ")")
+}
+
+// skipComment returns the index after a comment starting at i
+func skipComment(source string, i int) (int, bool) {
+	switch {
+	case strings.HasPrefix(source[i:], "//"):
+		if end := strings.IndexByte(source[i:], '\n'); end >= 0 {
+			return i + end, true
+		}
+		return len(source), true
+	case strings.HasPrefix(source[i:], "/*"):
+		if end := strings.Index(source[i+2:], "*/"); end >= 0 {
+			return i + 2 + end + 2, true
+		}
+		return len(source), true
+	}
+	return i, false
+}
+
+// skipString returns the index after a string literal starting at i. Quoted
+// strings end at a newline; template literals may span lines.
+func skipString(source string, i int) int {
+	quote := source[i]
+	for j := i + 1; j < len(source); j++ {
+		switch source[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(source)
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or -1
+func matchingBrace(source string, open int) int {
+	depth := 0
+	for i := open; i < len(source); {
+		if end, ok := skipComment(source, i); ok {
+			i = end
+			continue
+		}
+		switch source[i] {
+		case '"', '\'', '`':
+			i = skipString(source, i)
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+		i++
+	}
+	return -1
+}
@@ -0,0 +1,355 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Template kinds with built-in transformations; any other kind leaves the
+// sections as generated
+const (
+	TemplateDevelopment   = "development"
+	TemplateDocumentation = "documentation"
+	TemplateReview        = "review"
+	TemplateDebug         = "debug"
+	TemplateFull          = "full"
+	TemplateSummary       = "summary"
+)
+
+// documentBlock is one file's heading and fenced block within a content section
+type documentBlock struct {
+	document FileDocument
+	hashes   string // markdown level of the file heading
+	text     string // heading and block as generated, plus any note that follows
+}
+
+// splitDocuments cuts a content section into the text before its first file,
+// one block per document and the text after the last. Documents whose block
+// cannot be found stay in the surrounding text.
+func splitDocuments(section ContextSection) (head string, blocks []documentBlock, tail string) {
+	pos := 0
+	for _, document := range section.Documents {
+		start, end, ok := documentSpan(section.Content, pos, document)
+		if !ok {
+			continue
+		}
+		gap := section.Content[pos:start]
+		if len(blocks) == 0 {
+			head = gap
+		} else {
+			// Text between blocks, such as a file that could not be read, stays with the block before it
+			blocks[len(blocks)-1].text += gap
+		}
+		hashes := section.Content[start:strings.Index(section.Content[start:], " ")+start]
+		blocks = append(blocks, documentBlock{document: document, hashes: hashes, text: section.Content[start:end]})
+		pos = end
+	}
+	if len(blocks) == 0 {
+		return section.Content, nil, ""
+	}
+	return head, blocks, section.Content[pos:]
+}
+
+// blockSection assembles a content section from a title heading and file blocks
+func blockSection(title, hashes string, blocks []documentBlock) ContextSection {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s\n\n", hashes, title))
+	section := ContextSection{Title: title, IsContent: true}
+	for _, block := range blocks {
+		content.WriteString(block.text)
+		section.Files = append(section.Files, block.document.Path)
+		section.Documents = append(section.Documents, block.document)
+	}
+	section.Content = content.String()
+	return section
+}
+
+// sectionHashes returns the markdown level of a section's own heading
+func sectionHashes(content string) string {
+	hashes := content[:len(content)-len(strings.TrimLeft(content, "#"))]
+	if hashes == "" {
+		return "#"
+	}
+	return hashes
+}
+
+// isDocumentationFile reports whether a path holds prose rather than code
+func isDocumentationFile(path string) bool {
+	if isReadmeFile(path) {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".rst", ".adoc", ".txt":
+		return true
+	}
+	return false
+}
+
+// isLogFile reports whether a path holds log output
+func isLogFile(path string) bool {
+	slashed := filepath.ToSlash(strings.ToLower(path))
+	return strings.HasSuffix(slashed, ".log") || strings.Contains("/"+slashed, "/logs/")
+}
+
+// publicAPI reduces a file to its API for the documentation template, using
+// the same extractors as the outline detail: exported declarations with their
+// doc comments for Go, the outline for the other languages they read. It
+// returns false for languages without an extractor.
+func publicAPI(language, content string) (string, bool) {
+	if language == "go" {
+		return goOutline(content, false)
+	}
+	extract, ok := symbolExtractors[language]
+	if !ok {
+		return "", false
+	}
+	return extract(content, DetailOutline)
+}
+
+// ApplyTemplate returns a copy of result with its sections rewritten for a
+// template kind. The input is left unchanged.
+func ApplyTemplate(result *ContextResult, kind string) *ContextResult {
+	transformed := *result
+	sections := make([]ContextSection, 0, len(result.Sections))
+
+	switch kind {
+	case TemplateSummary:
+		// Overview and structure only
+		for _, section := range result.Sections {
+			if !section.IsContent {
+				sections = append(sections, section)
+			}
+		}
+	case TemplateDevelopment:
+		// Code and tests with the READMEs; other prose is left for the documentation template
+		for _, section := range result.Sections {
+			sections = appendNonEmpty(sections, filterDocuments(section, func(block documentBlock) bool {
+				return isReadmeFile(block.document.Path) || !isDocumentationFile(block.document.Path)
+			}))
+		}
+	case TemplateDocumentation:
+		sections = documentationSections(result.Sections)
+	case TemplateReview:
+		sections = reviewSections(result.Sections)
+	case TemplateDebug:
+		sections = debugSections(result.Sections)
+	default:
+		sections = append(sections, result.Sections...)
+	}
+
+	transformed.Sections = sections
+	chars := len(transformed.Summary)
+	for _, section := range sections {
+		chars += len(section.Content)
+	}
+	transformed.TokenEstimate = chars / 4
+	return &transformed
+}
+
+// rewriteDocuments returns a copy of result with each file of its content
+// sections passed through rewrite. Blocks of documents rewrite leaves as they
+// were keep their text; the API Outline, whose blocks are per package, is kept.
+func rewriteDocuments(result *ContextResult, rewrite func(FileDocument) FileDocument) *ContextResult {
+	transformed := *result
+	transformed.Sections = make([]ContextSection, 0, len(result.Sections))
+	for _, section := range result.Sections {
+		transformed.Sections = append(transformed.Sections, rewriteSection(section, rewrite))
+	}
+	chars := len(transformed.Summary)
+	for _, section := range transformed.Sections {
+		chars += len(section.Content)
+	}
+	transformed.TokenEstimate = chars / 4
+	return &transformed
+}
+
+// rewriteSection rebuilds the file blocks of one content section
+func rewriteSection(section ContextSection, rewrite func(FileDocument) FileDocument) ContextSection {
+	if !section.IsContent || len(section.Documents) == 0 || section.Title == outlineTitle {
+		return section
+	}
+
+	head, blocks, tail := splitDocuments(section)
+	if len(blocks) == 0 {
+		return section
+	}
+	var content strings.Builder
+	content.WriteString(head)
+	documents := make([]FileDocument, 0, len(section.Documents))
+	for _, block := range blocks {
+		document := rewrite(block.document)
+		if document != block.document {
+			block.text = fmt.Sprintf("%s %s\n\n", block.hashes, document.Path)
+			if document.Note != "" {
+				block.text += fmt.Sprintf("*%s*\n\n", document.Note)
+			}
+			block.text += fmt.Sprintf("```%s\n%s\n```\n\n", document.Language, document.Content)
+		}
+		content.WriteString(block.text)
+		documents = append(documents, document)
+	}
+	content.WriteString(tail)
+
+	section.Content = content.String()
+	section.Documents = documents
+	return section
+}
+
+// appendNote adds to a file's note, lower-casing the addition after a "; "
+func appendNote(note, addition string) string {
+	if note == "" {
+		return addition
+	}
+	return note + "; " + strings.ToLower(addition)
+}
+
+// filterDocuments keeps the file blocks of a content section that pass keep
+func filterDocuments(section ContextSection, keep func(documentBlock) bool) ContextSection {
+	if !section.IsContent || len(section.Documents) == 0 {
+		return section
+	}
+	_, blocks, _ := splitDocuments(section)
+	excluded := make(map[string]bool)
+	for _, block := range blocks {
+		if !keep(block) {
+			excluded[block.document.Path] = true
+		}
+	}
+	return section.WithoutFiles(excluded)
+}
+
+// appendNonEmpty adds a section unless it is a content section left without files
+func appendNonEmpty(sections []ContextSection, section ContextSection) []ContextSection {
+	if section.IsContent && len(section.Documents) == 0 && len(section.Files) == 0 {
+		return sections
+	}
+	return append(sections, section)
+}
+
+// documentationSections puts READMEs and other prose first, followed by the
+// public declarations of the code; file type statistics and private code are dropped
+func documentationSections(original []ContextSection) []ContextSection {
+	var sections []ContextSection
+	var docs, api []documentBlock
+	hashes := "#"
+	contentAt := -1
+
+	for _, section := range original {
+		if section.Title == "File Type Analysis" {
+			continue
+		}
+		if !section.IsContent || len(section.Documents) == 0 {
+			sections = append(sections, section)
+			continue
+		}
+		if contentAt < 0 {
+			contentAt = len(sections)
+			hashes = sectionHashes(section.Content)
+		}
+
+		_, blocks, _ := splitDocuments(section)
+		for _, block := range blocks {
+			document := block.document
+			if isDocumentationFile(document.Path) {
+				docs = append(docs, block)
+				continue
+			}
+			declarations, ok := publicAPI(document.Language, document.Content)
+			if !ok {
+				continue
+			}
+			document.Content = strings.TrimRight(declarations, "\n")
+			document.Note = "Public declarations only"
+			block.document = document
+			block.text = fmt.Sprintf("%s %s\n\n*%s*\n\n```%s\n%s\n```\n\n",
+				block.hashes, document.Path, document.Note, document.Language, document.Content)
+			api = append(api, block)
+		}
+	}
+
+	var content []ContextSection
+	if len(docs) > 0 {
+		content = append(content, blockSection("Documentation", hashes, docs))
+	}
+	if len(api) > 0 {
+		content = append(content, blockSection("Public API", hashes, api))
+	}
+	if contentAt < 0 {
+		return append(sections, content...)
+	}
+	return append(sections[:contentAt], append(content, sections[contentAt:]...)...)
+}
+
+// reviewSections leads with the diff when there is one, then the code under
+// review; prose files and file type statistics are dropped
+func reviewSections(original []ContextSection) []ContextSection {
+	var diffs, overview, rest []ContextSection
+	for _, section := range original {
+		switch {
+		case section.Title == "File Type Analysis":
+			continue
+		case section.IsContent && isDiffSection(section):
+			diffs = append(diffs, section)
+		case !section.IsContent && len(rest) == 0 && len(overview) == 0:
+			overview = append(overview, section)
+		default:
+			rest = appendNonEmpty(rest, filterDocuments(section, func(block documentBlock) bool {
+				return !isDocumentationFile(block.document.Path)
+			}))
+		}
+	}
+
+	sections := append(overview, diffs...)
+	return append(sections, rest...)
+}
+
+// isDiffSection reports whether every file in a section is a diff
+func isDiffSection(section ContextSection) bool {
+	if len(section.Documents) == 0 {
+		return false
+	}
+	for _, document := range section.Documents {
+		if document.Language != "diff" {
+			return false
+		}
+	}
+	return true
+}
+
+// debugSections gathers tests and logs into one section ahead of the rest of
+// the code, so failing behaviour is read before the implementation
+func debugSections(original []ContextSection) []ContextSection {
+	var sections []ContextSection
+	var evidence []documentBlock
+	hashes := "#"
+	contentAt := -1
+
+	for _, section := range original {
+		if !section.IsContent || len(section.Documents) == 0 {
+			sections = append(sections, section)
+			continue
+		}
+		if contentAt < 0 {
+			contentAt = len(sections)
+			hashes = sectionHashes(section.Content)
+		}
+
+		_, blocks, _ := splitDocuments(section)
+		for _, block := range blocks {
+			if IsTestFile(block.document.Path) || isLogFile(block.document.Path) {
+				evidence = append(evidence, block)
+			}
+		}
+		sections = appendNonEmpty(sections, filterDocuments(section, func(block documentBlock) bool {
+			path := block.document.Path
+			return !IsTestFile(path) && !isLogFile(path) && !isDocumentationFile(path)
+		}))
+	}
+
+	if len(evidence) == 0 {
+		return sections
+	}
+	tests := blockSection("Tests and Logs", hashes, evidence)
+	return append(sections[:contentAt], append([]ContextSection{tests}, sections[contentAt:]...)...)
+}
@@ -0,0 +1,207 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Truncation records an oversized file cut short at a structural boundary
+type Truncation struct {
+	Path  string
+	Shown int // lines kept
+	Total int // lines in the file
+}
+
+// braceLanguages are fence languages whose top-level declarations end in a
+// closing brace, parenthesis or semicolon
+var braceLanguages = map[string]bool{
+	"go": true, "javascript": true, "jsx": true, "typescript": true, "tsx": true,
+	"java": true, "kotlin": true, "scala": true, "c": true, "cpp": true, "csharp": true,
+	"rust": true, "php": true, "swift": true, "dart": true, "objectivec": true,
+	"css": true, "scss": true,
+}
+
+// truncateAtBoundary keeps as much of an oversized file as fits in the size
+// limit, cut after the last complete top-level declaration (or before the
+// last markdown heading, or at the last blank line), and counts the lines
+// left out without holding them in memory
+func (cg *ContextGenerator) truncateAtBoundary(r io.Reader, language string) (string, Truncation, error) {
+	prefix, err := cg.readLimited(r, cg.maxFileSize)
+	if err != nil {
+		return "", Truncation{}, err
+	}
+	rest, err := countLines(r)
+	if err != nil {
+		return "", Truncation{}, err
+	}
+	total := strings.Count(prefix, "\n") + rest.newlines
+	endsWithNewline := strings.HasSuffix(prefix, "\n")
+	if rest.read > 0 {
+		endsWithNewline = rest.endsWithNewline
+	}
+	if prefix != "" && !endsWithNewline {
+		total++
+	}
+
+	kept := strings.TrimRight(prefix[:structuralCut(prefix, language)], "\n")
+	shown := 0
+	if kept != "" {
+		shown = strings.Count(kept, "\n") + 1
+	}
+	truncation := Truncation{Shown: shown, Total: total}
+	if omitted := total - shown; omitted > 0 {
+		kept += fmt.Sprintf("\n\n…truncated %d lines…", omitted)
+	}
+	return kept, truncation, nil
+}
+
+// lineCount is what countLines saw of the rest of a file
+type lineCount struct {
+	read            int64
+	newlines        int
+	endsWithNewline bool
+}
+
+// countLines drains r, counting its newlines
+func countLines(r io.Reader) (lineCount, error) {
+	var count lineCount
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buffer)
+		if n > 0 {
+			count.read += int64(n)
+			count.newlines += bytes.Count(buffer[:n], []byte{'\n'})
+			count.endsWithNewline = buffer[n-1] == '\n'
+		}
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+// structuralCut returns where to cut text so it ends on a whole unit of its
+// language, falling back to the last paragraph break, then the last line
+func structuralCut(text, language string) int {
+	var cut int
+	switch {
+	case braceLanguages[language]:
+		cut = braceBoundary(text)
+	case language == "python":
+		cut = pythonBoundary(text)
+	case language == "markdown":
+		cut = headingBoundary(text)
+	}
+	if cut > 0 {
+		return cut
+	}
+	if paragraph := strings.LastIndex(text, "\n\n"); paragraph > 0 {
+		return paragraph + 1
+	}
+	if line := strings.LastIndexByte(text, '\n'); line > 0 {
+		return line + 1
+	}
+	return len(text)
+}
+
+// braceBoundary returns the end of the last line that closes a top-level
+// block or statement, or 0 when there is none
+func braceBoundary(text string) int {
+	cut := 0
+	braces, parens := 0, 0
+	last := byte(0) // last code character on the current line
+	for i := 0; i < len(text); {
+		if end, ok := skipComment(text, i); ok {
+			i = end
+			continue
+		}
+		c := text[i]
+		switch c {
+		case '"', '\'', '`':
+			i = skipString(text, i)
+			last = c
+			continue
+		case '{':
+			braces++
+		case '}':
+			braces--
+		case '(':
+			parens++
+		case ')':
+			parens--
+		case '\n':
+			if braces <= 0 && parens <= 0 && (last == '}' || last == ')' || last == ';') {
+				cut = i + 1
+			}
+			last = 0
+			i++
+			continue
+		}
+		if c != ' ' && c != '\t' && c != '\r' {
+			last = c
+		}
+		i++
+	}
+	return cut
+}
+
+// pythonBoundary returns the start of the last top-level statement (with its
+// decorators) outside a triple-quoted string, or 0 when there is none
+func pythonBoundary(text string) int {
+	cut := 0
+	inString := ""
+	offset := 0
+	start := -1 // start of the run of decorators before the current line
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inString == "" && trimmed != "" && indentWidth(line) == 0 && !strings.HasPrefix(trimmed, "#") &&
+			!continuesStatement(trimmed) {
+			if start < 0 {
+				start = offset
+			}
+			if !strings.HasPrefix(trimmed, "@") {
+				if start > 0 {
+					cut = start
+				}
+				start = -1
+			}
+		}
+		inString = tripleQuoteState(line, inString)
+		offset += len(line)
+	}
+	return cut
+}
+
+// continuesStatement reports whether an unindented Python line belongs to the
+// statement before it
+func continuesStatement(trimmed string) bool {
+	for _, prefix := range []string{")", "]", "}", "else", "elif ", "except", "finally", "case "} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// headingBoundary returns the start of the last markdown heading outside a
+// code fence, or 0 when there is none
+func headingBoundary(text string) int {
+	cut := 0
+	fenced := false
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fenced = !fenced
+		case !fenced && strings.HasPrefix(line, "#") && offset > 0:
+			cut = offset
+		}
+		offset += len(line)
+	}
+	return cut
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEventLogRingBuffer(t *testing.T) {
+	log := NewEventLog(3)
+	
+	for i := 1; i <= 5; i++ {
+		log.Record(EventNavigation, "event %d", i)
+	}
+	
+	events := log.Events()
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	
+	// Oldest events are dropped first
+	if events[0].Message != "event 3" || events[2].Message != "event 5" {
+		t.Errorf("Expected events 3-5, got %q..%q", events[0].Message, events[2].Message)
+	}
+}
+
+func TestEventLogAnonymize(t *testing.T) {
+	log := NewEventLog(10)
+	log.SetAnonymize(true)
+	
+	log.Record(EventScanStart, "Scanning /home/user/project")
+	
+	events := log.Events()
+	if strings.Contains(events[0].Message, "/home/user") {
+		t.Errorf("Expected path to be redacted, got %q", events[0].Message)
+	}
+	if !strings.Contains(events[0].Message, "<path>") {
+		t.Errorf("Expected redaction marker, got %q", events[0].Message)
+	}
+}
+
+func TestEventLogOutput(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewEventLog(10)
+	log.SetOutput(&buf)
+	
+	log.Record(EventError, "something failed")
+	
+	if !strings.Contains(buf.String(), "[error] something failed") {
+		t.Errorf("Expected event to be written to output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of state transition recorded
+type EventType string
+
+const (
+	EventScanStart     EventType = "scan_start"
+	EventScanComplete  EventType = "scan_complete"
+	EventScanPause     EventType = "scan_pause"
+	EventGeneration    EventType = "generation"
+	EventError         EventType = "error"
+	EventNavigation    EventType = "navigation"
+	EventExport        EventType = "export"
+	EventChat          EventType = "chat"
+	EventSettings      EventType = "settings"
+)
+
+// Event represents a single recorded state transition
+type Event struct {
+	Time    time.Time
+	Type    EventType
+	Message string
+}
+
+// EventLog records events in a fixed-size ring buffer
+type EventLog struct {
+	mu        sync.Mutex
+	events    []Event
+	next      int
+	count     int
+	anonymize bool
+	output    io.Writer
+	file      *os.File
+}
+
+// pathPattern matches absolute Unix and Windows paths
+var pathPattern = regexp.MustCompile(`(?:[A-Za-z]:)?[/\\][^\s'"]+`)
+
+// NewEventLog creates an event log holding up to capacity events
+func NewEventLog(capacity int) *EventLog {
+	if capacity <= 0 {
+		capacity = 100
+	}
+	
+	return &EventLog{
+		events: make([]Event, capacity),
+	}
+}
+
+// SetAnonymize toggles redaction of file system paths in messages
+func (l *EventLog) SetAnonymize(anonymize bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.anonymize = anonymize
+}
+
+// SetOutput mirrors recorded events to a writer
+func (l *EventLog) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.output = w
+}
+
+// OpenFile appends recorded events to a log file
+func (l *EventLog) OpenFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+	}
+	l.file = file
+	l.output = file
+	return nil
+}
+
+// Close closes the log file if one was opened
+func (l *EventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	l.output = nil
+	return err
+}
+
+// Record adds an event to the log
+func (l *EventLog) Record(eventType EventType, format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	message := fmt.Sprintf(format, args...)
+	if l.anonymize {
+		message = pathPattern.ReplaceAllString(message, "<path>")
+	}
+	
+	event := Event{
+		Time:    time.Now(),
+		Type:    eventType,
+		Message: message,
+	}
+	
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	if l.count < len(l.events) {
+		l.count++
+	}
+	
+	if l.output != nil {
+		fmt.Fprintln(l.output, event.String())
+	}
+}
+
+// Events returns recorded events from oldest to newest
+func (l *EventLog) Events() []Event {
+	if l == nil {
+		return nil
+	}
+	
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	
+	result := make([]Event, 0, l.count)
+	start := (l.next - l.count + len(l.events)) % len(l.events)
+	for i := 0; i < l.count; i++ {
+		result = append(result, l.events[(start+i)%len(l.events)])
+	}
+	return result
+}
+
+// String formats an event as a single log line
+func (e Event) String() string {
+	return fmt.Sprintf("%s [%s] %s", e.Time.Format("15:04:05.000"), e.Type, e.Message)
+}
@@ -194,6 +194,16 @@ func (tm ToastManager) Update(msg tea.Msg) (ToastManager, tea.Cmd) {
 	return tm, tea.Batch(cmds...)
 }
 
+// HasActive reports whether a toast of the given type is currently visible
+func (tm ToastManager) HasActive(toastType ToastType) bool {
+	for _, toast := range tm.toasts {
+		if toast.IsVisible() && toast.toastType == toastType {
+			return true
+		}
+	}
+	return false
+}
+
 // View renders all visible toasts
 func (tm ToastManager) View() string {
 	if len(tm.toasts) == 0 {
@@ -1,13 +1,22 @@
 package folder
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// mouseWheelStep is how many rows one wheel notch moves the cursor
+const mouseWheelStep = 3
+
+// maxQuickPicks is how many bookmarks and recent folders fit on the number keys
+const maxQuickPicks = 9
+
 // BrowserModel represents the folder browser UI
 type BrowserModel struct {
 	tree         *FolderTree
@@ -19,6 +28,18 @@ type BrowserModel struct {
 	showStats    bool
 	confirmMode  bool
 	errorMessage string
+	filter       browserFilter
+	index        *filterIndex       // every path below the root, once walked
+	cancelIndex  context.CancelFunc // stops the walk in progress, if any
+	bookmarks    []string
+	recent       []string
+	minimal      bool // leave the key hints out of the footer
+}
+
+// quickPick is a bookmarked or recently used folder offered above the tree
+type quickPick struct {
+	path       string
+	bookmarked bool
 }
 
 // ViewportInfo tracks what's currently visible
@@ -33,6 +54,13 @@ type BrowserMsg struct {
 	Data interface{}
 }
 
+// NodeStats carries directory stats calculated in the background
+type NodeStats struct {
+	Node  *FolderNode
+	Stats *FolderStats
+	Err   error
+}
+
 // NewBrowserModel creates a new folder browser
 func NewBrowserModel(rootPath string) (*BrowserModel, error) {
 	tree, err := NewFolderTree(rootPath)
@@ -52,9 +80,19 @@ func NewBrowserModel(rootPath string) (*BrowserModel, error) {
 	return browser, nil
 }
 
+// Init starts calculating stats for the initially highlighted directory
+func (m *BrowserModel) Init() tea.Cmd {
+	return m.statsCmd()
+}
+
 // refreshView updates the visible nodes list
 func (m *BrowserModel) refreshView() {
-	m.visibleNodes = m.tree.GetVisibleNodes()
+	if m.filter.active() {
+		m.tree.revealIndexed(m.index, m.filter)
+		m.visibleNodes = m.tree.filterNodes(m.filter)
+	} else {
+		m.visibleNodes = m.tree.GetVisibleNodes()
+	}
 	
 	// Ensure cursor is within bounds
 	if m.cursor >= len(m.visibleNodes) {
@@ -71,6 +109,12 @@ func (m *BrowserModel) refreshView() {
 // updateViewport adjusts the viewport to keep cursor visible
 func (m *BrowserModel) updateViewport() {
 	m.viewport.size = m.height - 4 // Reserve space for header and footer
+	if picks := len(m.quickPicks()); picks > 0 {
+		m.viewport.size -= picks + 2
+	}
+	if m.viewport.size < 1 {
+		m.viewport.size = 1
+	}
 	
 	// Adjust offset to keep cursor visible
 	if m.cursor < m.viewport.offset {
@@ -89,7 +133,12 @@ func (m *BrowserModel) updateViewport() {
 func (m *BrowserModel) Update(msg tea.Msg) (*BrowserModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		// Stats follow the cursor, so check the newly highlighted directory
+		browser, cmd := m.handleKeyPress(msg)
+		return browser, tea.Batch(cmd, browser.statsCmd())
+	case tea.MouseMsg:
+		browser, cmd := m.handleMouse(msg)
+		return browser, tea.Batch(cmd, browser.statsCmd())
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -106,8 +155,30 @@ func (m *BrowserModel) handleKeyPress(msg tea.KeyMsg) (*BrowserModel, tea.Cmd) {
 	if m.confirmMode {
 		return m.handleConfirmMode(msg)
 	}
+	if m.filter.mode != filterNone {
+		return m.handleFilterInput(msg)
+	}
 	
 	switch msg.String() {
+	case "/":
+		m.filter.mode = filterPath
+	case "f":
+		m.filter.mode = filterExt
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		index := int(msg.String()[0] - '1')
+		if picks := m.quickPicks(); index < len(picks) {
+			return m.jumpTo(picks[index].path)
+		}
+	case "*":
+		if node := m.getCurrentNode(); node != nil && node.IsDir {
+			return m, m.nodeMsg("toggle_bookmark", node)
+		}
+	case "esc":
+		if m.filter.active() {
+			m.filter = browserFilter{}
+			m.stopIndex()
+			m.applyFilter()
+		}
 	case "up", "k":
 		if m.cursor > 0 {
 			m.cursor--
@@ -122,16 +193,31 @@ func (m *BrowserModel) handleKeyPress(msg tea.KeyMsg) (*BrowserModel, tea.Cmd) {
 		return m.handleLeft()
 	case "right", "l", "enter":
 		return m.handleRight()
-	case "space":
+	case " ", "space":
 		return m.handleSelection()
+	case "a", "A":
+		if node := m.getCurrentNode(); node != nil {
+			if err := m.tree.ToggleCheckedChildren(node); err != nil {
+				m.errorMessage = fmt.Sprintf("Error expanding folder: %v", err)
+			}
+			m.refreshView()
+		}
 	case "s":
 		m.showStats = !m.showStats
 	case "r":
 		return m.handleRefresh()
 	case "c":
-		if m.getCurrentNode() != nil && m.getCurrentNode().IsDir {
+		if len(m.tree.CheckedPaths()) > 0 || (m.getCurrentNode() != nil && m.getCurrentNode().IsDir) {
 			m.confirmMode = true
 		}
+	case "n":
+		if node := m.getCurrentNode(); node != nil && node != m.tree.root {
+			return m, m.nodeMsg("never_include", node)
+		}
+	case "e":
+		if node := m.getCurrentNode(); node != nil {
+			return m, m.nodeMsg("explain_exclusion", node)
+		}
 	case "home":
 		m.cursor = 0
 		m.updateViewport()
@@ -155,12 +241,188 @@ func (m *BrowserModel) handleKeyPress(msg tea.KeyMsg) (*BrowserModel, tea.Cmd) {
 	return m, nil
 }
 
+// jumpTo re-roots the browser at path, clearing any filter
+func (m *BrowserModel) jumpTo(path string) (*BrowserModel, tea.Cmd) {
+	if err := m.tree.NavigateToPath(path); err != nil {
+		m.errorMessage = fmt.Sprintf("Cannot open %s: %v", path, err)
+		return m, nil
+	}
+	
+	m.errorMessage = ""
+	m.filter = browserFilter{}
+	m.stopIndex()
+	m.cursor = 0
+	m.viewport.offset = 0
+	m.refreshView()
+	return m, nil
+}
+
+// SetQuickPicks sets the bookmarked and recently used folders offered above the tree
+func (m *BrowserModel) SetQuickPicks(bookmarks, recent []string) {
+	m.bookmarks = bookmarks
+	m.recent = recent
+	m.updateViewport()
+}
+
+// SetMinimalChrome leaves the key hints out of the footer for screenshots and demos
+func (m *BrowserModel) SetMinimalChrome(minimal bool) {
+	m.minimal = minimal
+}
+
+// quickPicks lists bookmarks first, then recent folders that aren't bookmarked,
+// up to maxQuickPicks
+func (m *BrowserModel) quickPicks() []quickPick {
+	var picks []quickPick
+	seen := make(map[string]bool)
+	add := func(paths []string, bookmarked bool) {
+		for _, path := range paths {
+			if len(picks) == maxQuickPicks {
+				return
+			}
+			if !seen[path] {
+				seen[path] = true
+				picks = append(picks, quickPick{path: path, bookmarked: bookmarked})
+			}
+		}
+	}
+	add(m.bookmarks, true)
+	add(m.recent, false)
+	return picks
+}
+
+// handleFilterInput edits the filter being typed, narrowing the tree as it changes
+func (m *BrowserModel) handleFilterInput(msg tea.KeyMsg) (*BrowserModel, tea.Cmd) {
+	editing := m.filter.mode
+	input := m.filter.query
+	if editing == filterExt {
+		input = m.filter.extInput
+	}
+	
+	switch msg.String() {
+	case "enter":
+		m.filter.mode = filterNone
+		return m, nil
+	case "esc":
+		// Drop the filter being typed; the other one stays
+		input = ""
+		m.filter.mode = filterNone
+	case "ctrl+c":
+		return m, tea.Quit
+	case "backspace":
+		if len(input) > 0 {
+			runes := []rune(input)
+			input = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace {
+			return m, nil
+		}
+		input += string(msg.Runes)
+	}
+	
+	if editing == filterExt {
+		m.filter.extInput = input
+		m.filter.extensions = parseExtensions(input)
+	} else {
+		m.filter.query = input
+	}
+	m.applyFilter()
+	if !m.filter.active() {
+		m.stopIndex()
+		return m, nil
+	}
+	return m, m.startIndex()
+}
+
+// startIndex walks the tree in the background so the filter reaches folders
+// that were never expanded; it returns nil once the walk is done or running
+func (m *BrowserModel) startIndex() tea.Cmd {
+	if m.index != nil || m.cancelIndex != nil {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelIndex = cancel
+	return indexCmd(ctx, m.tree.GetPath(), m.tree.showHidden)
+}
+
+// stopIndex cancels any walk in progress and forgets the index
+func (m *BrowserModel) stopIndex() {
+	if m.cancelIndex != nil {
+		m.cancelIndex()
+		m.cancelIndex = nil
+	}
+	m.index = nil
+}
+
+// Close stops any background work, for when the browser is dismissed
+func (m *BrowserModel) Close() {
+	m.stopIndex()
+}
+
+// applyFilter rebuilds the visible nodes, keeping the cursor on the same node when it is still shown
+func (m *BrowserModel) applyFilter() {
+	current := m.getCurrentNode()
+	m.refreshView()
+	if index := m.findNodeIndex(current); index >= 0 {
+		m.cursor = index
+	} else {
+		m.cursor = 0
+	}
+	m.updateViewport()
+}
+
+// Filtering reports whether a filter is being typed, so callers leave keys like esc to the browser
+func (m *BrowserModel) Filtering() bool {
+	return m.filter.mode != filterNone
+}
+
+// handleMouse selects the clicked row, expanding or collapsing it when it is a
+// folder, and moves the cursor with the wheel. Y is relative to the top of View.
+func (m *BrowserModel) handleMouse(msg tea.MouseMsg) (*BrowserModel, tea.Cmd) {
+	if m.confirmMode || len(m.visibleNodes) == 0 {
+		return m, nil
+	}
+	
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.cursor -= mouseWheelStep
+		if m.cursor < 0 {
+			m.cursor = 0
+		}
+		m.updateViewport()
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.cursor += mouseWheelStep
+		if m.cursor >= len(m.visibleNodes) {
+			m.cursor = len(m.visibleNodes) - 1
+		}
+		m.updateViewport()
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		row := msg.Y - strings.Count(m.renderHeader(), "\n")
+		index := m.viewport.offset + row
+		if row < 0 || row >= m.viewport.size || index >= len(m.visibleNodes) {
+			return m, nil
+		}
+		m.cursor = index
+		if m.visibleNodes[index].IsDir && m.visibleNodes[index].IsExpanded {
+			m.tree.CollapseNode(m.visibleNodes[index])
+			m.refreshView()
+			return m, nil
+		}
+		return m.handleRight()
+	}
+	
+	return m, nil
+}
+
 // handleConfirmMode processes input in confirmation mode
 func (m *BrowserModel) handleConfirmMode(msg tea.KeyMsg) (*BrowserModel, tea.Cmd) {
 	switch msg.String() {
 	case "y", "enter":
-		// Confirm selection
+		// Confirm selection; checked items take precedence over the highlighted folder
 		m.confirmMode = false
+		if paths := m.tree.CheckedPaths(); len(paths) > 0 {
+			return m, m.selectPaths(paths)
+		}
 		return m, m.selectFolder()
 	case "n", "esc":
 		// Cancel
@@ -219,11 +481,11 @@ func (m *BrowserModel) handleRight() (*BrowserModel, tea.Cmd) {
 	return m, nil
 }
 
-// handleSelection toggles selection of current item
+// handleSelection toggles the checkbox of the current item
 func (m *BrowserModel) handleSelection() (*BrowserModel, tea.Cmd) {
 	currentNode := m.getCurrentNode()
 	if currentNode != nil {
-		m.tree.SelectNode(currentNode)
+		m.tree.ToggleChecked(currentNode)
 	}
 	
 	return m, nil
@@ -231,6 +493,7 @@ func (m *BrowserModel) handleSelection() (*BrowserModel, tea.Cmd) {
 
 // handleRefresh refreshes the current view
 func (m *BrowserModel) handleRefresh() (*BrowserModel, tea.Cmd) {
+	m.stopIndex()
 	err := m.tree.refreshTree()
 	if err != nil {
 		m.errorMessage = fmt.Sprintf("Error refreshing: %v", err)
@@ -238,6 +501,9 @@ func (m *BrowserModel) handleRefresh() (*BrowserModel, tea.Cmd) {
 		m.refreshView()
 		m.errorMessage = ""
 	}
+	if m.filter.active() {
+		return m, m.startIndex()
+	}
 	
 	return m, nil
 }
@@ -252,6 +518,26 @@ func (m *BrowserModel) selectFolder() tea.Cmd {
 	}
 }
 
+// selectPaths returns a command reporting the checked files and folders
+func (m *BrowserModel) selectPaths(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		return BrowserMsg{
+			Type: "paths_selected",
+			Data: paths,
+		}
+	}
+}
+
+// nodeMsg reports an action on a node, such as "never_include" or "explain_exclusion"
+func (m *BrowserModel) nodeMsg(msgType string, node *FolderNode) tea.Cmd {
+	return func() tea.Msg {
+		return BrowserMsg{
+			Type: msgType,
+			Data: node,
+		}
+	}
+}
+
 // getCurrentNode returns the currently highlighted node
 func (m *BrowserModel) getCurrentNode() *FolderNode {
 	if m.cursor >= 0 && m.cursor < len(m.visibleNodes) {
@@ -273,32 +559,7 @@ func (m *BrowserModel) findNodeIndex(node *FolderNode) int {
 // View renders the folder browser
 func (m *BrowserModel) View() string {
 	var result strings.Builder
-	
-	// Header
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
-		BorderBottom(true).
-		BorderStyle(lipgloss.NormalBorder()).
-		Width(m.width)
-	
-	currentPath := m.tree.GetPath()
-	if len(currentPath) > m.width-20 {
-		currentPath = "..." + currentPath[len(currentPath)-(m.width-23):]
-	}
-	
-	header := fmt.Sprintf("📁 Browse Folders: %s", currentPath)
-	result.WriteString(headerStyle.Render(header))
-	result.WriteString("\n\n")
-	
-	// Error message
-	if m.errorMessage != "" {
-		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
-			Bold(true)
-		result.WriteString(errorStyle.Render("⚠️ " + m.errorMessage))
-		result.WriteString("\n\n")
-	}
+	result.WriteString(m.renderHeader())
 	
 	// Folder tree
 	if len(m.visibleNodes) == 0 {
@@ -317,8 +578,10 @@ func (m *BrowserModel) View() string {
 		for i := start; i < end; i++ {
 			node := m.visibleNodes[i]
 			isSelected := i == m.cursor
-			line := RenderTreeLine(node, isSelected, m.width-2)
+			line := RenderTreeLine(node, isSelected, m.width-3)
 			result.WriteString(line)
+			result.WriteString(" ")
+			result.WriteString(m.renderScrollbarCell(i - start))
 			result.WriteString("\n")
 		}
 	}
@@ -336,6 +599,122 @@ func (m *BrowserModel) View() string {
 	return result.String()
 }
 
+// renderHeader renders the path header and any error above the tree
+func (m *BrowserModel) renderHeader() string {
+	var result strings.Builder
+	
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4")).
+		BorderBottom(true).
+		BorderStyle(lipgloss.NormalBorder()).
+		Width(m.width)
+	
+	currentPath := m.tree.GetPath()
+	if len(currentPath) > m.width-20 {
+		currentPath = "..." + currentPath[len(currentPath)-(m.width-23):]
+	}
+	
+	header := fmt.Sprintf("📁 Browse Folders: %s", currentPath)
+	result.WriteString(headerStyle.Render(header))
+	result.WriteString("\n\n")
+	
+	if picks := m.renderQuickPicks(); picks != "" {
+		result.WriteString(picks)
+		result.WriteString("\n\n")
+	}
+	
+	if filter := m.renderFilter(); filter != "" {
+		result.WriteString(filter)
+		result.WriteString("\n\n")
+	}
+	
+	// Error message
+	if m.errorMessage != "" {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#EF4444")).
+			Bold(true)
+		result.WriteString(errorStyle.Render("⚠️ " + m.errorMessage))
+		result.WriteString("\n\n")
+	}
+	
+	return result.String()
+}
+
+// renderQuickPicks renders the numbered bookmarks and recent folders
+func (m *BrowserModel) renderQuickPicks() string {
+	picks := m.quickPicks()
+	if len(picks) == 0 {
+		return ""
+	}
+	
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+	pickStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#374151"))
+	currentStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#3B82F6")).
+		Bold(true)
+	
+	home, _ := os.UserHomeDir()
+	lines := []string{titleStyle.Render("Recent/Bookmarks")}
+	for i, pick := range picks {
+		icon := "🕘"
+		if pick.bookmarked {
+			icon = "⭐"
+		}
+		path := pick.path
+		if home != "" && (path == home || strings.HasPrefix(path, home+string(filepath.Separator))) {
+			path = "~" + strings.TrimPrefix(path, home)
+		}
+		if limit := m.width - 10; limit > 3 && len(path) > limit {
+			path = "..." + path[len(path)-(limit-3):]
+		}
+		
+		line := fmt.Sprintf("  %d %s %s", i+1, icon, path)
+		if pick.path == m.tree.GetPath() {
+			lines = append(lines, currentStyle.Render(line))
+		} else {
+			lines = append(lines, pickStyle.Render(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderFilter renders the active filters, with a cursor on the one being typed
+func (m *BrowserModel) renderFilter() string {
+	if !m.filter.active() && m.filter.mode == filterNone {
+		return ""
+	}
+	
+	filterStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	var parts []string
+	if m.filter.query != "" || m.filter.mode == filterPath {
+		query := m.filter.query
+		if m.filter.mode == filterPath {
+			query += "▌"
+		}
+		parts = append(parts, "🔍 Filter: "+query)
+	}
+	if m.filter.extInput != "" || m.filter.mode == filterExt {
+		types := strings.Join(m.filter.extensions, ", ")
+		if m.filter.mode == filterExt {
+			types = m.filter.extInput + "▌"
+		}
+		parts = append(parts, "📄 Type: "+types)
+	}
+	
+	hint := "Esc: clear filters"
+	if m.filter.mode != filterNone {
+		hint = "Enter: apply • Esc: clear"
+	}
+	return filterStyle.Render(strings.Join(parts, " • ")) + "  " +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Italic(true).Render(hint)
+}
+
 // renderFooter renders the footer with stats and controls
 func (m *BrowserModel) renderFooter() string {
 	var result strings.Builder
@@ -354,23 +733,88 @@ func (m *BrowserModel) renderFooter() string {
 				currentNode.Name,
 				FormatCount(currentNode.FileCount),
 				FormatSize(currentNode.Size))
+			if !currentNode.StatsLoaded {
+				stats = fmt.Sprintf("📊 Selected: %s | calculating…", currentNode.Name)
+			}
 			
 			result.WriteString(statsStyle.Render(stats))
 			result.WriteString("\n")
 		}
 	}
 	
+	// Position indicator
+	positionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#7D56F4"))
+	result.WriteString(positionStyle.Render(m.renderPosition()))
+	result.WriteString("\n")
+	
 	// Instructions
 	instructionStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#6B7280")).
 		Italic(true)
 	
-	instructions := "↑↓: navigate • ←→: collapse/expand • Space: select • C: confirm • S: toggle stats • R: refresh"
+	if checked := len(m.tree.CheckedPaths()); checked > 0 {
+		result.WriteString(positionStyle.Render(fmt.Sprintf("☑ %d selected", checked)))
+		result.WriteString("\n")
+	}
+	
+	if m.minimal {
+		return result.String()
+	}
+	instructions := "↑↓: navigate • PgUp/PgDn: page • ←→: collapse/expand • Space: check • A: check all in folder • C: confirm • N: never include/undo • E: why excluded? • /: filter • F: file type • *: bookmark • 1-9: quick pick • S: toggle stats • R: refresh"
 	result.WriteString(instructionStyle.Render(instructions))
 	
 	return result.String()
 }
 
+// renderPosition renders the "N of M" indicator with scroll percentage
+func (m *BrowserModel) renderPosition() string {
+	total := len(m.visibleNodes)
+	if total == 0 {
+		return "0 of 0"
+	}
+	
+	return fmt.Sprintf("%d of %d • %d%%", m.cursor+1, total, m.scrollPercentage())
+}
+
+// scrollPercentage returns how far the viewport has scrolled (0-100)
+func (m *BrowserModel) scrollPercentage() int {
+	maxOffset := len(m.visibleNodes) - m.viewport.size
+	if maxOffset <= 0 {
+		return 100
+	}
+	
+	offset := m.viewport.offset
+	if offset > maxOffset {
+		offset = maxOffset
+	}
+	return offset * 100 / maxOffset
+}
+
+// renderScrollbarCell renders the scrollbar character for a viewport row
+func (m *BrowserModel) renderScrollbarCell(row int) string {
+	trackStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#374151"))
+	thumbStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
+	
+	total := len(m.visibleNodes)
+	size := m.viewport.size
+	if size <= 0 || total <= size {
+		return thumbStyle.Render("┃")
+	}
+	
+	// Thumb length is proportional to the visible fraction of the list
+	thumbSize := size * size / total
+	if thumbSize < 1 {
+		thumbSize = 1
+	}
+	thumbStart := m.scrollPercentage() * (size - thumbSize) / 100
+	
+	if row >= thumbStart && row < thumbStart+thumbSize {
+		return thumbStyle.Render("┃")
+	}
+	return trackStyle.Render("│")
+}
+
 // renderConfirmDialog renders the confirmation dialog
 func (m *BrowserModel) renderConfirmDialog() string {
 	currentNode := m.getCurrentNode()
@@ -387,14 +831,40 @@ func (m *BrowserModel) renderConfirmDialog() string {
 		Width(60).
 		Align(lipgloss.Center)
 	
+	if paths := m.tree.CheckedPaths(); len(paths) > 0 {
+		return dialogStyle.Render(fmt.Sprintf("Scan %d selected items?\n\n%s\n\nPress Y to confirm, N to cancel.",
+			len(paths), m.checkedSummary(paths)))
+	}
+	
 	message := fmt.Sprintf("Select folder '%s'?\n\nThis will scan %s files (%s) and generate context.\n\nPress Y to confirm, N to cancel.",
 		currentNode.Name,
 		FormatCount(currentNode.FileCount),
 		FormatSize(currentNode.Size))
+	if !currentNode.StatsLoaded {
+		message = fmt.Sprintf("Select folder '%s'?\n\nThis will scan the folder and generate context.\n\nPress Y to confirm, N to cancel.",
+			currentNode.Name)
+	}
 	
 	return dialogStyle.Render(message)
 }
 
+// checkedSummary lists the first few checked paths relative to the browser root
+func (m *BrowserModel) checkedSummary(paths []string) string {
+	const shown = 5
+	var names []string
+	for i, path := range paths {
+		if i == shown {
+			names = append(names, fmt.Sprintf("…and %d more", len(paths)-shown))
+			break
+		}
+		if rel, err := filepath.Rel(m.tree.GetPath(), path); err == nil {
+			path = rel
+		}
+		names = append(names, path)
+	}
+	return strings.Join(names, "\n")
+}
+
 // GetSelectedFolder returns the currently selected folder
 func (m *BrowserModel) GetSelectedFolder() *FolderNode {
 	return m.tree.GetSelectedNode()
@@ -420,7 +890,40 @@ func (m *BrowserModel) handleBrowserMsg(msg BrowserMsg) (*BrowserModel, tea.Cmd)
 		return m, nil
 	case "refresh":
 		return m.handleRefresh()
+	case "stats_loaded":
+		if result, ok := msg.Data.(NodeStats); ok && result.Node != nil {
+			result.Node.statsPending = false
+			if result.Err == nil {
+				m.tree.applyStats(result.Node, result.Stats)
+			}
+		}
+	case "filter_indexed":
+		if index, ok := msg.Data.(filterIndex); ok && m.cancelIndex != nil && index.root == m.tree.GetPath() {
+			m.cancelIndex()
+			m.cancelIndex = nil
+			m.index = &index
+			m.applyFilter()
+		}
 	}
 	
 	return m, nil
+}
+
+// statsCmd calculates the highlighted directory's stats off the UI loop,
+// or returns nil if they are known or already being calculated
+func (m *BrowserModel) statsCmd() tea.Cmd {
+	node := m.getCurrentNode()
+	if node == nil || !node.IsDir || node.IsCycle || node.StatsLoaded || node.statsPending {
+		return nil
+	}
+	
+	node.statsPending = true
+	tree := m.tree
+	return func() tea.Msg {
+		stats, err := tree.GetFolderStats(node.Path)
+		return BrowserMsg{
+			Type: "stats_loaded",
+			Data: NodeStats{Node: node, Stats: stats, Err: err},
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,234 @@
+package folder
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Filter input modes
+const (
+	filterNone = iota
+	filterPath // typing after "/"
+	filterExt  // typing after "f"
+)
+
+// browserFilter narrows the tree to nodes matching a path query and/or file
+// extensions. Folders that were never expanded are searched through an index
+// built in the background.
+type browserFilter struct {
+	query      string   // substring or fuzzy match on the path
+	extensions []string // lowercase, with the leading dot
+	extInput   string   // extensions as typed, e.g. "go, ts"
+	mode       int      // which filter is being typed, if any
+}
+
+// The background walk behind the filter stops this many levels below the root
+// or after this many entries; at most filterRevealLimit matches are loaded
+// into the tree for each change to the filter
+const (
+	filterIndexDepth  = 8
+	filterIndexLimit  = 20000
+	filterRevealLimit = 200
+)
+
+// filterIndex is every entry the background walk found below a tree root
+type filterIndex struct {
+	root    string
+	entries []indexEntry
+}
+
+// indexEntry is one file or folder in a filterIndex
+type indexEntry struct {
+	relPath string // slash-separated, relative to the root
+	isDir   bool
+}
+
+// indexCmd walks root off the UI loop, skipping hidden entries unless
+// showHidden, and reports nothing if ctx is cancelled first
+func indexCmd(ctx context.Context, root string, showHidden bool) tea.Cmd {
+	return func() tea.Msg {
+		var entries []indexEntry
+		filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return filepath.SkipAll
+			}
+			if err != nil || path == root {
+				return nil // unreadable folders are left out
+			}
+			if !showHidden && strings.HasPrefix(entry.Name(), ".") {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil
+			}
+			relPath = filepath.ToSlash(relPath)
+			entries = append(entries, indexEntry{relPath: relPath, isDir: entry.IsDir()})
+			if len(entries) >= filterIndexLimit {
+				return filepath.SkipAll
+			}
+			if entry.IsDir() && strings.Count(relPath, "/")+1 >= filterIndexDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		return BrowserMsg{Type: "filter_indexed", Data: filterIndex{root: root, entries: entries}}
+	}
+}
+
+// active reports whether any filter narrows the tree
+func (f browserFilter) active() bool {
+	return f.query != "" || len(f.extensions) > 0
+}
+
+// parseExtensions turns "go, .TS md" into [".go" ".ts" ".md"]
+func parseExtensions(input string) []string {
+	var extensions []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		ext := strings.ToLower(strings.TrimPrefix(field, "*"))
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if ext != "." {
+			extensions = append(extensions, ext)
+		}
+	}
+	return extensions
+}
+
+// matchesQuery reports whether the path relative to the root contains the
+// query, or the name contains its characters in order (so "brw" finds browser.go)
+func matchesQuery(relPath, name, query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(relPath), query) {
+		return true
+	}
+
+	name = strings.ToLower(name)
+	for _, r := range query {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(string(r)):]
+	}
+	return true
+}
+
+// matches reports whether node passes the filter on its own
+func (f browserFilter) matches(node *FolderNode, root string) bool {
+	relPath, err := filepath.Rel(root, node.Path)
+	if err != nil {
+		relPath = node.Path
+	}
+	return f.matchesEntry(filepath.ToSlash(relPath), node.Name, node.IsDir)
+}
+
+// matchesEntry reports whether a file or folder at relPath passes the filter
+func (f browserFilter) matchesEntry(relPath, name string, isDir bool) bool {
+	if len(f.extensions) > 0 {
+		if isDir {
+			return false
+		}
+		ext := strings.ToLower(filepath.Ext(name))
+		found := false
+		for _, want := range f.extensions {
+			if ext == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.query == "" {
+		return true
+	}
+	return matchesQuery(relPath, name, f.query)
+}
+
+// revealIndexed loads the folders leading to the indexed entries that pass
+// filter, without expanding them, so filterNodes can find those entries
+func (ft *FolderTree) revealIndexed(index *filterIndex, filter browserFilter) {
+	if index == nil || index.root != ft.currentPath {
+		return
+	}
+	revealed := 0
+	for _, entry := range index.entries {
+		if revealed >= filterRevealLimit {
+			return
+		}
+		if filter.matchesEntry(entry.relPath, filepath.Base(entry.relPath), entry.isDir) {
+			ft.revealPath(entry.relPath)
+			revealed++
+		}
+	}
+}
+
+// revealPath loads each unloaded folder on the way to relPath
+func (ft *FolderTree) revealPath(relPath string) {
+	node := ft.root
+	parts := strings.Split(relPath, "/")
+	for _, name := range parts[:len(parts)-1] {
+		var next *FolderNode
+		for _, child := range node.Children {
+			if child.Name == name {
+				next = child
+				break
+			}
+		}
+		if next == nil || !next.IsDir || next.IsCycle {
+			return
+		}
+		if next.Children == nil && ft.loadChildren(next) != nil {
+			return
+		}
+		node = next
+	}
+}
+
+// filterNodes returns the loaded nodes that pass filter, plus the folders
+// leading to them, in display order. Collapsed folders are searched too.
+func (ft *FolderTree) filterNodes(filter browserFilter) []*FolderNode {
+	var nodes []*FolderNode
+	ft.collectFilteredNodes(ft.root, filter, &nodes)
+	if len(nodes) == 0 {
+		// Keep the root so the tree never disappears entirely
+		nodes = append(nodes, ft.root)
+	}
+	return nodes
+}
+
+// collectFilteredNodes appends node and its matching descendants, returning
+// whether anything under node matched
+func (ft *FolderTree) collectFilteredNodes(node *FolderNode, filter browserFilter, nodes *[]*FolderNode) bool {
+	mark := len(*nodes)
+	*nodes = append(*nodes, node)
+
+	found := node != ft.root && filter.matches(node, ft.currentPath)
+	if !node.IsCycle {
+		for _, child := range node.Children {
+			if ft.collectFilteredNodes(child, filter, nodes) {
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		*nodes = (*nodes)[:mark]
+	}
+	return found
+}
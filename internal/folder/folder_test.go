@@ -1,9 +1,14 @@
 package folder
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestNewFolderTree(t *testing.T) {
@@ -437,4 +442,472 @@ func TestHiddenFileHandling(t *testing.T) {
 	if visibleCount != 1 {
 		t.Errorf("Expected 1 visible file when hidden enabled, got %d", visibleCount)
 	}
-}
\ No newline at end of file
+}
+
+func TestBrowserFooterPosition(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "browser_position_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	for i := 0; i < 30; i++ {
+		os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("file%02d.txt", i)), []byte("x"), 0644)
+	}
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	
+	total := len(browser.visibleNodes)
+	
+	browser.cursor = 4
+	browser.updateViewport()
+	footer := browser.renderFooter()
+	if !strings.Contains(footer, fmt.Sprintf("5 of %d", total)) {
+		t.Errorf("Expected footer to contain '5 of %d', got %q", total, footer)
+	}
+	
+	if !strings.Contains(footer, "0%") {
+		t.Error("Expected scroll percentage 0% at the top of the list")
+	}
+	
+	// Paging to the end moves the indicator and scroll percentage
+	browser.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	footer = browser.renderFooter()
+	if !strings.Contains(footer, fmt.Sprintf("%d of %d • 100%%", total, total)) {
+		t.Errorf("Expected footer to show last position at 100%%, got %q", footer)
+	}
+}
+
+func TestSymlinkCycleTraversalTerminates(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "symlink_cycle_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	// a/b/loop -> a creates a parent-child cycle once symlinks are followed
+	nested := filepath.Join(tempDir, "a", "b")
+	os.MkdirAll(nested, 0755)
+	os.WriteFile(filepath.Join(nested, "file.txt"), []byte("content"), 0644)
+	if err := os.Symlink(filepath.Join(tempDir, "a"), filepath.Join(nested, "loop")); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	
+	tree, err := NewFolderTree(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+	if err := tree.SetFollowSymlinks(true); err != nil {
+		t.Fatalf("Failed to follow symlinks: %v", err)
+	}
+	
+	for _, path := range []string{filepath.Join(tempDir, "a"), nested} {
+		if err := tree.ExpandNode(tree.GetNodeByPath(path)); err != nil {
+			t.Fatalf("Failed to expand %s: %v", path, err)
+		}
+	}
+	
+	loop := tree.GetNodeByPath(filepath.Join(nested, "loop"))
+	if loop == nil || !loop.IsDir || !loop.IsCycle {
+		t.Fatalf("Expected loop symlink to be a directory marked as a cycle, got %+v", loop)
+	}
+	
+	if err := tree.ExpandNode(loop); err != nil || loop.IsExpanded {
+		t.Error("Expected cyclic node to stay collapsed")
+	}
+	
+	// Force a cyclic node graph and make sure traversal still terminates
+	loop.IsCycle = false
+	loop.IsExpanded = true
+	loop.Children = []*FolderNode{tree.GetNodeByPath(filepath.Join(tempDir, "a"))}
+	
+	done := make(chan []*FolderNode)
+	go func() { done <- tree.GetVisibleNodes() }()
+	select {
+	case nodes := <-done:
+		if len(nodes) == 0 {
+			t.Error("Expected visible nodes")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected GetVisibleNodes to terminate on a cyclic tree")
+	}
+	
+	if !strings.Contains(RenderTreeLine(&FolderNode{Name: "loop", IsDir: true, IsCycle: true}, false, 80), "↻") {
+		t.Error("Expected cycle marker in rendered tree line")
+	}
+}
+
+func TestSymlinkedRootIsResolved(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "symlink_root_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	realDir, _ := filepath.EvalSymlinks(tempDir)
+	target := filepath.Join(realDir, "real")
+	os.MkdirAll(filepath.Join(target, "src"), 0755)
+	link := filepath.Join(realDir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	
+	tree, err := NewFolderTree(link)
+	if err != nil {
+		t.Fatalf("Failed to create tree: %v", err)
+	}
+	if tree.GetPath() != target {
+		t.Errorf("Expected tree rooted at %s, got %s", target, tree.GetPath())
+	}
+	if tree.GetNodeByPath(filepath.Join(target, "src")) == nil {
+		t.Error("Expected child nodes under the resolved path")
+	}
+}
+
+func TestDirectoryStatsLoadLazily(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lazy_stats_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	sub := filepath.Join(tempDir, "sub")
+	os.MkdirAll(filepath.Join(sub, "nested"), 0755)
+	os.WriteFile(filepath.Join(sub, "a.go"), []byte("package a\n"), 0644)
+	os.WriteFile(filepath.Join(sub, "nested", "b.go"), []byte("package b\n"), 0644)
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser: %v", err)
+	}
+	
+	// Highlight "sub", which follows the root
+	browser, cmd := browser.Update(tea.KeyMsg{Type: tea.KeyDown})
+	node := browser.getCurrentNode()
+	if node == nil || node.Path != sub {
+		t.Fatalf("Expected cursor on %s, got %+v", sub, node)
+	}
+	if node.StatsLoaded || node.FileCount != 0 {
+		t.Fatalf("Expected stats to start empty, got %d files", node.FileCount)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a stats command for the highlighted directory")
+	}
+	if !strings.Contains(browser.View(), "calculating…") {
+		t.Error("Expected calculating placeholder while stats load")
+	}
+	if browser.statsCmd() != nil {
+		t.Error("Expected no duplicate stats command while one is pending")
+	}
+	
+	browser, _ = browser.Update(cmd())
+	if !node.StatsLoaded || node.FileCount != 2 || node.DirCount != 2 {
+		t.Errorf("Expected 2 files and 2 directories after stats resolve, got %d files and %d dirs",
+			node.FileCount, node.DirCount)
+	}
+	
+	// Cached stats survive collapsing and re-expanding the root
+	browser.tree.CollapseNode(browser.tree.root)
+	browser.tree.ExpandNode(browser.tree.root)
+	if cached := browser.tree.GetNodeByPath(sub); cached == nil || !cached.StatsLoaded {
+		t.Error("Expected stats to be reused from the cache for rebuilt nodes")
+	}
+}
+
+func TestMultiSelectEmitsCheckedPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "api"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "web"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "api", "handler.go"), []byte("package api\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "api", "routes.go"), []byte("package api\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# demo\n"), 0644)
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	moveTo := func(name string) {
+		for i, node := range browser.visibleNodes {
+			if node.Name == name {
+				browser.cursor = i
+				return
+			}
+		}
+		t.Fatalf("Node %s not visible", name)
+	}
+	press := func(key tea.KeyMsg) tea.Cmd {
+		var cmd tea.Cmd
+		browser, cmd = browser.handleKeyPress(key)
+		return cmd
+	}
+	
+	moveTo("README.md")
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	moveTo("api")
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	
+	root := browser.tree.GetPath()
+	want := []string{
+		filepath.Join(root, "README.md"),
+		filepath.Join(root, "api", "handler.go"),
+		filepath.Join(root, "api", "routes.go"),
+	}
+	if got := browser.tree.CheckedPaths(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected checked paths %v, got %v", want, got)
+	}
+	if !strings.Contains(browser.View(), "☑ 3 selected") {
+		t.Error("Expected the footer to count the checked items")
+	}
+	
+	// A again on a fully checked folder clears it
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got := browser.tree.CheckedPaths(); len(got) != 1 {
+		t.Fatalf("Expected A to uncheck the folder's entries, got %v", got)
+	}
+	
+	moveTo("web")
+	press(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !strings.Contains(browser.View(), "Scan 2 selected items?") {
+		t.Error("Expected the confirm dialog to list the checked items")
+	}
+	msg := press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})()
+	selected, ok := msg.(BrowserMsg)
+	if !ok || selected.Type != "paths_selected" {
+		t.Fatalf("Expected a paths_selected message, got %#v", msg)
+	}
+	if paths := selected.Data.([]string); len(paths) != 2 || paths[1] != filepath.Join(root, "web") {
+		t.Errorf("Unexpected selected paths: %v", paths)
+	}
+}
+
+func TestMouseClickExpandsFolders(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "api", "v1"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "api", "handler.go"), []byte("package api\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# demo\n"), 0644)
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	browser, _ = browser.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	rowOf := func(name string) int {
+		for i, node := range browser.visibleNodes {
+			if node.Name == name {
+				return strings.Count(browser.renderHeader(), "\n") + i - browser.viewport.offset
+			}
+		}
+		t.Fatalf("Node %s not visible", name)
+		return 0
+	}
+	click := func(y int) {
+		browser, _ = browser.Update(tea.MouseMsg{Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	}
+	
+	// The row under the pointer is the one in the view
+	if lines := strings.Split(browser.View(), "\n"); !strings.Contains(lines[rowOf("api")], "api") {
+		t.Fatalf("Expected row %d to show api, got %q", rowOf("api"), lines[rowOf("api")])
+	}
+	
+	click(rowOf("api"))
+	if node := browser.getCurrentNode(); node.Name != "api" || !node.IsExpanded {
+		t.Fatalf("Expected a click to select and expand api, got %+v", node)
+	}
+	if rowOf("handler.go") < 0 {
+		t.Fatal("Expected api's children to be visible")
+	}
+	click(rowOf("api"))
+	if browser.getCurrentNode().IsExpanded {
+		t.Error("Expected a second click to collapse api")
+	}
+	
+	click(rowOf("README.md"))
+	if browser.getCurrentNode().Name != "README.md" {
+		t.Errorf("Expected a click to select README.md, got %s", browser.getCurrentNode().Name)
+	}
+	click(0)
+	if browser.getCurrentNode().Name != "README.md" {
+		t.Error("Expected clicks on the header to be ignored")
+	}
+	
+	browser, _ = browser.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp})
+	if browser.cursor != 0 {
+		t.Errorf("Expected the wheel to move the cursor up, got %d", browser.cursor)
+	}
+}
+
+func TestFilterNarrowsVisibleNodes(t *testing.T) {
+	tempDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tempDir, "internal", "folder"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "internal", "folder", "browser.go"), []byte("package folder\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "internal", "folder", "notes.md"), []byte("# notes\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# demo\n"), 0644)
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	// Load the nested folder, then collapse it; filters still search it
+	internalDir := browser.tree.GetNodeByPath(filepath.Join(tempDir, "internal"))
+	browser.tree.ExpandNode(internalDir)
+	browser.tree.ExpandNode(browser.tree.GetNodeByPath(filepath.Join(tempDir, "internal", "folder")))
+	browser.tree.CollapseNode(internalDir)
+	browser.refreshView()
+	
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			browser, _ = browser.Update(key)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	names := func() string {
+		var names []string
+		for _, node := range browser.visibleNodes {
+			names = append(names, node.Name)
+		}
+		return strings.Join(names, " ")
+	}
+	root := filepath.Base(tempDir)
+	
+	// Fuzzy match on the name, with the folders leading to it
+	press(runes("/"), runes("b"), runes("r"), runes("w"))
+	if got, want := names(), root+" internal folder browser.go"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if !browser.Filtering() || !strings.Contains(browser.View(), "Filter: brw▌") {
+		t.Error("Expected the filter being typed in the header")
+	}
+	
+	// Typing continues to narrow; Enter keeps the filter
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		runes("main"), tea.KeyMsg{Type: tea.KeyEnter})
+	if got, want := names(), root+" main.go"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if browser.Filtering() || !strings.Contains(browser.View(), "Filter: main") {
+		t.Error("Expected the applied filter to stay in the header")
+	}
+	
+	// Extension filters combine with the path filter
+	press(tea.KeyMsg{Type: tea.KeyEsc}, runes("f"), runes("md"), tea.KeyMsg{Type: tea.KeyEnter})
+	if got, want := names(), root+" internal folder notes.md README.md"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if !strings.Contains(browser.View(), "Type: .md") {
+		t.Error("Expected the file type filter in the header")
+	}
+	press(runes("/"), runes("internal"), tea.KeyMsg{Type: tea.KeyEnter})
+	if got, want := names(), root+" internal folder notes.md"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	
+	// Esc clears everything and the tree comes back as it was
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if got, want := names(), root+" internal main.go README.md"; got != want {
+		t.Errorf("Expected the unfiltered tree %q, got %q", want, got)
+	}
+}
+
+func TestFilterSearchesUnloadedFolders(t *testing.T) {
+	tempDir := t.TempDir()
+	deep := filepath.Join(tempDir, "services", "billing", "api")
+	os.MkdirAll(deep, 0755)
+	os.WriteFile(filepath.Join(deep, "handler.go"), []byte("package api\n"), 0644)
+	os.MkdirAll(filepath.Join(tempDir, ".git", "objects"), 0755)
+	os.WriteFile(filepath.Join(tempDir, ".git", "objects", "handler.pack"), []byte("x"), 0644)
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	
+	// Typing starts one background walk; its result reveals the nested file
+	var walk tea.Cmd
+	for _, r := range "/handler" {
+		var cmd tea.Cmd
+		browser, cmd = browser.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if cmd != nil {
+			if walk != nil {
+				t.Fatal("Expected a single background walk while typing")
+			}
+			walk = cmd
+		}
+	}
+	if walk == nil {
+		t.Fatal("Expected the filter to start a background walk")
+	}
+	browser, _ = browser.Update(walk())
+	var names []string
+	for _, node := range browser.visibleNodes {
+		names = append(names, node.Name)
+	}
+	if got, want := strings.Join(names, " "), filepath.Base(tempDir)+" services billing api handler.go"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if node := browser.tree.GetNodeByPath(filepath.Join(tempDir, "services")); node == nil || node.IsExpanded {
+		t.Error("Expected revealed folders to stay collapsed")
+	}
+	
+	// Clearing the filter cancels a walk still in progress
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	browser, _ = browser.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	browser, walk = browser.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if walk == nil || walk() != nil {
+		t.Error("Expected a cancelled walk to report nothing")
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	got := strings.Join(parseExtensions("go, .TS *.md ,"), " ")
+	if got != ".go .ts .md" {
+		t.Errorf("Unexpected extensions: %q", got)
+	}
+}
+
+func TestQuickPicksJumpToFolders(t *testing.T) {
+	tempDir := t.TempDir()
+	deep := filepath.Join(tempDir, "services", "billing", "api")
+	os.MkdirAll(deep, 0755)
+	os.WriteFile(filepath.Join(deep, "handler.go"), []byte("package api\n"), 0644)
+	other := filepath.Join(tempDir, "services")
+	
+	browser, err := NewBrowserModel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create browser model: %v", err)
+	}
+	browser.SetQuickPicks([]string{deep}, []string{other, deep})
+	
+	picks := browser.quickPicks()
+	if len(picks) != 2 || picks[0].path != deep || !picks[0].bookmarked || picks[1].path != other || picks[1].bookmarked {
+		t.Fatalf("Expected the bookmark first and the recent folder once, got %+v", picks)
+	}
+	view := browser.View()
+	if !strings.Contains(view, "Recent/Bookmarks") || !strings.Contains(view, "1 ⭐") || !strings.Contains(view, "2 🕘") {
+		t.Errorf("Expected numbered quick picks above the tree, got:\n%s", view)
+	}
+	
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if browser.tree.GetPath() != deep || browser.getCurrentNode().Name != "api" {
+		t.Fatalf("Expected 1 to jump to the bookmark, got %s", browser.tree.GetPath())
+	}
+	
+	_, cmd := browser.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")})
+	msg := cmd()
+	if m, ok := msg.(BrowserMsg); !ok || m.Type != "toggle_bookmark" || m.Data.(*FolderNode).Path != deep {
+		t.Errorf("Expected * to ask to bookmark the folder, got %#v", msg)
+	}
+	
+	browser.SetQuickPicks(nil, []string{filepath.Join(tempDir, "missing")})
+	browser, _ = browser.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if browser.tree.GetPath() != deep || !strings.Contains(browser.View(), "Cannot open") {
+		t.Error("Expected a missing folder to report an error and stay put")
+	}
+}
@@ -14,18 +14,24 @@ import (
 
 // FolderNode represents a node in the folder tree
 type FolderNode struct {
-	Name       string
-	Path       string
-	IsDir      bool
-	Size       int64
-	FileCount  int
-	DirCount   int
-	ModTime    time.Time
-	Children   []*FolderNode
-	Parent     *FolderNode
-	IsExpanded bool
-	IsSelected bool
-	Level      int
+	Name        string
+	Path        string
+	IsDir       bool
+	Size        int64
+	FileCount   int
+	DirCount    int
+	ModTime     time.Time
+	Children    []*FolderNode
+	Parent      *FolderNode
+	IsExpanded  bool
+	IsSelected  bool
+	IsChecked   bool // part of the multi-selection
+	Level       int
+	RealPath    string // path with symlinks resolved
+	IsCycle     bool   // symlink pointing back at one of its ancestors
+	StatsLoaded bool   // directory stats have been calculated
+	
+	statsPending bool // a stats command is in flight
 }
 
 // FolderStats represents statistics for a folder
@@ -45,7 +51,10 @@ type FolderTree struct {
 	expandedPaths  map[string]bool
 	maxDepth       int
 	showHidden     bool
+	followSymlinks bool
 	sortBy         SortType
+	statsCache     map[string]*FolderStats // directory stats by path, filled lazily
+	checked        map[string]bool         // multi-selected paths; survives reloads
 }
 
 // SortType defines how folders should be sorted
@@ -65,6 +74,9 @@ func NewFolderTree(rootPath string) (*FolderTree, error) {
 		return nil, fmt.Errorf("invalid path: %w", err)
 	}
 	
+	// Resolve a symlinked root so every node path is canonical
+	absPath = resolvePath(absPath)
+	
 	// Check if path exists and is a directory
 	info, err := os.Stat(absPath)
 	if err != nil {
@@ -78,6 +90,8 @@ func NewFolderTree(rootPath string) (*FolderTree, error) {
 	tree := &FolderTree{
 		currentPath:   absPath,
 		expandedPaths: make(map[string]bool),
+		statsCache:    make(map[string]*FolderStats),
+		checked:       make(map[string]bool),
 		maxDepth:      10,
 		showHidden:    false,
 		sortBy:        SortByName,
@@ -105,6 +119,7 @@ func (ft *FolderTree) buildTree() error {
 		ModTime:    info.ModTime(),
 		IsExpanded: true,
 		Level:      0,
+		RealPath:   resolvePath(ft.currentPath),
 	}
 	
 	return ft.loadChildren(ft.root)
@@ -135,20 +150,39 @@ func (ft *FolderTree) loadChildren(node *FolderNode) error {
 			continue // Skip files we can't stat
 		}
 		
+		isDir := entry.IsDir()
+		if ft.followSymlinks && entry.Type()&fs.ModeSymlink != 0 {
+			if target, err := os.Stat(fullPath); err == nil {
+				isDir = target.IsDir()
+			}
+		}
+		
 		child := &FolderNode{
 			Name:       entry.Name(),
 			Path:       fullPath,
-			IsDir:      entry.IsDir(),
+			IsDir:      isDir,
 			Size:       info.Size(),
 			ModTime:    info.ModTime(),
 			Parent:     node,
 			IsExpanded: ft.expandedPaths[fullPath],
+			IsChecked:  ft.checked[fullPath],
 			Level:      node.Level + 1,
+			RealPath:   resolvePath(fullPath),
 		}
 		
-		// Calculate stats for directories
+		// Symlinks back into an ancestor are shown but never descended into
+		if child.IsDir && hasAncestorPath(node, child.RealPath) {
+			child.IsCycle = true
+			child.IsExpanded = false
+			node.Children = append(node.Children, child)
+			continue
+		}
+		
+		// Directory stats are calculated lazily; reuse any already known
 		if child.IsDir {
-			ft.calculateStats(child)
+			if stats, ok := ft.statsCache[child.Path]; ok {
+				ft.applyStats(child, stats)
+			}
 			
 			// Load children if expanded
 			if child.IsExpanded {
@@ -165,20 +199,32 @@ func (ft *FolderTree) loadChildren(node *FolderNode) error {
 	return nil
 }
 
-// calculateStats calculates statistics for a directory
-func (ft *FolderTree) calculateStats(node *FolderNode) {
-	if !node.IsDir {
-		return
+// resolvePath returns the path with symlinks resolved, or the path itself on error
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
 	}
-	
-	stats, err := ft.GetFolderStats(node.Path)
-	if err != nil {
-		return
+	return path
+}
+
+// hasAncestorPath reports whether node or any of its parents resolves to realPath
+func hasAncestorPath(node *FolderNode, realPath string) bool {
+	for current := node; current != nil; current = current.Parent {
+		if current.RealPath == realPath {
+			return true
+		}
 	}
-	
+	return false
+}
+
+// applyStats records a directory's stats on its node and in the cache
+func (ft *FolderTree) applyStats(node *FolderNode, stats *FolderStats) {
+	ft.statsCache[node.Path] = stats
 	node.FileCount = stats.TotalFiles
 	node.DirCount = stats.TotalDirectories
 	node.Size = stats.TotalSize
+	node.StatsLoaded = true
+	node.statsPending = false
 }
 
 // GetFolderStats calculates comprehensive statistics for a folder
@@ -264,7 +310,7 @@ func (ft *FolderTree) sortChildren(children []*FolderNode) {
 
 // ExpandNode expands a directory node
 func (ft *FolderTree) ExpandNode(node *FolderNode) error {
-	if !node.IsDir || node.IsExpanded {
+	if !node.IsDir || node.IsExpanded || node.IsCycle {
 		return nil
 	}
 	
@@ -323,22 +369,93 @@ func (ft *FolderTree) GetSelectedNode() *FolderNode {
 	return ft.selectedNode
 }
 
+// ToggleChecked adds a node to the multi-selection or removes it
+func (ft *FolderTree) ToggleChecked(node *FolderNode) {
+	ft.setChecked(node, !node.IsChecked)
+}
+
+// ToggleCheckedChildren checks every entry of a directory, or every sibling
+// of a file; if they are all checked already, it unchecks them instead
+func (ft *FolderTree) ToggleCheckedChildren(node *FolderNode) error {
+	parent := node
+	if !node.IsDir || node.IsCycle {
+		parent = node.Parent
+	}
+	if parent == nil {
+		return nil
+	}
+	if !parent.IsExpanded {
+		if err := ft.ExpandNode(parent); err != nil {
+			return err
+		}
+	}
+	
+	allChecked := true
+	for _, child := range parent.Children {
+		if !child.IsChecked {
+			allChecked = false
+			break
+		}
+	}
+	for _, child := range parent.Children {
+		ft.setChecked(child, !allChecked)
+	}
+	return nil
+}
+
+// setChecked updates a node's checkbox and the remembered selection
+func (ft *FolderTree) setChecked(node *FolderNode, checked bool) {
+	node.IsChecked = checked
+	if checked {
+		ft.checked[node.Path] = true
+	} else {
+		delete(ft.checked, node.Path)
+	}
+}
+
+// CheckedPaths returns the multi-selected paths in sorted order
+func (ft *FolderTree) CheckedPaths() []string {
+	paths := make([]string, 0, len(ft.checked))
+	for path := range ft.checked {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ClearChecked empties the multi-selection
+func (ft *FolderTree) ClearChecked() {
+	for _, node := range ft.GetVisibleNodes() {
+		node.IsChecked = false
+	}
+	ft.checked = make(map[string]bool)
+}
+
 // GetVisibleNodes returns all currently visible nodes in display order
 func (ft *FolderTree) GetVisibleNodes() []*FolderNode {
 	var nodes []*FolderNode
-	ft.collectVisibleNodes(ft.root, &nodes)
+	ft.collectVisibleNodes(ft.root, &nodes, make(map[string]bool))
 	return nodes
 }
 
-// collectVisibleNodes recursively collects visible nodes
-func (ft *FolderTree) collectVisibleNodes(node *FolderNode, nodes *[]*FolderNode) {
+// collectVisibleNodes recursively collects visible nodes. The resolved paths
+// on the current branch are tracked so a cyclic tree cannot recurse forever.
+func (ft *FolderTree) collectVisibleNodes(node *FolderNode, nodes *[]*FolderNode, onBranch map[string]bool) {
 	*nodes = append(*nodes, node)
 	
-	if node.IsExpanded {
-		for _, child := range node.Children {
-			ft.collectVisibleNodes(child, nodes)
-		}
+	key := node.RealPath
+	if key == "" {
+		key = node.Path
 	}
+	if !node.IsExpanded || node.IsCycle || onBranch[key] {
+		return
+	}
+	
+	onBranch[key] = true
+	for _, child := range node.Children {
+		ft.collectVisibleNodes(child, nodes, onBranch)
+	}
+	delete(onBranch, key)
 }
 
 // SetSortType changes the sorting method
@@ -353,8 +470,15 @@ func (ft *FolderTree) SetShowHidden(show bool) error {
 	return ft.refreshTree()
 }
 
+// SetFollowSymlinks toggles treating symlinked directories as directories
+func (ft *FolderTree) SetFollowSymlinks(follow bool) error {
+	ft.followSymlinks = follow
+	return ft.refreshTree()
+}
+
 // refreshTree rebuilds the tree with current settings
 func (ft *FolderTree) refreshTree() error {
+	ft.statsCache = make(map[string]*FolderStats)
 	return ft.buildTree()
 }
 
@@ -396,6 +520,9 @@ func (ft *FolderTree) findNodeByPath(node *FolderNode, path string) *FolderNode
 	if node.Path == path {
 		return node
 	}
+	if node.IsCycle {
+		return nil
+	}
 	
 	for _, child := range node.Children {
 		if found := ft.findNodeByPath(child, path); found != nil {
@@ -439,8 +566,17 @@ func RenderTreeLine(node *FolderNode, isSelected bool, width int) string {
 	indent := strings.Repeat("  ", node.Level)
 	result.WriteString(indent)
 	
+	// Checkbox for the multi-selection
+	if node.IsChecked {
+		result.WriteString("☑ ")
+	} else {
+		result.WriteString("☐ ")
+	}
+	
 	// Add expansion indicator for directories
-	if node.IsDir {
+	if node.IsCycle {
+		result.WriteString("↻ ")
+	} else if node.IsDir {
 		if node.IsExpanded {
 			result.WriteString("▼ ")
 		} else {
@@ -465,7 +601,9 @@ func RenderTreeLine(node *FolderNode, isSelected bool, width int) string {
 	result.WriteString(name)
 	
 	// Add stats for directories
-	if node.IsDir && (node.FileCount > 0 || node.DirCount > 0) {
+	if node.IsDir && node.statsPending {
+		result.WriteString(" (calculating…)")
+	} else if node.IsDir && (node.FileCount > 0 || node.DirCount > 0) {
 		stats := fmt.Sprintf(" (%s, %s files)", 
 			FormatSize(node.Size), 
 			FormatCount(node.FileCount))
@@ -0,0 +1,214 @@
+package gitdiff
+
+import (
+	"bytes"
+	stdcontext "context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"ai-context-cli/internal/context"
+)
+
+// ErrNoGit is returned when the git command is not installed
+var ErrNoGit = errors.New("git is not installed")
+
+// ChangesError is a failure to collect the changes, such as an unknown ref or
+// a directory outside any repository, as opposed to one generating their context
+type ChangesError struct {
+	Err error
+}
+
+func (e *ChangesError) Error() string { return e.Err.Error() }
+
+func (e *ChangesError) Unwrap() error { return e.Err }
+
+// Ref is a revision offered as the base of a diff
+type Ref struct {
+	Name    string
+	Kind    string // head, branch or commit
+	Subject string // commit message subject, for commits
+}
+
+// run executes git in dir and returns its output, or its error message on failure
+func run(dir string, args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", ErrNoGit
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", errors.New(message)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Root returns the top of the work tree containing dir
+func Root(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	return filepath.FromSlash(strings.TrimSpace(out)), nil
+}
+
+// Refs lists the bases to choose from: HEAD, the local branches and the most
+// recent commits
+func Refs(root string, commits int) ([]Ref, error) {
+	refs := []Ref{{Name: "HEAD", Kind: "head", Subject: "uncommitted changes"}}
+
+	branches, err := run(root, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range strings.Fields(branches) {
+		refs = append(refs, Ref{Name: branch, Kind: "branch"})
+	}
+
+	// A repository without commits has no log
+	log, err := run(root, "log", fmt.Sprintf("-n%d", commits), "--format=%h%x09%s")
+	if err != nil {
+		return refs, nil
+	}
+	for _, line := range strings.Split(strings.TrimSpace(log), "\n") {
+		hash, subject, ok := strings.Cut(line, "\t")
+		if ok {
+			refs = append(refs, Ref{Name: hash, Kind: "commit", Subject: subject})
+		}
+	}
+	return refs, nil
+}
+
+// statusNames maps git's name-status letters to ChangedFile statuses
+var statusNames = map[byte]string{
+	'A': "added",
+	'M': "modified",
+	'D': "deleted",
+	'R': "renamed",
+	'T': "modified",
+}
+
+// Changes returns the files under dir that differ from ref, staged or not,
+// each with its diff, followed by untracked files. dir may be anywhere in the
+// work tree; changes outside it are left out. Paths are absolute.
+func Changes(dir, ref string) ([]context.ChangedFile, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid ref %q", ref)
+	}
+	if _, err := run(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown ref %q", ref)
+	}
+
+	out, err := run(dir, "diff", "--name-status", "-z", "-M", "--relative", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	entries := parseNameStatus(out)
+
+	// One diff for the whole change set, split into a patch per file. Both
+	// listings come from the same comparison, so they are in the same order;
+	// should they ever disagree, each file's diff is asked for on its own.
+	patch, err := run(dir, "diff", "-M", "--relative", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	diffs := splitPatch(patch)
+
+	var changes []context.ChangedFile
+	for i, entry := range entries {
+		diff := ""
+		if len(diffs) == len(entries) {
+			diff = diffs[i]
+		} else if diff, err = run(dir, append([]string{"diff", "-M", "--relative", ref, "--"}, entry.paths...)...); err != nil {
+			return nil, err
+		}
+		name, ok := statusNames[entry.status]
+		if !ok {
+			name = "modified"
+		}
+		changes = append(changes, context.ChangedFile{
+			Path:   filepath.Join(dir, filepath.FromSlash(entry.paths[len(entry.paths)-1])),
+			Status: name,
+			Diff:   diff,
+		})
+	}
+
+	untracked, err := run(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(strings.TrimSuffix(untracked, "\x00"), "\x00") {
+		if path != "" {
+			changes = append(changes, context.ChangedFile{Path: filepath.Join(dir, filepath.FromSlash(path)), Status: "untracked"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// nameStatus is one file in git's --name-status listing
+type nameStatus struct {
+	status byte
+	paths  []string // the old then the new path for renames and copies
+}
+
+// parseNameStatus reads the output of git diff --name-status -z
+func parseNameStatus(out string) []nameStatus {
+	var entries []nameStatus
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		entry := nameStatus{status: fields[i][0], paths: []string{fields[i+1]}}
+		if entry.status == 'R' || entry.status == 'C' {
+			// Renames and copies list the old path, then the new one
+			if i+2 >= len(fields) {
+				break
+			}
+			i++
+			entry.paths = append(entry.paths, fields[i+1])
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// splitPatch splits a multi-file diff into one patch per file
+func splitPatch(patch string) []string {
+	var diffs []string
+	for len(patch) > 0 {
+		next := strings.Index(patch[1:], "\ndiff --git ")
+		if next < 0 {
+			diffs = append(diffs, patch)
+			break
+		}
+		diffs = append(diffs, patch[:next+2])
+		patch = patch[next+2:]
+	}
+	return diffs
+}
+
+// Context generates the context for the files under config.RootPath changed
+// since ref. Changes elsewhere in the repository are left out, so a caller
+// restricted to the root never reads past it.
+func Context(ctx stdcontext.Context, generator *context.ContextGenerator, config context.ScanConfig, ref string) (*context.ContextResult, error) {
+	if _, err := Root(config.RootPath); err != nil {
+		return nil, &ChangesError{err}
+	}
+	changes, err := Changes(config.RootPath, ref)
+	if err != nil {
+		return nil, &ChangesError{err}
+	}
+	if len(changes) == 0 {
+		return nil, &ChangesError{fmt.Errorf("no changes since %s", ref)}
+	}
+	return generator.GenerateDiffContext(ctx, config, ref, changes, filepath.Base(config.RootPath))
+}
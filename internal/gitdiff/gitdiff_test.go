@@ -0,0 +1,108 @@
+package gitdiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRepo creates a repository with one commit holding files
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	for name, content := range files {
+		os.WriteFile(filepath.Join(root, name), []byte(content), 0644)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "initial commit")
+	return root
+}
+
+func TestChangesSinceRef(t *testing.T) {
+	root := gitRepo(t, map[string]string{
+		"main.go":   "package main\n",
+		"old.go":    "package main\n\nfunc old() {}\n",
+		"README.md": "# demo\n",
+	})
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.Remove(filepath.Join(root, "old.go"))
+	os.WriteFile(filepath.Join(root, "new.go"), []byte("package main\n"), 0644)
+
+	// The temp dir may sit behind a symlink, which git resolves
+	top, err := Root(root)
+	if err != nil {
+		t.Fatalf("Root failed: %v", err)
+	}
+	if expected, _ := filepath.EvalSymlinks(root); top != expected {
+		t.Errorf("Expected root %s, got %s", expected, top)
+	}
+
+	changes, err := Changes(root, "HEAD")
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, change := range changes {
+		statuses[filepath.Base(change.Path)] = change.Status
+	}
+	if statuses["main.go"] != "modified" || statuses["old.go"] != "deleted" || statuses["new.go"] != "untracked" {
+		t.Errorf("Expected modified, deleted and untracked files, got %v", statuses)
+	}
+	if _, ok := statuses["README.md"]; ok {
+		t.Error("Expected unchanged files to be left out")
+	}
+	for _, change := range changes {
+		if filepath.Base(change.Path) == "main.go" && (!strings.Contains(change.Diff, "+func main() {}") || strings.Contains(change.Diff, "old.go")) {
+			t.Errorf("Expected the diff of main.go alone, got %q", change.Diff)
+		}
+		if filepath.Base(change.Path) == "old.go" && (!strings.Contains(change.Diff, "-func old() {}") || strings.Contains(change.Diff, "main.go")) {
+			t.Errorf("Expected the diff of old.go alone, got %q", change.Diff)
+		}
+	}
+
+	// A subdirectory sees only the changes inside it
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	os.WriteFile(filepath.Join(root, "sub", "inner.go"), []byte("package sub\n"), 0644)
+	changes, err = Changes(filepath.Join(root, "sub"), "HEAD")
+	if err != nil {
+		t.Fatalf("Changes in a subdirectory failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != filepath.Join(root, "sub", "inner.go") {
+		t.Errorf("Expected only sub/inner.go, got %+v", changes)
+	}
+
+	if _, err := Changes(root, "no-such-branch"); err == nil {
+		t.Error("Expected an unknown ref to be rejected")
+	}
+	if _, err := Changes(root, "--output=/tmp/x"); err == nil {
+		t.Error("Expected a ref that looks like an option to be rejected")
+	}
+}
+
+func TestRefsListsBranchesAndCommits(t *testing.T) {
+	root := gitRepo(t, map[string]string{"main.go": "package main\n"})
+
+	refs, err := Refs(root, 5)
+	if err != nil {
+		t.Fatalf("Refs failed: %v", err)
+	}
+	if len(refs) != 3 || refs[0].Name != "HEAD" || refs[1].Name != "main" || refs[1].Kind != "branch" {
+		t.Fatalf("Expected HEAD, main and one commit, got %+v", refs)
+	}
+	if refs[2].Kind != "commit" || refs[2].Subject != "initial commit" {
+		t.Errorf("Expected the initial commit, got %+v", refs[2])
+	}
+}
@@ -0,0 +1,301 @@
+// Package highlight colors code in the context preview. It is a small
+// tokenizer covering the fence languages the generator emits; it keeps to
+// lipgloss so the preview gains colors without a lexer dependency such as
+// chroma, which can replace it if richer highlighting is ever wanted.
+package highlight
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Token styles, in the app's palette
+var (
+	keywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4")).Bold(true)
+	stringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981"))
+	numberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B"))
+	commentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Italic(true)
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	hunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#3B82F6"))
+)
+
+// syntax is just enough of a language to color keywords, strings, numbers and comments
+type syntax struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockStart   string
+	blockEnd     string
+	quotes       string // characters that open a string
+	multiline    string // quotes whose strings may span lines, e.g. Go raw strings
+}
+
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(list) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	cStyle    = syntax{lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: `"'`}
+	hashStyle = syntax{lineComments: []string{"#"}, quotes: `"'`}
+)
+
+// with returns a copy of s with a keyword list and extra quote characters
+func (s syntax) with(keywords, multiline string) syntax {
+	s.keywords = words(keywords)
+	s.quotes += multiline
+	s.multiline = multiline
+	return s
+}
+
+// languages maps code fence languages to their syntax
+var languages = map[string]syntax{
+	"go":         cStyle.with("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota", "`"),
+	"javascript": cStyle.with("async await break case catch class const continue debugger default delete do else export extends finally for function if import in instanceof let new of return static super switch this throw try typeof var void while yield null undefined true false", "`"),
+	"typescript": cStyle.with("abstract as async await break case catch class const continue declare default delete do else enum export extends finally for from function if implements import in instanceof interface keyof let namespace new of private protected public readonly return static super switch this throw try type typeof var void while yield null undefined true false", "`"),
+	"java":       cStyle.with("abstract assert boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long native new package private protected public return short static super switch synchronized this throw throws try void volatile while null true false var record", ""),
+	"kotlin":     cStyle.with("as break class continue do else false for fun if in interface is null object package return super this throw true try typealias val var when while data sealed override private public internal", ""),
+	"c":          cStyle.with("auto break case char const continue default do double else enum extern float for goto if int long register return short signed sizeof static struct switch typedef union unsigned void volatile while NULL #include #define", ""),
+	"cpp":        cStyle.with("auto bool break case catch char class const constexpr continue default delete do double else enum explicit extern false float for friend if inline int long namespace new nullptr operator private protected public return short signed sizeof static struct switch template this throw true try typedef typename union unsigned using virtual void volatile while #include #define", ""),
+	"csharp":     cStyle.with("abstract as async await base bool break case catch class const continue default delegate do double else enum event false finally for foreach if in int interface internal is namespace new null object out override private protected public readonly ref return sealed static string struct switch this throw true try using var virtual void while", ""),
+	"rust":       cStyle.with("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while", ""),
+	"swift":      cStyle.with("as break case catch class continue default defer do else enum extension false for func guard if import in init let nil protocol return self static struct switch throw throws true try var where while", ""),
+	"dart":       cStyle.with("abstract async await break case catch class const continue default do else enum extends false final finally for if import in is new null return static super switch this throw true try var void while", ""),
+	"php":        cStyle.with("abstract array as break case catch class const continue default do echo else elseif extends false final for foreach function if implements interface namespace new null private protected public return static switch throw true try use var while", ""),
+	"scala":      cStyle.with("abstract case catch class def do else extends false final for if implicit import lazy match new null object override package private protected return sealed super this throw trait true try type val var while with yield", ""),
+	"css":        cStyle.with("", ""),
+	"python":     hashStyle.with("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False self", ""),
+	"ruby":       hashStyle.with("alias and begin break case class def defined? do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield", ""),
+	"bash":       hashStyle.with("case do done elif else esac export fi for function if in local return select then until while", ""),
+	"r":          hashStyle.with("break else FALSE for function if in next NULL repeat return TRUE while", ""),
+	"yaml":       hashStyle.with("true false null yes no", ""),
+	"toml":       hashStyle.with("true false", ""),
+	"powershell": hashStyle.with("begin break catch continue else elseif end exit filter finally for foreach function if in param process return switch throw trap try until while", ""),
+	"sql":        {keywords: words("select from where insert into values update set delete create table alter drop index join left right inner outer on group by order having limit offset and or not null as distinct union all primary key foreign references default case when then else end SELECT FROM WHERE INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE ALTER DROP INDEX JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT OFFSET AND OR NOT NULL AS DISTINCT UNION ALL PRIMARY KEY FOREIGN REFERENCES DEFAULT CASE WHEN THEN ELSE END"), lineComments: []string{"--"}, blockStart: "/*", blockEnd: "*/", quotes: `'"`},
+	"json":       {keywords: words("true false null"), quotes: `"`},
+	"html":       {blockStart: "<!--", blockEnd: "-->", quotes: `"'`},
+}
+
+// aliases share another language's syntax
+var aliases = map[string]string{
+	"jsx": "javascript", "tsx": "typescript", "vue": "javascript", "svelte": "javascript", "js": "javascript", "ts": "typescript",
+	"kts": "kotlin", "h": "c", "hpp": "cpp", "objectivec": "c", "scss": "css", "zsh": "bash", "fish": "bash", "sh": "bash",
+	"ini": "toml", "xml": "html", "py": "python", "rb": "ruby", "rs": "rust", "yml": "yaml",
+}
+
+func lookup(language string) (syntax, bool) {
+	language = strings.ToLower(language)
+	if alias, ok := aliases[language]; ok {
+		language = alias
+	}
+	s, ok := languages[language]
+	return s, ok
+}
+
+// Supported reports whether language is colored; "diff" always is
+func Supported(language string) bool {
+	_, ok := lookup(language)
+	return ok || strings.EqualFold(language, "diff")
+}
+
+// state carries a block comment or multi-line string from one line to the next
+type state struct {
+	inComment bool
+	quote     byte // open multi-line string quote, or 0
+}
+
+// highlighter colors lines of one code block
+type highlighter struct {
+	diff   bool
+	syntax syntax
+	known  bool
+	state  state
+}
+
+func newHighlighter(language string) *highlighter {
+	s, ok := lookup(language)
+	return &highlighter{diff: strings.EqualFold(language, "diff"), syntax: s, known: ok}
+}
+
+// line colors one line and advances the block state; render=false only advances
+func (h *highlighter) line(text string, render bool) string {
+	if h.diff {
+		return diffLine(text, render)
+	}
+	if !h.known {
+		return text
+	}
+
+	var out strings.Builder
+	emit := func(style lipgloss.Style, token string) {
+		if render {
+			out.WriteString(style.Render(token))
+		}
+	}
+	plain := func(token string) {
+		if render {
+			out.WriteString(token)
+		}
+	}
+
+	s := h.syntax
+	i := 0
+	for i < len(text) {
+		switch {
+		case h.state.inComment:
+			end := strings.Index(text[i:], s.blockEnd)
+			if end < 0 {
+				emit(commentStyle, text[i:])
+				return out.String()
+			}
+			emit(commentStyle, text[i:i+end+len(s.blockEnd)])
+			i += end + len(s.blockEnd)
+			h.state.inComment = false
+		case h.state.quote != 0:
+			end := closingQuote(text, i, h.state.quote)
+			if end < 0 {
+				emit(stringStyle, text[i:])
+				return out.String()
+			}
+			emit(stringStyle, text[i:end+1])
+			i = end + 1
+			h.state.quote = 0
+		case s.blockStart != "" && strings.HasPrefix(text[i:], s.blockStart):
+			h.state.inComment = true
+			emit(commentStyle, s.blockStart)
+			i += len(s.blockStart)
+		case hasAnyPrefix(text[i:], s.lineComments):
+			emit(commentStyle, text[i:])
+			return out.String()
+		case strings.IndexByte(s.quotes, text[i]) >= 0:
+			quote := text[i]
+			end := closingQuote(text, i+1, quote)
+			if end < 0 {
+				if strings.IndexByte(s.multiline, quote) >= 0 {
+					h.state.quote = quote
+				}
+				emit(stringStyle, text[i:])
+				return out.String()
+			}
+			emit(stringStyle, text[i:end+1])
+			i = end + 1
+		case isDigit(text[i]) && (i == 0 || !isWordByte(text[i-1])):
+			end := i
+			for end < len(text) && (isWordByte(text[end]) || text[end] == '.') {
+				end++
+			}
+			emit(numberStyle, text[i:end])
+			i = end
+		case isWordByte(text[i]) || text[i] == '#':
+			end := i + 1
+			for end < len(text) && (isWordByte(text[end]) || text[end] == '?') {
+				end++
+			}
+			if s.keywords[text[i:end]] {
+				emit(keywordStyle, text[i:end])
+			} else {
+				plain(text[i:end])
+			}
+			i = end
+		default:
+			plain(text[i : i+1])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// diffLine colors added, removed and hunk header lines
+func diffLine(text string, render bool) string {
+	if !render {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(text, "+++") || strings.HasPrefix(text, "---"):
+		return commentStyle.Render(text)
+	case strings.HasPrefix(text, "+"):
+		return addedStyle.Render(text)
+	case strings.HasPrefix(text, "-"):
+		return removedStyle.Render(text)
+	case strings.HasPrefix(text, "@@"):
+		return hunkStyle.Render(text)
+	}
+	return text
+}
+
+// closingQuote finds the unescaped quote closing a string from position i
+func closingQuote(text string, i int, quote byte) int {
+	for ; i < len(text); i++ {
+		if text[i] == '\\' && quote != '`' {
+			i++
+			continue
+		}
+		if text[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b)) || isDigit(b)
+}
+
+// Code colors a block of code in language; unknown languages come back unchanged
+func Code(language, code string) string {
+	h := newHighlighter(language)
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		lines[i] = h.line(line, true)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown returns lines[from:to] of a Markdown document with the code inside
+// fenced blocks colored by the fence language. Lines before from are only
+// scanned, so a window of a long document costs little more than its own lines.
+func Markdown(lines []string, from, to int) []string {
+	if to > len(lines) {
+		to = len(lines)
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	var block *highlighter
+	result := make([]string, 0, to-from)
+	for i := 0; i < to; i++ {
+		line := lines[i]
+		render := i >= from
+		switch {
+		case block == nil && strings.HasPrefix(line, "```"):
+			block = newHighlighter(strings.TrimSpace(strings.TrimPrefix(line, "```")))
+		case block != nil && line == "```":
+			block = nil
+		case block != nil && (block.known || block.diff):
+			if colored := block.line(line, render); render {
+				line = colored
+			}
+		}
+		if render {
+			result = append(result, line)
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func init() {
+	// Tests don't run in a terminal, so colors are dropped; tag each style
+	// instead so the tests can still tell the tokens apart
+	tag := func(style *lipgloss.Style, name string) {
+		*style = style.Transform(func(s string) string { return "<" + name + ">" + s + "</" + name + ">" })
+	}
+	tag(&keywordStyle, "keyword")
+	tag(&stringStyle, "string")
+	tag(&numberStyle, "number")
+	tag(&commentStyle, "comment")
+	tag(&addedStyle, "added")
+	tag(&removedStyle, "removed")
+	tag(&hunkStyle, "hunk")
+}
+
+func TestCodeColorsTokens(t *testing.T) {
+	got := Code("go", `func main() { return "hi" } // done`)
+
+	for _, want := range []string{
+		keywordStyle.Render("func"),
+		keywordStyle.Render("return"),
+		stringStyle.Render(`"hi"`),
+		commentStyle.Render("// done"),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, keywordStyle.Render("main")) {
+		t.Errorf("Expected identifiers to stay plain, got %q", got)
+	}
+}
+
+func TestCodeLeavesUnknownLanguagesAlone(t *testing.T) {
+	code := "func main() {}"
+	if got := Code("brainfuck", code); got != code {
+		t.Errorf("Expected unknown languages unchanged, got %q", got)
+	}
+	if Supported("brainfuck") || !Supported("go") {
+		t.Error("Expected Supported to report known languages only")
+	}
+}
+
+func TestMarkdownOnlyColorsFencedCode(t *testing.T) {
+	lines := []string{
+		"return is a word here",
+		"```python",
+		"return None",
+		"```",
+		"return again",
+	}
+
+	got := Markdown(lines, 0, len(lines))
+	if len(got) != len(lines) {
+		t.Fatalf("Expected %d lines, got %d", len(lines), len(got))
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if got[i] != lines[i] {
+			t.Errorf("Expected line %d unchanged, got %q", i, got[i])
+		}
+	}
+	if !strings.Contains(got[2], keywordStyle.Render("return")) {
+		t.Errorf("Expected the fenced line colored, got %q", got[2])
+	}
+}
+
+func TestMarkdownWindowKeepsBlockState(t *testing.T) {
+	lines := []string{
+		"```go",
+		"/* a long",
+		"comment that spans",
+		"lines */ var x = 1",
+		"```",
+	}
+
+	got := Markdown(lines, 2, 4)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(got))
+	}
+	if got[0] != commentStyle.Render("comment that spans") {
+		t.Errorf("Expected the comment to carry into the window, got %q", got[0])
+	}
+	if !strings.Contains(got[1], keywordStyle.Render("var")) {
+		t.Errorf("Expected code after the comment colored, got %q", got[1])
+	}
+}
+
+func TestMarkdownColorsDiffs(t *testing.T) {
+	lines := []string{"```diff", "@@ -1 +1 @@", "-old", "+new", " same", "```"}
+
+	got := Markdown(lines, 0, len(lines))
+	if got[1] != hunkStyle.Render("@@ -1 +1 @@") || got[2] != removedStyle.Render("-old") ||
+		got[3] != addedStyle.Render("+new") || got[4] != " same" {
+		t.Errorf("Unexpected diff colors: %q", got)
+	}
+}
@@ -0,0 +1,201 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"ai-context-cli/pkg/types"
+)
+
+// service names the entries this tool keeps in the system keychain
+const service = "ai-context-cli"
+
+// ErrNotFound is returned when no key is stored for a provider
+var ErrNotFound = errors.New("no key stored")
+
+// ErrUnavailable is returned when there is no system keychain to use
+var ErrUnavailable = errors.New("no system keychain available")
+
+// Keyring stores provider API keys outside the config file
+type Keyring interface {
+	Available() bool
+	Get(provider string) (string, error)
+	Set(provider, key string) error
+	Delete(provider string) error
+}
+
+// Source says where a provider's key was found
+type Source string
+
+const (
+	SourceNone    Source = ""
+	SourceKeyring Source = "keychain"
+	SourceEnv     Source = "env"
+	SourceConfig  Source = "config"
+)
+
+// envVars lists the environment variables checked for each provider's key
+var envVars = map[string][]string{
+	"openai":     {"OPENAI_API_KEY"},
+	"anthropic":  {"ANTHROPIC_API_KEY"},
+	"google":     {"GEMINI_API_KEY", "GOOGLE_API_KEY"},
+	"azure":      {"AZURE_OPENAI_API_KEY"},
+	"openrouter": {"OPENROUTER_API_KEY"},
+}
+
+// Providers returns the providers that take an API key
+func Providers() []string {
+	return []string{"openai", "anthropic", "google", "azure", "openrouter"}
+}
+
+// EnvVar returns the main environment variable holding a provider's key
+func EnvVar(provider string) string {
+	if names := envVars[provider]; len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// commandKeyring drives the platform keychain command line tool
+type commandKeyring struct {
+	name string
+}
+
+// System detects the platform keychain: the login keychain on macOS and the
+// Secret Service (via secret-tool) on Linux. Other platforms and Linux
+// sessions without a D-Bus session bus report unavailable.
+func System() Keyring {
+	switch runtime.GOOS {
+	case "darwin":
+		return commandKeyring{name: "security"}
+	case "linux", "freebsd", "openbsd":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return commandKeyring{name: "secret-tool"}
+		}
+	}
+	return commandKeyring{}
+}
+
+// Available reports whether the keychain command exists
+func (k commandKeyring) Available() bool {
+	if k.name == "" {
+		return false
+	}
+	_, err := exec.LookPath(k.name)
+	return err == nil
+}
+
+// Get reads a provider's key from the keychain
+func (k commandKeyring) Get(provider string) (string, error) {
+	if !k.Available() {
+		return "", ErrUnavailable
+	}
+	var cmd *exec.Cmd
+	if k.name == "security" {
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", provider, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "provider", provider)
+	}
+	out, err := cmd.Output()
+	key := strings.TrimRight(string(out), "\r\n")
+	if err != nil || key == "" {
+		return "", ErrNotFound
+	}
+	return key, nil
+}
+
+// Set stores or replaces a provider's key in the keychain
+func (k commandKeyring) Set(provider, key string) error {
+	if !k.Available() {
+		return ErrUnavailable
+	}
+	var cmd *exec.Cmd
+	if k.name == "security" {
+		// -w without a value, and last, makes security prompt for the key on
+		// stdin, once and then to confirm, so it never shows up in ps
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", provider, "-w")
+		cmd.Stdin = strings.NewReader(key + "\n" + key + "\n")
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label="+service+" "+provider+" API key",
+			"service", service, "provider", provider)
+		cmd.Stdin = strings.NewReader(key)
+	}
+	return commandError(cmd.CombinedOutput())
+}
+
+// Delete removes a provider's key from the keychain
+func (k commandKeyring) Delete(provider string) error {
+	if !k.Available() {
+		return ErrUnavailable
+	}
+	var cmd *exec.Cmd
+	if k.name == "security" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", service, "-a", provider)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", service, "provider", provider)
+	}
+	return commandError(cmd.CombinedOutput())
+}
+
+// commandError turns a failed command's output into its error message
+func commandError(out []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if message := strings.TrimSpace(string(out)); message != "" {
+		return errors.New(message)
+	}
+	return err
+}
+
+// Lookup finds a provider's key in the keychain, then the environment, and
+// finally the plaintext value from the config file
+func Lookup(k Keyring, provider, configured string) (string, Source) {
+	if k != nil && k.Available() {
+		if key, err := k.Get(provider); err == nil {
+			return key, SourceKeyring
+		}
+	}
+	for _, name := range envVars[provider] {
+		if key := os.Getenv(name); key != "" {
+			return key, SourceEnv
+		}
+	}
+	if configured != "" {
+		return configured, SourceConfig
+	}
+	return "", SourceNone
+}
+
+// WithKey returns the model with its API key filled in from Lookup
+func WithKey(k Keyring, model types.AIModel) types.AIModel {
+	if key, source := Lookup(k, model.Provider, model.APIKey); source != SourceNone {
+		model.APIKey = key
+	}
+	return model
+}
+
+// Mask hides all but the start and end of a key so it can be shown on screen
+func Mask(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) < 12 {
+		return strings.Repeat("•", 8)
+	}
+	return key[:3] + "…" + key[len(key)-4:]
+}
+
+// MaskIn replaces every occurrence of the keys in text with their masked form,
+// for error messages that may echo a request URL or header
+func MaskIn(text string, keys ...string) string {
+	for _, key := range keys {
+		if key != "" {
+			text = strings.ReplaceAll(text, key, Mask(key))
+		}
+	}
+	return text
+}
@@ -0,0 +1,68 @@
+package keyring
+
+import (
+	"strings"
+	"testing"
+
+	"ai-context-cli/pkg/types"
+)
+
+// memoryKeyring keeps keys in a map
+type memoryKeyring map[string]string
+
+func (k memoryKeyring) Available() bool { return true }
+func (k memoryKeyring) Get(provider string) (string, error) {
+	if key, ok := k[provider]; ok {
+		return key, nil
+	}
+	return "", ErrNotFound
+}
+func (k memoryKeyring) Set(provider, key string) error { k[provider] = key; return nil }
+func (k memoryKeyring) Delete(provider string) error   { delete(k, provider); return nil }
+
+func TestLookupPrefersKeychainThenEnvThenConfig(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-from-environment-0001")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+	keys := memoryKeyring{"anthropic": "sk-ant-from-keychain-0002"}
+
+	tests := []struct {
+		provider   string
+		configured string
+		want       string
+		source     Source
+	}{
+		{"anthropic", "plaintext", "sk-ant-from-keychain-0002", SourceKeyring},
+		{"openai", "plaintext", "sk-from-environment-0001", SourceEnv},
+		{"google", "AIza-from-config", "AIza-from-config", SourceConfig},
+		{"ollama", "", "", SourceNone},
+	}
+	for _, tt := range tests {
+		key, source := Lookup(keys, tt.provider, tt.configured)
+		if key != tt.want || source != tt.source {
+			t.Errorf("Lookup(%s) = %q from %q, want %q from %q", tt.provider, key, source, tt.want, tt.source)
+		}
+	}
+
+	model := WithKey(keys, types.AIModel{Name: "claude", Provider: "anthropic"})
+	if model.APIKey != "sk-ant-from-keychain-0002" {
+		t.Errorf("Expected WithKey to fill in the keychain key, got %q", model.APIKey)
+	}
+}
+
+func TestMaskHidesKeys(t *testing.T) {
+	key := "sk-abcdefghijklmnop1234"
+	masked := Mask(key)
+	if masked != "sk-…1234" {
+		t.Errorf("Expected the key's ends only, got %q", masked)
+	}
+	if Mask("short") != "••••••••" || Mask("") != "" {
+		t.Error("Expected short keys to be hidden entirely")
+	}
+
+	message := MaskIn(`Get "https://example.com/v1?key=`+key+`": timeout`, key)
+	if strings.Contains(message, key) || !strings.Contains(message, masked) {
+		t.Errorf("Expected the key to be masked in %q", message)
+	}
+}
@@ -0,0 +1,194 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-context-cli/internal/context"
+)
+
+// dirName is the library folder inside the config directory
+const dirName = "library"
+
+// ErrNotFound is returned when an entry id is not in the library
+var ErrNotFound = errors.New("no such saved context")
+
+// Entry describes one saved context
+type Entry struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Project    string    `json:"project"`
+	SavedAt    time.Time `json:"saved_at"`
+	Tokens     int       `json:"tokens"`
+	Files      int       `json:"files"`
+	Format     string    `json:"format,omitempty"`
+	ExportPath string    `json:"export_path,omitempty"` // file the context was exported to
+}
+
+// record is the file kept for each entry: its metadata and the context itself
+type record struct {
+	Entry   Entry                  `json:"entry"`
+	Context *context.ContextResult `json:"context"`
+}
+
+// Library keeps saved contexts as one JSON file each in a directory
+type Library struct {
+	dir string
+}
+
+// Open returns the library in a config directory; the folder is created on first save
+func Open(configDir string) *Library {
+	return &Library{dir: filepath.Join(configDir, dirName)}
+}
+
+// Dir returns the folder the library is stored in
+func (l *Library) Dir() string {
+	return l.dir
+}
+
+// path returns the file for an entry id
+func (l *Library) path(id string) string {
+	return filepath.Join(l.dir, id+".json")
+}
+
+// Add saves a context under name and returns its entry
+func (l *Library) Add(result *context.ContextResult, name, format, exportPath string) (Entry, error) {
+	if result == nil {
+		return Entry{}, fmt.Errorf("no context to save")
+	}
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return Entry{}, err
+	}
+
+	savedAt := time.Now()
+	entry := Entry{
+		ID:         l.newID(savedAt),
+		Name:       strings.TrimSpace(name),
+		Project:    result.ProjectName,
+		SavedAt:    savedAt,
+		Tokens:     result.TokenEstimate,
+		Files:      result.TotalFiles,
+		Format:     format,
+		ExportPath: exportPath,
+	}
+	if entry.Name == "" {
+		entry.Name = entry.Project
+	}
+	return entry, l.write(record{Entry: entry, Context: result})
+}
+
+// newID returns an unused id ordered by save time
+func (l *Library) newID(savedAt time.Time) string {
+	base := savedAt.Format("20060102-150405")
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(l.path(id)); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// write stores a record beside its destination and renames it into place
+func (l *Library) write(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(l.dir, rec.Entry.ID+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), l.path(rec.Entry.ID))
+}
+
+// read loads the record for an entry id
+func (l *Library) read(id string) (record, error) {
+	var rec record
+	data, err := os.ReadFile(l.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return rec, ErrNotFound
+	}
+	if err != nil {
+		return rec, err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("reading saved context %s: %w", id, err)
+	}
+	if rec.Context == nil {
+		return rec, fmt.Errorf("reading saved context %s: missing context", id)
+	}
+	return rec, nil
+}
+
+// List returns the saved contexts, newest first. Files that cannot be read are
+// skipped so one damaged entry does not hide the rest.
+func (l *Library) List() ([]Entry, error) {
+	files, err := os.ReadDir(l.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		rec, err := l.read(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, rec.Entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].SavedAt.After(entries[j].SavedAt) })
+	return entries, nil
+}
+
+// Load returns the context saved under an entry id
+func (l *Library) Load(id string) (*context.ContextResult, error) {
+	rec, err := l.read(id)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Context, nil
+}
+
+// Rename changes an entry's display name; the exported file is left alone
+func (l *Library) Rename(id, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	rec, err := l.read(id)
+	if err != nil {
+		return err
+	}
+	rec.Entry.Name = name
+	return l.write(rec)
+}
+
+// Delete removes an entry from the library; the exported file is left alone
+func (l *Library) Delete(id string) error {
+	err := os.Remove(l.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
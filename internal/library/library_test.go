@@ -0,0 +1,68 @@
+package library
+
+import (
+	"errors"
+	"testing"
+
+	"ai-context-cli/internal/context"
+)
+
+func TestLibraryAddListRenameDelete(t *testing.T) {
+	lib := Open(t.TempDir())
+	if entries, err := lib.List(); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected an empty library, got %v (%v)", entries, err)
+	}
+
+	first, err := lib.Add(&context.ContextResult{ProjectName: "api", TokenEstimate: 120, TotalFiles: 3,
+		Sections: []context.ContextSection{{Title: "Overview", Content: "# api"}}}, "api.md", "markdown", "/out/api.md")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	second, err := lib.Add(&context.ContextResult{ProjectName: "web", TokenEstimate: 80}, "", "json", "")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("Expected distinct ids, got %s twice", first.ID)
+	}
+	if second.Name != "web" {
+		t.Errorf("Expected an unnamed entry to take the project name, got %q", second.Name)
+	}
+
+	entries, err := lib.List()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected two entries, got %v (%v)", entries, err)
+	}
+	if entries[0].ID != second.ID {
+		t.Errorf("Expected the newest entry first, got %+v", entries)
+	}
+	if entries[1].Project != "api" || entries[1].Tokens != 120 || entries[1].Files != 3 || entries[1].ExportPath != "/out/api.md" {
+		t.Errorf("Expected metadata to round-trip, got %+v", entries[1])
+	}
+
+	result, err := lib.Load(first.ID)
+	if err != nil || result.Sections[0].Content != "# api" {
+		t.Fatalf("Expected the saved context back, got %+v (%v)", result, err)
+	}
+
+	if err := lib.Rename(first.ID, "  api baseline "); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if err := lib.Rename(first.ID, " "); err == nil {
+		t.Error("Expected an empty name to be rejected")
+	}
+	entries, _ = lib.List()
+	if entries[1].Name != "api baseline" {
+		t.Errorf("Expected the renamed entry, got %+v", entries[1])
+	}
+
+	if err := lib.Delete(second.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := lib.Load(second.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := lib.Delete(second.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package mcp
+
+import (
+	"bufio"
+	stdcontext "context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/library"
+	"ai-context-cli/internal/server"
+)
+
+// ProtocolVersion is the MCP revision the server implements
+const ProtocolVersion = "2024-11-05"
+
+// resourcePrefix starts the URI of every saved context
+const resourcePrefix = "context://library/"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Options configures the MCP server
+type Options struct {
+	Name    string // reported to clients in serverInfo
+	Version string
+	// Root, when set, is the only directory tree the tool may scan
+	Root string
+	// LibraryDir is the config directory whose saved contexts are listed as resources
+	LibraryDir string
+}
+
+// message is a JSON-RPC request, notification or response
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// tool describes a callable tool and its JSON Schema input
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// textContent is the only content type the tool returns
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// contextTool is the get_project_context tool
+var contextTool = tool{
+	Name:        "get_project_context",
+	Description: "Scan a project folder and return its structure and file contents as AI-ready context.",
+	InputSchema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path":              map[string]interface{}{"type": "string", "description": "Absolute path of the project folder"},
+			"format":            map[string]interface{}{"type": "string", "enum": []string{"markdown", "text", "json", "xml"}},
+			"template":          map[string]interface{}{"type": "string", "enum": []string{context.TemplateDevelopment, context.TemplateDocumentation, context.TemplateReview, context.TemplateDebug, context.TemplateFull, context.TemplateSummary}},
+			"since":             map[string]interface{}{"type": "string", "description": "Only include files changed since this git ref, with their diffs"},
+			"outline":           map[string]interface{}{"type": "string", "enum": []string{"off", "add", "only"}, "description": "Add an API outline of Go packages, or use it in place of Go file contents"},
+			"detail":            map[string]interface{}{"type": "string", "enum": []string{"full", "outline", "skeleton"}, "description": "Show code files whole, as signatures only, or with function bodies elided"},
+			"include_generated": map[string]interface{}{"type": "boolean", "description": "Keep lockfiles, generated code and duplicate copies, which are left out by default"},
+			"compress":          map[string]interface{}{"type": "string", "description": "Comma-separated compression modes: license, comments, blank-lines, data (JSON/YAML), all or none"},
+		},
+		"required": []string{"path"},
+	},
+}
+
+// toolArguments are the inputs contextTool accepts. Redaction is deliberately
+// not one of them, so neither the client nor the model can turn it off.
+type toolArguments struct {
+	Path             string `json:"path"`
+	Format           string `json:"format"`
+	Template         string `json:"template"`
+	Since            string `json:"since"`
+	Outline          string `json:"outline"`
+	Detail           string `json:"detail"`
+	Compress         string `json:"compress"`
+	IncludeGenerated bool   `json:"include_generated"`
+}
+
+// request converts the arguments to a server request
+func (a toolArguments) request() server.Request {
+	return server.Request{
+		Path:             a.Path,
+		Format:           a.Format,
+		Template:         a.Template,
+		Since:            a.Since,
+		Outline:          a.Outline,
+		Detail:           a.Detail,
+		Compress:         a.Compress,
+		IncludeGenerated: a.IncludeGenerated,
+	}
+}
+
+// Server answers MCP requests for project context
+type Server struct {
+	options Options
+}
+
+// New returns an MCP server
+func New(options Options) (*Server, error) {
+	if options.Root != "" {
+		root, err := filepath.Abs(options.Root)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = filepath.EvalSymlinks(root); err != nil {
+			return nil, err
+		}
+		options.Root = root
+	}
+	return &Server{options: options}, nil
+}
+
+// Serve reads newline-delimited JSON-RPC messages from in and writes the
+// responses to out until in is closed
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if response := s.handle([]byte(line)); response != nil {
+			if err := encoder.Encode(response); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one message, returning nil for notifications
+func (s *Server) handle(data []byte) *message {
+	var request message
+	if err := json.Unmarshal(data, &request); err != nil {
+		return &message{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, err.Error()}}
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		if request.ID == nil {
+			return nil
+		}
+		return &message{JSONRPC: "2.0", ID: request.ID, Error: &rpcError{codeInvalidRequest, "not a JSON-RPC 2.0 request"}}
+	}
+
+	result, err := s.call(request.Method, request.Params)
+	if request.ID == nil {
+		// Notifications such as notifications/initialized get no reply
+		return nil
+	}
+	response := &message{JSONRPC: "2.0", ID: request.ID, Result: result}
+	if err != nil {
+		var callErr *rpcError
+		if !errors.As(err, &callErr) {
+			callErr = &rpcError{codeInvalidParams, err.Error()}
+		}
+		response.Result, response.Error = nil, callErr
+	}
+	return response
+}
+
+// call dispatches a method to its handler
+func (s *Server) call(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"resources": map[string]interface{}{},
+			},
+			"serverInfo": map[string]string{"name": s.options.Name, "version": s.options.Version},
+		}, nil
+	case "ping", "notifications/initialized", "notifications/cancelled":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": []tool{contextTool}}, nil
+	case "tools/call":
+		return s.callTool(params)
+	case "resources/list":
+		return s.listResources()
+	case "resources/read":
+		return s.readResource(params)
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", method)}
+}
+
+// callTool runs get_project_context; failures are reported as tool errors so
+// the model can see them and retry
+func (s *Server) callTool(params json.RawMessage) (interface{}, error) {
+	var call struct {
+		Name      string        `json:"name"`
+		Arguments toolArguments `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, err
+	}
+	if call.Name != contextTool.Name {
+		return nil, fmt.Errorf("unknown tool %q", call.Name)
+	}
+
+	root, err := server.ResolvePath(s.options.Root, call.Arguments.Path)
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	generated, err := server.Generate(stdcontext.Background(), root, call.Arguments.request(), server.Redaction{})
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: generated.Content}}}, nil
+}
+
+// listResources lists the saved contexts in the library, newest first
+func (s *Server) listResources() (interface{}, error) {
+	resources := []resource{}
+	if s.options.LibraryDir != "" {
+		entries, err := library.Open(s.options.LibraryDir).List()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			resources = append(resources, resource{
+				URI:         resourcePrefix + entry.ID,
+				Name:        entry.Name,
+				Description: fmt.Sprintf("%s, %d files, ~%d tokens, saved %s", entry.Project, entry.Files, entry.Tokens, entry.SavedAt.Format("2006-01-02 15:04")),
+				MimeType:    "text/markdown",
+			})
+		}
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+// readResource returns a saved context as Markdown
+func (s *Server) readResource(params json.RawMessage) (interface{}, error) {
+	var read struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &read); err != nil {
+		return nil, err
+	}
+	id := strings.TrimPrefix(read.URI, resourcePrefix)
+	if id == read.URI || id == "" || strings.ContainsAny(id, `/\`) || s.options.LibraryDir == "" {
+		return nil, fmt.Errorf("unknown resource %q", read.URI)
+	}
+
+	result, err := library.Open(s.options.LibraryDir).Load(id)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", read.URI, err)
+	}
+	return map[string]interface{}{
+		"contents": []resourceContents{{URI: read.URI, MimeType: "text/markdown", Text: result.Markdown()}},
+	}, nil
+}
@@ -268,4 +268,16 @@ var (
 			{Title: "Model Selection", Active: true},
 		},
 	}
+	
+	ChatScreen = Screen{
+		ID:       "chat",
+		Title:    "Chat",
+		ParentID: "main_menu",
+		Path:     []string{"Context Engine", "Chat"},
+		ShowBack: true,
+		Breadcrumbs: []Breadcrumb{
+			{Title: "Context Engine", Active: false},
+			{Title: "Chat", Active: true},
+		},
+	}
 )
\ No newline at end of file
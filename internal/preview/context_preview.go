@@ -2,11 +2,15 @@ package preview
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/highlight"
+	"ai-context-cli/internal/usage"
+	"ai-context-cli/pkg/types"
 )
 
 // ContextPreviewModel represents the context preview interface
@@ -20,6 +24,18 @@ type ContextPreviewModel struct {
 	editMode        bool
 	templateMode    bool
 	currentTemplate int
+	fileJumpMode    bool
+	fileJumpCursor  int
+	sizeListMode    bool
+	sizeListCursor  int
+	fileToggleMode  bool
+	toggleCursor    int
+	compressMode    bool
+	compressCursor  int
+	content         textViewport // scrolls the current section in full view
+	highlight       bool         // color code blocks by language
+	minimalChrome   bool         // leave the key hints out of the footer
+	truncateAt      int // characters shown before content is collapsed
 	
 	// UI state
 	width        int
@@ -34,6 +50,42 @@ type ContextPreviewModel struct {
 	
 	// Available templates
 	templates []ContextTemplate
+	
+	// Target model used for budget checks
+	model *types.AIModel
+	
+	// Output format used when the context is saved
+	formatter context.Formatter
+	
+	// Deleted sections, most recent last, so they can be restored
+	deleted []deletedSection
+	
+	// Per-file toggles by section title, holding each section as generated
+	fileToggles map[string]*fileToggle
+	
+	// Sections before the first template was applied; each template starts from these
+	baseSections []context.ContextSection
+	
+	// Template last applied, if any, how much of each code file is shown and
+	// how it is compressed; all are reapplied to baseSections when one changes
+	applied     *ContextTemplate
+	detail      context.ContentDetail
+	compression context.Compression
+	
+	// Tokens each compression mode would save, estimated before compressing
+	savings []context.CompressionSaving
+}
+
+// fileToggle is a content section as generated and the files left out of it
+type fileToggle struct {
+	original context.ContextSection
+	excluded map[string]bool
+}
+
+// deletedSection is a section removed from the preview and where it was
+type deletedSection struct {
+	section context.ContextSection
+	index   int
 }
 
 // ViewportInfo tracks what's currently visible
@@ -48,8 +100,13 @@ type ContextTemplate struct {
 	Description string
 	Template    string
 	Icon        string
+	SizeFactor  float64 // Estimated fraction of the full context kept
+	Preamble    string  // Instructions prepended to the context when applied
 }
 
+// preambleSectionTitle marks the section holding an applied template's preamble
+const preambleSectionTitle = "Instructions"
+
 // PreviewMsg represents messages for the preview system
 type PreviewMsg struct {
 	Type string
@@ -64,6 +121,29 @@ type TokenEstimate struct {
 	Cost       float64
 }
 
+// SectionSize is one section's share of the context
+type SectionSize struct {
+	Index      int
+	Title      string
+	Characters int
+	Tokens     int
+	Percent    float64 // share of all characters in the context
+}
+
+// defaultTruncateAt is the number of characters shown before content is collapsed
+const defaultTruncateAt = 500
+
+const (
+	// minContentHeight keeps the full view usable on short terminals
+	minContentHeight = 5
+	// previewChromeLines is roughly how many lines the header, section title,
+	// padding, file list, scroll indicator and footer take around the content
+	previewChromeLines = 18
+)
+
+// mouseWheelStep is how many lines one wheel notch scrolls the section
+const mouseWheelStep = 3
+
 // NewContextPreviewModel creates a new context preview model
 func NewContextPreviewModel(contextResult *context.ContextResult, scanResult *context.ScanResult) *ContextPreviewModel {
 	templates := getDefaultTemplates()
@@ -75,6 +155,8 @@ func NewContextPreviewModel(contextResult *context.ContextResult, scanResult *co
 		height:         20,
 		templates:      templates,
 		currentSection: 0,
+		truncateAt:     defaultTruncateAt,
+		highlight:      true,
 		viewport: ViewportInfo{
 			offset: 0,
 			size:   15,
@@ -89,40 +171,103 @@ func getDefaultTemplates() []ContextTemplate {
 			Name:        "Development Focus",
 			Description: "Optimized for code development and debugging",
 			Template:    "development",
+			Preamble:    "Help me develop and debug the following project.",
+			SizeFactor:  0.9,
 			Icon:        "💻",
 		},
 		{
 			Name:        "Documentation",
 			Description: "Focused on generating documentation",
-			Template:    "documentation", 
+			Template:    "documentation",
+			Preamble:    "Write clear documentation for the following code. The READMEs and the public declarations of each file are included; describe what the API offers and how to use it.",
+			SizeFactor:  0.4,
 			Icon:        "📚",
 		},
 		{
 			Name:        "Code Review",
 			Description: "Structured for code review and analysis",
 			Template:    "review",
+			Preamble:    "Review the following code for bugs and style issues. Start with the diff when one is included, then check error handling, edge cases, naming and test coverage, and list findings by severity with file and line.",
+			SizeFactor:  0.8,
 			Icon:        "🔍",
 		},
 		{
 			Name:        "Bug Analysis",
 			Description: "Targeted for debugging and issue resolution",
 			Template:    "debug",
+			Preamble:    "Analyze the following code to find the root cause of the reported issue. Tests and logs come first; use them to narrow down where the behaviour goes wrong.",
+			SizeFactor:  0.7,
 			Icon:        "🐛",
 		},
 		{
 			Name:        "Full Context",
 			Description: "Complete project context with all details",
 			Template:    "full",
+			SizeFactor:  1.0,
 			Icon:        "📋",
 		},
+		{
+			Name:        "Summary Only",
+			Description: "Project overview and structure without file contents",
+			Template:    "summary",
+			Icon:        "📝",
+			SizeFactor:  0.1,
+		},
+	}
+}
+
+// SetCustomTemplates merges user-defined templates into the built-in list.
+// A custom template with a built-in's name replaces it; others are appended.
+func (m *ContextPreviewModel) SetCustomTemplates(custom []types.ContextTemplate) {
+	templates := getDefaultTemplates()
+	for _, definition := range custom {
+		template := templateFromConfig(definition)
+		
+		replaced := false
+		for i := range templates {
+			if templates[i].Name == template.Name {
+				templates[i] = template
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			templates = append(templates, template)
+		}
+	}
+	
+	m.templates = templates
+	if m.currentTemplate >= len(templates) {
+		m.currentTemplate = 0
 	}
 }
 
+// templateFromConfig converts a configured template into a preview template
+func templateFromConfig(definition types.ContextTemplate) ContextTemplate {
+	template := ContextTemplate{
+		Name:        definition.Name,
+		Description: definition.Description,
+		Template:    definition.ID,
+		Icon:        definition.Icon,
+		SizeFactor:  definition.SizeFactor,
+		Preamble:    definition.Instructions(),
+	}
+	if template.Icon == "" {
+		template.Icon = "🧩"
+	}
+	if template.SizeFactor <= 0 {
+		template.SizeFactor = 1.0
+	}
+	return template
+}
+
 // Update handles preview messages and key events
 func (m *ContextPreviewModel) Update(msg tea.Msg) (*ContextPreviewModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+	case tea.MouseMsg:
+		m.handleMouse(msg)
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -134,6 +279,24 @@ func (m *ContextPreviewModel) Update(msg tea.Msg) (*ContextPreviewModel, tea.Cmd
 	return m, nil
 }
 
+// handleMouse scrolls the current section with the wheel, showing it in full
+// since truncated content has nothing to scroll
+func (m *ContextPreviewModel) handleMouse(msg tea.MouseMsg) {
+	if m.editMode || m.templateMode || m.fileJumpMode || m.sizeListMode || m.fileToggleMode || m.compressMode ||
+		len(m.contextResult.Sections) == 0 {
+		return
+	}
+	
+	m.syncContent()
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.content.ScrollBy(-mouseWheelStep)
+	case tea.MouseButtonWheelDown:
+		m.showFullContent = true
+		m.content.ScrollBy(mouseWheelStep)
+	}
+}
+
 // handleKeyPress processes keyboard input
 func (m *ContextPreviewModel) handleKeyPress(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
 	if m.editMode {
@@ -144,6 +307,26 @@ func (m *ContextPreviewModel) handleKeyPress(msg tea.KeyMsg) (*ContextPreviewMod
 		return m.handleTemplateMode(msg)
 	}
 	
+	if m.fileJumpMode {
+		return m.handleFileJumpMode(msg)
+	}
+	
+	if m.sizeListMode {
+		return m.handleSizeListMode(msg)
+	}
+	
+	if m.fileToggleMode {
+		return m.handleFileToggleMode(msg)
+	}
+	
+	if m.compressMode {
+		return m.handleCompressMode(msg)
+	}
+	
+	if m.showFullContent && m.handleScrollKey(msg.String()) {
+		return m, nil
+	}
+	
 	switch msg.String() {
 	case "esc":
 		// Exit preview mode
@@ -161,30 +344,76 @@ func (m *ContextPreviewModel) handleKeyPress(msg tea.KeyMsg) (*ContextPreviewMod
 	case "left", "h":
 		if m.currentSection > 0 {
 			m.currentSection--
+			m.content.GotoTop()
 		}
 	case "right", "l":
 		if m.currentSection < len(m.contextResult.Sections)-1 {
 			m.currentSection++
+			m.content.GotoTop()
 		}
 	case "enter", " ":
 		m.showFullContent = !m.showFullContent
 	case "e":
-		// Enter edit mode
-		m.editMode = true
-		if m.currentSection < len(m.contextResult.Sections) {
-			m.originalContent = m.contextResult.Sections[m.currentSection].Content
-			m.editingContent = m.originalContent
+		// Enter edit mode; there is nothing to edit once every section is deleted
+		if len(m.contextResult.Sections) == 0 {
+			break
 		}
+		m.clampSelection()
+		m.editMode = true
+		m.originalContent = m.contextResult.Sections[m.currentSection].Content
+		m.editingContent = m.originalContent
 	case "t":
 		// Enter template mode
 		m.templateMode = true
 		m.currentTemplate = 0
+	case "f":
+		// Enter file jump mode
+		if len(m.getEmbeddedFiles()) > 0 {
+			m.fileJumpMode = true
+			m.fileJumpCursor = 0
+		}
+	case "c":
+		// List sections by size
+		m.sizeListMode = true
+		m.sizeListCursor = 0
+	case "i":
+		// Include or exclude the current section's files
+		if len(m.toggleableFiles()) > 0 {
+			m.fileToggleMode = true
+			m.toggleCursor = 0
+		}
 	case "r":
 		// Refresh context
 		return m, m.refreshContext()
 	case "s":
 		// Save current context
 		return m, m.saveContext()
+	case "H":
+		// Colors can be noisy on some terminals
+		m.highlight = !m.highlight
+	case "o":
+		// Cycle the output format used for saving
+		m.formatter = context.NextFormatter(m.Formatter())
+	case "m":
+		// Cycle code files between full, outline and skeleton
+		m.detail = context.NextContentDetail(m.detail)
+		m.rebuildSections()
+	case "x":
+		// Choose compression modes by what each would save
+		if m.savings == nil {
+			base, _ := m.uncompressedResult()
+			m.savings = context.CompressionSavings(base)
+		}
+		m.compressMode = true
+		m.compressCursor = 0
+	case "K", "shift+up":
+		m.moveSection(-1)
+	case "J", "shift+down":
+		m.moveSection(1)
+	case "d", "delete":
+		m.deleteSection()
+	case "u":
+		m.restoreSection()
 	case "home":
 		m.cursor = 0
 		m.currentSection = 0
@@ -192,12 +421,55 @@ func (m *ContextPreviewModel) handleKeyPress(msg tea.KeyMsg) (*ContextPreviewMod
 	case "end":
 		m.cursor = len(m.contextResult.Sections) - 1
 		m.currentSection = len(m.contextResult.Sections) - 1
+		m.clampSelection()
 		m.updateViewport()
 	}
 	
 	return m, nil
 }
 
+// handleScrollKey scrolls the full section view, reporting whether key was a scroll key
+func (m *ContextPreviewModel) handleScrollKey(key string) bool {
+	m.syncContent()
+	switch key {
+	case "up", "k":
+		m.content.ScrollBy(-1)
+	case "down", "j":
+		m.content.ScrollBy(1)
+	case "pgup", "b":
+		m.content.PageUp()
+	case "pgdown":
+		m.content.PageDown()
+	case "ctrl+u":
+		m.content.HalfPageUp()
+	case "ctrl+d":
+		m.content.HalfPageDown()
+	case "g", "home":
+		m.content.GotoTop()
+	case "G", "end":
+		m.content.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+// contentHeight is how many lines of a section the full view shows
+func (m *ContextPreviewModel) contentHeight() int {
+	if height := m.height - previewChromeLines; height > minContentHeight {
+		return height
+	}
+	return minContentHeight
+}
+
+// syncContent points the viewport at the current section at the current height
+func (m *ContextPreviewModel) syncContent() {
+	if m.currentSection < len(m.contextResult.Sections) {
+		m.content.SetContent(m.contextResult.Sections[m.currentSection].Content)
+	}
+	m.content.SetHeight(m.contentHeight())
+}
+
 // handleEditMode processes input in edit mode
 func (m *ContextPreviewModel) handleEditMode(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
 	switch msg.String() {
@@ -207,9 +479,10 @@ func (m *ContextPreviewModel) handleEditMode(msg tea.KeyMsg) (*ContextPreviewMod
 		m.editingContent = ""
 		m.originalContent = ""
 	case "ctrl+s":
-		// Save edit
+		// Save edit; the edited content becomes the section's baseline for file toggles
 		if m.currentSection < len(m.contextResult.Sections) {
 			m.contextResult.Sections[m.currentSection].Content = m.editingContent
+			delete(m.fileToggles, m.contextResult.Sections[m.currentSection].Title)
 		}
 		m.editMode = false
 		m.editingContent = ""
@@ -253,6 +526,192 @@ func (m *ContextPreviewModel) handleTemplateMode(msg tea.KeyMsg) (*ContextPrevie
 	return m, nil
 }
 
+// handleFileJumpMode processes input in file jump mode
+func (m *ContextPreviewModel) handleFileJumpMode(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
+	files := m.getEmbeddedFiles()
+	
+	switch msg.String() {
+	case "esc":
+		// Cancel file jump
+		m.fileJumpMode = false
+	case "up", "k":
+		if m.fileJumpCursor > 0 {
+			m.fileJumpCursor--
+		}
+	case "down", "j":
+		if m.fileJumpCursor < len(files)-1 {
+			m.fileJumpCursor++
+		}
+	case "enter", " ":
+		if m.fileJumpCursor < len(files) {
+			m.jumpToFile(files[m.fileJumpCursor])
+		}
+		m.fileJumpMode = false
+	}
+	
+	return m, nil
+}
+
+// toggleableFiles returns the current section's files as generated, whether
+// currently included or not
+func (m *ContextPreviewModel) toggleableFiles() []context.FileDocument {
+	if m.currentSection >= len(m.contextResult.Sections) {
+		return nil
+	}
+	section := m.contextResult.Sections[m.currentSection]
+	if toggle, ok := m.fileToggles[section.Title]; ok {
+		return toggle.original.Documents
+	}
+	return section.Documents
+}
+
+// handleFileToggleMode processes input in the per-file include list
+func (m *ContextPreviewModel) handleFileToggleMode(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
+	files := m.toggleableFiles()
+	
+	switch msg.String() {
+	case "esc", "i":
+		m.fileToggleMode = false
+	case "up", "k":
+		if m.toggleCursor > 0 {
+			m.toggleCursor--
+		}
+	case "down", "j":
+		if m.toggleCursor < len(files)-1 {
+			m.toggleCursor++
+		}
+	case "enter", " ":
+		if m.toggleCursor < len(files) {
+			m.toggleFile(files[m.toggleCursor].Path)
+		}
+	}
+	
+	return m, nil
+}
+
+// toggleFile includes or excludes one file of the current section and
+// rebuilds the section's content from the generated original
+func (m *ContextPreviewModel) toggleFile(path string) {
+	section := &m.contextResult.Sections[m.currentSection]
+	if m.fileToggles == nil {
+		m.fileToggles = make(map[string]*fileToggle)
+	}
+	toggle, ok := m.fileToggles[section.Title]
+	if !ok {
+		toggle = &fileToggle{original: *section, excluded: make(map[string]bool)}
+		m.fileToggles[section.Title] = toggle
+	}
+	
+	toggle.excluded[path] = !toggle.excluded[path]
+	*section = toggle.original.WithoutFiles(toggle.excluded)
+	m.content.GotoTop()
+	m.updateTokenEstimate()
+}
+
+// fileExcluded reports whether a file of the current section is toggled off
+func (m *ContextPreviewModel) fileExcluded(path string) bool {
+	if m.currentSection >= len(m.contextResult.Sections) {
+		return false
+	}
+	toggle, ok := m.fileToggles[m.contextResult.Sections[m.currentSection].Title]
+	return ok && toggle.excluded[path]
+}
+
+// handleSizeListMode processes input in the section size list
+func (m *ContextPreviewModel) handleSizeListMode(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
+	sizes := m.sectionSizesBySize()
+	
+	switch msg.String() {
+	case "esc", "c":
+		m.sizeListMode = false
+	case "up", "k":
+		if m.sizeListCursor > 0 {
+			m.sizeListCursor--
+		}
+	case "down", "j":
+		if m.sizeListCursor < len(sizes)-1 {
+			m.sizeListCursor++
+		}
+	case "enter", " ":
+		// Jump to the selected section
+		if m.sizeListCursor < len(sizes) {
+			m.currentSection = sizes[m.sizeListCursor].Index
+			m.content.GotoTop()
+		}
+		m.sizeListMode = false
+	}
+	
+	return m, nil
+}
+
+// handleCompressMode processes input in the compression list
+func (m *ContextPreviewModel) handleCompressMode(msg tea.KeyMsg) (*ContextPreviewModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "x":
+		m.compressMode = false
+	case "up", "k":
+		if m.compressCursor > 0 {
+			m.compressCursor--
+		}
+	case "down", "j":
+		if m.compressCursor < len(context.CompressionModes)-1 {
+			m.compressCursor++
+		}
+	case "enter", " ":
+		m.compression ^= context.CompressionModes[m.compressCursor]
+		m.rebuildSections()
+	}
+	
+	return m, nil
+}
+
+// getEmbeddedFiles returns the union of files embedded across all sections
+func (m *ContextPreviewModel) getEmbeddedFiles() []string {
+	var files []string
+	seen := make(map[string]bool)
+	
+	for _, section := range m.contextResult.Sections {
+		for _, file := range section.Files {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	
+	return files
+}
+
+// jumpToFile moves to the section containing the file and scrolls to its header
+func (m *ContextPreviewModel) jumpToFile(file string) bool {
+	for i, section := range m.contextResult.Sections {
+		for _, f := range section.Files {
+			if f != file {
+				continue
+			}
+			
+			m.currentSection = i
+			m.cursor = i
+			m.showFullContent = true
+			m.syncContent()
+			m.content.GotoTop()
+			
+			// Scroll the content to the file's header line at any header level
+			for lineNum, line := range strings.Split(section.Content, "\n") {
+				if strings.HasPrefix(line, "#") && strings.TrimLeft(line, "#") == " "+file {
+					m.content.GotoLine(lineNum)
+					break
+				}
+			}
+			
+			m.updateViewport()
+			return true
+		}
+	}
+	
+	return false
+}
+
 // updateViewport adjusts the viewport to keep cursor visible
 func (m *ContextPreviewModel) updateViewport() {
 	m.viewport.size = m.height - 8 // Reserve space for header and footer
@@ -287,11 +746,25 @@ func (m *ContextPreviewModel) View() string {
 		result.WriteString("\n\n")
 	}
 	
+	// Context window hint
+	if hint := m.renderBudgetHint(); hint != "" {
+		result.WriteString(hint)
+		result.WriteString("\n\n")
+	}
+	
 	// Content based on mode
 	if m.editMode {
 		result.WriteString(m.renderEditMode())
 	} else if m.templateMode {
 		result.WriteString(m.renderTemplateMode())
+	} else if m.fileJumpMode {
+		result.WriteString(m.renderFileJumpMode())
+	} else if m.sizeListMode {
+		result.WriteString(m.renderSizeListMode())
+	} else if m.fileToggleMode {
+		result.WriteString(m.renderFileToggleMode())
+	} else if m.compressMode {
+		result.WriteString(m.renderCompressMode())
 	} else {
 		result.WriteString(m.renderContextPreview())
 	}
@@ -321,9 +794,48 @@ func (m *ContextPreviewModel) renderHeader() string {
 		formatNumber(estimate.Tokens),
 		estimate.Cost)
 	
+	if gauge := m.renderBudgetGauge(); gauge != "" {
+		return headerStyle.Render(header + "\n" + gauge)
+	}
 	return headerStyle.Render(header)
 }
 
+// budgetUsage returns the estimated tokens as a fraction of the model's window
+func (m *ContextPreviewModel) budgetUsage() (float64, bool) {
+	if m.model == nil || m.model.MaxTokens <= 0 {
+		return 0, false
+	}
+	return float64(m.calculateTokenEstimate().Tokens) / float64(m.model.MaxTokens), true
+}
+
+// renderBudgetGauge renders a bar showing context size against the model's window
+func (m *ContextPreviewModel) renderBudgetGauge() string {
+	usage, ok := m.budgetUsage()
+	if !ok {
+		return ""
+	}
+	
+	const gaugeWidth = 20
+	filled := int(usage*gaugeWidth + 0.5)
+	if filled > gaugeWidth {
+		filled = gaugeWidth
+	}
+	
+	color := "#10B981"
+	if usage > 1 {
+		color = "#EF4444"
+	} else if usage > 0.8 {
+		color = "#F59E0B"
+	}
+	
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).
+		Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#374151")).
+		Render(strings.Repeat("░", gaugeWidth-filled))
+	
+	return fmt.Sprintf("Budget %s %.0f%% of %s", bar, usage*100, m.model.Name)
+}
+
 // renderContextPreview renders the main context preview
 func (m *ContextPreviewModel) renderContextPreview() string {
 	var result strings.Builder
@@ -335,16 +847,23 @@ func (m *ContextPreviewModel) renderContextPreview() string {
 		result.WriteString(emptyStyle.Render("No context sections available"))
 		return result.String()
 	}
+	if m.currentSection < 0 || m.currentSection >= len(m.contextResult.Sections) {
+		m.clampSelection()
+	}
 	
 	// Section navigation
 	sectionNavStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#3B82F6")).
 		Bold(true)
 	
-	navText := fmt.Sprintf("Section %d/%d: %s", 
+	size := m.SectionSizes()[m.currentSection]
+	navText := fmt.Sprintf("Section %d/%d: %s · %s chars · ~%s tokens (%.0f%%)", 
 		m.currentSection+1, 
 		len(m.contextResult.Sections),
-		m.contextResult.Sections[m.currentSection].Title)
+		m.contextResult.Sections[m.currentSection].Title,
+		formatNumber(size.Characters),
+		formatNumber(size.Tokens),
+		size.Percent)
 	result.WriteString(sectionNavStyle.Render(navText))
 	result.WriteString("\n\n")
 	
@@ -356,12 +875,33 @@ func (m *ContextPreviewModel) renderContextPreview() string {
 		Padding(1, 2)
 	
 	content := section.Content
-	if !m.showFullContent && len(content) > 500 {
-		content = content[:500] + "...\n\nPress ENTER to show full content"
+	if m.showFullContent {
+		// Render from a copy so View doesn't move the viewport
+		view := m.content
+		view.SetContent(content)
+		view.SetHeight(m.contentHeight())
+		window := view.View()
+		if m.highlight {
+			// Highlighting only the window keeps long sections fast
+			from, to := view.Window()
+			window = strings.Join(highlight.Markdown(view.lines, from, to), "\n")
+		}
+		result.WriteString(contentStyle.Render(window))
+		result.WriteString("\n")
+		result.WriteString(renderScrollIndicator(&view))
+	} else {
+		indicator := ""
+		if len(content) > m.truncateAt {
+			content = content[:m.truncateAt]
+			indicator = "...\n\n" + truncationIndicator(m.truncateAt, len(section.Content))
+		}
+		if m.highlight {
+			lines := strings.Split(content, "\n")
+			content = strings.Join(highlight.Markdown(lines, 0, len(lines)), "\n")
+		}
+		result.WriteString(contentStyle.Render(content + indicator))
 	}
 	
-	result.WriteString(contentStyle.Render(content))
-	
 	// Section metadata
 	if len(section.Files) > 0 {
 		result.WriteString("\n\n")
@@ -447,6 +987,178 @@ func (m *ContextPreviewModel) renderTemplateMode() string {
 	return result.String()
 }
 
+// renderFileJumpMode renders the embedded file selection interface
+func (m *ContextPreviewModel) renderFileJumpMode() string {
+	var result strings.Builder
+	
+	fileHeaderStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	result.WriteString(fileHeaderStyle.Render("📄 Jump to File"))
+	result.WriteString("\n\n")
+	
+	files := m.getEmbeddedFiles()
+	
+	// Keep the file cursor visible within the available height
+	size := m.height - 8
+	if size < 1 {
+		size = 1
+	}
+	start := 0
+	if m.fileJumpCursor >= size {
+		start = m.fileJumpCursor - size + 1
+	}
+	end := start + size
+	if end > len(files) {
+		end = len(files)
+	}
+	
+	for i := start; i < end; i++ {
+		var fileStyle lipgloss.Style
+		if i == m.fileJumpCursor {
+			fileStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			fileStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		result.WriteString(fileStyle.Render(files[i]))
+		result.WriteString("\n")
+	}
+	
+	return result.String()
+}
+
+// renderFileToggleMode renders the current section's files with their include state
+func (m *ContextPreviewModel) renderFileToggleMode() string {
+	var result strings.Builder
+	
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	result.WriteString(headerStyle.Render(fmt.Sprintf("🗂️ Files in %s", m.contextResult.Sections[m.currentSection].Title)))
+	result.WriteString("\n\n")
+	
+	files := m.toggleableFiles()
+	
+	// Keep the cursor visible within the available height
+	size := m.height - 8
+	if size < 1 {
+		size = 1
+	}
+	start := 0
+	if m.toggleCursor >= size {
+		start = m.toggleCursor - size + 1
+	}
+	end := start + size
+	if end > len(files) {
+		end = len(files)
+	}
+	
+	for i := start; i < end; i++ {
+		file := files[i]
+		mark := "[x]"
+		color := "#374151"
+		if m.fileExcluded(file.Path) {
+			mark = "[ ]"
+			color = "#6B7280"
+		}
+		line := fmt.Sprintf("%s %s · ~%s tokens", mark, file.Path, formatNumber(len(file.Content)/4))
+		
+		fileStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(color)).
+			Padding(0, 1)
+		if i == m.toggleCursor {
+			fileStyle = fileStyle.
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true)
+		}
+		result.WriteString(fileStyle.Render(line))
+		result.WriteString("\n")
+	}
+	
+	return result.String()
+}
+
+// renderSizeListMode renders the sections from largest to smallest with their share
+func (m *ContextPreviewModel) renderSizeListMode() string {
+	var result strings.Builder
+	
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	result.WriteString(headerStyle.Render("📏 Section Sizes"))
+	result.WriteString("\n\n")
+	
+	for i, size := range m.sectionSizesBySize() {
+		var sizeStyle lipgloss.Style
+		if i == m.sizeListCursor {
+			sizeStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			sizeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		line := fmt.Sprintf("%-32s %10s chars  ~%8s tokens  %5.1f%%",
+			size.Title, formatNumber(size.Characters), formatNumber(size.Tokens), size.Percent)
+		result.WriteString(sizeStyle.Render(line))
+		result.WriteString("\n")
+	}
+	
+	return result.String()
+}
+
+// renderCompressMode lists the compression modes with the tokens each would save
+func (m *ContextPreviewModel) renderCompressMode() string {
+	var result strings.Builder
+	
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#3B82F6"))
+	
+	result.WriteString(headerStyle.Render("🗜️ Compression"))
+	result.WriteString("\n\n")
+	
+	for i, saving := range m.savings {
+		var modeStyle lipgloss.Style
+		if i == m.compressCursor {
+			modeStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("#3B82F6")).
+				Foreground(lipgloss.Color("#FFFFFF")).
+				Bold(true).
+				Padding(0, 1)
+		} else {
+			modeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#374151")).
+				Padding(0, 1)
+		}
+		
+		check := "[ ]"
+		if m.compression.Has(saving.Mode) {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %-24s saves ~%s tokens", check, saving.Mode.Description(), formatNumber(saving.Tokens))
+		result.WriteString(modeStyle.Render(line))
+		result.WriteString("\n")
+	}
+	
+	return result.String()
+}
+
 // renderFooter renders the footer with controls and statistics
 func (m *ContextPreviewModel) renderFooter() string {
 	var result strings.Builder
@@ -468,6 +1180,9 @@ func (m *ContextPreviewModel) renderFooter() string {
 	
 	result.WriteString(statsStyle.Render(stats))
 	result.WriteString("\n")
+	if m.minimalChrome {
+		return result.String()
+	}
 	
 	// Instructions
 	instructionStyle := lipgloss.NewStyle().
@@ -479,8 +1194,23 @@ func (m *ContextPreviewModel) renderFooter() string {
 		instructions = "Edit mode active"
 	} else if m.templateMode {
 		instructions = "↑↓: select template • Enter: apply • ESC: cancel"
+	} else if m.fileJumpMode {
+		instructions = "↑↓: select file • Enter: jump • ESC: cancel"
+	} else if m.sizeListMode {
+		instructions = "↑↓: select section • Enter: jump • ESC: close"
+	} else if m.fileToggleMode {
+		instructions = "↑↓: select file • Space/Enter: include or exclude • ESC: close"
+	} else if m.compressMode {
+		instructions = "↑↓: select mode • Space/Enter: toggle • ESC: close"
 	} else {
-		instructions = "←→: navigate sections • Enter: toggle full view • E: edit • T: templates • S: save • R: refresh • ESC: exit"
+		highlighting := "on"
+		if !m.highlight {
+			highlighting = "off"
+		}
+		instructions = "←→: navigate sections • Enter: toggle full view • J/K: move section • D: delete section • I: include/exclude files • H: highlighting (" + highlighting + ") • E: edit • T: templates • F: jump to file • C: section sizes • S: save • O: format (" + m.Formatter().Name() + ") • M: detail (" + m.detail.String() + ") • X: compression (" + m.compression.String() + ") • R: refresh • ESC: exit"
+		if len(m.deleted) > 0 {
+			instructions += fmt.Sprintf(" • U: restore (%d deleted)", len(m.deleted))
+		}
 	}
 	
 	result.WriteString(instructionStyle.Render(instructions))
@@ -500,8 +1230,11 @@ func (m *ContextPreviewModel) calculateTokenEstimate() TokenEstimate {
 	// Rough token estimation (1 token ≈ 4 characters for GPT models)
 	estimatedTokens := totalChars / 4
 	
-	// Rough cost estimation (assuming GPT-4 pricing)
-	costPer1KTokens := 0.03 // $0.03 per 1K tokens (input)
+	// Rough cost estimation at the model's configured input price
+	costPer1KTokens := usage.DefaultCostPer1KTokens
+	if m.model != nil {
+		costPer1KTokens = m.model.TokenCost(costPer1KTokens)
+	}
 	estimatedCost := float64(estimatedTokens) / 1000.0 * costPer1KTokens
 	
 	return TokenEstimate{
@@ -512,6 +1245,108 @@ func (m *ContextPreviewModel) calculateTokenEstimate() TokenEstimate {
 	}
 }
 
+// SectionSizes reports each section's characters, estimated tokens and share of the total,
+// using the same 4-characters-per-token estimate as the header
+func (m *ContextPreviewModel) SectionSizes() []SectionSize {
+	totalChars := m.calculateTokenEstimate().Characters
+	
+	sizes := make([]SectionSize, len(m.contextResult.Sections))
+	for i, section := range m.contextResult.Sections {
+		chars := len(section.Content)
+		sizes[i] = SectionSize{
+			Index:      i,
+			Title:      section.Title,
+			Characters: chars,
+			Tokens:     chars / 4,
+		}
+		if totalChars > 0 {
+			sizes[i].Percent = float64(chars) / float64(totalChars) * 100
+		}
+	}
+	return sizes
+}
+
+// sectionSizesBySize returns the section sizes, largest first
+func (m *ContextPreviewModel) sectionSizesBySize() []SectionSize {
+	sizes := m.SectionSizes()
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].Characters > sizes[j].Characters
+	})
+	return sizes
+}
+
+// SetModel sets the target model used for context window checks
+func (m *ContextPreviewModel) SetModel(model types.AIModel) {
+	m.model = &model
+}
+
+// SetFormatter sets the output format used when the context is saved
+func (m *ContextPreviewModel) SetFormatter(formatter context.Formatter) {
+	m.formatter = formatter
+}
+
+// Formatter returns the selected output format, Markdown by default
+func (m *ContextPreviewModel) Formatter() context.Formatter {
+	if m.formatter == nil {
+		return context.MarkdownFormatter{}
+	}
+	return m.formatter
+}
+
+// SuggestTemplate recommends the richest template whose projected size fits
+// the model's context window. It returns false when no suggestion is needed
+// or none of the templates fit.
+func (m *ContextPreviewModel) SuggestTemplate() (ContextTemplate, bool) {
+	if m.model == nil || m.model.MaxTokens <= 0 {
+		return ContextTemplate{}, false
+	}
+	
+	tokens := m.calculateTokenEstimate().Tokens
+	if tokens <= m.model.MaxTokens {
+		return ContextTemplate{}, false
+	}
+	
+	var best ContextTemplate
+	found := false
+	for _, template := range m.templates {
+		projected := int(float64(tokens) * template.SizeFactor)
+		if projected > m.model.MaxTokens {
+			continue
+		}
+		if !found || template.SizeFactor > best.SizeFactor {
+			best = template
+			found = true
+		}
+	}
+	
+	return best, found
+}
+
+// renderBudgetHint renders a hint when the context exceeds the model window
+func (m *ContextPreviewModel) renderBudgetHint() string {
+	if m.model == nil || m.model.MaxTokens <= 0 {
+		return ""
+	}
+	
+	tokens := m.calculateTokenEstimate().Tokens
+	if tokens <= m.model.MaxTokens {
+		return ""
+	}
+	
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B")).
+		Bold(true)
+	
+	hint := fmt.Sprintf("💡 Context (~%s tokens) exceeds %s window (%s tokens)",
+		formatNumber(tokens), m.model.Name, formatNumber(m.model.MaxTokens))
+	if template, ok := m.SuggestTemplate(); ok {
+		hint += fmt.Sprintf(" • try the '%s' template (~%s tokens)",
+			template.Name, formatNumber(int(float64(tokens)*template.SizeFactor)))
+	}
+	
+	return hintStyle.Render(hint)
+}
+
 // refreshContext refreshes the context data
 func (m *ContextPreviewModel) refreshContext() tea.Cmd {
 	return func() tea.Msg {
@@ -532,8 +1367,13 @@ func (m *ContextPreviewModel) saveContext() tea.Cmd {
 	}
 }
 
-// applyTemplate applies a selected template
+// applyTemplate rewrites the sections for a template, starting from the
+// sections as they were before any template, and prepends its preamble.
+// Deleted sections and file toggles refer to the replaced sections, so they are cleared.
 func (m *ContextPreviewModel) applyTemplate(template ContextTemplate) tea.Cmd {
+	m.applied = &template
+	m.rebuildSections()
+	
 	return func() tea.Msg {
 		return PreviewMsg{
 			Type: "template_applied",
@@ -542,6 +1382,132 @@ func (m *ContextPreviewModel) applyTemplate(template ContextTemplate) tea.Cmd {
 	}
 }
 
+// rebuildSections reapplies the chosen template, content detail and
+// compression to the sections as first shown, discarding deletions and file toggles
+func (m *ContextPreviewModel) rebuildSections() {
+	result, preamble := m.uncompressedResult()
+	m.savings = context.CompressionSavings(result)
+	m.contextResult.Sections = context.ApplyCompression(result, m.compression).Sections
+	m.deleted = nil
+	m.fileToggles = nil
+	
+	m.setPreamble(preamble)
+	m.updateTokenEstimate()
+}
+
+// uncompressedResult applies the chosen template and content detail to the
+// sections as first shown, returning them before compression along with the
+// template's preamble; compression savings are always measured on these
+func (m *ContextPreviewModel) uncompressedResult() (*context.ContextResult, string) {
+	if m.baseSections == nil {
+		m.baseSections = m.contextResult.Sections
+		if len(m.baseSections) > 0 && m.baseSections[0].Title == preambleSectionTitle {
+			m.baseSections = m.baseSections[1:]
+		}
+	}
+	base := *m.contextResult
+	base.Sections = m.baseSections
+	
+	result := &base
+	preamble := ""
+	if m.applied != nil {
+		result = context.ApplyTemplate(result, m.applied.Template)
+		preamble = m.applied.Preamble
+	}
+	return context.ApplyContentDetail(result, m.detail), preamble
+}
+
+// setPreamble makes the preamble the first section, replacing one from a previous template
+func (m *ContextPreviewModel) setPreamble(preamble string) {
+	if m.contextResult == nil {
+		return
+	}
+	
+	sections := m.contextResult.Sections
+	if len(sections) > 0 && sections[0].Title == preambleSectionTitle {
+		sections = sections[1:]
+	}
+	if preamble != "" {
+		sections = append([]context.ContextSection{{
+			Title:   preambleSectionTitle,
+			Content: preamble + "\n\n",
+		}}, sections...)
+	}
+	
+	m.contextResult.Sections = sections
+	m.currentSection = 0
+	m.updateViewport()
+}
+
+// moveSection swaps the current section with its neighbour and keeps it selected
+func (m *ContextPreviewModel) moveSection(delta int) {
+	sections := m.contextResult.Sections
+	target := m.currentSection + delta
+	if m.currentSection >= len(sections) || target < 0 || target >= len(sections) {
+		return
+	}
+	
+	sections[m.currentSection], sections[target] = sections[target], sections[m.currentSection]
+	m.currentSection = target
+	m.content.GotoTop()
+}
+
+// deleteSection removes the current section, remembering it for restoreSection
+func (m *ContextPreviewModel) deleteSection() {
+	sections := m.contextResult.Sections
+	if m.currentSection < 0 || m.currentSection >= len(sections) {
+		return
+	}
+	
+	m.deleted = append(m.deleted, deletedSection{section: sections[m.currentSection], index: m.currentSection})
+	m.contextResult.Sections = append(sections[:m.currentSection:m.currentSection], sections[m.currentSection+1:]...)
+	m.clampSelection()
+	m.content.GotoTop()
+	m.updateTokenEstimate()
+}
+
+// restoreSection puts the most recently deleted section back where it was
+func (m *ContextPreviewModel) restoreSection() {
+	if len(m.deleted) == 0 {
+		return
+	}
+	last := m.deleted[len(m.deleted)-1]
+	m.deleted = m.deleted[:len(m.deleted)-1]
+	
+	sections := m.contextResult.Sections
+	index := last.index
+	if index > len(sections) {
+		index = len(sections)
+	}
+	restored := make([]context.ContextSection, 0, len(sections)+1)
+	restored = append(restored, sections[:index]...)
+	restored = append(restored, last.section)
+	m.contextResult.Sections = append(restored, sections[index:]...)
+	m.currentSection = index
+	m.clampSelection()
+	m.content.GotoTop()
+	m.updateTokenEstimate()
+}
+
+// clampSelection keeps the cursor and current section on an existing section,
+// or on 0 once every section is deleted
+func (m *ContextPreviewModel) clampSelection() {
+	last := max(0, len(m.contextResult.Sections)-1)
+	m.cursor = min(max(m.cursor, 0), last)
+	m.currentSection = min(max(m.currentSection, 0), last)
+}
+
+// DeletedSections returns how many deleted sections can be restored
+func (m *ContextPreviewModel) DeletedSections() int {
+	return len(m.deleted)
+}
+
+// updateTokenEstimate keeps the result's estimate in step with its sections,
+// counting the summary as the generator does
+func (m *ContextPreviewModel) updateTokenEstimate() {
+	m.contextResult.TokenEstimate = (m.calculateTokenEstimate().Characters + len(m.contextResult.Summary)) / 4
+}
+
 // exitPreview exits the preview mode
 func (m *ContextPreviewModel) exitPreview() tea.Cmd {
 	return func() tea.Msg {
@@ -557,6 +1523,46 @@ func (m *ContextPreviewModel) GetContextResult() *context.ContextResult {
 	return m.contextResult
 }
 
+// SetTruncateAt sets how many characters are shown before content is collapsed
+func (m *ContextPreviewModel) SetTruncateAt(chars int) {
+	if chars <= 0 {
+		chars = defaultTruncateAt
+	}
+	m.truncateAt = chars
+}
+
+// truncationIndicator describes how much of the content is visible
+func truncationIndicator(shown, total int) string {
+	percent := 0
+	if total > 0 {
+		percent = shown * 100 / total
+	}
+	return fmt.Sprintf("Showing %s of %s chars (%d%%) • %s hidden • Press ENTER to show full content",
+		formatCount(shown), formatCount(total), percent, formatCount(total-shown))
+}
+
+// renderScrollIndicator shows where the full view is within the section
+func renderScrollIndicator(view *textViewport) string {
+	end := view.offset + view.height
+	if end > len(view.lines) {
+		end = len(view.lines)
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#6B7280")).
+		Render(fmt.Sprintf("Lines %s–%s of %s (%d%%) • ↑↓/jk: scroll • PgUp/PgDn: page • g/G: top/bottom • Enter: collapse",
+			formatCount(view.offset+1), formatCount(end), formatCount(len(view.lines)), view.Percent()))
+}
+
+// SetHighlight turns syntax colors for code blocks on or off
+func (m *ContextPreviewModel) SetHighlight(enabled bool) {
+	m.highlight = enabled
+}
+
+// SetMinimalChrome leaves the key hints out of the footer for screenshots and demos
+func (m *ContextPreviewModel) SetMinimalChrome(minimal bool) {
+	m.minimalChrome = minimal
+}
+
 // SetSize updates the preview dimensions
 func (m *ContextPreviewModel) SetSize(width, height int) {
 	m.width = width
@@ -588,4 +1594,21 @@ func formatNumber(n int) string {
 	} else {
 		return fmt.Sprintf("%.1fM", float64(n)/1000000)
 	}
-}
\ No newline at end of file
+}
+
+// formatCount formats an exact count with thousands separators
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	
+	digits := fmt.Sprintf("%d", n)
+	var result strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			result.WriteRune(',')
+		}
+		result.WriteRune(digit)
+	}
+	return result.String()
+}
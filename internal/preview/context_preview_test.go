@@ -1,10 +1,14 @@
 package preview
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"ai-context-cli/internal/context"
+	"ai-context-cli/pkg/types"
 )
 
 func TestNewContextPreviewModel(t *testing.T) {
@@ -133,6 +137,12 @@ func TestCalculateTokenEstimate(t *testing.T) {
 	if estimate.Tokens != expectedTokens {
 		t.Errorf("Expected tokens %d, got %d", expectedTokens, estimate.Tokens)
 	}
+	
+	// The cost follows the selected model's price
+	model.SetModel(types.AIModel{Name: "priced", CostPer1K: 0.06})
+	if priced := model.calculateTokenEstimate().Cost; priced < 2*estimate.Cost-1e-9 || priced > 2*estimate.Cost+1e-9 {
+		t.Errorf("Expected the model's price to double the cost, got %f from %f", priced, estimate.Cost)
+	}
 }
 
 func TestUpdateViewport(t *testing.T) {
@@ -264,4 +274,656 @@ func TestTemplateNavigation(t *testing.T) {
 	if model.currentTemplate != 0 {
 		t.Errorf("Expected template to stay at 0, got %d", model.currentTemplate)
 	}
-}
\ No newline at end of file
+}
+
+func TestJumpToFile(t *testing.T) {
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Project Overview", Content: "# Project Overview\n"},
+			{
+				Title:   "GO Files Content",
+				Content: "# GO Files Content\n\n## main.go\n\n```go\npackage main\n```\n\n## util.go\n\n```go\npackage util\n```\n",
+				Files:   []string{"main.go", "util.go"},
+			},
+			{
+				Title:   "MD Files Content",
+				Content: "# MD Files Content\n\n## README.md\n\n```markdown\n# Readme\n```\n",
+				Files:   []string{"README.md"},
+			},
+		},
+	}
+	
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	
+	files := model.getEmbeddedFiles()
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 embedded files, got %d", len(files))
+	}
+	
+	// Open file jump mode and select README.md
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if !model.fileJumpMode {
+		t.Fatal("Expected file jump mode after pressing f")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	
+	if model.fileJumpMode {
+		t.Error("Expected file jump mode to close after selection")
+	}
+	
+	if model.currentSection != 2 {
+		t.Errorf("Expected current section 2, got %d", model.currentSection)
+	}
+	
+	// Jumping to util.go should scroll to its header within the section
+	if !model.jumpToFile("util.go") {
+		t.Fatal("Expected jump to util.go to succeed")
+	}
+	
+	if model.currentSection != 1 {
+		t.Errorf("Expected current section 1, got %d", model.currentSection)
+	}
+	
+	if model.content.offset != 8 {
+		t.Errorf("Expected content offset 8, got %d", model.content.offset)
+	}
+	
+	if model.jumpToFile("missing.go") {
+		t.Error("Expected jump to unknown file to fail")
+	}
+}
+
+func TestSuggestTemplateForOversizedContext(t *testing.T) {
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Big Section", Content: strings.Repeat("x", 40000)}, // ~10K tokens
+		},
+	}
+	
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	
+	// Without a model there is nothing to compare against
+	if _, ok := model.SuggestTemplate(); ok {
+		t.Error("Expected no suggestion without a target model")
+	}
+	
+	// Only the summary template fits a small window
+	model.SetModel(types.AIModel{Name: "small-model", MaxTokens: 1500})
+	template, ok := model.SuggestTemplate()
+	if !ok {
+		t.Fatal("Expected a template suggestion for oversized context")
+	}
+	if template.Name != "Summary Only" {
+		t.Errorf("Expected 'Summary Only' suggestion, got %q", template.Name)
+	}
+	
+	if !strings.Contains(model.View(), "Summary Only") {
+		t.Error("Expected preview to surface the template hint")
+	}
+	
+	// A larger window allows a richer template
+	model.SetModel(types.AIModel{Name: "medium-model", MaxTokens: 5000})
+	template, ok = model.SuggestTemplate()
+	if !ok || template.Name != "Documentation" {
+		t.Errorf("Expected 'Documentation' suggestion, got %q", template.Name)
+	}
+	
+	// No suggestion when the context already fits
+	model.SetModel(types.AIModel{Name: "large-model", MaxTokens: 100000})
+	if _, ok := model.SuggestTemplate(); ok {
+		t.Error("Expected no suggestion when context fits the model window")
+	}
+}
+
+func TestTruncationIndicatorCounts(t *testing.T) {
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Large", Content: strings.Repeat("a", 12480)},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	model.SetSize(120, 40)
+	
+	view := model.View()
+	if !strings.Contains(view, "Showing 500 of 12,480 chars (4%)") {
+		t.Error("Expected indicator with exact shown and total counts")
+	}
+	if !strings.Contains(view, "11,980 hidden") {
+		t.Error("Expected indicator to report 11,980 hidden chars")
+	}
+	
+	model.SetTruncateAt(2000)
+	if !strings.Contains(model.View(), "Showing 2,000 of 12,480 chars (16%)") {
+		t.Error("Expected indicator to follow the configured truncation limit")
+	}
+	
+	if got := formatCount(1234567); got != "1,234,567" {
+		t.Errorf("formatCount(1234567) = %q, expected 1,234,567", got)
+	}
+}
+
+func TestBudgetGaugeReportsUsage(t *testing.T) {
+	// 4,000 characters estimate to 1,000 tokens
+	contextResult := &context.ContextResult{
+		ProjectName: "gauge",
+		Sections: []context.ContextSection{
+			{Title: "Content", Content: strings.Repeat("a", 4000)},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	model.SetSize(120, 40)
+	
+	if model.renderBudgetGauge() != "" {
+		t.Error("Expected no gauge without a model")
+	}
+	
+	model.SetModel(types.AIModel{Name: "small-model", MaxTokens: 2000})
+	usage, ok := model.budgetUsage()
+	if !ok || usage < 0.49 || usage > 0.51 {
+		t.Errorf("Expected ~50%% budget usage, got %.2f", usage)
+	}
+	if !strings.Contains(model.View(), "50% of small-model") {
+		t.Error("Expected gauge with 50% in the preview header")
+	}
+	
+	model.SetModel(types.AIModel{Name: "tiny-model", MaxTokens: 500})
+	if !strings.Contains(model.renderBudgetGauge(), "200% of tiny-model") {
+		t.Error("Expected gauge to report usage past 100%")
+	}
+}
+
+func TestApplyTemplatePrependsPreamble(t *testing.T) {
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Project Overview", Content: "# Project\n"},
+			{Title: "GO Files Content", Content: "package main\n"},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	
+	var review ContextTemplate
+	for _, template := range model.templates {
+		if template.Template == "review" {
+			review = template
+		}
+	}
+	if review.Preamble == "" {
+		t.Fatal("Expected the review template to carry a preamble")
+	}
+	
+	msg := model.applyTemplate(review)()
+	if msg.(PreviewMsg).Type != "template_applied" {
+		t.Errorf("Expected template_applied message, got %v", msg)
+	}
+	
+	sections := model.GetContextResult().Sections
+	if len(sections) != 3 || !strings.HasPrefix(sections[0].Content, review.Preamble) {
+		t.Fatalf("Expected review preamble as the first section, got %+v", sections)
+	}
+	
+	// Switching templates replaces the preamble rather than stacking them
+	model.applyTemplate(model.templates[len(model.templates)-1])
+	sections = model.GetContextResult().Sections
+	if len(sections) != 2 || sections[0].Title != "Project Overview" {
+		t.Errorf("Expected preamble removed for a template without one, got %+v", sections)
+	}
+}
+
+func TestCycleContentDetail(t *testing.T) {
+	source := "package main\n\nfunc main() {\n\tprintln(\"body\")\n}"
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Project Overview", Content: "# Project\n"},
+			{
+				Title:     "GO Files Content",
+				Content:   "## GO Files Content\n\n### main.go\n\n```go\n" + source + "\n```\n\n",
+				Files:     []string{"main.go"},
+				IsContent: true,
+				Documents: []context.FileDocument{{Path: "main.go", Language: "go", Content: source}},
+			},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	content := func() string {
+		return model.GetContextResult().Sections[len(model.GetContextResult().Sections)-1].Content
+	}
+	press := func() {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	}
+	
+	press()
+	if !strings.Contains(content(), "*Signatures only*") || strings.Contains(content(), "println") {
+		t.Errorf("Expected m to show signatures only, got:\n%s", content())
+	}
+	if !strings.Contains(model.View(), "M: detail (outline)") {
+		t.Error("Expected the footer to name the detail level")
+	}
+	
+	press()
+	if !strings.Contains(content(), "func main() { … }") {
+		t.Errorf("Expected a skeleton with the body elided, got:\n%s", content())
+	}
+	
+	// Detail is reapplied on top of a template, and cycling back restores the files
+	var review ContextTemplate
+	for _, template := range model.templates {
+		if template.Template == "review" {
+			review = template
+		}
+	}
+	model.applyTemplate(review)
+	if !strings.Contains(content(), "{ … }") {
+		t.Error("Expected the skeleton to be kept when a template is applied")
+	}
+	press()
+	if !strings.Contains(content(), "println(\"body\")") {
+		t.Errorf("Expected full detail to restore the file, got:\n%s", content())
+	}
+	if !strings.HasPrefix(model.GetContextResult().Sections[0].Content, review.Preamble) {
+		t.Error("Expected the template to stay applied when the detail changes")
+	}
+}
+
+func TestCompressionPanel(t *testing.T) {
+	source := "// Copyright 2024 Example\n\npackage main\n\n// main runs.\nfunc main() {}"
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{
+				Title:     "GO Files Content",
+				Content:   "## GO Files Content\n\n### main.go\n\n```go\n" + source + "\n```\n\n",
+				Files:     []string{"main.go"},
+				IsContent: true,
+				Documents: []context.FileDocument{{Path: "main.go", Language: "go", Content: source}},
+			},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	key := func(r rune) {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	
+	key('x')
+	view := model.View()
+	if !strings.Contains(view, "Compression") || !strings.Contains(view, "[ ] Strip comments") {
+		t.Fatalf("Expected x to list the compression modes, got:\n%s", view)
+	}
+	if !strings.Contains(view, "saves ~") {
+		t.Error("Expected each mode to show its token savings")
+	}
+	
+	// Comments are the second mode
+	key('j')
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	content := model.GetContextResult().Sections[0].Content
+	if strings.Contains(content, "main runs") || !strings.Contains(content, "*Compressed: comments*") {
+		t.Errorf("Expected comments stripped and noted, got:\n%s", content)
+	}
+	if !strings.Contains(model.View(), "[x] Strip comments") {
+		t.Error("Expected the mode to be checked")
+	}
+	
+	// Toggling again restores the file
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(model.GetContextResult().Sections[0].Content, "main runs") {
+		t.Error("Expected comments back once the mode is off")
+	}
+	
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.compressMode || !strings.Contains(model.View(), "X: compression (none)") {
+		t.Error("Expected esc to close the panel and the footer to name the compression")
+	}
+	
+	// Savings are measured on the uncompressed sections, not what is shown now
+	savings := append([]context.CompressionSaving(nil), model.savings...)
+	model.savings = nil
+	model.contextResult.Sections = nil
+	key('x')
+	if len(model.savings) != len(savings) || model.savings[1] != savings[1] || savings[1].Tokens <= 0 {
+		t.Errorf("Expected savings %v from the base sections, got %v", savings, model.savings)
+	}
+}
+
+func TestSectionSizesReportTokenShare(t *testing.T) {
+	overview := strings.Repeat("a", 400)
+	content := strings.Repeat("b", 1202)
+	contextResult := &context.ContextResult{
+		ProjectName: "sizes",
+		Sections: []context.ContextSection{
+			{Title: "Overview", Content: overview},
+			{Title: "GO Files Content", Content: content},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	
+	sizes := model.SectionSizes()
+	if len(sizes) != 2 {
+		t.Fatalf("Expected 2 section sizes, got %d", len(sizes))
+	}
+	known := sizes[1]
+	if known.Characters != 1202 || known.Tokens != 1202/4 {
+		t.Errorf("Expected 1202 chars and %d tokens, got %d and %d", 1202/4, known.Characters, known.Tokens)
+	}
+	if want := 1202.0 / 1602.0 * 100; known.Percent < want-0.01 || known.Percent > want+0.01 {
+		t.Errorf("Expected %.2f%% share, got %.2f%%", want, known.Percent)
+	}
+	
+	// The section nav annotates the current section
+	if !strings.Contains(model.View(), "400 chars · ~100 tokens (25%)") {
+		t.Error("Expected the section nav to show the current section's size")
+	}
+	
+	// The size list puts the largest section first and jumps to it
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if !strings.Contains(model.View(), "Section Sizes") {
+		t.Fatal("Expected c to open the section size list")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.currentSection != 1 {
+		t.Errorf("Expected enter to jump to the largest section, got section %d", model.currentSection)
+	}
+}
+
+func TestCustomTemplateMergesAndApplies(t *testing.T) {
+	contextResult := &context.ContextResult{
+		Sections: []context.ContextSection{
+			{Title: "Project Overview", Content: "# Project\n"},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	builtIns := len(model.templates)
+	
+	model.SetCustomTemplates([]types.ContextTemplate{
+		{ID: "security", Name: "Security Audit", Preamble: "Audit the following code for security issues."},
+		{ID: "review", Name: "Code Review", Template: "Review strictly: {{.context}}", Icon: "🧐"},
+	})
+	
+	if len(model.templates) != builtIns+1 {
+		t.Fatalf("Expected one appended template and one override, got %d templates", len(model.templates))
+	}
+	var security, review ContextTemplate
+	for _, template := range model.templates {
+		switch template.Name {
+		case "Security Audit":
+			security = template
+		case "Code Review":
+			review = template
+		}
+	}
+	if review.Icon != "🧐" || review.Preamble != "Review strictly:" {
+		t.Errorf("Expected the custom Code Review to replace the built-in, got %+v", review)
+	}
+	if security.Icon == "" || security.SizeFactor != 1.0 {
+		t.Errorf("Expected defaults for unset icon and size factor, got %+v", security)
+	}
+	
+	model.applyTemplate(security)
+	sections := model.GetContextResult().Sections
+	if len(sections) != 2 || !strings.HasPrefix(sections[0].Content, "Audit the following code") {
+		t.Errorf("Expected the custom preamble as the first section, got %+v", sections)
+	}
+}
+
+func TestOutputFormatCycles(t *testing.T) {
+	contextResult := &context.ContextResult{
+		ProjectName: "formats",
+		Sections:    []context.ContextSection{{Title: "Overview", Content: "# Overview\n"}},
+	}
+	model := NewContextPreviewModel(contextResult, &context.ScanResult{})
+	
+	if model.Formatter().Name() != "markdown" {
+		t.Fatalf("Expected markdown by default, got %s", model.Formatter().Name())
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	if model.Formatter().Name() != "text" {
+		t.Errorf("Expected o to switch to text, got %s", model.Formatter().Name())
+	}
+	if !strings.Contains(model.View(), "O: format (text)") {
+		t.Error("Expected the instructions to show the selected format")
+	}
+}
+
+func TestSectionReorderDeleteAndRestore(t *testing.T) {
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections: []context.ContextSection{
+			{Title: "Overview", Content: strings.Repeat("o", 40)},
+			{Title: "Structure", Content: strings.Repeat("s", 80)},
+			{Title: "Files", Content: strings.Repeat("f", 400)},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	press := func(keys ...string) {
+		for _, key := range keys {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		}
+	}
+	titles := func() string {
+		var names []string
+		for _, section := range contextResult.Sections {
+			names = append(names, section.Title)
+		}
+		return strings.Join(names, ",")
+	}
+	
+	// Move the overview below the structure, then back to the top
+	press("J")
+	if titles() != "Structure,Overview,Files" || model.currentSection != 1 {
+		t.Errorf("Expected the section to move down and stay selected, got %s at %d", titles(), model.currentSection)
+	}
+	press("K", "K")
+	if titles() != "Overview,Structure,Files" || model.currentSection != 0 {
+		t.Errorf("Expected the section back on top, got %s at %d", titles(), model.currentSection)
+	}
+	
+	// Deleting the file contents drops their tokens from the estimate
+	press("l", "l", "d")
+	if titles() != "Overview,Structure" || model.currentSection != 1 {
+		t.Errorf("Expected the last section deleted, got %s at %d", titles(), model.currentSection)
+	}
+	if contextResult.TokenEstimate != 30 || model.calculateTokenEstimate().Tokens != 30 {
+		t.Errorf("Expected ~30 tokens after deleting, got %d", contextResult.TokenEstimate)
+	}
+	if !strings.Contains(model.View(), "U: restore (1 deleted)") {
+		t.Error("Expected the footer to offer restoring the deleted section")
+	}
+	
+	press("h", "d", "u", "u")
+	if titles() != "Overview,Structure,Files" || model.DeletedSections() != 0 {
+		t.Errorf("Expected both sections restored in place, got %s", titles())
+	}
+	if contextResult.TokenEstimate != 130 {
+		t.Errorf("Expected the estimate to include restored sections, got %d", contextResult.TokenEstimate)
+	}
+}
+
+func TestDeletingEverySectionKeepsKeysSafe(t *testing.T) {
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections: []context.ContextSection{
+			{Title: "Overview", Content: "overview"},
+			{Title: "Files", Content: "files"},
+		},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			model, _ = model.Update(key)
+			model.View()
+		}
+	}
+	runes := func(key string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)} }
+	end := tea.KeyMsg{Type: tea.KeyEnd}
+	
+	press(runes("d"), runes("d"))
+	if len(contextResult.Sections) != 0 {
+		t.Fatalf("Expected every section deleted, got %d", len(contextResult.Sections))
+	}
+	
+	press(runes("e"), end, runes("d"), end)
+	if model.editMode || model.currentSection != 0 || model.cursor != 0 {
+		t.Errorf("Expected no edit mode and the selection on 0, got edit=%v section=%d cursor=%d",
+			model.editMode, model.currentSection, model.cursor)
+	}
+	
+	press(runes("u"), end, runes("u"))
+	if len(contextResult.Sections) != 2 || model.currentSection != 0 {
+		t.Errorf("Expected both sections restored with the first selected, got %d at %d",
+			len(contextResult.Sections), model.currentSection)
+	}
+}
+
+func TestFileTogglesRebuildSection(t *testing.T) {
+	documents := []context.FileDocument{
+		{Path: "a.go", Language: "go", Content: "package a"},
+		{Path: "b.go", Language: "go", Content: strings.Repeat("b", 200)},
+	}
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections: []context.ContextSection{{
+			Title:     "Go Files",
+			Content:   "# Go Files\n\n## a.go\n\n```go\npackage a\n```\n\n## b.go\n\n```go\n" + strings.Repeat("b", 200) + "\n```\n\n",
+			Files:     []string{"a.go", "b.go"},
+			IsContent: true,
+			Documents: documents,
+		}},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			model, _ = model.Update(key)
+		}
+	}
+	before := contextResult.Sections[0].Content
+	
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")}, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeySpace})
+	section := contextResult.Sections[0]
+	if strings.Contains(section.Content, "## b.go") || len(section.Files) != 1 {
+		t.Errorf("Expected b.go to be excluded, got:\n%s", section.Content)
+	}
+	if contextResult.TokenEstimate != len(section.Content)/4 {
+		t.Errorf("Expected the estimate to follow the section, got %d", contextResult.TokenEstimate)
+	}
+	if !strings.Contains(model.View(), "[ ] b.go") {
+		t.Error("Expected the file list to show b.go as excluded")
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if contextResult.Sections[0].Content != before || len(contextResult.Sections[0].Documents) != 2 {
+		t.Error("Expected including b.go again to restore the section as generated")
+	}
+}
+
+func TestMouseWheelScrollsSection(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections:    []context.ContextSection{{Title: "Files", Content: strings.Join(lines, "\n")}},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	
+	model, _ = model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown})
+	if !model.showFullContent || model.content.offset != mouseWheelStep {
+		t.Fatalf("Expected the wheel to show the full section and scroll, got offset %d", model.content.offset)
+	}
+	if view := model.View(); strings.Contains(view, "line 3\n") || !strings.Contains(view, "line 4") {
+		t.Errorf("Expected the first lines to scroll away, got:\n%s", view)
+	}
+	
+	for i := 0; i < 50; i++ {
+		model, _ = model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown})
+	}
+	if model.content.offset != 100-model.contentHeight() {
+		t.Errorf("Expected scrolling to stop on the last page, got %d", model.content.offset)
+	}
+	for i := 0; i < 50; i++ {
+		model, _ = model.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp})
+	}
+	if model.content.offset != 0 {
+		t.Errorf("Expected scrolling back to the top, got %d", model.content.offset)
+	}
+}
+
+func TestFullViewScrollsLongSections(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 3000; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections:    []context.ContextSection{{Title: "Files", Content: strings.Join(lines, "\n") + "\n"}},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			model, _ = model.Update(key)
+		}
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+	height := 40 - previewChromeLines
+	
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	view := model.View()
+	if !strings.Contains(view, fmt.Sprintf("line %d ", height)) || strings.Contains(view, fmt.Sprintf("line %d ", height+1)) {
+		t.Errorf("Expected the first %d lines in the full view", height)
+	}
+	if !strings.Contains(view, fmt.Sprintf("Lines 1–%d of 3,000 (0%%)", height)) {
+		t.Errorf("Expected a scroll indicator, got:\n%s", view)
+	}
+	
+	press(tea.KeyMsg{Type: tea.KeyPgDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if model.content.offset != height {
+		t.Errorf("Expected a page and a line down, got offset %d", model.content.offset)
+	}
+	press(tea.KeyMsg{Type: tea.KeyPgUp}, runes("k"))
+	if model.content.offset != 0 {
+		t.Errorf("Expected to be back at the top, got offset %d", model.content.offset)
+	}
+	
+	press(runes("G"))
+	if model.content.offset != 3000-height || !strings.Contains(model.View(), "line 3000") || !strings.Contains(model.View(), "(100%)") {
+		t.Errorf("Expected G to show the last page, got offset %d", model.content.offset)
+	}
+	press(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if model.content.offset != 3000-height-(height+1)/2 {
+		t.Errorf("Expected half a page up, got offset %d", model.content.offset)
+	}
+	press(runes("g"))
+	if model.content.offset != 0 {
+		t.Errorf("Expected g to go to the top, got offset %d", model.content.offset)
+	}
+	
+	// Collapsed, the arrows go back to moving between sections
+	press(tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyDown})
+	if model.showFullContent || model.content.offset != 0 {
+		t.Error("Expected scroll keys to stay out of the collapsed view")
+	}
+}
+
+func TestHighlightToggle(t *testing.T) {
+	contextResult := &context.ContextResult{
+		ProjectName: "test-project",
+		Sections:    []context.ContextSection{{Title: "Files", Content: "```go\nfunc main() {}\n```"}},
+	}
+	model := NewContextPreviewModel(contextResult, nil)
+	if !model.highlight || !strings.Contains(model.View(), "H: highlighting (on)") {
+		t.Fatal("Expected highlighting on by default")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	if model.highlight || !strings.Contains(model.View(), "H: highlighting (off)") {
+		t.Error("Expected H to turn highlighting off")
+	}
+
+	model.SetHighlight(true)
+	if !model.highlight {
+		t.Error("Expected SetHighlight to turn highlighting back on")
+	}
+}
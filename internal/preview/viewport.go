@@ -0,0 +1,112 @@
+package preview
+
+import "strings"
+
+// textViewport shows a fixed number of lines of a longer text and scrolls through it
+type textViewport struct {
+	lines  []string
+	height int
+	offset int // first visible line
+}
+
+// SetContent replaces the text, keeping the offset in range
+func (v *textViewport) SetContent(content string) {
+	v.lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	v.clamp()
+}
+
+// SetHeight changes how many lines are visible
+func (v *textViewport) SetHeight(height int) {
+	if height < 1 {
+		height = 1
+	}
+	v.height = height
+	v.clamp()
+}
+
+// maxOffset is the offset that shows the last page
+func (v *textViewport) maxOffset() int {
+	if max := len(v.lines) - v.height; max > 0 {
+		return max
+	}
+	return 0
+}
+
+func (v *textViewport) clamp() {
+	if v.offset > v.maxOffset() {
+		v.offset = v.maxOffset()
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// ScrollBy moves the window down by delta lines, or up when negative
+func (v *textViewport) ScrollBy(delta int) {
+	limit := v.maxOffset()
+	if v.offset > limit {
+		// Past the last page after GotoLine; scrolling up shouldn't snap back
+		limit = v.offset
+	}
+	v.offset += delta
+	if v.offset > limit {
+		v.offset = limit
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// PageDown and PageUp move by a whole window, keeping one line of overlap
+func (v *textViewport) PageDown() { v.ScrollBy(v.pageSize()) }
+func (v *textViewport) PageUp()   { v.ScrollBy(-v.pageSize()) }
+
+// HalfPageDown and HalfPageUp move by half a window
+func (v *textViewport) HalfPageDown() { v.ScrollBy((v.height + 1) / 2) }
+func (v *textViewport) HalfPageUp()   { v.ScrollBy(-(v.height + 1) / 2) }
+
+func (v *textViewport) pageSize() int {
+	if v.height > 1 {
+		return v.height - 1
+	}
+	return 1
+}
+
+// GotoTop and GotoBottom jump to the first and last page
+func (v *textViewport) GotoTop()    { v.offset = 0 }
+func (v *textViewport) GotoBottom() { v.offset = v.maxOffset() }
+
+// GotoLine puts line at the top of the window, even on the last page, so a
+// jump target is always the first thing shown
+func (v *textViewport) GotoLine(line int) {
+	v.offset = line
+	if v.offset >= len(v.lines) {
+		v.offset = len(v.lines) - 1
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// Percent is how far through the text the window is, 100 on the last page
+func (v *textViewport) Percent() int {
+	if v.offset >= v.maxOffset() {
+		return 100
+	}
+	return v.offset * 100 / v.maxOffset()
+}
+
+// Window returns the range of visible lines
+func (v *textViewport) Window() (from, to int) {
+	to = v.offset + v.height
+	if to > len(v.lines) {
+		to = len(v.lines)
+	}
+	return v.offset, to
+}
+
+// View returns the visible lines
+func (v *textViewport) View() string {
+	from, to := v.Window()
+	return strings.Join(v.lines[from:to], "\n")
+}
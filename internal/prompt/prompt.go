@@ -0,0 +1,171 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"ai-context-cli/pkg/types"
+)
+
+// Data is what every template can refer to
+type Data struct {
+	ProjectName string
+	Context     string // the formatted context
+	Model       string
+	Files       int
+	Tokens      int
+	Date        time.Time
+}
+
+// builtins are the variables filled from Data; "context" is the older
+// lowercase placeholder and means the same as Context
+var builtins = map[string]bool{
+	"ProjectName": true,
+	"Context":     true,
+	"context":     true,
+	"Model":       true,
+	"Files":       true,
+	"Tokens":      true,
+	"Date":        true,
+}
+
+// Builtins lists the variables every template can use, for display
+func Builtins() []string {
+	return []string{"ProjectName", "Context", "Model", "Files", "Tokens", "Date"}
+}
+
+// fieldReferences returns the top-level fields a template refers to, in
+// order, by walking its parse tree so that {{if .X}}, {{range .X}} and
+// {{.X | f}} count too; a template that does not parse refers to nothing
+func fieldReferences(text string) []string {
+	parsed, err := template.New("prompt").Parse(text)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			// Fields inside range and with refer to the element, not the data
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			names = append(names, n.Ident[0])
+		}
+	}
+	for _, defined := range parsed.Templates() {
+		walk(defined.Tree.Root)
+	}
+	return names
+}
+
+// Variables returns the user-supplied variables of a template: those it
+// declares plus those it refers to, without the built-ins, sorted
+func Variables(t types.ContextTemplate) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !builtins[name] && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range t.Variables {
+		add(name)
+	}
+	for _, name := range fieldReferences(t.Template) {
+		add(name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate parses a template's text, reporting syntax errors
+func Validate(text string) error {
+	_, err := template.New("prompt").Option("missingkey=zero").Parse(text)
+	return err
+}
+
+// Render fills a template with the context and the user's variable values.
+// Templates that never mention the context get it appended, and a template
+// with only a preamble places the preamble before it.
+func Render(t types.ContextTemplate, data Data, values map[string]string) (string, error) {
+	text := t.Template
+	if strings.TrimSpace(text) == "" {
+		text = t.Preamble
+	}
+	parsed, err := template.New(t.Name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+
+	fields := map[string]interface{}{
+		"ProjectName": data.ProjectName,
+		"Context":     data.Context,
+		"context":     data.Context,
+		"Model":       data.Model,
+		"Files":       data.Files,
+		"Tokens":      data.Tokens,
+		"Date":        data.Date.Format("2006-01-02"),
+	}
+	for _, name := range Variables(t) {
+		fields[name] = values[name]
+	}
+
+	var out strings.Builder
+	if err := parsed.Execute(&out, fields); err != nil {
+		return "", fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	rendered := strings.TrimSpace(out.String())
+	if !mentionsContext(text) {
+		if rendered == "" {
+			return data.Context, nil
+		}
+		rendered += "\n\n" + data.Context
+	}
+	return rendered, nil
+}
+
+// mentionsContext reports whether a template places the context itself
+func mentionsContext(text string) bool {
+	for _, name := range fieldReferences(text) {
+		if name == "Context" || name == "context" {
+			return true
+		}
+	}
+	return false
+}
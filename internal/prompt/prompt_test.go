@@ -0,0 +1,71 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ai-context-cli/pkg/types"
+)
+
+func TestRenderFillsVariables(t *testing.T) {
+	template := types.ContextTemplate{
+		Name:      "Review",
+		Template:  "You are reviewing {{.ProjectName}} for {{.Team}} on {{.Date}}.\n{{.Context}}\nFocus: {{.Focus}}",
+		Variables: []string{"Team"},
+	}
+	if variables := Variables(template); strings.Join(variables, ",") != "Focus,Team" {
+		t.Errorf("Expected Focus and Team as user variables, got %v", variables)
+	}
+
+	data := Data{ProjectName: "demo", Context: "# Overview", Date: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}
+	rendered, err := Render(template, data, map[string]string{"Team": "platform", "Focus": "errors"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := "You are reviewing demo for platform on 2024-05-06.\n# Overview\nFocus: errors"
+	if rendered != expected {
+		t.Errorf("Expected %q, got %q", expected, rendered)
+	}
+
+	// Unset variables render empty rather than failing
+	if rendered, err := Render(template, data, nil); err != nil || !strings.Contains(rendered, "for  on") {
+		t.Errorf("Expected empty values for unset variables, got %q (%v)", rendered, err)
+	}
+}
+
+func TestVariablesFoundInsideActions(t *testing.T) {
+	template := types.ContextTemplate{
+		Name:     "Conditional",
+		Template: "{{if .Urgent}}URGENT: {{end}}{{with .Ticket}}{{.}}{{end}}{{.Audience | printf \"%s\"}}",
+	}
+	if variables := Variables(template); strings.Join(variables, ",") != "Audience,Ticket,Urgent" {
+		t.Errorf("Expected variables used in if, with and pipelines, got %v", variables)
+	}
+
+	rendered, err := Render(template, Data{Context: "CONTEXT"}, map[string]string{"Urgent": "yes"})
+	if err != nil || !strings.HasPrefix(rendered, "URGENT:") {
+		t.Errorf("Expected the if variable to be filled, got %q (%v)", rendered, err)
+	}
+}
+
+func TestRenderPlacesContext(t *testing.T) {
+	data := Data{ProjectName: "demo", Context: "CONTEXT"}
+
+	legacy := types.ContextTemplate{Name: "Default", Template: "You are a helpful AI assistant. {{.context}}"}
+	if rendered, _ := Render(legacy, data, nil); rendered != "You are a helpful AI assistant. CONTEXT" {
+		t.Errorf("Expected the lowercase placeholder to work, got %q", rendered)
+	}
+
+	preamble := types.ContextTemplate{Name: "Audit", Preamble: "Audit {{.ProjectName}}."}
+	if rendered, _ := Render(preamble, data, nil); rendered != "Audit demo.\n\nCONTEXT" {
+		t.Errorf("Expected the context after the preamble, got %q", rendered)
+	}
+
+	if _, err := Render(types.ContextTemplate{Name: "Broken", Template: "{{.Context"}, data, nil); err == nil {
+		t.Error("Expected a syntax error")
+	}
+	if err := Validate("{{if}}"); err == nil {
+		t.Error("Expected Validate to reject a malformed template")
+	}
+}
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"ai-context-cli/pkg/types"
+)
+
+// ListsModels reports whether a provider serves an OpenAI-compatible /models listing
+func ListsModels(provider string) bool {
+	switch provider {
+	case "openai", "openrouter", "lmstudio":
+		return true
+	}
+	return false
+}
+
+// ModelsURL returns the model listing endpoint beside a chat completions endpoint
+func ModelsURL(model types.AIModel) string {
+	base := strings.TrimSuffix(model.APIEndpoint, "/")
+	base = strings.TrimSuffix(base, "/chat/completions")
+	return base + "/models"
+}
+
+// ListModels fetches the models a provider serves. Each result copies the
+// template's provider, endpoint and key, so it can be added to the config as is.
+func ListModels(client *http.Client, template types.AIModel) ([]types.AIModel, error) {
+	if !ListsModels(template.Provider) {
+		return nil, fmt.Errorf("provider %q does not support model listing", template.Provider)
+	}
+	if template.APIEndpoint == "" {
+		return nil, fmt.Errorf("model %s has no API endpoint", template.Name)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, ModelsURL(template), nil)
+	if err != nil {
+		return nil, err
+	}
+	if template.APIKey != "" {
+		request.Header.Set("Authorization", "Bearer "+template.APIKey)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 400 {
+		io.Copy(io.Discard, response.Body)
+		return nil, fmt.Errorf("endpoint returned %s", response.Status)
+	}
+
+	// OpenRouter adds context_length to the OpenAI listing shape
+	var listing struct {
+		Data []struct {
+			ID            string `json:"id"`
+			ContextLength int    `json:"context_length"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("decoding model list: %w", err)
+	}
+
+	models := make([]types.AIModel, 0, len(listing.Data))
+	for _, entry := range listing.Data {
+		model := types.AIModel{
+			Name:         entry.ID,
+			Provider:     template.Provider,
+			APIEndpoint:  template.APIEndpoint,
+			APIKey:       template.APIKey,
+			MaxTokens:    entry.ContextLength,
+			Capabilities: chatModel,
+		}
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models, nil
+}
@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ai-context-cli/pkg/types"
+)
+
+// googleBaseURL is the Generative Language API host used by Gemini models
+const googleBaseURL = "https://generativelanguage.googleapis.com"
+
+// defaultAzureAPIVersion is used for Azure OpenAI models without an api-version
+const defaultAzureAPIVersion = "2024-06-01"
+
+// Capability sets shared by the registered models
+var (
+	chatModel     = []types.ModelCapability{types.CapabilityChat, types.CapabilityCodeGeneration}
+	codeModel     = []types.ModelCapability{types.CapabilityChat, types.CapabilityCodeGeneration, types.CapabilityCodeReview, types.CapabilityFunctionCalling}
+	longCodeModel = []types.ModelCapability{types.CapabilityChat, types.CapabilityCodeGeneration, types.CapabilityCodeReview, types.CapabilityLongContext, types.CapabilityVision}
+)
+
+// registeredModels lists the built-in models known for each provider
+var registeredModels = []types.AIModel{
+	{Name: "gpt-3.5-turbo", Provider: "openai", APIEndpoint: "https://api.openai.com/v1/chat/completions", MaxTokens: 16385, Capabilities: chatModel},
+	{Name: "gpt-4", Provider: "openai", APIEndpoint: "https://api.openai.com/v1/chat/completions", MaxTokens: 8192, Capabilities: codeModel},
+	{Name: "claude-3-sonnet", Provider: "anthropic", APIEndpoint: "https://api.anthropic.com/v1/messages", MaxTokens: 200000, Capabilities: longCodeModel},
+	{Name: "gemini-pro", Provider: "google", MaxTokens: 32760, Capabilities: chatModel},
+	{Name: "gemini-1.5-pro", Provider: "google", MaxTokens: 2097152, Capabilities: longCodeModel},
+	{Name: "gemini-1.5-flash", Provider: "google", MaxTokens: 1048576, Capabilities: longCodeModel},
+	{Name: "gemini-2.0-flash", Provider: "google", MaxTokens: 1048576, Capabilities: longCodeModel},
+	{Name: "gemini-2.0-flash-exp", Provider: "google", MaxTokens: 1048576, Capabilities: longCodeModel},
+	// OpenRouter serves many vendors' models behind one key; list them with `models available openrouter`
+	{Name: "openrouter/auto", Provider: "openrouter", APIEndpoint: "https://openrouter.ai/api/v1/chat/completions", MaxTokens: 128000, Capabilities: chatModel},
+	// LM Studio's local server answers for whichever model is loaded
+	{Name: "local-model", Provider: "lmstudio", APIEndpoint: "http://localhost:1234/v1/chat/completions", MaxTokens: 8192, Capabilities: chatModel},
+	// Template for an Azure OpenAI deployment; replace the resource host and deployment name
+	{Name: "gpt-4o", Provider: "azure", APIEndpoint: "https://YOUR-RESOURCE.openai.azure.com", Deployment: "gpt-4o", APIVersion: defaultAzureAPIVersion, MaxTokens: 128000, Capabilities: longCodeModel},
+}
+
+// registryWarnings holds validation problems found when the registry loads
+var registryWarnings = ValidateModels(registeredModels)
+
+// ValidateModels reports capability strings that match no ModelCapability constant
+func ValidateModels(models []types.AIModel) []string {
+	var warnings []string
+	for _, model := range models {
+		for _, capability := range model.UnknownCapabilities() {
+			warnings = append(warnings, fmt.Sprintf("model %s: unknown capability %q", model.Name, capability))
+		}
+	}
+	return warnings
+}
+
+// RegistryWarnings returns validation problems in the built-in registry
+func RegistryWarnings() []string {
+	return registryWarnings
+}
+
+// WithCapability returns the models that declare a capability
+func WithCapability(models []types.AIModel, capability types.ModelCapability) []types.AIModel {
+	var matching []types.AIModel
+	for _, model := range models {
+		if model.HasCapability(capability) {
+			matching = append(matching, model)
+		}
+	}
+	return matching
+}
+
+// Models returns the registered models, optionally filtered by provider
+func Models(provider string) []types.AIModel {
+	var models []types.AIModel
+	for _, model := range registeredModels {
+		if provider == "" || model.Provider == provider {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// googleAPIVersion returns the API path version a Gemini model is served from.
+// Experimental, preview and 2.x models are only available on v1beta.
+func googleAPIVersion(modelName string) string {
+	name := strings.ToLower(modelName)
+	if strings.Contains(name, "-exp") || strings.Contains(name, "preview") || strings.HasPrefix(name, "gemini-2") {
+		return "v1beta"
+	}
+	return "v1"
+}
+
+// GoogleURL builds the generateContent URL for a Gemini model. A configured
+// endpoint overrides the host and version; the model path and action are
+// appended unless already present. The API key is sent in the x-goog-api-key
+// header rather than the URL, which request errors quote in full.
+func GoogleURL(model types.AIModel) string {
+	base := strings.TrimSuffix(model.APIEndpoint, "/")
+	if base == "" {
+		base = fmt.Sprintf("%s/%s", googleBaseURL, googleAPIVersion(model.Name))
+	}
+	
+	base = strings.TrimSuffix(base, ":generateContent")
+	if !strings.Contains(base, "/models/") {
+		base = fmt.Sprintf("%s/models/%s", base, model.Name)
+	}
+	
+	return base + ":generateContent"
+}
+
+// AzureURL builds the chat completions URL for an Azure OpenAI deployment. The
+// endpoint is the resource host; one that already names a deployment is kept,
+// and the api-version parameter is added unless present.
+func AzureURL(model types.AIModel) string {
+	base := strings.TrimSuffix(model.APIEndpoint, "/")
+	if base == "" {
+		return ""
+	}
+	
+	if !strings.Contains(base, "/openai/deployments/") {
+		deployment := model.Deployment
+		if deployment == "" {
+			deployment = model.Name
+		}
+		base = fmt.Sprintf("%s/openai/deployments/%s/chat/completions", base, url.PathEscape(deployment))
+	}
+	if strings.Contains(base, "api-version=") {
+		return base
+	}
+	
+	version := model.APIVersion
+	if version == "" {
+		version = defaultAzureAPIVersion
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "api-version=" + url.QueryEscape(version)
+}
+
+// TestURL returns the URL used to check connectivity for a model
+func TestURL(model types.AIModel) string {
+	switch model.Provider {
+	case "google":
+		return GoogleURL(model)
+	case "azure":
+		return AzureURL(model)
+	default:
+		return model.APIEndpoint
+	}
+}
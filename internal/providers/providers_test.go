@@ -0,0 +1,342 @@
+package providers
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"ai-context-cli/pkg/types"
+)
+
+func TestGeminiModelsRegistered(t *testing.T) {
+	names := make(map[string]bool)
+	for _, model := range Models("google") {
+		names[model.Name] = true
+	}
+	
+	for _, name := range []string{"gemini-pro", "gemini-1.5-pro", "gemini-1.5-flash", "gemini-2.0-flash"} {
+		if !names[name] {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+}
+
+func TestGoogleTestURL(t *testing.T) {
+	pattern := regexp.MustCompile(`^https://generativelanguage\.googleapis\.com/(v1|v1beta)/models/[\w.-]+:generateContent$`)
+	
+	testCases := []struct {
+		model    types.AIModel
+		expected string
+	}{
+		{
+			types.AIModel{Name: "gemini-1.5-pro", Provider: "google", APIKey: "secret"},
+			"https://generativelanguage.googleapis.com/v1/models/gemini-1.5-pro:generateContent",
+		},
+		{
+			types.AIModel{Name: "gemini-2.0-flash", Provider: "google", APIKey: "secret"},
+			"https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent",
+		},
+		{
+			// Legacy configs stored the full action URL
+			types.AIModel{
+				Name:        "gemini-pro",
+				Provider:    "google",
+				APIEndpoint: "https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent",
+				APIKey:      "secret",
+			},
+			"https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent",
+		},
+	}
+	
+	for _, tc := range testCases {
+		got := TestURL(tc.model)
+		if got != tc.expected {
+			t.Errorf("TestURL(%s) = %q, expected %q", tc.model.Name, got, tc.expected)
+		}
+		if !pattern.MatchString(got) {
+			t.Errorf("TestURL(%s) = %q does not match the Gemini URL pattern", tc.model.Name, got)
+		}
+	}
+}
+
+func TestGoogleKeyStaysOutOfErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-goog-api-key") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	
+	tester := NewConnectionTester()
+	model := types.AIModel{Name: "gemini-pro", Provider: "google", APIEndpoint: server.URL, APIKey: "secret"}
+	if result := tester.Test(model); !result.Success {
+		t.Fatalf("Expected the key sent in the header, got %+v", result)
+	}
+	
+	// Grab a free port, then close it so the request fails
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	model.APIEndpoint = "http://" + listener.Addr().String()
+	listener.Close()
+	
+	result := tester.Test(model)
+	if result.Error == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	if strings.Contains(result.Error.Error(), "secret") || strings.Contains(result.Details(), "secret") {
+		t.Errorf("Expected the key kept out of the error, got %v", result.Error)
+	}
+}
+
+func TestModelCapabilityValidation(t *testing.T) {
+	model := types.AIModel{
+		Name:         "reviewer",
+		Capabilities: []types.ModelCapability{types.CapabilityCodeReview, "code-reveiw"},
+	}
+	
+	if !model.HasCapability(types.CapabilityCodeReview) {
+		t.Error("Expected model to have CapabilityCodeReview")
+	}
+	if model.HasCapability(types.CapabilityVision) {
+		t.Error("Expected model not to have CapabilityVision")
+	}
+	
+	warnings := ValidateModels([]types.AIModel{model})
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "code-reveiw") {
+		t.Errorf("Expected unknown capability to be flagged, got %v", warnings)
+	}
+	
+	if len(RegistryWarnings()) != 0 {
+		t.Errorf("Expected built-in registry to be valid, got %v", RegistryWarnings())
+	}
+	
+	reviewers := WithCapability(Models(""), types.CapabilityCodeReview)
+	if len(reviewers) == 0 {
+		t.Error("Expected registered models with code review capability")
+	}
+}
+
+func TestConnectionTesterUsesClock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tester := NewConnectionTester()
+	tester.SetClock(func() time.Time { return fixed })
+	
+	result := tester.Test(types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL, APIKey: "secret"})
+	if !result.Success {
+		t.Fatalf("Expected successful test, got %v", result.Error)
+	}
+	if !result.Timestamp.Equal(fixed) {
+		t.Errorf("Expected timestamp %v, got %v", fixed, result.Timestamp)
+	}
+	
+	result = tester.Test(types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL, APIKey: "wrong"})
+	if result.Success || result.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected unauthorized failure, got %+v", result)
+	}
+}
+
+func TestConnectionFailureHints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	
+	tester := NewConnectionTester()
+	result := tester.Test(types.AIModel{Name: "gpt-4", Provider: "openai", APIEndpoint: server.URL, APIKey: "wrong"})
+	details := result.Details()
+	if !strings.Contains(details, "401") {
+		t.Errorf("Expected raw status in details, got %q", details)
+	}
+	if !strings.Contains(details, "Hint: Check your API key") {
+		t.Errorf("Expected API key hint in details, got %q", details)
+	}
+	
+	rateLimited := TestResult{StatusCode: http.StatusTooManyRequests}
+	if rateLimited.Hint() != "Rate limited; wait and retry" {
+		t.Errorf("Expected rate limit hint, got %q", rateLimited.Hint())
+	}
+	
+	// Grab a free port, then close it so connections are refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	
+	result = tester.Test(types.AIModel{Name: "llama2", Provider: "ollama", APIEndpoint: "http://" + address})
+	if result.Hint() != "Start Ollama (`ollama serve`)" {
+		t.Errorf("Expected Ollama hint, got %q (error: %v)", result.Hint(), result.Error)
+	}
+}
+
+// manualTicker delivers ticks only when the test sends them
+type manualTicker struct {
+	ticks chan time.Time
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.ticks }
+func (t *manualTicker) Stop()               {}
+
+func TestStatusRefresherRetestsAfterInterval(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := time.Minute
+	model := types.AIModel{Name: "local", Provider: "ollama"}
+	
+	statusCode := http.StatusOK
+	calls := make(chan time.Time, 10)
+	var clock time.Time
+	tester := func(m types.AIModel) TestResult {
+		calls <- clock
+		return TestResult{ModelName: m.Name, Success: statusCode == http.StatusOK, StatusCode: statusCode, Timestamp: clock}
+	}
+	
+	if NewStatusRefresher(tester, []types.AIModel{model}, 0).Enabled() {
+		t.Error("Expected a zero interval to disable refresh")
+	}
+	
+	ticker := &manualTicker{ticks: make(chan time.Time)}
+	refresher := NewStatusRefresher(tester, []types.AIModel{model}, interval)
+	refresher.SetTicker(func(d time.Duration) Ticker {
+		if d != interval {
+			t.Errorf("Expected ticker interval %v, got %v", interval, d)
+		}
+		return ticker
+	})
+	refresher.Start()
+	defer refresher.Close()
+	
+	tick := func(at time.Time) {
+		clock = at
+		ticker.ticks <- at
+	}
+	waitForCall := func() time.Time {
+		select {
+		case at := <-calls:
+			return at
+		case <-time.After(time.Second):
+			t.Fatal("Expected the tester to be re-invoked after the interval")
+		}
+		return time.Time{}
+	}
+	
+	tick(start.Add(interval))
+	waitForCall()
+	result := <-refresher.Updates()
+	if !result.Success {
+		t.Error("Expected the refreshed result to be reported")
+	}
+	if badge := result.Badge(start.Add(4 * interval)); badge != "✓ connected · checked 3m ago" {
+		t.Errorf("Unexpected badge %q", badge)
+	}
+	
+	// A rate-limited result skips the next refresh
+	statusCode = http.StatusTooManyRequests
+	tick(start.Add(2 * interval))
+	waitForCall()
+	<-refresher.Updates()
+	tick(start.Add(3 * interval))
+	tick(start.Add(4 * interval))
+	if at := waitForCall(); !at.Equal(start.Add(4 * interval)) {
+		t.Errorf("Expected the rate-limited model to be skipped until %v, tested at %v", start.Add(4*interval), at)
+	}
+	
+	refresher.Close()
+	select {
+	case ticker.ticks <- start.Add(5 * interval):
+		t.Error("Expected no ticks to be consumed after Close")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if status, ok := refresher.Status("local"); !ok || status.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected last status to be kept, got %+v", status)
+	}
+}
+
+func TestAzureURLAndAuth(t *testing.T) {
+	testCases := []struct {
+		model    types.AIModel
+		expected string
+	}{
+		{
+			types.AIModel{Name: "gpt-4o", Provider: "azure", APIEndpoint: "https://acme.openai.azure.com/", Deployment: "prod-gpt4o", APIVersion: "2024-02-01"},
+			"https://acme.openai.azure.com/openai/deployments/prod-gpt4o/chat/completions?api-version=2024-02-01",
+		},
+		{
+			// The deployment defaults to the model name and the version to the built-in one
+			types.AIModel{Name: "gpt-4o", Provider: "azure", APIEndpoint: "https://acme.openai.azure.com"},
+			"https://acme.openai.azure.com/openai/deployments/gpt-4o/chat/completions?api-version=" + defaultAzureAPIVersion,
+		},
+		{
+			types.AIModel{Name: "gpt-4o", Provider: "azure", APIEndpoint: "https://acme.openai.azure.com/openai/deployments/x/chat/completions?api-version=2023-05-15"},
+			"https://acme.openai.azure.com/openai/deployments/x/chat/completions?api-version=2023-05-15",
+		},
+	}
+	for _, tc := range testCases {
+		if got := TestURL(tc.model); got != tc.expected {
+			t.Errorf("TestURL(%s) = %q, expected %q", tc.model.APIEndpoint, got, tc.expected)
+		}
+	}
+	
+	var apiKey, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey, path = r.Header.Get("api-key"), r.URL.Path
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	
+	result := NewConnectionTester().Test(types.AIModel{Name: "gpt-4o", Provider: "azure", APIEndpoint: server.URL, Deployment: "prod", APIKey: "secret"})
+	if !result.Success || apiKey != "secret" || path != "/openai/deployments/prod/chat/completions" {
+		t.Errorf("Expected an api-key request to the deployment, got %+v (key %q, path %q)", result, apiKey, path)
+	}
+	if len(Models("azure")) == 0 {
+		t.Error("Expected an Azure template in the registry")
+	}
+}
+
+func TestListModelsFromModelsEndpoint(t *testing.T) {
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		w.Write([]byte(`{"data":[{"id":"openai/gpt-4o","context_length":128000},{"id":"anthropic/claude-3.5-sonnet","context_length":200000}]}`))
+	}))
+	defer server.Close()
+	
+	template := types.AIModel{Name: "openrouter/auto", Provider: "openrouter", APIEndpoint: server.URL + "/api/v1/chat/completions", APIKey: "or-key"}
+	models, err := ListModels(server.Client(), template)
+	if err != nil {
+		t.Fatalf("ListModels failed: %v", err)
+	}
+	if path != "/api/v1/models" || auth != "Bearer or-key" {
+		t.Errorf("Expected an authorized request to the models endpoint, got %s with %q", path, auth)
+	}
+	if len(models) != 2 || models[0].Name != "anthropic/claude-3.5-sonnet" || models[0].MaxTokens != 200000 {
+		t.Fatalf("Expected the listed models sorted by name, got %+v", models)
+	}
+	if models[1].Provider != "openrouter" || models[1].APIEndpoint != template.APIEndpoint {
+		t.Errorf("Expected listed models to reuse the template's provider and endpoint, got %+v", models[1])
+	}
+	
+	if _, err := ListModels(server.Client(), types.AIModel{Provider: "anthropic", APIEndpoint: server.URL}); err == nil {
+		t.Error("Expected providers without a listing to be rejected")
+	}
+	for _, provider := range []string{"openrouter", "lmstudio"} {
+		if len(Models(provider)) == 0 {
+			t.Errorf("Expected a %s entry in the registry", provider)
+		}
+	}
+}
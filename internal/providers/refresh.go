@@ -0,0 +1,185 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"ai-context-cli/pkg/types"
+)
+
+// Ticker delivers refresh ticks; tests substitute a manually driven one
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// timeTicker adapts time.Ticker to the Ticker interface
+type timeTicker struct {
+	ticker *time.Ticker
+}
+
+func (t timeTicker) C() <-chan time.Time { return t.ticker.C }
+func (t timeTicker) Stop()               { t.ticker.Stop() }
+
+// newTimeTicker is the default ticker factory
+func newTimeTicker(interval time.Duration) Ticker {
+	return timeTicker{ticker: time.NewTicker(interval)}
+}
+
+// StatusRefresher re-tests model connections in the background on an interval
+type StatusRefresher struct {
+	test      func(types.AIModel) TestResult
+	models    []types.AIModel
+	interval  time.Duration
+	newTicker func(time.Duration) Ticker
+	
+	mu          sync.Mutex
+	statuses    map[string]TestResult
+	rateLimited map[string]time.Time // model name -> skip refreshes until
+	
+	updates   chan TestResult
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStatusRefresher creates a refresher; an interval of zero or less disables it
+func NewStatusRefresher(test func(types.AIModel) TestResult, models []types.AIModel, interval time.Duration) *StatusRefresher {
+	return &StatusRefresher{
+		test:        test,
+		models:      models,
+		interval:    interval,
+		newTicker:   newTimeTicker,
+		statuses:    make(map[string]TestResult),
+		rateLimited: make(map[string]time.Time),
+		updates:     make(chan TestResult, len(models)),
+		done:        make(chan struct{}),
+	}
+}
+
+// SetTicker overrides the ticker factory used by Start
+func (r *StatusRefresher) SetTicker(newTicker func(time.Duration) Ticker) {
+	r.newTicker = newTicker
+}
+
+// Enabled reports whether background refresh is configured
+func (r *StatusRefresher) Enabled() bool {
+	return r.interval > 0
+}
+
+// Start begins refreshing in the background; it does nothing when disabled
+func (r *StatusRefresher) Start() {
+	if !r.Enabled() {
+		return
+	}
+	go r.run(r.newTicker(r.interval))
+}
+
+// Close stops refreshing; in-flight tests finish but are not reported
+func (r *StatusRefresher) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+	return nil
+}
+
+// Updates returns the channel of refreshed results
+func (r *StatusRefresher) Updates() <-chan TestResult {
+	return r.updates
+}
+
+// Record stores a result from a manual test so refreshes and badges agree
+func (r *StatusRefresher) Record(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	r.statuses[result.ModelName] = result
+	if result.StatusCode == http.StatusTooManyRequests {
+		// Back off for an extra interval before hitting the endpoint again
+		r.rateLimited[result.ModelName] = result.Timestamp.Add(2 * r.interval)
+	} else {
+		delete(r.rateLimited, result.ModelName)
+	}
+}
+
+// Status returns the last known result for a model
+func (r *StatusRefresher) Status(modelName string) (TestResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result, ok := r.statuses[modelName]
+	return result, ok
+}
+
+// run tests every model on each tick until closed, then closes Updates so
+// listeners stop too
+func (r *StatusRefresher) run(ticker Ticker) {
+	defer close(r.updates)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-r.done:
+			return
+		case now := <-ticker.C():
+			r.refresh(now)
+		}
+	}
+}
+
+// refresh tests models one at a time, skipping any still rate limited
+func (r *StatusRefresher) refresh(now time.Time) {
+	for _, model := range r.models {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+		
+		r.mu.Lock()
+		until, limited := r.rateLimited[model.Name]
+		r.mu.Unlock()
+		if limited && now.Before(until) {
+			continue
+		}
+		
+		result := r.test(model)
+		r.Record(result)
+		
+		select {
+		case r.updates <- result:
+		case <-r.done:
+			return
+		default:
+			// Drop the update if nobody is listening; Status still has it
+		}
+	}
+}
+
+// Badge renders a short status, with the latency of a successful test and the
+// time since the result was checked
+func (r TestResult) Badge(now time.Time) string {
+	status := "✗ failed"
+	if r.Success {
+		status = "✓ connected"
+		if r.Latency > 0 {
+			status += fmt.Sprintf(" (%dms)", r.Latency.Milliseconds())
+		}
+	}
+	if r.Timestamp.IsZero() {
+		return status
+	}
+	return fmt.Sprintf("%s · checked %s", status, formatAge(now.Sub(r.Timestamp)))
+}
+
+// formatAge renders a duration as a coarse "ago" label
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	default:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	}
+}
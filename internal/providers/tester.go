@@ -0,0 +1,182 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"ai-context-cli/pkg/types"
+)
+
+// TestResult describes the outcome of a model connection test
+type TestResult struct {
+	ModelName  string
+	Provider   string
+	Success    bool
+	StatusCode int
+	Latency    time.Duration
+	Error      error
+	Timestamp  time.Time
+}
+
+// ConnectionTester checks that a model's endpoint accepts requests
+type ConnectionTester struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewConnectionTester creates a tester with a short request timeout
+func NewConnectionTester() *ConnectionTester {
+	return &ConnectionTester{
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// SetClock overrides the time source used for timestamps and latency
+func (ct *ConnectionTester) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	ct.now = now
+}
+
+// SetHTTPClient replaces the HTTP client used for requests
+func (ct *ConnectionTester) SetHTTPClient(client *http.Client) {
+	ct.client = client
+}
+
+// Test sends a minimal request to the model's endpoint
+func (ct *ConnectionTester) Test(model types.AIModel) TestResult {
+	start := ct.now()
+	result := TestResult{ModelName: model.Name, Provider: model.Provider, Timestamp: start}
+	
+	request, err := buildTestRequest(model)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	
+	response, err := ct.client.Do(request)
+	result.Latency = ct.now().Sub(start)
+	if err != nil {
+		result.Error = fmt.Errorf("request failed: %w", err)
+		return result
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+	
+	result.StatusCode = response.StatusCode
+	if response.StatusCode >= 400 {
+		result.Error = fmt.Errorf("endpoint returned %s", response.Status)
+		return result
+	}
+	
+	result.Success = true
+	return result
+}
+
+// Hint returns an actionable suggestion for common failures, or "" if none applies
+func (r TestResult) Hint() string {
+	if r.Success {
+		return ""
+	}
+	
+	switch r.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "Check your API key"
+	case http.StatusTooManyRequests:
+		return "Rate limited; wait and retry"
+	case http.StatusNotFound:
+		return "Check the model name and API endpoint"
+	}
+	
+	if errors.Is(r.Error, syscall.ECONNREFUSED) {
+		if r.Provider == "ollama" {
+			return "Start Ollama (`ollama serve`)"
+		}
+		if r.Provider == "lmstudio" {
+			return "Start the LM Studio server (`lms server start`)"
+		}
+		return "Check that the API endpoint is reachable"
+	}
+	return ""
+}
+
+// Details renders the result as lines for a details panel, including any hint
+func (r TestResult) Details() string {
+	var lines []string
+	if r.Success {
+		lines = append(lines, fmt.Sprintf("Status: connected (%s)", r.Latency.Round(time.Millisecond)))
+	} else {
+		lines = append(lines, "Status: failed")
+	}
+	if r.Error != nil {
+		lines = append(lines, fmt.Sprintf("Error: %v", r.Error))
+	}
+	if hint := r.Hint(); hint != "" {
+		lines = append(lines, "Hint: "+hint)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildTestRequest creates the smallest valid request for each provider
+func buildTestRequest(model types.AIModel) (*http.Request, error) {
+	endpoint := TestURL(model)
+	if endpoint == "" {
+		return nil, fmt.Errorf("model %s has no API endpoint", model.Name)
+	}
+	
+	var payload interface{}
+	switch model.Provider {
+	case "openai", "anthropic", "azure", "openrouter", "lmstudio":
+		payload = map[string]interface{}{
+			"model":      model.Name,
+			"max_tokens": 1,
+			"messages":   []map[string]string{{"role": "user", "content": "ping"}},
+		}
+	case "google":
+		payload = map[string]interface{}{
+			"contents": []map[string]interface{}{
+				{"parts": []map[string]string{{"text": "ping"}}},
+			},
+		}
+	default:
+		return http.NewRequest(http.MethodGet, endpoint, nil)
+	}
+	
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	
+	request, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	
+	switch model.Provider {
+	case "openai", "openrouter", "lmstudio":
+		if model.APIKey != "" {
+			request.Header.Set("Authorization", "Bearer "+model.APIKey)
+		}
+	case "azure":
+		request.Header.Set("api-key", model.APIKey)
+	case "anthropic":
+		request.Header.Set("x-api-key", model.APIKey)
+		request.Header.Set("anthropic-version", "2023-06-01")
+	case "google":
+		if model.APIKey != "" {
+			request.Header.Set("x-goog-api-key", model.APIKey)
+		}
+	}
+	
+	return request, nil
+}
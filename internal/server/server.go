@@ -0,0 +1,362 @@
+package server
+
+import (
+	stdcontext "context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ai-context-cli/internal/context"
+	"ai-context-cli/internal/gitdiff"
+)
+
+// maxRequestBytes bounds request bodies; requests only carry options, never files
+const maxRequestBytes = 1 << 20
+
+// Options configures the HTTP handler
+type Options struct {
+	// Token, when set, must be sent as "Authorization: Bearer <token>"
+	Token string
+	// Root, when set, is the only directory tree requests may scan
+	Root string
+	// Redaction applies to every request; clients cannot turn it off
+	Redaction Redaction
+}
+
+// Redaction is how detected secrets are masked, fixed when the server starts
+type Redaction struct {
+	Disabled bool     // serve secrets unmasked
+	Skip     []string // patterns left unmasked
+}
+
+// Request selects the directory and how its context is generated.
+// Only Path is used by /scan.
+type Request struct {
+	Path     string `json:"path"`
+	Format   string `json:"format,omitempty"`   // markdown, text, json or xml
+	Paths    string `json:"paths,omitempty"`    // relative or absolute
+	Since    string `json:"since,omitempty"`    // only files changed since this git ref
+	Outline  string `json:"outline,omitempty"`  // off, add or only: API outline for Go files
+	Detail   string `json:"detail,omitempty"`   // full, outline or skeleton: how much of each code file
+	Compress string `json:"compress,omitempty"` // comma-separated: license, comments, blank-lines, data, all or none
+	Template string `json:"template,omitempty"` // development, documentation, review, debug, full or summary
+
+	IncludeGenerated bool `json:"include_generated,omitempty"` // keep lockfiles, generated code and duplicate copies
+}
+
+// ScanResponse summarizes the files a directory would contribute
+type ScanResponse struct {
+	Root         string         `json:"root"`
+	Files        []string       `json:"files"`
+	Directories  int            `json:"directories"`
+	SizeBytes    int64          `json:"size_bytes"`
+	Lines        int            `json:"lines"`
+	Excluded     int            `json:"excluded"`
+	ProjectTypes []string       `json:"project_types"`
+	Extensions   map[string]int `json:"extensions"`
+	Errors       []string       `json:"errors,omitempty"` // paths skipped because they could not be read
+}
+
+// ContextResponse is the generated context, formatted as requested
+type ContextResponse struct {
+	Project    string `json:"project"`
+	Format     string `json:"format"`
+	Files      int    `json:"files"`
+	Tokens     int    `json:"tokens"`
+	Redactions int    `json:"redactions"`
+	Content    string `json:"content"`
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// requestError is a problem with the request itself rather than the server
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e requestError) Error() string { return e.message }
+
+// handler serves the scanner and generator over HTTP
+type handler struct {
+	options Options
+	mux     *http.ServeMux
+}
+
+// New returns the HTTP handler for the headless server
+func New(options Options) (http.Handler, error) {
+	if options.Root != "" {
+		root, err := filepath.Abs(options.Root)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = filepath.EvalSymlinks(root); err != nil {
+			return nil, err
+		}
+		options.Root = root
+	}
+
+	h := &handler{options: options, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/health", h.health)
+	h.mux.HandleFunc("/scan", h.post(h.scan))
+	h.mux.HandleFunc("/context", h.post(h.generate))
+	return h, nil
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Without a token, refuse browsers (which send Origin) and DNS-rebound
+	// names, so only local tools can reach the server
+	if h.options.Token == "" && (r.Header.Get("Origin") != "" || !Loopback(r.Host)) {
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: "without a token only local, non-browser clients are served"})
+		return
+	}
+	if h.options.Token != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(h.options.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid token"})
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// health reports that the server is up, for tools probing before they connect
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// post decodes a JSON request for endpoint and writes its result or error
+func (h *handler) post(endpoint func(stdcontext.Context, Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "use POST"})
+			return
+		}
+
+		var request Request
+		decoder := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil && err != io.EOF {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+
+		response, err := endpoint(r.Context(), request)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var invalid requestError
+			if errors.As(err, &invalid) {
+				status = invalid.status
+			}
+			writeJSON(w, status, errorResponse{Error: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// scan summarizes a directory without generating context
+func (h *handler) scan(ctx stdcontext.Context, request Request) (interface{}, error) {
+	root, err := h.resolveRoot(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	result, err := context.NewProjectScanner(context.DefaultScanConfig(root)).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(result.Files))
+	for _, file := range context.NewContextGenerator().IncludedFiles(result) {
+		relativePath, err := filepath.Rel(root, file.Path)
+		if err != nil {
+			relativePath = file.Path
+		}
+		files = append(files, filepath.ToSlash(relativePath))
+	}
+	return ScanResponse{
+		Root:         result.RootPath,
+		Files:        files,
+		Directories:  result.TotalDirectories,
+		SizeBytes:    result.TotalSize,
+		Lines:        result.TotalLines,
+		Excluded:     result.ExcludedFiles,
+		ProjectTypes: result.ProjectTypes,
+		Extensions:   result.Extensions,
+		Errors:       scanErrors(result),
+	}, nil
+}
+
+// scanErrors describes the paths a scan skipped
+func scanErrors(result *context.ScanResult) []string {
+	var errors []string
+	for _, scanErr := range result.Errors {
+		errors = append(errors, scanErr.Error())
+	}
+	return errors
+}
+
+// generate generates and formats the context for a directory
+func (h *handler) generate(ctx stdcontext.Context, request Request) (interface{}, error) {
+	root, err := h.resolveRoot(request.Path)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(ctx, root, request, h.options.Redaction)
+}
+
+// Generate builds and formats the context for root with the request's options,
+// ignoring request.Path. Errors caused by the options wrap a request error, and
+// the work stops early if ctx is cancelled.
+func Generate(ctx stdcontext.Context, root string, request Request, redaction Redaction) (ContextResponse, error) {
+	formatter, err := context.ParseFormat(request.Format)
+	if err != nil {
+		return ContextResponse{}, requestError{http.StatusBadRequest, err.Error()}
+	}
+	pathStyle, err := context.ParsePathStyle(request.Paths)
+	if err != nil {
+		return ContextResponse{}, requestError{http.StatusBadRequest, err.Error()}
+	}
+	outlineMode, err := context.ParseOutlineMode(request.Outline)
+	if err != nil {
+		return ContextResponse{}, requestError{http.StatusBadRequest, err.Error()}
+	}
+	contentDetail, err := context.ParseContentDetail(request.Detail)
+	if err != nil {
+		return ContextResponse{}, requestError{http.StatusBadRequest, err.Error()}
+	}
+	compression, err := context.ParseCompression(request.Compress)
+	if err != nil {
+		return ContextResponse{}, requestError{http.StatusBadRequest, err.Error()}
+	}
+	if !validTemplate(request.Template) {
+		return ContextResponse{}, requestError{http.StatusBadRequest, fmt.Sprintf("unknown template %q", request.Template)}
+	}
+
+	generator := context.NewContextGenerator()
+	generator.SetPathStyle(pathStyle)
+	generator.SetOutlineMode(outlineMode)
+	generator.SetContentDetail(contentDetail)
+	generator.SetCompression(compression)
+	generator.SetRedaction(!redaction.Disabled, redaction.Skip)
+
+	scanConfig := context.DefaultScanConfig(root)
+	scanConfig.SkipGenerated = !request.IncludeGenerated
+	var generated *context.ContextResult
+	if request.Since != "" {
+		generated, err = gitdiff.Context(ctx, generator, scanConfig, request.Since)
+		var changesErr *gitdiff.ChangesError
+		if errors.As(err, &changesErr) {
+			err = requestError{http.StatusBadRequest, err.Error()}
+		}
+	} else {
+		var result *context.ScanResult
+		if result, err = context.NewProjectScanner(scanConfig).Scan(ctx); err != nil {
+			return ContextResponse{}, err
+		}
+		generated, err = generator.GenerateContext(ctx, result, filepath.Base(root))
+	}
+	if err != nil {
+		return ContextResponse{}, err
+	}
+	if request.Template != "" {
+		generated = context.ApplyTemplate(generated, request.Template)
+	}
+
+	formatted, err := formatter.Format(generated)
+	if err != nil {
+		return ContextResponse{}, err
+	}
+	return ContextResponse{
+		Project:    generated.ProjectName,
+		Format:     formatter.Name(),
+		Files:      generated.TotalFiles,
+		Tokens:     generated.TokenEstimate,
+		Redactions: len(generated.Redactions),
+		Content:    formatted,
+	}, nil
+}
+
+// validTemplate reports whether kind names a context template, or is empty
+func validTemplate(kind string) bool {
+	switch kind {
+	case "", context.TemplateDevelopment, context.TemplateDocumentation, context.TemplateReview,
+		context.TemplateDebug, context.TemplateFull, context.TemplateSummary:
+		return true
+	}
+	return false
+}
+
+// resolveRoot checks a requested directory against the handler's root
+func (h *handler) resolveRoot(path string) (string, error) {
+	return ResolvePath(h.options.Root, path)
+}
+
+// ResolvePath checks a requested directory exists and lies inside root, which
+// must be absolute with symlinks resolved. An empty root allows any absolute
+// path; with a root, path may be relative to it or empty for the root itself.
+func ResolvePath(root, path string) (string, error) {
+	if path == "" {
+		if root == "" {
+			return "", requestError{http.StatusBadRequest, "path is required"}
+		}
+		path = root
+	}
+	if !filepath.IsAbs(path) {
+		if root == "" {
+			return "", requestError{http.StatusBadRequest, "path must be absolute"}
+		}
+		path = filepath.Join(root, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", requestError{http.StatusNotFound, fmt.Sprintf("cannot scan %s: %v", path, err)}
+	}
+	if root != "" {
+		relativePath, err := filepath.Rel(root, resolved)
+		if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return "", requestError{http.StatusForbidden, fmt.Sprintf("%s is outside %s", path, root)}
+		}
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", requestError{http.StatusNotFound, fmt.Sprintf("cannot scan %s: %v", path, err)}
+	}
+	if !info.IsDir() {
+		return "", requestError{http.StatusBadRequest, fmt.Sprintf("%s is not a directory", path)}
+	}
+	return resolved, nil
+}
+
+// Loopback reports whether host, with or without a port, names this machine
+func Loopback(host string) bool {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// writeJSON writes value as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
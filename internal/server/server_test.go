@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serverFixture serves a small project, optionally restricting requests to it
+func serverFixture(t *testing.T, token string, restrict bool) (*httptest.Server, string) {
+	t.Helper()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "pkg"), 0755)
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile(filepath.Join(root, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+	os.WriteFile(filepath.Join(root, "README.md"), []byte("# Demo\n"), 0644)
+
+	options := Options{Token: token}
+	if restrict {
+		options.Root = root
+	}
+	handler, err := New(options)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, root
+}
+
+func post(t *testing.T, server *httptest.Server, path, token string, body interface{}, response interface{}) int {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	request, _ := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(string(data)))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		t.Fatalf("Failed to decode %s response: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+func TestScanAndContextEndpoints(t *testing.T) {
+	server, root := serverFixture(t, "", false)
+
+	var scan ScanResponse
+	if status := post(t, server, "/scan", "", Request{Path: root}, &scan); status != http.StatusOK {
+		t.Fatalf("Expected 200 from /scan, got %d", status)
+	}
+	if strings.Join(scan.Files, ",") != "README.md,main.go,pkg/util.go" || scan.Extensions[".go"] != 2 {
+		t.Errorf("Unexpected scan %+v", scan)
+	}
+
+	var generated ContextResponse
+	if status := post(t, server, "/context", "", Request{Path: root, Format: "json"}, &generated); status != http.StatusOK {
+		t.Fatalf("Expected 200 from /context, got %d", status)
+	}
+	if generated.Format != "json" || generated.Files != 3 || generated.Tokens == 0 || !strings.Contains(generated.Content, "func main() {}") {
+		t.Errorf("Unexpected context %+v", generated)
+	}
+
+	var summary ContextResponse
+	post(t, server, "/context", "", Request{Path: root, Template: "summary"}, &summary)
+	if strings.Contains(summary.Content, "func main() {}") || summary.Tokens >= generated.Tokens {
+		t.Errorf("Expected the summary template to drop file contents, got:\n%s", summary.Content)
+	}
+
+	for _, bad := range []Request{{Path: root, Format: "yaml"}, {Path: root, Template: "poetry"}, {Path: "relative"}, {}} {
+		var failure errorResponse
+		if status := post(t, server, "/context", "", bad, &failure); status != http.StatusBadRequest || failure.Error == "" {
+			t.Errorf("Expected 400 with an error for %+v, got %d %+v", bad, status, failure)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/context")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", resp.StatusCode)
+	}
+}
+
+func TestTokenAndRootRestrictRequests(t *testing.T) {
+	server, root := serverFixture(t, "secret", true)
+
+	var failure errorResponse
+	if status := post(t, server, "/scan", "", Request{}, &failure); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", status)
+	}
+	if status := post(t, server, "/scan", "wrong", Request{}, &failure); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with the wrong token, got %d", status)
+	}
+
+	// Paths default to and resolve against the root
+	var scan ScanResponse
+	if status := post(t, server, "/scan", "secret", Request{}, &scan); status != http.StatusOK || len(scan.Files) != 3 {
+		t.Errorf("Expected the root to be scanned, got %d %+v", status, scan)
+	}
+	if status := post(t, server, "/scan", "secret", Request{Path: "pkg"}, &scan); status != http.StatusOK || len(scan.Files) != 1 {
+		t.Errorf("Expected pkg to be scanned, got %d %+v", status, scan)
+	}
+
+	outside := t.TempDir()
+	for _, path := range []string{outside, "..", filepath.Join(root, "missing")} {
+		if status := post(t, server, "/scan", "secret", Request{Path: path}, &failure); status != http.StatusForbidden && status != http.StatusNotFound {
+			t.Errorf("Expected %s to be refused, got %d", path, status)
+		}
+	}
+}
+
+func TestRequestsWithoutTokenMustBeLocal(t *testing.T) {
+	server, root := serverFixture(t, "", false)
+
+	for name, header := range map[string][2]string{
+		"browser":      {"Origin", "http://evil.example"},
+		"rebound host": {"Host", "evil.example:7878"},
+	} {
+		body := strings.NewReader(`{"path": "` + filepath.ToSlash(root) + `"}`)
+		request, _ := http.NewRequest(http.MethodPost, server.URL+"/scan", body)
+		if header[0] == "Host" {
+			request.Host = header[1]
+		} else {
+			request.Header.Set(header[0], header[1])
+		}
+		resp, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("Expected a %s request to be refused, got %d", name, resp.StatusCode)
+		}
+	}
+
+	for host, expected := range map[string]bool{"127.0.0.1:7878": true, "localhost": true, "[::1]:80": true, ":7878": false, "0.0.0.0:7878": false, "example.com": false} {
+		if Loopback(host) != expected {
+			t.Errorf("Expected Loopback(%q) to be %v", host, expected)
+		}
+	}
+}
+
+func TestSinceStaysInsideRequestedPath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	os.MkdirAll(filepath.Join(repo, "project"), 0755)
+	os.WriteFile(filepath.Join(repo, "project", "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "secret.txt"), []byte("outside\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "initial commit")
+	os.WriteFile(filepath.Join(repo, "project", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "secret.txt"), []byte("changed outside\n"), 0644)
+
+	handler, err := New(Options{Root: filepath.Join(repo, "project")})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var generated ContextResponse
+	if status := post(t, server, "/context", "", Request{Since: "HEAD"}, &generated); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	if !strings.Contains(generated.Content, "func main() {}") || strings.Contains(generated.Content, "changed outside") {
+		t.Errorf("Expected only the changes under the root, got:\n%s", generated.Content)
+	}
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ai-context-cli/internal/context"
+)
+
+// fileName is the session file inside the config directory
+const fileName = "session.json"
+
+// ErrNoSession is returned by Load when nothing has been saved yet
+var ErrNoSession = errors.New("no saved session")
+
+// Session is the work saved between runs: what was scanned, the scan itself,
+// the context generated from it and the model it was meant for
+type Session struct {
+	SavedAt       time.Time              `json:"saved_at"`
+	ScanRoot      string                 `json:"scan_root"`
+	ScanPaths     []string               `json:"scan_paths,omitempty"` // files and folders selected under ScanRoot
+	StructureOnly bool                   `json:"structure_only,omitempty"`
+	Model         string                 `json:"model,omitempty"`
+	ScanResult    *context.ScanResult    `json:"scan_result"`
+	ContextResult *context.ContextResult `json:"context_result"`
+}
+
+// Path returns the session file for a config directory
+func Path(configDir string) string {
+	return filepath.Join(configDir, fileName)
+}
+
+// Save writes the session, replacing the previous one. The file is written
+// beside its destination and renamed into place so a crash never leaves a
+// half-written session behind.
+func Save(path string, s *Session) error {
+	if s.ScanResult == nil || s.ContextResult == nil {
+		return fmt.Errorf("session has no scan or context to save")
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), fileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// Load reads the saved session, returning ErrNoSession if there is none
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("reading session %s: %w", path, err)
+	}
+	if s.ScanResult == nil || s.ContextResult == nil {
+		return nil, fmt.Errorf("reading session %s: missing scan or context", path)
+	}
+	return &s, nil
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ai-context-cli/internal/context"
+)
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	path := Path(t.TempDir())
+	if _, err := Load(path); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("Expected ErrNoSession before saving, got %v", err)
+	}
+
+	saved := &Session{
+		SavedAt:   time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		ScanRoot:  "/work/project",
+		ScanPaths: []string{"/work/project/api"},
+		Model:     "gpt-4",
+		ScanResult: &context.ScanResult{
+			RootPath:   "/work/project",
+			TotalFiles: 1,
+			Files:      []context.FileInfo{{Path: "/work/project/api/main.go", Size: 12, Extension: ".go"}},
+			Extensions: map[string]int{".go": 1},
+		},
+		ContextResult: &context.ContextResult{
+			ProjectName:   "project",
+			TokenEstimate: 42,
+			Sections:      []context.ContextSection{{Title: "Overview", Content: "# Overview"}},
+		},
+	}
+	if err := Save(path, saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.SavedAt.Equal(saved.SavedAt) || loaded.ScanRoot != saved.ScanRoot || loaded.Model != "gpt-4" {
+		t.Errorf("Expected session fields to round-trip, got %+v", loaded)
+	}
+	if len(loaded.ScanPaths) != 1 || loaded.ScanResult.Files[0].Path != "/work/project/api/main.go" {
+		t.Errorf("Expected selected paths and scan files to round-trip, got %+v", loaded)
+	}
+	if loaded.ContextResult.TokenEstimate != 42 || loaded.ContextResult.Sections[0].Content != "# Overview" {
+		t.Errorf("Expected the generated context to round-trip, got %+v", loaded.ContextResult)
+	}
+
+	// Only the session file is left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files, got %d entries", len(entries))
+	}
+
+	os.WriteFile(path, []byte("{not json"), 0644)
+	if _, err := Load(path); err == nil || errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected a corrupt session to be reported, got %v", err)
+	}
+}
@@ -8,7 +8,8 @@ import (
 	"golang.org/x/term"
 )
 
-const version = "v0.1.0"
+// Version is the application version shown in the banner and CLI
+const Version = "v0.1.0"
 
 var logoArt = []string{
 	"╔══════════════════════════════════════════════════════════════════╗",
@@ -118,7 +119,7 @@ func RenderBanner(config BannerConfig) string {
 	// Add version in bottom right if requested
 	if config.ShowVersion {
 		result.WriteString("\n")
-		versionText := fmt.Sprintf("%s", version)
+		versionText := fmt.Sprintf("%s", Version)
 		styledVersion := versionStyle.Render(versionText)
 		// Use lipgloss.Width to account for ANSI codes
 		actualWidth := lipgloss.Width(styledVersion)
@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultCostPer1KTokens prices tokens for models without a configured cost
+const DefaultCostPer1KTokens = 0.03
+
+// Tracker accumulates tokens sent and estimated spend across a session
+type Tracker struct {
+	mu         sync.Mutex
+	costPer1K  float64
+	tokens     int
+	cost       float64
+	operations int
+}
+
+// NewTracker creates a tracker priced at DefaultCostPer1KTokens
+func NewTracker() *Tracker {
+	return &Tracker{costPer1K: DefaultCostPer1KTokens}
+}
+
+// SetCostPer1KTokens changes the price applied to operations recorded afterwards
+func (t *Tracker) SetCostPer1KTokens(cost float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.costPer1K = cost
+}
+
+// Record adds one copy/send operation of the given token count
+func (t *Tracker) Record(tokens int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	
+	t.tokens += tokens
+	t.cost += float64(tokens) / 1000.0 * t.costPer1K
+	t.operations++
+}
+
+// Tokens returns the cumulative tokens recorded
+func (t *Tracker) Tokens() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tokens
+}
+
+// Cost returns the cumulative estimated spend in dollars
+func (t *Tracker) Cost() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cost
+}
+
+// Operations returns how many operations have been recorded
+func (t *Tracker) Operations() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.operations
+}
+
+// Summary renders the totals for the status line, e.g. "Session: ~12K tokens, ~$0.04"
+func (t *Tracker) Summary() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return fmt.Sprintf("Session: ~%s tokens, ~$%.2f", formatTokens(t.tokens), t.cost)
+}
+
+// formatTokens abbreviates token counts with K/M suffixes
+func formatTokens(tokens int) string {
+	switch {
+	case tokens < 1000:
+		return fmt.Sprintf("%d", tokens)
+	case tokens < 1000000:
+		return fmt.Sprintf("%dK", (tokens+500)/1000)
+	default:
+		return fmt.Sprintf("%.1fM", float64(tokens)/1000000)
+	}
+}
@@ -0,0 +1,38 @@
+package usage
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrackerAccumulatesOperations(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Record(8000)
+	tracker.Record(4000)
+	
+	if tracker.Tokens() != 12000 {
+		t.Errorf("Expected 12000 tokens, got %d", tracker.Tokens())
+	}
+	if tracker.Operations() != 2 {
+		t.Errorf("Expected 2 operations, got %d", tracker.Operations())
+	}
+	if math.Abs(tracker.Cost()-0.36) > 1e-9 {
+		t.Errorf("Expected cost $0.36, got %f", tracker.Cost())
+	}
+	
+	expected := "Session: ~12K tokens, ~$0.36"
+	if tracker.Summary() != expected {
+		t.Errorf("Expected %q, got %q", expected, tracker.Summary())
+	}
+}
+
+func TestTrackerCostChangeAppliesToLaterOperations(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Record(1000)
+	tracker.SetCostPer1KTokens(0.01)
+	tracker.Record(1000)
+	
+	if math.Abs(tracker.Cost()-0.04) > 1e-9 {
+		t.Errorf("Expected cost $0.04, got %f", tracker.Cost())
+	}
+}
@@ -0,0 +1,166 @@
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Watcher delivers the paths of changed files under a directory tree
+type Watcher interface {
+	Events() <-chan string
+	Close() error
+}
+
+// ExcludeFunc reports whether a path should be ignored by the watcher
+type ExcludeFunc func(path string, isDir bool) bool
+
+// fileState is the part of a file's metadata used to detect changes
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// PollingWatcher detects changes by periodically comparing file metadata
+type PollingWatcher struct {
+	root      string
+	interval  time.Duration
+	exclude   ExcludeFunc
+	events    chan string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewPollingWatcher starts watching root, polling at the given interval
+func NewPollingWatcher(root string, interval time.Duration, exclude ExcludeFunc) *PollingWatcher {
+	w := &PollingWatcher{
+		root:     root,
+		interval: interval,
+		exclude:  exclude,
+		events:   make(chan string, 100),
+		done:     make(chan struct{}),
+	}
+	go w.poll()
+	return w
+}
+
+// Events returns the channel of changed paths
+func (w *PollingWatcher) Events() <-chan string {
+	return w.events
+}
+
+// Close stops polling
+func (w *PollingWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	return nil
+}
+
+// poll compares snapshots until the watcher is closed
+func (w *PollingWatcher) poll() {
+	defer close(w.events)
+	
+	previous := w.snapshot()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			current := w.snapshot()
+			for _, path := range changedPaths(previous, current) {
+				select {
+				case w.events <- path:
+				case <-w.done:
+					return
+				}
+			}
+			previous = current
+		}
+	}
+}
+
+// snapshot records the state of every non-excluded file under root
+func (w *PollingWatcher) snapshot() map[string]fileState {
+	files := make(map[string]fileState)
+	filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if path != w.root && w.exclude != nil && w.exclude(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return files
+}
+
+// changedPaths lists files added, modified or removed between two snapshots
+func changedPaths(previous, current map[string]fileState) []string {
+	var changed []string
+	for path, state := range current {
+		if old, ok := previous[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// Loop regenerates output once changes settle for the debounce window
+type Loop struct {
+	Watcher    Watcher
+	Debounce   time.Duration
+	Regenerate func() error
+	OnError    func(error)
+}
+
+// Run processes watcher events until stop is closed or the watcher ends
+func (l *Loop) Run(stop <-chan struct{}) {
+	timer := time.NewTimer(l.Debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+	
+	events := l.Watcher.Events()
+	for {
+		select {
+		case <-stop:
+			return
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+			// Restart the debounce window on every change
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(l.Debounce)
+		case <-timer.C:
+			if err := l.Regenerate(); err != nil && l.OnError != nil {
+				l.OnError(err)
+			}
+		}
+	}
+}
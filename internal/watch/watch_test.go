@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeWatcher lets tests emit change events directly
+type fakeWatcher struct {
+	events chan string
+}
+
+func (f *fakeWatcher) Events() <-chan string { return f.events }
+func (f *fakeWatcher) Close() error          { return nil }
+
+func TestLoopRegeneratesAfterDebounce(t *testing.T) {
+	watcher := &fakeWatcher{events: make(chan string)}
+	var regenerations int32
+	
+	loop := &Loop{
+		Watcher:  watcher,
+		Debounce: 50 * time.Millisecond,
+		Regenerate: func() error {
+			atomic.AddInt32(&regenerations, 1)
+			return nil
+		},
+	}
+	
+	stop := make(chan struct{})
+	defer close(stop)
+	go loop.Run(stop)
+	
+	// A burst of changes collapses into one regeneration
+	watcher.events <- "main.go"
+	watcher.events <- "main.go"
+	watcher.events <- "util.go"
+	
+	time.Sleep(20 * time.Millisecond)
+	if got := atomic.LoadInt32(&regenerations); got != 0 {
+		t.Fatalf("Expected no regeneration inside the debounce window, got %d", got)
+	}
+	
+	time.Sleep(100 * time.Millisecond)
+	if got := atomic.LoadInt32(&regenerations); got != 1 {
+		t.Errorf("Expected exactly one regeneration after the debounce window, got %d", got)
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"kept.go":    {modTime: now, size: 10},
+		"edited.go":  {modTime: now, size: 10},
+		"removed.go": {modTime: now, size: 10},
+	}
+	current := map[string]fileState{
+		"kept.go":   {modTime: now, size: 10},
+		"edited.go": {modTime: now.Add(time.Second), size: 12},
+		"added.go":  {modTime: now, size: 1},
+	}
+	
+	changed := make(map[string]bool)
+	for _, path := range changedPaths(previous, current) {
+		changed[path] = true
+	}
+	
+	if len(changed) != 3 || !changed["edited.go"] || !changed["removed.go"] || !changed["added.go"] {
+		t.Errorf("Expected edited, removed and added files, got %v", changed)
+	}
+}
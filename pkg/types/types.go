@@ -1,18 +1,121 @@
 package types
 
+import (
+	"strings"
+	"time"
+)
+
 type AIModel struct {
-	Name        string `json:"name"`
-	Provider    string `json:"provider"`
-	APIEndpoint string `json:"api_endpoint"`
-	APIKey      string `json:"api_key,omitempty"`
+	Name         string            `json:"name"`
+	Provider     string            `json:"provider"`
+	APIEndpoint  string            `json:"api_endpoint"`
+	APIKey       string            `json:"api_key,omitempty"`
+	MaxTokens    int               `json:"max_tokens,omitempty"`
+	LastUsed     time.Time         `json:"last_used,omitempty"`
+	Capabilities []ModelCapability `json:"capabilities,omitempty"`
+	TimeoutSecs  int               `json:"timeout_seconds,omitempty"`    // request timeout; zero uses the client default
+	Retries      int               `json:"retries,omitempty"`            // extra attempts after a failed or rate-limited request
+	Temperature  *float64          `json:"temperature,omitempty"`        // sampling temperature; nil uses the provider default
+	Deployment   string            `json:"deployment,omitempty"`         // Azure OpenAI deployment name; defaults to Name
+	APIVersion   string            `json:"api_version,omitempty"`        // Azure OpenAI api-version query parameter
+	CostPer1K    float64           `json:"cost_per_1k_tokens,omitempty"` // input price in dollars per 1K tokens, for spend estimates
+}
+
+// Timeout returns the model's request timeout, or fallback when none is set
+func (m AIModel) Timeout(fallback time.Duration) time.Duration {
+	if m.TimeoutSecs <= 0 {
+		return fallback
+	}
+	return time.Duration(m.TimeoutSecs) * time.Second
+}
+
+// TokenCost returns the model's input price per 1K tokens, or fallback when none is set
+func (m AIModel) TokenCost(fallback float64) float64 {
+	if m.CostPer1K <= 0 {
+		return fallback
+	}
+	return m.CostPer1K
+}
+
+// ModelCapability describes something a model is suited for
+type ModelCapability string
+
+const (
+	CapabilityChat            ModelCapability = "chat"
+	CapabilityCodeGeneration  ModelCapability = "code_generation"
+	CapabilityCodeReview      ModelCapability = "code_review"
+	CapabilityLongContext     ModelCapability = "long_context"
+	CapabilityVision          ModelCapability = "vision"
+	CapabilityFunctionCalling ModelCapability = "function_calling"
+)
+
+// KnownCapabilities lists every valid ModelCapability
+func KnownCapabilities() []ModelCapability {
+	return []ModelCapability{
+		CapabilityChat,
+		CapabilityCodeGeneration,
+		CapabilityCodeReview,
+		CapabilityLongContext,
+		CapabilityVision,
+		CapabilityFunctionCalling,
+	}
+}
+
+// IsKnown reports whether the capability matches one of the defined constants
+func (c ModelCapability) IsKnown() bool {
+	for _, known := range KnownCapabilities() {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCapability reports whether the model declares the given capability
+func (m AIModel) HasCapability(capability ModelCapability) bool {
+	for _, c := range m.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownCapabilities returns declared capabilities that match no constant
+func (m AIModel) UnknownCapabilities() []ModelCapability {
+	var unknown []ModelCapability
+	for _, c := range m.Capabilities {
+		if !c.IsKnown() {
+			unknown = append(unknown, c)
+		}
+	}
+	return unknown
 }
 
 type ContextTemplate struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Template    string `json:"template"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Template    string   `json:"template"`
 	Variables   []string `json:"variables"`
+	Icon        string   `json:"icon,omitempty"`
+	Preamble    string   `json:"preamble,omitempty"`
+	SizeFactor  float64  `json:"size_factor,omitempty"`
+}
+
+// contextPlaceholder marks where the generated context goes in a template
+const contextPlaceholder = "{{.context}}"
+
+// Instructions returns the text to place before the context: the preamble if set,
+// otherwise the template with its context placeholder removed
+func (t ContextTemplate) Instructions() string {
+	if t.Preamble != "" {
+		return t.Preamble
+	}
+	if !strings.Contains(t.Template, contextPlaceholder) {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(t.Template, contextPlaceholder, ""))
 }
 
 type ChatMessage struct {
@@ -25,4 +128,4 @@ type ChatSession struct {
 	Model    AIModel       `json:"model"`
 	Messages []ChatMessage `json:"messages"`
 	Context  string        `json:"context"`
-}
\ No newline at end of file
+}
@@ -1,8 +1,15 @@
 package integration
 
 import (
+	"encoding/json"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+
+	"ai-context-cli/internal/config"
 )
 
 func TestCLIVersion(t *testing.T) {
@@ -30,4 +37,294 @@ func TestCLIHelp(t *testing.T) {
 	if len(output) == 0 {
 		t.Error("Expected help output to be non-empty")
 	}
-}
\ No newline at end of file
+}
+
+func TestCLIList(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "cli_list_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	os.MkdirAll(filepath.Join(tempDir, "pkg"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "node_modules", "dep"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# Readme\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "debug.log"), []byte("log\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "logo.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "empty.txt"), []byte{}, 0644)
+	os.WriteFile(filepath.Join(tempDir, "node_modules", "dep", "index.js"), []byte("module.exports = {}\n"), 0644)
+	
+	cmd := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "--list", tempDir)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to run CLI: %v", err)
+	}
+	
+	expected := "README.md\nmain.go\npkg/util.go\n"
+	if string(output) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(output))
+	}
+}
+
+func TestCLIListHonorsConfigAndBlocklist(t *testing.T) {
+	home := t.TempDir()
+	projectDir := t.TempDir()
+	os.MkdirAll(filepath.Join(projectDir, "secret"), 0755)
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(projectDir, "big.txt"), []byte(strings.Repeat("x", 500)), 0644)
+	os.WriteFile(filepath.Join(projectDir, "secret", "keys.go"), []byte("package secret\n"), 0644)
+	
+	configDir := filepath.Join(home, ".ai-context-cli")
+	os.MkdirAll(configDir, 0755)
+	os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"max_file_size": 100}`), 0644)
+	resolved, err := filepath.EvalSymlinks(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to resolve project dir: %v", err)
+	}
+	if err := config.SaveBlocklist(configDir, &config.Blocklist{Project: resolved, Entries: []string{"secret"}}); err != nil {
+		t.Fatalf("Failed to save blocklist: %v", err)
+	}
+	
+	run := func(args ...string) string {
+		cmd := exec.Command("go", append([]string{"run", "../../cmd/ai-context-cli/main.go"}, args...)...)
+		cmd.Env = append(os.Environ(), "HOME="+home)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to run CLI: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+	
+	// --list and generate both skip what the config and the blocklist exclude
+	if listed := run("--list", projectDir); listed != "main.go\n" {
+		t.Errorf("Expected only main.go listed, got %q", listed)
+	}
+	
+	output := filepath.Join(t.TempDir(), "context.md")
+	run("generate", "--output", output, projectDir)
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if strings.Contains(string(data), "keys.go") || strings.Contains(string(data), "big.txt") {
+		t.Errorf("Expected generate to skip blocklisted and oversized files, got:\n%s", data)
+	}
+}
+
+func TestCLIListSymlinkedRoot(t *testing.T) {
+	tempDir := t.TempDir()
+	realDir := filepath.Join(tempDir, "real")
+	os.MkdirAll(filepath.Join(realDir, "pkg"), 0755)
+	os.WriteFile(filepath.Join(realDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(realDir, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+	link := filepath.Join(tempDir, "link")
+	if err := os.Symlink(realDir, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	
+	output, err := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "--list", link).Output()
+	if err != nil {
+		t.Fatalf("Failed to run CLI: %v", err)
+	}
+	
+	expected := "main.go\npkg/util.go\n"
+	if string(output) != expected {
+		t.Errorf("Expected paths relative to the linked root %q, got %q", expected, string(output))
+	}
+}
+
+func TestCLIGenerateSymlinkedRootSkipsOutput(t *testing.T) {
+	tempDir := t.TempDir()
+	realDir := filepath.Join(tempDir, "real")
+	os.MkdirAll(realDir, 0755)
+	os.WriteFile(filepath.Join(realDir, "main.go"), []byte("package main\n"), 0644)
+	link := filepath.Join(tempDir, "link")
+	if err := os.Symlink(realDir, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+	
+	// The second run would pick up the first run's output if it were not excluded
+	output := filepath.Join(link, "context.md")
+	for run := 0; run < 2; run++ {
+		args := []string{"run", "../../cmd/ai-context-cli/main.go", "generate", "--output", output, link}
+		if out, err := exec.Command("go", args...).CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run CLI: %v\n%s", err, out)
+		}
+	}
+	
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if strings.Contains(string(data), "## context.md") {
+		t.Errorf("Expected the earlier output excluded from the context, got:\n%s", data)
+	}
+}
+
+func TestCLIGenerateSplit(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "cli_split_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	
+	projectDir := filepath.Join(tempDir, "project")
+	os.MkdirAll(projectDir, 0755)
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(projectDir, "README.md"), []byte("# Readme\n"), 0644)
+	
+	generate := func(output string, extra ...string) {
+		args := append([]string{"run", "../../cmd/ai-context-cli/main.go", "generate", "--output", output}, extra...)
+		args = append(args, projectDir)
+		if out, err := exec.Command("go", args...).CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run CLI: %v\n%s", err, out)
+		}
+	}
+	
+	// headings collects the top-level section headings of a file
+	headings := func(path string) []string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		var found []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "# ") {
+				found = append(found, line)
+			}
+		}
+		return found
+	}
+	
+	generate(filepath.Join(tempDir, "full.md"))
+	generate(filepath.Join(tempDir, "split.md"), "--split")
+	
+	structure := headings(filepath.Join(tempDir, "split.structure.md"))
+	content := headings(filepath.Join(tempDir, "split.content.md"))
+	if len(structure) == 0 || len(content) == 0 {
+		t.Fatalf("Expected sections in both files, got %v and %v", structure, content)
+	}
+	
+	full := headings(filepath.Join(tempDir, "full.md"))
+	combined := append(append([]string{}, structure...), content...)
+	sort.Strings(full)
+	sort.Strings(combined)
+	if strings.Join(full, "\n") != strings.Join(combined, "\n") {
+		t.Errorf("Expected split sections %v to equal full sections %v", combined, full)
+	}
+}
+
+func TestCLIGenerateContentLayoutFlags(t *testing.T) {
+	tempDir := t.TempDir()
+	projectDir := filepath.Join(tempDir, "project")
+	os.MkdirAll(filepath.Join(projectDir, "pkg"), 0755)
+	os.WriteFile(filepath.Join(projectDir, "main.go"), []byte("package main\r\n\r\nfunc main() {}\r\n"), 0644)
+	os.WriteFile(filepath.Join(projectDir, "main_test.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(projectDir, "pkg", "util.go"), []byte("package pkg\n"), 0644)
+	os.WriteFile(filepath.Join(projectDir, "pkg", "README.md"), []byte("Shared utilities\n"), 0644)
+	
+	generate := func(extra ...string) string {
+		output := filepath.Join(tempDir, "context.md")
+		args := append([]string{"run", "../../cmd/ai-context-cli/main.go", "generate", "--output", output}, extra...)
+		args = append(args, projectDir)
+		if out, err := exec.Command("go", args...).CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run CLI: %v\n%s", err, out)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		return string(data)
+	}
+	
+	markdown := generate("--group", "directory", "--separate-tests", "--dir-readmes",
+		"--header-level", "2", "--keep-line-endings", "--read-buffer", "16")
+	for _, heading := range []string{"## pkg/ Files Content\n", "## Tests\n", "## Directory READMEs\n"} {
+		if !strings.Contains(markdown, heading) {
+			t.Errorf("Expected %q in the output, got:\n%s", heading, markdown)
+		}
+	}
+	if strings.Contains(markdown, "\n# ") {
+		t.Error("Expected no level 1 headers with --header-level 2")
+	}
+	if !strings.Contains(markdown, "func main() {}\r\n") {
+		t.Error("Expected CRLF line endings kept with --keep-line-endings")
+	}
+	
+	if markdown := generate("--max-sections", "1"); !strings.Contains(markdown, "more file groups omitted (max 1 content sections)") {
+		t.Errorf("Expected --max-sections to cap the content sections, got:\n%s", markdown)
+	}
+	
+	cmd := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "generate", "--group", "sideways", projectDir)
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "unknown content grouping") {
+		t.Errorf("Expected an unknown grouping to be rejected, got %v:\n%s", err, out)
+	}
+}
+
+func TestCLIScanJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module demo\n"), 0644)
+	
+	output, err := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "scan", "--json", "--path", tempDir).Output()
+	if err != nil {
+		t.Fatalf("Failed to run CLI: %v", err)
+	}
+	
+	var summary struct {
+		Files        int      `json:"files"`
+		ProjectTypes []string `json:"project_types"`
+	}
+	if err := json.Unmarshal(output, &summary); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", output, err)
+	}
+	if summary.Files != 2 {
+		t.Errorf("Expected 2 files, got %d", summary.Files)
+	}
+	
+	missing := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "scan", filepath.Join(tempDir, "missing"))
+	if err := missing.Run(); err == nil {
+		t.Error("Expected scanning a missing directory to fail")
+	}
+}
+
+func TestCLIModels(t *testing.T) {
+	home := t.TempDir()
+	run := func(args ...string) ([]byte, int) {
+		cmd := exec.Command("go", append([]string{"run", "../../cmd/ai-context-cli/main.go", "models"}, args...)...)
+		cmd.Env = append(os.Environ(), "HOME="+home)
+		output, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return output, exitErr.ExitCode()
+		}
+		if err != nil {
+			t.Fatalf("Failed to run CLI: %v", err)
+		}
+		return output, 0
+	}
+	
+	output, code := run("list", "--json")
+	if code != 0 {
+		t.Fatalf("Expected models list to succeed, got exit code %d", code)
+	}
+	var models []struct {
+		Name     string `json:"name"`
+		Provider string `json:"provider"`
+	}
+	if err := json.Unmarshal(output, &models); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", output, err)
+	}
+	if len(models) == 0 || models[0].Name != "gpt-3.5-turbo" {
+		t.Errorf("Expected the default model to be listed, got %+v", models)
+	}
+	
+	// go run reports any non-zero status as 1, so check the message rather than the code
+	cmd := exec.Command("go", "run", "../../cmd/ai-context-cli/main.go", "models", "test", "not-configured")
+	cmd.Env = append(os.Environ(), "HOME="+home)
+	combined, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(combined), `model "not-configured" is not configured`) {
+		t.Errorf("Expected testing an unknown model to fail, got %q", combined)
+	}
+}